@@ -32,7 +32,11 @@ func New() *cli.App {
 	ctl.ErrWriter = os.Stdout
 
 	ctl.Commands = append(ctl.Commands, server.NewCommands()...)
-	ctl.Commands = append(ctl.Commands, smartcontract.NewCommands()...)
+	contractCmds := smartcontract.NewCommands()
+	// Storage commands need a local DB, so they're provided by the server
+	// package.
+	contractCmds[0].Subcommands = append(contractCmds[0].Subcommands, server.NewContractStorageCommand())
+	ctl.Commands = append(ctl.Commands, contractCmds...)
 	ctl.Commands = append(ctl.Commands, wallet.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, vm.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, util.NewCommands()...)
@@ -6,10 +6,16 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/epicchainlabs/epicchain-go/internal/testchain"
 	"github.com/epicchainlabs/epicchain-go/internal/testcli"
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 	"gopkg.in/yaml.v3"
 )
 
@@ -156,3 +162,144 @@ func TestDBDumpRestoreIncremental(t *testing.T) {
 	// Restore second 15 blocks from incremental dump.
 	e.Run(t, append(restoreBaseArgs, "--in", incDump, "-n", "--count", "15")...)
 }
+
+func loadLevelDBConfig(t *testing.T, path string) config.Config {
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = path
+	return cfg
+}
+
+func writeConfig(t *testing.T, cfg config.Config, dir string) string {
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	cfgPath := filepath.Join(dir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+	return cfgPath
+}
+
+// newTestChainDB creates a chain of the given height with GAS transfers in
+// the DB specified by cfg and returns the hashes of its blocks.
+func newTestChainDB(t *testing.T, cfg config.Config, height uint32) []util.Uint256 {
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(store, cfg.Blockchain(), zaptest.NewLogger(t))
+	require.NoError(t, err)
+	go bc.Run()
+	defer bc.Close()
+	gasHash, err := bc.GetNativeContractScriptHash(nativenames.Gas)
+	require.NoError(t, err)
+	for i := uint32(1); i <= height; i++ {
+		tx, err := testchain.NewTransferFromOwner(bc, gasHash, util.Uint160{1, 2, 3}, 1, i, i+1)
+		require.NoError(t, err)
+		require.NoError(t, bc.AddBlock(testchain.NewBlock(t, bc, 1, 0, tx)))
+	}
+	var hashes []util.Uint256
+	for i := uint32(0); i <= height; i++ {
+		hashes = append(hashes, bc.GetHeaderHash(i))
+	}
+	return hashes
+}
+
+// checkTestChainDB checks that the DB specified by cfg contains the chain
+// with the given block hashes.
+func checkTestChainDB(t *testing.T, cfg config.Config, hashes []util.Uint256) *core.Blockchain {
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(store, cfg.Blockchain(), zaptest.NewLogger(t))
+	require.NoError(t, err)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	require.Equal(t, uint32(len(hashes)-1), bc.BlockHeight())
+	for i := range hashes {
+		require.Equal(t, hashes[i], bc.GetHeaderHash(uint32(i)))
+	}
+	return bc
+}
+
+func TestDBResync(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create the source chain.
+	const height = 10
+	srcCfg := loadLevelDBConfig(t, filepath.Join(tmpDir, "srcchain"))
+	srcCfgPath := writeConfig(t, srcCfg, filepath.Join(tmpDir, "src"))
+	hashes := newTestChainDB(t, srcCfg, height)
+
+	dstPath := filepath.Join(tmpDir, "dstchain")
+	dstCfg := loadLevelDBConfig(t, dstPath)
+	dstCfg.ApplicationConfiguration.KeepOnlyLatestState = true
+	dstDir := filepath.Join(tmpDir, "dst")
+	writeConfig(t, dstCfg, dstDir)
+
+	e := testcli.NewExecutor(t, false)
+	baseArgs := []string{"neo-go", "db", "resync", "--unittest",
+		"--config-path", dstDir, "--source-config-file", srcCfgPath}
+
+	t.Run("missing source config", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "db", "resync", "--unittest", "--config-path", dstDir,
+			"--source-config-file", filepath.Join(tmpDir, "unknown.yml"))
+	})
+	t.Run("same DB", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "db", "resync", "--unittest",
+			"--config-path", filepath.Join(tmpDir, "src"), "--source-config-file", srcCfgPath)
+	})
+
+	e.Run(t, append(baseArgs, "--count", "4")...)
+	e.Run(t, baseArgs...)
+	// Nothing to replay.
+	e.RunWithError(t, baseArgs...)
+
+	dst := checkTestChainDB(t, dstCfg, hashes)
+	require.True(t, dst.GetConfig().Ledger.KeepOnlyLatestState)
+}
+
+func TestDBDumpRestoreV2(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	const height = 20
+	srcCfg := loadLevelDBConfig(t, filepath.Join(tmpDir, "srcchain"))
+	srcDir := filepath.Join(tmpDir, "src")
+	writeConfig(t, srcCfg, srcDir)
+	hashes := newTestChainDB(t, srcCfg, height)
+
+	e := testcli.NewExecutor(t, false)
+	dumpPath := filepath.Join(tmpDir, "dump.v2")
+	dumpArgs := []string{"neo-go", "db", "dump", "--unittest", "--config-path", srcDir}
+	t.Run("bad format", func(t *testing.T) {
+		e.RunWithError(t, append(dumpArgs, "--format", "3", "--out", dumpPath)...)
+	})
+	e.Run(t, append(dumpArgs, "--format", "2", "--out", dumpPath)...)
+	incDumpPath := filepath.Join(tmpDir, "dump.v2.inc")
+	e.Run(t, append(dumpArgs, "--format", "2", "--start", "16", "--out", incDumpPath)...)
+
+	dstCfg := loadLevelDBConfig(t, filepath.Join(tmpDir, "dstchain"))
+	dstDir := filepath.Join(tmpDir, "dst")
+	writeConfig(t, dstCfg, dstDir)
+	restoreArgs := []string{"neo-go", "db", "restore", "--unittest", "--config-path", dstDir}
+
+	t.Run("corrupted", func(t *testing.T) {
+		data, err := os.ReadFile(dumpPath)
+		require.NoError(t, err)
+		data[len(data)/2] ^= 0xff
+		badPath := filepath.Join(tmpDir, "bad.v2")
+		require.NoError(t, os.WriteFile(badPath, data, os.ModePerm))
+		e.RunWithError(t, append(restoreArgs, "--verify", "--in", badPath)...)
+	})
+	t.Run("verify v1", func(t *testing.T) {
+		v1Path := filepath.Join(tmpDir, "dump.v1")
+		e.Run(t, append(dumpArgs, "--out", v1Path)...)
+		e.RunWithError(t, append(restoreArgs, "--verify", "--in", v1Path)...)
+	})
+	t.Run("gap", func(t *testing.T) {
+		e.RunWithError(t, append(restoreArgs, "--in", incDumpPath)...)
+	})
+
+	e.Run(t, append(restoreArgs, "--verify", "--in", dumpPath, "--count", "6")...)
+	// Dump index is used to skip already restored blocks.
+	e.Run(t, append(restoreArgs, "--in", dumpPath, "--count", "10")...)
+	e.Run(t, append(restoreArgs, "--in", incDumpPath)...)
+	checkTestChainDB(t, dstCfg, hashes)
+}
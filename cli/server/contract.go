@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/epicchainlabs/epicchain-go/cli/cmdargs"
+	"github.com/epicchainlabs/epicchain-go/cli/flags"
+	"github.com/epicchainlabs/epicchain-go/cli/options"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/contractdump"
+	"github.com/urfave/cli"
+)
+
+// NewContractStorageCommand returns 'storage' command that is a part of
+// 'contract' command set, but it works with the local node database.
+func NewContractStorageCommand() cli.Command {
+	cfgFlags := []cli.Flag{options.Config, options.ConfigFile, options.RelativePath}
+	cfgFlags = append(cfgFlags, options.Network...)
+	cfgFlags = append(cfgFlags, options.Debug)
+	exportFlags := append([]cli.Flag{
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "height of the state to export contract storage from (default: current state height)",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "output file (stdout if not given)",
+		},
+	}, cfgFlags...)
+	importFlags := append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "input file with the contract snapshot",
+		},
+	}, cfgFlags...)
+	return cli.Command{
+		Name:  "storage",
+		Usage: "contract storage export and import",
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "export contract state and storage from the local node database",
+				UsageText: "neo-go contract storage export [--height height] [-o file] [--config-path path] [-p/-m/-t] [--config-file file] <hash>",
+				Description: `Exports contract state with all of its storage items at the given height
+   from the local node database into the JSON file. State for this height must be
+   available, so it's the latest state for nodes with KeepOnlyLatestState and
+   the StateRetention setting limits the set of heights available. <hash> is a
+   contract hash (LE) or address.
+`,
+				Action: exportContractStorage,
+				Flags:  exportFlags,
+			},
+			{
+				Name:      "import",
+				Usage:     "import contract snapshot into the local node database",
+				UsageText: "neo-go contract storage import -i file [--config-path path] [-p/-m/-t] [--config-file file]",
+				Description: `Adds contract with its storage from the snapshot made by the 'export'
+   command to the local node database. It's only possible for a database with
+   nothing but the genesis block and it changes the genesis state, so every node
+   of the network needs to import the same snapshots in the same order. Intended
+   to be used for private networks.
+`,
+				Action: importContractStorage,
+				Flags:  importFlags,
+			},
+		},
+	}
+}
+
+func exportContractStorage(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		return cli.NewExitError("no contract hash was provided, specify one as the first argument", 1)
+	}
+	if len(args) > 1 {
+		return cli.NewExitError("only one contract hash is accepted", 1)
+	}
+	hash, err := flags.ParseAddress(args[0])
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid contract hash: %w", err), 1)
+	}
+	chain, closer, err := initLocalChain(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	h := chain.GetStateModule().CurrentLocalHeight()
+	if ctx.IsSet("height") {
+		h = uint32(ctx.Uint("height"))
+	}
+	snap, err := contractdump.Export(chain.GetStateModule(), chain.GetConfig().Magic, h, hash)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var out = os.Stdout
+	if path := ctx.String("out"); path != "" {
+		out, err = os.Create(path)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+	err = contractdump.WriteSnapshot(w, snap)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to write snapshot: %w", err), 1)
+	}
+	return nil
+}
+
+func importContractStorage(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	in := ctx.String("in")
+	if in == "" {
+		return cli.NewExitError("no input file was provided, specify it with '--in' or '-i' flag", 1)
+	}
+	f, err := os.Open(in)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer f.Close()
+	snap, err := contractdump.ReadSnapshot(bufio.NewReader(f))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	chain, closer, err := initLocalChain(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	err = contractdump.Import(chain, snap)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "Contract %s (ID %d) imported with %d storage items\n",
+		snap.Contract.Hash.StringLE(), snap.Contract.ID, len(snap.Storage))
+	return nil
+}
+
+// initLocalChain opens the local node database using the configuration from
+// the context. The function returned stops the chain and closes the database.
+func initLocalChain(ctx *cli.Context) (*core.Blockchain, func(), error) {
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, nil, cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return nil, nil, cli.NewExitError(err, 1)
+	}
+	chain, _, err := initBlockChain(cfg, log)
+	if err != nil {
+		if logCloser != nil {
+			_ = logCloser()
+		}
+		return nil, nil, err
+	}
+	go chain.Run()
+	return chain, func() {
+		chain.Close() // Closes the DB also.
+		if logCloser != nil {
+			_ = logCloser()
+		}
+	}, nil
+}
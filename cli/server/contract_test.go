@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/internal/testcli"
+	"github.com/epicchainlabs/epicchain-go/pkg/compiler"
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/contractdump"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestContractStorageImportExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = filepath.Join(tmpDir, "chain")
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "protocol.unit_testnet.yml"), out, os.ModePerm))
+
+	src := `package foo
+	func Main() int { return 1 }`
+	ctr := neotest.CompileSource(t, util.Uint160{1, 2, 3}, strings.NewReader(src), &compiler.Options{Name: "Foo"})
+	snap := &contractdump.Snapshot{
+		Magic:  cfg.ProtocolConfiguration.Magic + 1,
+		Height: 123,
+		Contract: state.Contract{ContractBase: state.ContractBase{
+			ID:       42,
+			Hash:     ctr.Hash,
+			NEF:      *ctr.NEF,
+			Manifest: *ctr.Manifest,
+		}},
+		Storage: []contractdump.StorageItem{
+			{Key: []byte{1}, Value: []byte{1, 1}},
+			{Key: []byte{2, 3}, Value: []byte{2}},
+		},
+	}
+	snapPath := filepath.Join(tmpDir, "snapshot.json")
+	f, err := os.Create(snapPath)
+	require.NoError(t, err)
+	require.NoError(t, contractdump.WriteSnapshot(f, snap))
+	require.NoError(t, f.Close())
+
+	e := testcli.NewExecutor(t, false)
+	cfgArgs := []string{"--unittest", "--config-path", tmpDir}
+	t.Run("import", func(t *testing.T) {
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "import"}, cfgArgs...)...)
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "import", "--in", filepath.Join(tmpDir, "missing")}, cfgArgs...)...)
+		e.Run(t, append([]string{"neo-go", "contract", "storage", "import", "--in", snapPath}, cfgArgs...)...)
+		e.CheckNextLine(t, "Contract "+ctr.Hash.StringLE()+" \\(ID 42\\) imported with 2 storage items")
+		// It's already there.
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "import", "--in", snapPath}, cfgArgs...)...)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		outPath := filepath.Join(tmpDir, "out.json")
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "export", "--out", outPath}, cfgArgs...)...)
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "export", "--out", outPath, "--height", "1"}, append(cfgArgs, ctr.Hash.StringLE())...)...)
+		e.RunWithError(t, append([]string{"neo-go", "contract", "storage", "export", "--out", outPath}, append(cfgArgs, util.Uint160{}.StringLE())...)...)
+		e.Run(t, append([]string{"neo-go", "contract", "storage", "export", "--out", outPath, "--height", "0"}, append(cfgArgs, ctr.Hash.StringLE())...)...)
+
+		f, err := os.Open(outPath)
+		require.NoError(t, err)
+		defer f.Close()
+		actual, err := contractdump.ReadSnapshot(f)
+		require.NoError(t, err)
+		require.Equal(t, cfg.ProtocolConfiguration.Magic, actual.Magic)
+		require.Equal(t, uint32(0), actual.Height)
+		require.Equal(t, snap.Contract.ID, actual.Contract.ID)
+		require.Equal(t, snap.Contract.Hash, actual.Contract.Hash)
+		require.Equal(t, snap.Contract.NEF, actual.Contract.NEF)
+		require.Equal(t, snap.Contract.Manifest.ABI, actual.Contract.Manifest.ABI)
+		require.Equal(t, snap.Storage, actual.Storage)
+	})
+}
@@ -0,0 +1,536 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/epicchainlabs/epicchain-go/cli/cmdargs"
+	"github.com/epicchainlabs/epicchain-go/cli/options"
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/chaindump"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+// hardforkManifests is a set of native contract manifests active after the
+// hardfork.
+type hardforkManifests struct {
+	Hardfork  string               `json:"hardfork"`
+	Height    *uint32              `json:"height,omitempty"`
+	Manifests []*manifest.Manifest `json:"manifests"`
+}
+
+func newHardforksCommand(cfgFlags []cli.Flag) cli.Command {
+	nativesFlags := append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "hardfork",
+			Usage: "hardfork to show the changes of (default: all known hardforks)",
+		},
+		cli.BoolFlag{
+			Name:  "manifests",
+			Usage: "print full native contract manifests in JSON instead of changes",
+		},
+	}, cfgFlags...)
+	replayFlags := append([]cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "hardfork",
+			Usage: "hypothetical hardfork enabling height in the Name:height form, can be specified multiple times",
+		},
+		cli.UintFlag{
+			Name:  "start, s",
+			Usage: "block number to start comparison from (default: the lowest affected hardfork height)",
+		},
+		cli.UintFlag{
+			Name:  "count, c",
+			Usage: "number of blocks to be compared (default or 0: up to the current height)",
+		},
+		cli.StringFlag{
+			Name:  "tmp-dir",
+			Usage: "directory to create the temporary database of the replayed chain in (default: system temporary directory)",
+		},
+	}, cfgFlags...)
+	return cli.Command{
+		Name:  "hardforks",
+		Usage: "hardfork activation analysis",
+		Subcommands: []cli.Command{
+			{
+				Name:      "natives",
+				Usage:     "show native contract changes made by hardforks",
+				UsageText: "neo-go hardforks natives [--hardfork name] [--manifests] [--config-path path] [-p/-m/-t] [--config-file file]",
+				Description: `Prints methods and events added, removed or changed (prices, call flags,
+   signatures) by every known hardfork for the native contracts of the network
+   along with the enabling height of the hardfork from the configuration. With
+   --manifests full native contract manifests are printed in JSON for the
+   genesis state and every hardfork instead.
+`,
+				Action: showNativesHardforks,
+				Flags:  nativesFlags,
+			},
+			{
+				Name:      "replay",
+				Usage:     "replay blocks with different hardfork heights and compare results",
+				UsageText: "neo-go hardforks replay --hardfork Name:height [--hardfork Name:height]... [-s start] [-c count] [--tmp-dir path] [--config-path path] [-p/-m/-t] [--config-file file]",
+				Description: `Processes blocks of the local node database with hardfork heights changed
+   as specified by --hardfork flags using a temporary chain and compares
+   application logs and state roots with the ones of the local database. Every
+   block starting from the genesis one is processed, but only blocks starting
+   from --start (and up to --count blocks) are compared. The temporary chain
+   uses LevelDB database created in --tmp-dir that keeps only the latest state
+   and is removed when the command exits, but it still needs disk space
+   comparable to the one used by the blocks up to the last compared one. The
+   local database itself is not changed. Exits with an error if differences
+   are found.
+`,
+				Action: replayHardforks,
+				Flags:  replayFlags,
+			},
+		},
+	}
+}
+
+// effectiveHardforks returns hardfork enabling heights the same way Blockchain
+// treats them, missing hardforks are not enabled.
+func effectiveHardforks(cfg config.ProtocolConfiguration) map[string]uint32 {
+	res := make(map[string]uint32, len(config.Hardforks))
+	if cfg.Hardforks == nil {
+		for _, hf := range config.Hardforks {
+			res[hf.String()] = 0
+		}
+		return res
+	}
+	for k, v := range cfg.Hardforks {
+		res[k] = v
+	}
+	if len(res) != 0 {
+		for _, hf := range config.Hardforks {
+			if _, ok := res[hf.String()]; ok {
+				break
+			}
+			res[hf.String()] = 0
+		}
+	}
+	return res
+}
+
+func showNativesHardforks(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	hfs := append([]config.Hardfork{config.HFDefault}, config.Hardforks...)
+	if name := ctx.String("hardfork"); name != "" {
+		if !config.IsHardforkValid(name) {
+			return cli.NewExitError(fmt.Errorf("unknown hardfork %s", name), 1)
+		}
+		for _, hf := range config.Hardforks {
+			if hf.String() == name {
+				hfs = []config.Hardfork{hf}
+			}
+		}
+	}
+	var (
+		heights = effectiveHardforks(cfg.ProtocolConfiguration)
+		natives = native.NewContracts(cfg.ProtocolConfiguration).Contracts
+	)
+	if ctx.Bool("manifests") {
+		res := make([]hardforkManifests, 0, len(hfs))
+		for _, hf := range hfs {
+			hfm := hardforkManifests{Hardfork: hf.String(), Manifests: []*manifest.Manifest{}}
+			if h, ok := heights[hf.String()]; ok || hf == config.HFDefault {
+				hfm.Height = &h
+			}
+			for _, c := range natives {
+				if activeIn := c.ActiveIn(); activeIn != nil && activeIn.Cmp(hf) > 0 {
+					continue
+				}
+				hf := hf
+				hfm.Manifests = append(hfm.Manifests, &c.Metadata().HFSpecificContractMD(&hf).Manifest)
+			}
+			res = append(res, hfm)
+		}
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(data))
+		return nil
+	}
+	for _, hf := range hfs {
+		if hf == config.HFDefault {
+			continue
+		}
+		if h, ok := heights[hf.String()]; ok {
+			fmt.Fprintf(ctx.App.Writer, "%s (height %d):\n", hf, h)
+		} else {
+			fmt.Fprintf(ctx.App.Writer, "%s (not enabled):\n", hf)
+		}
+		var changed bool
+		for _, c := range natives {
+			md := c.Metadata()
+			activeIn := c.ActiveIn()
+			if activeIn != nil && activeIn.Cmp(hf) > 0 {
+				continue
+			}
+			var (
+				cur   = hf
+				prev  = hf.Prev()
+				after = md.HFSpecificContractMD(&cur)
+				diff  []string
+			)
+			if activeIn != nil && *activeIn == hf {
+				diff = []string{fmt.Sprintf("+ contract activated with %d methods and %d events", len(after.Methods), len(after.Events))}
+			} else {
+				diff = diffNativeMD(md.HFSpecificContractMD(&prev), after)
+			}
+			if len(diff) == 0 {
+				continue
+			}
+			changed = true
+			fmt.Fprintf(ctx.App.Writer, "  %s (%s):\n", md.Name, md.Hash.StringLE())
+			for _, d := range diff {
+				fmt.Fprintf(ctx.App.Writer, "    %s\n", d)
+			}
+		}
+		if !changed {
+			fmt.Fprintln(ctx.App.Writer, "  no native contract changes")
+		}
+	}
+	return nil
+}
+
+// diffNativeMD returns human-readable differences between two versions of the
+// native contract metadata.
+func diffNativeMD(before, after *interop.HFSpecificContractMD) []string {
+	if before == after {
+		return nil
+	}
+	var res []string
+	for _, m := range before.Methods {
+		if _, ok := after.GetMethod(m.MD.Name, len(m.MD.Parameters)); !ok {
+			res = append(res, "- method "+methodString(m))
+		}
+	}
+	for _, m := range after.Methods {
+		old, ok := before.GetMethod(m.MD.Name, len(m.MD.Parameters))
+		if !ok {
+			res = append(res, "+ method "+methodString(m))
+			continue
+		}
+		var changes []string
+		if old.CPUFee != m.CPUFee {
+			changes = append(changes, fmt.Sprintf("CPU fee %d -> %d", old.CPUFee, m.CPUFee))
+		}
+		if old.StorageFee != m.StorageFee {
+			changes = append(changes, fmt.Sprintf("storage fee %d -> %d", old.StorageFee, m.StorageFee))
+		}
+		if old.RequiredFlags != m.RequiredFlags {
+			changes = append(changes, fmt.Sprintf("flags %s -> %s", old.RequiredFlags, m.RequiredFlags))
+		}
+		if old.MD.Safe != m.MD.Safe {
+			changes = append(changes, fmt.Sprintf("safe %t -> %t", old.MD.Safe, m.MD.Safe))
+		}
+		if oldSig, sig := signatureString(old.MD.Parameters)+" "+old.MD.ReturnType.String(),
+			signatureString(m.MD.Parameters)+" "+m.MD.ReturnType.String(); oldSig != sig {
+			changes = append(changes, fmt.Sprintf("signature %s -> %s", oldSig, sig))
+		}
+		if len(changes) != 0 {
+			res = append(res, fmt.Sprintf("~ method %s/%d: %s", m.MD.Name, len(m.MD.Parameters), strings.Join(changes, ", ")))
+		}
+	}
+	oldEvents := make(map[string]*manifest.Event, len(before.Events))
+	for _, e := range before.Events {
+		oldEvents[e.MD.Name] = e.MD
+	}
+	for _, e := range after.Events {
+		old, ok := oldEvents[e.MD.Name]
+		delete(oldEvents, e.MD.Name)
+		switch {
+		case !ok:
+			res = append(res, "+ event "+e.MD.Name+signatureString(e.MD.Parameters))
+		case signatureString(old.Parameters) != signatureString(e.MD.Parameters):
+			res = append(res, fmt.Sprintf("~ event %s: signature %s -> %s", e.MD.Name,
+				signatureString(old.Parameters), signatureString(e.MD.Parameters)))
+		}
+	}
+	for _, e := range before.Events {
+		if _, ok := oldEvents[e.MD.Name]; ok {
+			res = append(res, "- event "+e.MD.Name+signatureString(e.MD.Parameters))
+		}
+	}
+	if oldStd, std := strings.Join(before.Manifest.SupportedStandards, ", "),
+		strings.Join(after.Manifest.SupportedStandards, ", "); oldStd != std {
+		res = append(res, fmt.Sprintf("~ supported standards [%s] -> [%s]", oldStd, std))
+	}
+	// Compare everything else apart from ABI and standards at once.
+	oldM, m := before.Manifest, after.Manifest
+	oldM.ABI, m.ABI = manifest.ABI{}, manifest.ABI{}
+	oldM.SupportedStandards, m.SupportedStandards = nil, nil
+	oldData, err1 := json.Marshal(oldM)
+	data, err2 := json.Marshal(m)
+	if err1 != nil || err2 != nil || !bytes.Equal(oldData, data) {
+		res = append(res, "~ manifest groups, permissions, trusts or extra data")
+	}
+	return res
+}
+
+func methodString(m interop.HFSpecificMethodAndPrice) string {
+	s := fmt.Sprintf("%s%s %s, CPU fee %d, storage fee %d, flags %s", m.MD.Name,
+		signatureString(m.MD.Parameters), m.MD.ReturnType, m.CPUFee, m.StorageFee, m.RequiredFlags)
+	if m.MD.Safe {
+		s += ", safe"
+	}
+	return s
+}
+
+func signatureString(params []manifest.Parameter) string {
+	ps := make([]string, len(params))
+	for i := range params {
+		ps[i] = params[i].Name + " " + params[i].Type.String()
+	}
+	return "(" + strings.Join(ps, ", ") + ")"
+}
+
+func replayHardforks(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	overrides := ctx.StringSlice("hardfork")
+	if len(overrides) == 0 {
+		return cli.NewExitError("no hardfork heights specified", 1)
+	}
+	var (
+		heights  = effectiveHardforks(cfg.ProtocolConfiguration)
+		replayed = cfg.Blockchain()
+		start    = uint32(math.MaxUint32)
+	)
+	replayed.Hardforks = effectiveHardforks(cfg.ProtocolConfiguration)
+	for _, o := range overrides {
+		name, hStr, ok := strings.Cut(o, ":")
+		if !ok || !config.IsHardforkValid(name) {
+			return cli.NewExitError(fmt.Errorf("invalid hardfork height %q, Name:height expected", o), 1)
+		}
+		h, err := strconv.ParseUint(hStr, 10, 32)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid %s hardfork height: %w", name, err), 1)
+		}
+		replayed.Hardforks[name] = uint32(h)
+		if uint32(h) < start {
+			start = uint32(h)
+		}
+		if old, ok := heights[name]; ok && old < start {
+			start = old
+		}
+	}
+	if err := replayed.ProtocolConfiguration.Validate(); err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid hardforks configuration: %w", err), 1)
+	}
+	if ctx.IsSet("start") {
+		start = uint32(ctx.Uint("start"))
+	}
+	srcDB := cfg.ApplicationConfiguration.DBConfiguration
+	if srcDB.Type == dbconfig.InMemoryDB {
+		return cli.NewExitError(errors.New("in-memory database is not supported"), 1)
+	}
+	// Local DB is never changed.
+	srcDB.LevelDBOptions.ReadOnly = true
+	srcDB.BoltDBOptions.ReadOnly = true
+	srcDB.LSMOptions.ReadOnly = true
+
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	srcStore, err := storage.NewStore(srcDB)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
+	}
+	defer srcStore.Close()
+	src, err := core.NewBlockchain(srcStore, cfg.Blockchain(), log.With(zap.String("db", "local")))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize blockchain: %w", err), 1)
+	}
+	if start > src.BlockHeight() {
+		return cli.NewExitError(fmt.Errorf("start height %d is above the chain height %d", start, src.BlockHeight()), 1)
+	}
+	end := src.BlockHeight()
+	if count := uint32(ctx.Uint("count")); count != 0 && start+count-1 < end {
+		end = start + count - 1
+	}
+
+	// Replayed chain can be as big as the local one, so it's stored on disk.
+	tmpDir, err := os.MkdirTemp(ctx.String("tmp-dir"), "neogo-replay-")
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to create temporary directory: %w", err), 1)
+	}
+	defer os.RemoveAll(tmpDir)
+	dstStore, err := storage.NewStore(dbconfig.DBConfiguration{
+		Type:           dbconfig.LevelDB,
+		LevelDBOptions: dbconfig.LevelDBOptions{DataDirectoryPath: tmpDir},
+	})
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize replay storage: %w", err), 1)
+	}
+	// Only the latest state is needed for comparison.
+	replayed.KeepOnlyLatestState = true
+	replayed.RemoveUntraceableBlocks = false
+	chain, err := core.NewBlockchain(dstStore, replayed, log.With(zap.String("db", "replay")))
+	if err != nil {
+		dstStore.Close()
+		return cli.NewExitError(fmt.Errorf("could not initialize replay blockchain: %w", err), 1)
+	}
+	go chain.Run()
+	defer chain.Close()
+
+	var diffBlocks int
+	compare := func(b *block.Block) error {
+		diff, err := compareBlockResults(src, chain, b)
+		if err != nil {
+			return err
+		}
+		if len(diff) != 0 {
+			diffBlocks++
+		}
+		for _, d := range diff {
+			fmt.Fprintf(ctx.App.Writer, "block %d: %s\n", b.Index, d)
+		}
+		return nil
+	}
+	if start == 0 {
+		genesis, err := src.GetBlock(src.GetHeaderHash(0))
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to get genesis block: %w", err), 1)
+		}
+		if err := compare(genesis); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	log.Info("initialize replay",
+		zap.Uint32("start", start),
+		zap.Uint32("end", end))
+
+	gctx := newGraceContext()
+	err = chaindump.Copy(src, chain, 1, end, func(b *block.Block) error {
+		select {
+		case <-gctx.Done():
+			return gctx.Err()
+		default:
+		}
+		if b.Index < start {
+			return nil
+		}
+		return compare(b)
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if diffBlocks != 0 {
+		return cli.NewExitError(fmt.Errorf("differences found in %d blocks", diffBlocks), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "No differences found in blocks %d-%d\n", start, end)
+	return nil
+}
+
+// compareBlockResults returns human-readable differences between the results
+// of the given block processing by two chains.
+func compareBlockResults(src, dst *core.Blockchain, b *block.Block) ([]string, error) {
+	var res []string
+	srcAERs, err := src.GetAppExecResults(b.Hash(), trigger.All)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d application logs: %w", b.Index, err)
+	}
+	dstAERs, err := dst.GetAppExecResults(b.Hash(), trigger.All)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replayed block %d application logs: %w", b.Index, err)
+	}
+	for i := range srcAERs {
+		if i >= len(dstAERs) {
+			res = append(res, fmt.Sprintf("%s: missing execution", srcAERs[i].Trigger))
+			continue
+		}
+		for _, d := range compareExecutions(&srcAERs[i].Execution, &dstAERs[i].Execution) {
+			res = append(res, fmt.Sprintf("%s: %s", srcAERs[i].Trigger, d))
+		}
+	}
+	for _, tx := range b.Transactions {
+		srcAERs, err := src.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction %s application log: %w", tx.Hash().StringLE(), err)
+		}
+		dstAERs, err := dst.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replayed transaction %s application log: %w", tx.Hash().StringLE(), err)
+		}
+		if len(srcAERs) == 0 || len(dstAERs) == 0 {
+			continue
+		}
+		for _, d := range compareExecutions(&srcAERs[0].Execution, &dstAERs[0].Execution) {
+			res = append(res, fmt.Sprintf("transaction %s: %s", tx.Hash().StringLE(), d))
+		}
+	}
+	srcRoot, err := src.GetStateModule().GetStateRoot(b.Index)
+	if err == nil {
+		dstRoot, err := dst.GetStateModule().GetStateRoot(b.Index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replayed block %d state root: %w", b.Index, err)
+		}
+		if !srcRoot.Root.Equals(dstRoot.Root) {
+			res = append(res, fmt.Sprintf("state root %s -> %s", srcRoot.Root.StringLE(), dstRoot.Root.StringLE()))
+		}
+	}
+	return res, nil
+}
+
+func compareExecutions(a, b *state.Execution) []string {
+	var res []string
+	if a.VMState != b.VMState {
+		res = append(res, fmt.Sprintf("VM state %s -> %s", a.VMState, b.VMState))
+	}
+	if a.FaultException != b.FaultException {
+		res = append(res, fmt.Sprintf("exception %q -> %q", a.FaultException, b.FaultException))
+	}
+	if a.GasConsumed != b.GasConsumed {
+		res = append(res, fmt.Sprintf("GAS consumed %d -> %d", a.GasConsumed, b.GasConsumed))
+	}
+	aStack, err1 := json.Marshal(state.Execution{Stack: a.Stack})
+	bStack, err2 := json.Marshal(state.Execution{Stack: b.Stack})
+	if err1 != nil || err2 != nil || !bytes.Equal(aStack, bStack) {
+		res = append(res, "resulting stack differs")
+	}
+	if len(a.Events) != len(b.Events) {
+		res = append(res, fmt.Sprintf("%d notifications -> %d notifications", len(a.Events), len(b.Events)))
+	} else {
+		for i := range a.Events {
+			aEvent, err1 := json.Marshal(a.Events[i])
+			bEvent, err2 := json.Marshal(b.Events[i])
+			if err1 != nil || err2 != nil || !bytes.Equal(aEvent, bEvent) {
+				res = append(res, fmt.Sprintf("notification #%d differs", i))
+			}
+		}
+	}
+	return res
+}
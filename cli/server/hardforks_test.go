@@ -0,0 +1,129 @@
+package server_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/internal/testcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHardforksNatives(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	cfgArgs := []string{"--unittest", "--config-path", filepath.Join("..", "..", "config")}
+
+	t.Run("unknown hardfork", func(t *testing.T) {
+		e.RunWithError(t, append([]string{"neo-go", "hardforks", "natives", "--hardfork", "Unknown"}, cfgArgs...)...)
+	})
+	t.Run("changes", func(t *testing.T) {
+		e.Run(t, append([]string{"neo-go", "hardforks", "natives"}, cfgArgs...)...)
+		e.CheckNextLine(t, `^Aspidochelone \(height 25\):$`)
+		e.CheckNextLine(t, "no native contract changes")
+		e.CheckNextLine(t, `^Basilisk \(not enabled\):$`)
+		e.CheckNextLine(t, "no native contract changes")
+		e.CheckNextLine(t, `^Cockatrice \(not enabled\):$`)
+		e.CheckNextLine(t, `^  CryptoLib \(`)
+		e.CheckNextLine(t, `\+ method keccak256\(data ByteArray\) ByteArray, CPU fee 32768, storage fee 0, flags None, safe`)
+		e.CheckNextLine(t, `~ method verifyWithECDsa/4: signature .*curve Integer\) Boolean -> .*curveHash Integer\) Boolean`)
+		e.CheckNextLine(t, `^  NeoToken \(`)
+		e.CheckNextLine(t, `\+ method getCommitteeAddress\(\) Hash160, CPU fee 65536, storage fee 0, flags ReadStates, safe`)
+		e.CheckNextLine(t, `\+ event CommitteeChanged\(old Array, new Array\)`)
+		e.CheckNextLine(t, `^Domovoi \(not enabled\):$`)
+		e.CheckNextLine(t, `^  CryptoLib \(`)
+		e.CheckNextLine(t, `\+ method bn254Add\(x InteropInterface, y InteropInterface\) InteropInterface`)
+		e.CheckNextLine(t, `\+ method bn254Deserialize\(data ByteArray\) InteropInterface`)
+		e.CheckNextLine(t, `\+ method bn254Equal\(x InteropInterface, y InteropInterface\) Boolean`)
+		e.CheckNextLine(t, `\+ method bn254Mul\(x InteropInterface, mul ByteArray, neg Boolean\) InteropInterface`)
+		e.CheckNextLine(t, `\+ method bn254Pairing\(g1 InteropInterface, g2 InteropInterface\) InteropInterface`)
+		e.CheckNextLine(t, `\+ method bn254Serialize\(g InteropInterface\) ByteArray`)
+		e.CheckNextLine(t, `\+ method mimcBls12381\(data ByteArray\) ByteArray`)
+		e.CheckNextLine(t, `\+ method poseidon2Bls12381\(data ByteArray\) ByteArray`)
+		e.CheckNextLine(t, `\+ method recoverSecp256K1\(messageHash ByteArray, signature ByteArray\) ByteArray`)
+		e.CheckNextLine(t, `\+ method verifyWithEd25519\(message ByteArray, pubkey ByteArray, signature ByteArray\) Boolean`)
+		e.CheckEOF(t)
+	})
+	t.Run("single hardfork", func(t *testing.T) {
+		e.Run(t, append([]string{"neo-go", "hardforks", "natives", "--hardfork", "Basilisk"}, cfgArgs...)...)
+		e.CheckNextLine(t, `^Basilisk \(not enabled\):$`)
+		e.CheckNextLine(t, "no native contract changes")
+		e.CheckEOF(t)
+	})
+	t.Run("manifests", func(t *testing.T) {
+		e.Run(t, append([]string{"neo-go", "hardforks", "natives", "--manifests"}, cfgArgs...)...)
+		var res []struct {
+			Hardfork  string            `json:"hardfork"`
+			Height    *uint32           `json:"height"`
+			Manifests []json.RawMessage `json:"manifests"`
+		}
+		require.NoError(t, json.Unmarshal(e.Out.Bytes(), &res))
+		require.Equal(t, 5, len(res))
+		require.Equal(t, "Default", res[0].Hardfork)
+		require.Equal(t, uint32(0), *res[0].Height)
+		require.Equal(t, "Aspidochelone", res[1].Hardfork)
+		require.Equal(t, uint32(25), *res[1].Height)
+		require.Equal(t, "Cockatrice", res[3].Hardfork)
+		require.Nil(t, res[3].Height)
+		require.Equal(t, "Domovoi", res[4].Hardfork)
+		require.Nil(t, res[4].Height)
+		for _, r := range res {
+			require.Equal(t, len(res[0].Manifests), len(r.Manifests))
+		}
+	})
+}
+
+func TestHardforksReplay(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	const height = 10
+	cfg := loadLevelDBConfig(t, filepath.Join(tmpDir, "chain"))
+	cfgDir := filepath.Join(tmpDir, "cfg")
+	writeConfig(t, cfg, cfgDir)
+	newTestChainDB(t, cfg, height)
+
+	e := testcli.NewExecutor(t, false)
+	baseArgs := []string{"neo-go", "hardforks", "replay", "--unittest", "--config-path", cfgDir}
+
+	t.Run("no hardforks", func(t *testing.T) {
+		e.RunWithError(t, baseArgs...)
+	})
+	t.Run("invalid hardfork", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Aspidochelone")...)
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Unknown:5")...)
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Aspidochelone:-1")...)
+	})
+	t.Run("inconsistent hardforks", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Cockatrice:5")...)
+	})
+	t.Run("start above height", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Aspidochelone:20")...)
+	})
+	t.Run("no differences", func(t *testing.T) {
+		e.Run(t, append(baseArgs, "--hardfork", "Aspidochelone:3")...)
+		e.CheckNextLine(t, "No differences found in blocks 3-10")
+		e.CheckEOF(t)
+
+		e.Run(t, append(baseArgs, "--hardfork", "Aspidochelone:3", "--start", "0", "--count", "5")...)
+		e.CheckNextLine(t, "No differences found in blocks 0-4")
+		e.CheckEOF(t)
+
+		// Temporary database is removed.
+		replayDir := filepath.Join(tmpDir, "replay")
+		require.NoError(t, os.Mkdir(replayDir, 0700))
+		e.Run(t, append(baseArgs, "--hardfork", "Aspidochelone:3", "--tmp-dir", replayDir)...)
+		e.CheckNextLine(t, "No differences found in blocks 3-10")
+		e.CheckEOF(t)
+		entries, err := os.ReadDir(replayDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+	t.Run("differences", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "--hardfork", "Aspidochelone:5",
+			"--hardfork", "Basilisk:5", "--hardfork", "Cockatrice:5")...)
+		// Contract updates on hardfork activation.
+		e.CheckNextLine(t, "^block 5: OnPersist: 2 notifications -> 4 notifications$")
+		e.CheckNextLine(t, "^block 5: state root [0-9a-f]{64} -> [0-9a-f]{64}$")
+		e.CheckNextLine(t, "^block 6: state root [0-9a-f]{64} -> [0-9a-f]{64}$")
+	})
+}
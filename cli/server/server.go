@@ -1,12 +1,21 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	stdio "io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/epicchainlabs/epicchain-go/cli/cmdargs"
@@ -17,8 +26,11 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/chaindump"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/dao"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
 	corestate "github.com/epicchainlabs/epicchain-go/pkg/core/stateroot"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/io"
 	"github.com/epicchainlabs/epicchain-go/pkg/network"
@@ -26,6 +38,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/services/notary"
 	"github.com/epicchainlabs/epicchain-go/pkg/services/oracle"
 	"github.com/epicchainlabs/epicchain-go/pkg/services/rpcsrv"
+	"github.com/epicchainlabs/epicchain-go/pkg/services/statecheck"
 	"github.com/epicchainlabs/epicchain-go/pkg/services/stateroot"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
@@ -57,6 +70,11 @@ func NewCommands() []cli.Command {
 			Name:  "out, o",
 			Usage: "Output file (stdout if not given)",
 		},
+		cli.UintFlag{
+			Name:  "format",
+			Value: 1,
+			Usage: "dump format version (1 or 2)",
+		},
 	)
 	var cfgCountInFlags = make([]cli.Flag, len(cfgWithCountFlags))
 	copy(cfgCountInFlags, cfgWithCountFlags)
@@ -71,7 +89,24 @@ func NewCommands() []cli.Command {
 		},
 		cli.BoolFlag{
 			Name:  "incremental, n",
-			Usage: "use if dump is incremental",
+			Usage: "use if dump is incremental (v1 format only)",
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "check dump integrity before restoring (v2 format only)",
+		},
+		cli.IntFlag{
+			Name:  "workers",
+			Usage: "number of goroutines decoding and preverifying blocks ahead of processing (default or 0: number of CPUs, 1: sequential processing)",
+		},
+	)
+	var cfgCountSourceFlags = make([]cli.Flag, len(cfgWithCountFlags))
+	copy(cfgCountSourceFlags, cfgWithCountFlags)
+	cfgCountSourceFlags = append(cfgCountSourceFlags,
+		cli.StringFlag{
+			Name:     "source-config-file",
+			Usage:    "Path to the configuration file of the source node database",
+			Required: true,
 		},
 	)
 	var cfgHeightFlags = make([]cli.Flag, len(cfgFlags)+1)
@@ -81,6 +116,34 @@ func NewCommands() []cli.Command {
 		Usage:    "Height of the state to reset DB to",
 		Required: true,
 	}
+	var cfgStateOutFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgStateOutFlags, cfgFlags)
+	cfgStateOutFlags = append(cfgStateOutFlags,
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "Output file",
+		},
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "State sync point to make a snapshot for (default or 0: the latest one available)",
+		},
+	)
+	var cfgStateInFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgStateInFlags, cfgFlags)
+	cfgStateInFlags = append(cfgStateInFlags,
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input file",
+		},
+	)
+	var cfgCheckStateFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgCheckStateFlags, cfgFlags)
+	cfgCheckStateFlags = append(cfgCheckStateFlags,
+		cli.StringSliceFlag{
+			Name:  "rpc, r",
+			Usage: "Reference RPC node address, can be specified multiple times (default: StateCheck.Nodes from the configuration)",
+		},
+	)
 	return []cli.Command{
 		{
 			Name:      "node",
@@ -96,14 +159,14 @@ func NewCommands() []cli.Command {
 				{
 					Name:      "dump",
 					Usage:     "dump blocks (starting with block #1) to the file",
-					UsageText: "neo-go db dump -o file [-s start] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
+					UsageText: "neo-go db dump -o file [-s start] [-c count] [--format version] [--config-path path] [-p/-m/-t] [--config-file file]",
 					Action:    dumpDB,
 					Flags:     cfgCountOutFlags,
 				},
 				{
 					Name:      "restore",
 					Usage:     "restore blocks from the file",
-					UsageText: "neo-go db restore -i file [--dump] [-n] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
+					UsageText: "neo-go db restore -i file [--dump] [-n] [--verify] [--workers n] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
 					Action:    restoreDB,
 					Flags:     cfgCountInFlags,
 				},
@@ -114,8 +177,51 @@ func NewCommands() []cli.Command {
 					Action:    resetDB,
 					Flags:     cfgHeightFlags,
 				},
+				{
+					Name:      "resync",
+					Usage:     "replay blocks from another local node database",
+					UsageText: "neo-go db resync --source-config-file file [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    resyncDB,
+					Flags:     cfgCountSourceFlags,
+				},
+				{
+					Name:      "dump-state",
+					Usage:     "dump state snapshot for the state sync point to the file",
+					UsageText: "neo-go db dump-state -o file [--height height] [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    dumpState,
+					Flags:     cfgStateOutFlags,
+				},
+				{
+					Name:      "restore-state",
+					Usage:     "bootstrap a new node from the state snapshot file",
+					UsageText: "neo-go db restore-state -i file [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    restoreState,
+					Flags:     cfgStateInFlags,
+				},
+				{
+					Name:      "check-state",
+					Usage:     "compare local state roots with the ones of reference RPC nodes",
+					UsageText: "neo-go db check-state [-r address]... [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    checkState,
+					Flags:     cfgCheckStateFlags,
+				},
+				{
+					Name:      "stats",
+					Usage:     "print the number of keys and their sizes per key prefix and per contract",
+					UsageText: "neo-go db stats [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    dbStats,
+					Flags:     cfgFlags,
+				},
+				{
+					Name:      "compact",
+					Usage:     "compact the database reclaiming the space of deleted data",
+					UsageText: "neo-go db compact [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    compactDB,
+					Flags:     cfgFlags,
+				},
 			},
 		},
+		newHardforksCommand(cfgFlags),
 	}
 }
 
@@ -169,6 +275,10 @@ func dumpDB(ctx *cli.Context) error {
 	}
 	count := uint32(ctx.Uint("count"))
 	start := uint32(ctx.Uint("start"))
+	format := ctx.Uint("format")
+	if format > 2 {
+		return cli.NewExitError(fmt.Errorf("unsupported dump format %d", format), 1)
+	}
 
 	var outStream = os.Stdout
 	if out := ctx.String("out"); out != "" {
@@ -197,6 +307,17 @@ func dumpDB(ctx *cli.Context) error {
 	if count == 0 {
 		count = chainCount - start
 	}
+	if format == 2 {
+		bw := bufio.NewWriter(outStream)
+		err = chaindump.DumpV2(chain, bw, start, count)
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	}
 	if start != 0 {
 		writer.WriteU32LE(start)
 	}
@@ -224,6 +345,10 @@ func restoreDB(ctx *cli.Context) error {
 		defer func() { _ = logCloser() }()
 	}
 	count := uint32(ctx.Uint("count"))
+	workers := ctx.Int("workers")
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
 
 	var inStream = os.Stdin
 	if in := ctx.String("in"); in != "" {
@@ -233,7 +358,32 @@ func restoreDB(ctx *cli.Context) error {
 		}
 	}
 	defer inStream.Close()
-	reader := io.NewBinReaderFromIO(inStream)
+	var (
+		br     = bufio.NewReader(inStream)
+		reader *io.BinReader
+		v2     *chaindump.Reader
+	)
+	if prefix, _ := br.Peek(len(chaindump.DumpV2Magic)); chaindump.IsDumpV2(prefix) {
+		var src stdio.Reader = br
+		// Use the file directly if possible, so that the dump index can be used.
+		if _, err := inStream.Seek(0, stdio.SeekStart); err == nil {
+			src = inStream
+		}
+		v2, err = chaindump.NewReader(src, cfg.ProtocolConfiguration.StateRootInHeader)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if ctx.Bool("verify") {
+			if err := v2.Verify(); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+	} else {
+		if ctx.Bool("verify") {
+			return cli.NewExitError(errors.New("--verify is only supported for v2 dumps"), 1)
+		}
+		reader = io.NewBinReaderFromIO(br)
+	}
 
 	dumpDir := ctx.String("dump")
 	if dumpDir != "" {
@@ -250,6 +400,10 @@ func restoreDB(ctx *cli.Context) error {
 		chain.Close()
 	}()
 
+	if v2 != nil {
+		return restoreDBV2(chain, v2, count, workers, log, dumpDir)
+	}
+
 	var start uint32
 	if ctx.Bool("incremental") {
 		start = reader.ReadU32LE()
@@ -278,14 +432,57 @@ func restoreDB(ctx *cli.Context) error {
 		zap.Uint32("start", start),
 		zap.Uint32("height", chain.BlockHeight()),
 		zap.Uint32("skip", skip),
-		zap.Uint32("count", count))
+		zap.Uint32("count", count),
+		zap.Int("workers", workers))
+
+	f, persist := newRestoreHandler(chain, dumpDir)
+	defer persist()
+
+	err = chaindump.NewImporter(chain, workers, f).Restore(reader, skip, count)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
 
+// restoreDBV2 restores blocks from the v2 dump starting from the one following
+// the current chain height.
+func restoreDBV2(chain *core.Blockchain, r *chaindump.Reader, count uint32, workers int, log *zap.Logger, dumpDir string) error {
+	next := chain.BlockHeight() + 1
+	if chain.BlockHeight() == 0 && r.Start() == 0 {
+		next = 0
+	}
+	if next < r.Start() {
+		return cli.NewExitError(fmt.Errorf("expected height: %d, dump starts at %d", next, r.Start()), 1)
+	}
+	if next != r.Start() {
+		if err := r.Seek(next); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	log.Info("initialize restore",
+		zap.Uint32("start", r.Start()),
+		zap.Uint32("height", chain.BlockHeight()),
+		zap.Uint32("skip", next-r.Start()),
+		zap.Uint32("count", count),
+		zap.Int("workers", workers))
+
+	f, persist := newRestoreHandler(chain, dumpDir)
+	defer persist()
+
+	err := chaindump.NewImporter(chain, workers, f).RestoreV2(r, count)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// newRestoreHandler returns a function to be called for every restored block
+// and a function persisting the remaining storage changes if dumpDir is set.
+func newRestoreHandler(chain *core.Blockchain, dumpDir string) (func(*block.Block) error, func()) {
 	gctx := newGraceContext()
 	var lastIndex uint32
 	dump := newDump()
-	defer func() {
-		_ = dump.tryPersist(dumpDir, lastIndex)
-	}()
 
 	var f = func(b *block.Block) error {
 		select {
@@ -317,12 +514,9 @@ func restoreDB(ctx *cli.Context) error {
 			return nil
 		}
 	}
-
-	err = chaindump.Restore(chain, reader, skip, count, f)
-	if err != nil {
-		return cli.NewExitError(err, 1)
+	return f, func() {
+		_ = dump.tryPersist(dumpDir, lastIndex)
 	}
-	return nil
 }
 
 func resetDB(ctx *cli.Context) error {
@@ -358,6 +552,354 @@ func resetDB(ctx *cli.Context) error {
 	return nil
 }
 
+func resyncDB(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	srcCfg, err := config.LoadFile(ctx.String("source-config-file"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to load source configuration: %w", err), 1)
+	}
+	if srcCfg.ProtocolConfiguration.Magic != cfg.ProtocolConfiguration.Magic {
+		return cli.NewExitError(fmt.Errorf("source network magic %d doesn't match %d",
+			srcCfg.ProtocolConfiguration.Magic, cfg.ProtocolConfiguration.Magic), 1)
+	}
+	srcDB := srcCfg.ApplicationConfiguration.DBConfiguration
+	if srcDB.Type == dbconfig.InMemoryDB {
+		return cli.NewExitError(errors.New("in-memory source database is not supported"), 1)
+	}
+	if srcDB.Type == cfg.ApplicationConfiguration.DBConfiguration.Type &&
+		dbPath(srcDB) == dbPath(cfg.ApplicationConfiguration.DBConfiguration) {
+		return cli.NewExitError(errors.New("source and destination databases are the same"), 1)
+	}
+	// Source DB is never changed.
+	srcDB.LevelDBOptions.ReadOnly = true
+	srcDB.BoltDBOptions.ReadOnly = true
+	srcDB.LSMOptions.ReadOnly = true
+
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	srcStore, err := storage.NewStore(srcDB)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize source storage: %w", err), 1)
+	}
+	defer srcStore.Close()
+	// Source chain is not running, so nothing is persisted to its DB.
+	src, err := core.NewBlockchain(srcStore, srcCfg.Blockchain(), log.With(zap.String("db", "source")))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize source blockchain: %w", err), 1)
+	}
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	var (
+		start = chain.BlockHeight() + 1
+		count = uint32(ctx.Uint("count"))
+	)
+	if start > src.BlockHeight() {
+		return cli.NewExitError(fmt.Errorf("source height %d is not greater than %d", src.BlockHeight(), chain.BlockHeight()), 1)
+	}
+	if count == 0 || start+count > src.BlockHeight()+1 {
+		count = src.BlockHeight() + 1 - start
+	}
+	log.Info("initialize resync",
+		zap.Uint32("start", start),
+		zap.Uint32("count", count),
+		zap.Uint32("source height", src.BlockHeight()))
+
+	gctx := newGraceContext()
+	err = chaindump.Copy(src, chain, start, count, func(b *block.Block) error {
+		select {
+		case <-gctx.Done():
+			return gctx.Err()
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// dbPath returns the location of the database configured.
+func dumpState(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	out := ctx.String("out")
+	if out == "" {
+		return cli.NewExitError("output file is mandatory", 1)
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	p := uint32(ctx.Uint("height"))
+	if p == 0 {
+		// The next block is required to get the state root.
+		interval := uint32(chain.GetConfig().StateSyncInterval)
+		if chain.BlockHeight() > 0 {
+			p = (chain.BlockHeight() - 1) / interval * interval
+		}
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	w := io.NewBinWriterFromIO(bw)
+	err = chain.GetStateSyncModule().ExportSnapshot(w, p)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to dump state for %d: %w", p, err), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "State snapshot for height %d is written\n", p)
+	return nil
+}
+
+func restoreState(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	in := ctx.String("in")
+	if in == "" {
+		return cli.NewExitError("input file is mandatory", 1)
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	f, err := os.Open(in)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer f.Close()
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	err = chain.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromIO(bufio.NewReader(f)))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to restore state: %w", err), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "State is restored, chain height is %d\n", chain.BlockHeight())
+	return nil
+}
+
+func checkState(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	scCfg := cfg.ApplicationConfiguration.StateCheck
+	if nodes := ctx.StringSlice("rpc"); len(nodes) != 0 {
+		scCfg.Nodes = nodes
+	}
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	sc, err := statecheck.New(statecheck.Config{
+		Log:     log,
+		MainCfg: scCfg,
+		Chain:   chain,
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	ds, err := sc.Check()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to check state: %w", err), 1)
+	}
+	if len(ds) == 0 {
+		fmt.Fprintf(ctx.App.Writer, "State matches reference nodes at height %d\n", chain.GetStateModule().CurrentLocalHeight())
+		return nil
+	}
+	for _, d := range ds {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(data))
+	}
+	return cli.NewExitError("state divergence found", 1)
+}
+
+func dbPath(cfg dbconfig.DBConfiguration) string {
+	switch cfg.Type {
+	case dbconfig.LevelDB:
+		return filepath.Clean(cfg.LevelDBOptions.DataDirectoryPath)
+	case dbconfig.BoltDB:
+		return filepath.Clean(cfg.BoltDBOptions.FilePath)
+	case dbconfig.LSMDB:
+		return filepath.Clean(cfg.LSMOptions.DataDirectoryPath)
+	default:
+		return ""
+	}
+}
+
+func dbStats(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
+	}
+	defer store.Close()
+
+	stats, err := storage.GetStats(newGraceContext(), store)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to collect DB statistics: %w", err), 1)
+	}
+	var (
+		prefixes = make([]storage.KeyPrefix, 0, len(stats.Prefixes))
+		ids      = make([]int32, 0, len(stats.Contracts))
+		total    storage.PrefixStats
+		d        = dao.NewSimple(store, cfg.ProtocolConfiguration.StateRootInHeader)
+		buf      = bytes.NewBuffer(nil)
+	)
+	for p := range stats.Prefixes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+	fmt.Fprintln(buf, "Prefix\tKeys\tKey bytes\tValue bytes\t")
+	for _, p := range prefixes {
+		ps := stats.Prefixes[p]
+		total.Keys += ps.Keys
+		total.KeyBytes += ps.KeyBytes
+		total.ValueBytes += ps.ValueBytes
+		fmt.Fprintf(buf, "%s (0x%02x)\t%d\t%d\t%d\t\n", p, byte(p), ps.Keys, ps.KeyBytes, ps.ValueBytes)
+	}
+	fmt.Fprintf(buf, "Total\t%d\t%d\t%d\t\n", total.Keys, total.KeyBytes, total.ValueBytes)
+
+	for id := range stats.Contracts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "Contract\tKeys\tKey bytes\tValue bytes\t")
+	for _, id := range ids {
+		var (
+			cs   = stats.Contracts[id]
+			name = strconv.FormatInt(int64(id), 10)
+		)
+		if h, err := native.GetContractScriptHash(d, id); err == nil {
+			name += " (" + h.StringLE() + ")"
+		}
+		fmt.Fprintf(buf, "%s\t%d\t%d\t%d\t\n", name, cs.Keys, cs.KeyBytes, cs.ValueBytes)
+	}
+	tw := tabwriter.NewWriter(ctx.App.Writer, 0, 2, 2, ' ', 0)
+	if _, err = tw.Write(buf.Bytes()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err = tw.Flush(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func compactDB(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	dbCfg := cfg.ApplicationConfiguration.DBConfiguration
+	store, err := storage.NewStore(dbCfg)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
+	}
+	c, ok := store.(storage.Compactor)
+	if !ok {
+		_ = store.Close()
+		return cli.NewExitError(fmt.Errorf("%s DB doesn't support compaction", dbCfg.Type), 1)
+	}
+	start := time.Now()
+	err = c.Compact()
+	if closeErr := store.Close(); err == nil && closeErr != nil {
+		return cli.NewExitError(fmt.Errorf("failed to close the DB: %w", closeErr), 1)
+	}
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to compact the DB: %w", err), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "DB compacted in %s\n", time.Since(start))
+	return nil
+}
+
 // oracleService is an interface representing Oracle service with network.Service
 // capabilities and ability to submit oracle responses.
 type oracleService interface {
@@ -389,6 +931,14 @@ func mkConsensus(config config.Consensus, tpb time.Duration, chain *core.Blockch
 	if !config.Enabled {
 		return nil, nil
 	}
+	var store storage.Store
+	if config.RoundsDB.Type != "" {
+		var err error
+		store, err = storage.NewStore(config.RoundsDB)
+		if err != nil {
+			return nil, fmt.Errorf("can't open consensus rounds DB: %w", err)
+		}
+	}
 	srv, err := consensus.NewService(consensus.Config{
 		Logger:                log,
 		Broadcast:             serv.BroadcastExtensible,
@@ -398,9 +948,14 @@ func mkConsensus(config config.Consensus, tpb time.Duration, chain *core.Blockch
 		RequestTx:             serv.RequestTx,
 		StopTxFlow:            serv.StopTxFlow,
 		Wallet:                config.UnlockWallet,
+		WatchOnly:             config.WatchOnly,
+		Store:                 store,
 		TimePerBlock:          tpb,
 	})
 	if err != nil {
+		if store != nil {
+			_ = store.Close()
+		}
 		return nil, fmt.Errorf("can't initialize Consensus module: %w", err)
 	}
 
@@ -435,6 +990,22 @@ func mkP2PNotary(config config.P2PNotary, chain *core.Blockchain, serv *network.
 	return n, nil
 }
 
+func mkStateCheck(config config.StateCheck, chain *core.Blockchain, serv *network.Server, log *zap.Logger) (*statecheck.Service, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	sc, err := statecheck.New(statecheck.Config{
+		Log:     log,
+		MainCfg: config,
+		Chain:   chain,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't initialize StateCheck module: %w", err)
+	}
+	serv.AddService(sc)
+	return sc, nil
+}
+
 func startServer(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -494,8 +1065,13 @@ func startServer(ctx *cli.Context) error {
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
+	stateCheck, err := mkStateCheck(cfg.ApplicationConfiguration.StateCheck, chain, serv, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
 	errChan := make(chan error)
 	rpcServer := rpcsrv.New(chain, cfg.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+	rpcServer.SetConsensusStateProvider(dbftSrv)
 	serv.AddService(&rpcServer)
 
 	serv.Start()
@@ -555,6 +1131,7 @@ Main:
 				serv.DelService(&rpcServer)
 				rpcServer.Shutdown()
 				rpcServer = rpcsrv.New(chain, cfgnew.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+				rpcServer.SetConsensusStateProvider(dbftSrv)
 				serv.AddService(&rpcServer)
 				if !cfgnew.ApplicationConfiguration.RPC.StartWhenSynchronized || serv.IsInSync() {
 					// Here similar to the initial run (see above for-loop), so async.
@@ -617,9 +1194,22 @@ Main:
 				if serv.IsInSync() {
 					sr.Start()
 				}
+				if stateCheck != nil {
+					serv.DelService(stateCheck)
+					stateCheck.Shutdown()
+				}
+				stateCheck, err = mkStateCheck(cfgnew.ApplicationConfiguration.StateCheck, chain, serv, log)
+				if err != nil {
+					log.Error("failed to create state check service", zap.Error(err))
+					break // Keep going.
+				}
+				if stateCheck != nil && serv.IsInSync() {
+					stateCheck.Start()
+				}
 			case sigusr2:
 				if dbftSrv != nil {
 					serv.DelConsensusService(dbftSrv)
+					rpcServer.SetConsensusStateProvider(nil)
 					dbftSrv.Shutdown()
 				}
 				dbftSrv, err = mkConsensus(cfgnew.ApplicationConfiguration.Consensus, serverConfig.TimePerBlock, chain, serv, log)
@@ -627,6 +1217,7 @@ Main:
 					log.Error("failed to create consensus service", zap.Error(err))
 					break // Whatever happens, I'll leave it all to chance.
 				}
+				rpcServer.SetConsensusStateProvider(dbftSrv)
 				if dbftSrv != nil && serv.IsInSync() {
 					dbftSrv.Start()
 				}
@@ -1,14 +1,17 @@
 package server
 
 import (
+	"bytes"
 	"encoding/binary"
 	"flag"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
 
 	"github.com/epicchainlabs/epicchain-go/cli/options"
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
@@ -390,3 +393,58 @@ func TestResetDB(t *testing.T) {
 	err = resetDB(ctx)
 	require.NoError(t, err)
 }
+
+func TestDBStatsCompact(t *testing.T) {
+	d := t.TempDir()
+	err := os.Chdir(d)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(serverTestWD)) })
+	set := flag.NewFlagSet("flagSet", flag.ExitOnError)
+	set.String("config-path", filepath.Join(serverTestWD, "..", "..", "config"), "")
+	set.Bool("privnet", true, "")
+	set.Bool("debug", true, "")
+	app := cli.NewApp()
+	buf := bytes.NewBuffer(nil)
+	app.Writer = buf
+	ctx := cli.NewContext(app, set, nil)
+	cfg, err := options.GetConfigFromContext(ctx)
+	require.NoError(t, err)
+	chain, _, err := initBlockChain(cfg, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	go chain.Run()
+	chain.Close() // Persists the genesis block.
+
+	require.NoError(t, dbStats(ctx))
+	out := buf.String()
+	require.Contains(t, out, "SYSVersion (0xf0)")
+	require.Contains(t, out, "STStorage (0x70)")
+	require.Contains(t, out, "-1 (fffdc93764dbaddd97c48f252a53ea4643faa3fd)")
+
+	buf.Reset()
+	require.NoError(t, compactDB(ctx))
+	require.True(t, strings.HasPrefix(buf.String(), "DB compacted in "))
+}
+
+func TestCheckState(t *testing.T) {
+	d := t.TempDir()
+	err := os.Chdir(d)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(serverTestWD)) })
+	set := flag.NewFlagSet("flagSet", flag.ExitOnError)
+	set.String("config-path", filepath.Join(serverTestWD, "..", "..", "config"), "")
+	set.Bool("privnet", true, "")
+	set.Bool("debug", true, "")
+	rpc := cli.StringSlice{}
+	set.Var(&rpc, "rpc", "")
+	ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+	t.Run("no nodes", func(t *testing.T) {
+		err := checkState(ctx)
+		require.ErrorContains(t, err, "no reference nodes")
+	})
+	t.Run("unavailable node", func(t *testing.T) {
+		require.NoError(t, set.Set("rpc", "http://localhost:1"))
+		err := checkState(ctx)
+		require.ErrorContains(t, err, "failed to check state")
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/statesync"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
@@ -44,9 +45,12 @@ type FakeStateSync struct {
 	IsActiveFlag      atomic.Bool
 	IsInitializedFlag atomic.Bool
 	RequestHeaders    atomic.Bool
+	RequestMPTNodes   atomic.Bool
 	InitFunc          func(h uint32) error
 	TraverseFunc      func(root util.Uint256, process func(node mpt.Node, nodeBytes []byte) bool) error
 	AddMPTNodesFunc   func(nodes [][]byte) error
+	// GetUnknownMPTNodesBatchFunc is used by GetUnknownMPTNodesBatch if set.
+	GetUnknownMPTNodesBatchFunc func(limit int) []util.Uint256
 }
 
 // NewFakeChain returns a new FakeChain structure.
@@ -448,9 +452,7 @@ func (s *FakeStateSync) Init(currChainHeight uint32) error {
 func (s *FakeStateSync) NeedHeaders() bool { return s.RequestHeaders.Load() }
 
 // NeedMPTNodes implements the StateSync interface.
-func (s *FakeStateSync) NeedMPTNodes() bool {
-	panic("TODO")
-}
+func (s *FakeStateSync) NeedMPTNodes() bool { return s.RequestMPTNodes.Load() }
 
 // Traverse implements the StateSync interface.
 func (s *FakeStateSync) Traverse(root util.Uint256, process func(node mpt.Node, nodeBytes []byte) bool) error {
@@ -462,5 +464,13 @@ func (s *FakeStateSync) Traverse(root util.Uint256, process func(node mpt.Node,
 
 // GetUnknownMPTNodesBatch implements the StateSync interface.
 func (s *FakeStateSync) GetUnknownMPTNodesBatch(limit int) []util.Uint256 {
+	if s.GetUnknownMPTNodesBatchFunc != nil {
+		return s.GetUnknownMPTNodesBatchFunc(limit)
+	}
 	panic("TODO")
 }
+
+// GetMPTProgress implements the StateSync interface.
+func (s *FakeStateSync) GetMPTProgress() statesync.MPTProgress {
+	return statesync.MPTProgress{}
+}
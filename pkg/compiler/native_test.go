@@ -71,6 +71,7 @@ func TestRoleManagementRole(t *testing.T) {
 	require.EqualValues(t, noderoles.StateValidator, roles.StateValidator)
 	require.EqualValues(t, noderoles.NeoFSAlphabet, roles.NeoFSAlphabet)
 	require.EqualValues(t, noderoles.P2PNotary, roles.P2PNotary)
+	require.EqualValues(t, noderoles.ContractDeployer, roles.ContractDeployer)
 }
 
 func TestCryptoLibNamedCurve(t *testing.T) {
@@ -147,7 +148,7 @@ type nativeTestCase struct {
 
 // Here we test that corresponding method does exist, is invoked and correct value is returned.
 func TestNativeHelpersCompile(t *testing.T) {
-	cfg := config.ProtocolConfiguration{P2PSigExtensions: true}
+	cfg := config.ProtocolConfiguration{P2PSigExtensions: true, PermissionedDeployment: true}
 	cs := native.NewContracts(cfg)
 	u160 := `interop.Hash160("aaaaaaaaaaaaaaaaaaaa")`
 	u256 := `interop.Hash256("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")`
@@ -228,6 +229,9 @@ func TestNativeHelpersCompile(t *testing.T) {
 		{"getMinimumDeploymentFee", nil},
 		{"hasMethod", []string{u160, `"method"`, "0"}},
 		{"setMinimumDeploymentFee", []string{"42"}},
+		{"isDeployer", []string{u160}},
+		{"addDeployer", []string{u160}},
+		{"removeDeployer", []string{u160}},
 		{"update", []string{"nil", "nil"}},
 		{"updateWithData", []string{"nil", "nil", "123"}},
 	})
@@ -243,6 +247,16 @@ func TestNativeHelpersCompile(t *testing.T) {
 		{"bls12381Mul", []string{"crypto.Bls12381Point{}", "[]byte{1, 2, 3}", "true"}},
 		{"bls12381Pairing", []string{"crypto.Bls12381Point{}", "crypto.Bls12381Point{}"}},
 		{"keccak256", []string{"[]byte{1, 2, 3}"}},
+		{"recoverSecp256K1", []string{"[]byte{1, 2, 3}", "[]byte{4, 5, 6}"}},
+		{"verifyWithEd25519", []string{"[]byte{1, 2, 3}", "[]byte{4, 5, 6}", "[]byte{7, 8, 9}"}},
+		{"bn254Serialize", []string{"crypto.Bn254Point{}"}},
+		{"bn254Deserialize", []string{"[]byte{1, 2, 3}"}},
+		{"bn254Equal", []string{"crypto.Bn254Point{}", "crypto.Bn254Point{}"}},
+		{"bn254Add", []string{"crypto.Bn254Point{}", "crypto.Bn254Point{}"}},
+		{"bn254Mul", []string{"crypto.Bn254Point{}", "[]byte{1, 2, 3}", "true"}},
+		{"bn254Pairing", []string{"crypto.Bn254Point{}", "crypto.Bn254Point{}"}},
+		{"mimcBls12381", []string{"[]byte{1, 2, 3}"}},
+		{"poseidon2Bls12381", []string{"[]byte{1, 2, 3}"}},
 	})
 	runNativeTestCases(t, cs.Std.ContractMD, "std", []nativeTestCase{
 		{"serialize", []string{"[]byte{1, 2, 3}"}},
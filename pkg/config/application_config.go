@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,16 +24,17 @@ type ApplicationConfiguration struct {
 	Pprof      BasicService `yaml:"Pprof"`
 	Prometheus BasicService `yaml:"Prometheus"`
 
-	Relay     bool                `yaml:"Relay"`
-	Consensus Consensus           `yaml:"Consensus"`
-	RPC       RPC                 `yaml:"RPC"`
-	Oracle    OracleConfiguration `yaml:"Oracle"`
-	P2PNotary P2PNotary           `yaml:"P2PNotary"`
-	StateRoot StateRoot           `yaml:"StateRoot"`
+	Relay      bool                `yaml:"Relay"`
+	Consensus  Consensus           `yaml:"Consensus"`
+	RPC        RPC                 `yaml:"RPC"`
+	Oracle     OracleConfiguration `yaml:"Oracle"`
+	P2PNotary  P2PNotary           `yaml:"P2PNotary"`
+	StateCheck StateCheck          `yaml:"StateCheck"`
+	StateRoot  StateRoot           `yaml:"StateRoot"`
 }
 
 // EqualsButServices returns true when the o is the same as a except for services
-// (Oracle, P2PNotary, Pprof, Prometheus, RPC and StateRoot sections)
+// (Oracle, P2PNotary, Pprof, Prometheus, RPC, StateCheck and StateRoot sections)
 // and LogLevel field.
 func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration) bool {
 	if len(a.P2P.Addresses) != len(o.P2P.Addresses) {
@@ -51,7 +53,7 @@ func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration
 	}
 	if a.P2P.AttemptConnPeers != o.P2P.AttemptConnPeers ||
 		a.P2P.BroadcastFactor != o.P2P.BroadcastFactor ||
-		a.DBConfiguration != o.DBConfiguration ||
+		!reflect.DeepEqual(a.DBConfiguration, o.DBConfiguration) ||
 		a.P2P.DialTimeout != o.P2P.DialTimeout ||
 		a.P2P.ExtensiblePoolSize != o.P2P.ExtensiblePoolSize ||
 		a.LogPath != o.LogPath ||
@@ -19,6 +19,9 @@ const (
 	UserAgentPrefix = "NEO-GO:"
 	// UserAgentFormat is a formatted string used to generate user agent string.
 	UserAgentFormat = UserAgentWrapper + UserAgentPrefix + "%s" + UserAgentWrapper
+	// DefaultMaxConsensusStatsBlocks is the default maximum number of blocks
+	// that can be processed by a single `getconsensusstats` JSON-RPC request.
+	DefaultMaxConsensusStatsBlocks = 100000
 	// DefaultMaxIteratorResultItems is the default upper bound of traversed
 	// iterator items per JSON-RPC response. It covers both session-based and
 	// naive iterators.
@@ -38,6 +41,9 @@ const (
 	// DefaultMaxRequestHeaderBytes is the maximum permitted size of the headers
 	// in an HTTP request.
 	DefaultMaxRequestHeaderBytes = http.DefaultMaxHeaderBytes
+	// DefaultMaxSubscriptionReplayBlocks is the default maximum number of past
+	// blocks that can be replayed by a single websocket subscription.
+	DefaultMaxSubscriptionReplayBlocks = 10000
 )
 
 // Version is the version of the node, set at the build time.
@@ -102,6 +108,7 @@ func LoadFile(configPath string, relativePath ...string) (Config, error) {
 	if len(relativePath) == 1 && relativePath[0] != "" {
 		updateRelativePaths(relativePath[0], &config)
 	}
+	updateGenesisPaths(filepath.Dir(configPath), &config.ProtocolConfiguration.Genesis)
 
 	err = config.ProtocolConfiguration.Validate()
 	if err != nil {
@@ -122,8 +129,26 @@ func updateRelativePaths(relativePath string, config *Config) {
 	updatePath(&config.ApplicationConfiguration.LogPath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.BoltDBOptions.FilePath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath)
+	updatePath(&config.ApplicationConfiguration.DBConfiguration.LSMOptions.DataDirectoryPath)
 	updatePath(&config.ApplicationConfiguration.Consensus.UnlockWallet.Path)
+	updatePath(&config.ApplicationConfiguration.Consensus.RoundsDB.BoltDBOptions.FilePath)
+	updatePath(&config.ApplicationConfiguration.Consensus.RoundsDB.LevelDBOptions.DataDirectoryPath)
+	updatePath(&config.ApplicationConfiguration.Consensus.RoundsDB.LSMOptions.DataDirectoryPath)
 	updatePath(&config.ApplicationConfiguration.P2PNotary.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.Oracle.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.StateRoot.UnlockWallet.Path)
+	updatePath(&config.ApplicationConfiguration.StateCheck.DumpPath)
+}
+
+// updateGenesisPaths updates relative paths of genesis contract files, they're
+// a part of the protocol configuration, so they're always relative to the
+// configuration file directory.
+func updateGenesisPaths(configDir string, genesis *Genesis) {
+	for i := range genesis.Contracts {
+		for _, path := range []*string{&genesis.Contracts[i].NEF, &genesis.Contracts[i].Manifest} {
+			if *path != "" && !filepath.IsAbs(*path) {
+				*path = filepath.Join(configDir, *path)
+			}
+		}
+	}
 }
@@ -32,3 +32,23 @@ func TestUnknownConfigFields(t *testing.T) {
 		require.Contains(t, err.Error(), "field UnknownConfigurationField not found in type config.Config")
 	})
 }
+
+func TestGenesisContractsPaths(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := filepath.Join(tmp, "protocol.testnet.yml")
+	require.NoError(t, os.WriteFile(cfg, []byte(`ProtocolConfiguration:
+  StandbyCommittee:
+    - 02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2
+  ValidatorsCount: 1
+  Genesis:
+    Contracts:
+      - NEF: contracts/registry.nef
+        Manifest: /abs/registry.manifest.json
+`), os.ModePerm))
+
+	c, err := LoadFile(cfg, "/some/relative/path")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.ProtocolConfiguration.Genesis.Contracts))
+	require.Equal(t, filepath.Join(tmp, "contracts", "registry.nef"), c.ProtocolConfiguration.Genesis.Contracts[0].NEF)
+	require.Equal(t, "/abs/registry.manifest.json", c.ProtocolConfiguration.Genesis.Contracts[0].Manifest)
+}
@@ -1,4 +1,15 @@
 package config
 
+import "github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+
 // Consensus contains consensus service configuration.
-type Consensus InternalService
+type Consensus struct {
+	Enabled      bool   `yaml:"Enabled"`
+	UnlockWallet Wallet `yaml:"UnlockWallet"`
+	// WatchOnly makes the service follow dBFT rounds without signing or
+	// sending any messages even if UnlockWallet is specified.
+	WatchOnly bool `yaml:"WatchOnly"`
+	// RoundsDB is a configuration of a separate database used to save
+	// consensus round records, they're not saved if its Type is empty.
+	RoundsDB dbconfig.DBConfiguration `yaml:"RoundsDB"`
+}
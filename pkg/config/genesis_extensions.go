@@ -2,10 +2,12 @@ package config
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 )
 
 // Genesis represents a set of genesis block settings including the extensions
@@ -19,6 +21,10 @@ type Genesis struct {
 	// genesis block. It is NeoGo extension and must be disabled on the public
 	// Neo N3 networks.
 	Transaction *GenesisTransaction
+	// Contracts contains the list of contracts that should be deployed during
+	// genesis block processing. It is NeoGo extension and must be disabled on
+	// the public Neo N3 networks.
+	Contracts []GenesisContract
 }
 
 // GenesisTransaction is a placeholder for script that should be included into genesis
@@ -30,11 +36,34 @@ type GenesisTransaction struct {
 	SystemFee int64
 }
 
+// GenesisContract describes a contract that should be deployed in the genesis
+// block. Contracts are deployed by the standby validators multisignature
+// account in the order they're specified in, after native contracts
+// initialization.
+type GenesisContract struct {
+	// NEF is a path to the contract's NEF file.
+	NEF string
+	// Manifest is a path to the contract's manifest file.
+	Manifest string
+	// Data is an optional parameter passed to the contract's _deploy method.
+	Data *smartcontract.Parameter
+	// Storage contains the set of contract's storage items that are put
+	// after the contract deployment.
+	Storage []GenesisStorageItem
+}
+
+// GenesisStorageItem is a key-value pair of contract storage.
+type GenesisStorageItem struct {
+	Key   []byte
+	Value []byte
+}
+
 type (
 	// genesisAux is an auxiliary structure for Genesis YAML marshalling.
 	genesisAux struct {
 		Roles       map[string]keys.PublicKeys `yaml:"Roles"`
 		Transaction *genesisTransactionAux     `yaml:"Transaction"`
+		Contracts   []genesisContractAux       `yaml:"Contracts,omitempty"`
 	}
 	// genesisTransactionAux is an auxiliary structure for GenesisTransaction YAML
 	// marshalling.
@@ -42,6 +71,21 @@ type (
 		Script    string `yaml:"Script"`
 		SystemFee int64  `yaml:"SystemFee"`
 	}
+	// genesisContractAux is an auxiliary structure for GenesisContract YAML
+	// marshalling. Data is represented in the same JSON-like format that is
+	// used for RPC invocation parameters.
+	genesisContractAux struct {
+		NEF      string                  `yaml:"NEF"`
+		Manifest string                  `yaml:"Manifest"`
+		Data     any                     `yaml:"Data,omitempty"`
+		Storage  []genesisStorageItemAux `yaml:"Storage,omitempty"`
+	}
+	// genesisStorageItemAux is an auxiliary structure for GenesisStorageItem
+	// YAML marshalling.
+	genesisStorageItemAux struct {
+		Key   string `yaml:"Key"`
+		Value string `yaml:"Value"`
+	}
 )
 
 // MarshalYAML implements the YAML marshaler interface.
@@ -57,6 +101,28 @@ func (e Genesis) MarshalYAML() (any, error) {
 			SystemFee: e.Transaction.SystemFee,
 		}
 	}
+	for i, c := range e.Contracts {
+		ca := genesisContractAux{
+			NEF:      c.NEF,
+			Manifest: c.Manifest,
+		}
+		if c.Data != nil {
+			b, err := json.Marshal(c.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal data of genesis contract #%d: %w", i, err)
+			}
+			if err := json.Unmarshal(b, &ca.Data); err != nil {
+				return nil, fmt.Errorf("failed to marshal data of genesis contract #%d: %w", i, err)
+			}
+		}
+		for _, si := range c.Storage {
+			ca.Storage = append(ca.Storage, genesisStorageItemAux{
+				Key:   base64.StdEncoding.EncodeToString(si.Key),
+				Value: base64.StdEncoding.EncodeToString(si.Value),
+			})
+		}
+		aux.Contracts = append(aux.Contracts, ca)
+	}
 	return aux, nil
 }
 
@@ -87,5 +153,34 @@ func (e *Genesis) UnmarshalYAML(unmarshal func(any) error) error {
 		}
 	}
 
+	for i, ca := range aux.Contracts {
+		c := GenesisContract{
+			NEF:      ca.NEF,
+			Manifest: ca.Manifest,
+		}
+		if ca.Data != nil {
+			b, err := json.Marshal(ca.Data)
+			if err != nil {
+				return fmt.Errorf("failed to decode data of genesis contract #%d: %w", i, err)
+			}
+			c.Data = new(smartcontract.Parameter)
+			if err := json.Unmarshal(b, c.Data); err != nil {
+				return fmt.Errorf("failed to decode data of genesis contract #%d: %w", i, err)
+			}
+		}
+		for j, sa := range ca.Storage {
+			k, err := base64.StdEncoding.DecodeString(sa.Key)
+			if err != nil {
+				return fmt.Errorf("failed to decode key of storage item #%d of genesis contract #%d: %w", j, i, err)
+			}
+			v, err := base64.StdEncoding.DecodeString(sa.Value)
+			if err != nil {
+				return fmt.Errorf("failed to decode value of storage item #%d of genesis contract #%d: %w", j, i, err)
+			}
+			c.Storage = append(c.Storage, GenesisStorageItem{Key: k, Value: v})
+		}
+		e.Contracts = append(e.Contracts, c)
+	}
+
 	return nil
 }
@@ -27,6 +27,12 @@ const (
 	// https://github.com/neo-project/neo/pull/2925) and #3362 (ported from
 	// https://github.com/neo-project/neo/pull/3154).
 	HFCockatrice // Cockatrice
+	// HFDomovoi represents hard-fork that introduces recoverSecp256K1,
+	// verifyWithEd25519, BN254 curve operations (bn254Serialize,
+	// bn254Deserialize, bn254Equal, bn254Add, bn254Mul and bn254Pairing),
+	// mimcBls12381 and poseidon2Bls12381 methods of native CryptoLib
+	// contract.
+	HFDomovoi // Domovoi
 	// hfLast denotes the end of hardforks enum. Consider adding new hardforks
 	// before hfLast.
 	hfLast
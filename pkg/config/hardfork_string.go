@@ -12,13 +12,15 @@ func _() {
 	_ = x[HFAspidochelone-1]
 	_ = x[HFBasilisk-2]
 	_ = x[HFCockatrice-4]
-	_ = x[hfLast-8]
+	_ = x[HFDomovoi-8]
+	_ = x[hfLast-16]
 }
 
 const (
 	_Hardfork_name_0 = "DefaultAspidocheloneBasilisk"
 	_Hardfork_name_1 = "Cockatrice"
-	_Hardfork_name_2 = "hfLast"
+	_Hardfork_name_2 = "Domovoi"
+	_Hardfork_name_3 = "hfLast"
 )
 
 var (
@@ -33,6 +35,8 @@ func (i Hardfork) String() string {
 		return _Hardfork_name_1
 	case i == 8:
 		return _Hardfork_name_2
+	case i == 16:
+		return _Hardfork_name_3
 	default:
 		return "Hardfork(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -1,12 +1,14 @@
 package config
 
+import "time"
+
 // Ledger contains core node-specific settings that are not
 // a part of the ProtocolConfiguration (which is common for every node on the
 // network).
 type Ledger struct {
 	// GarbageCollectionPeriod sets the number of blocks to wait before
 	// starting the next MPT garbage collection cycle when RemoveUntraceableBlocks
-	// option is used.
+	// or StateRetention option is used.
 	GarbageCollectionPeriod uint32 `yaml:"GarbageCollectionPeriod"`
 	// KeepOnlyLatestState specifies if MPT should only store the latest state.
 	// If true, DB size will be smaller, but older roots won't be accessible.
@@ -16,9 +18,17 @@ type Ledger struct {
 	RemoveUntraceableBlocks bool `yaml:"RemoveUntraceableBlocks"`
 	// SaveStorageBatch enables storage batch saving before every persist.
 	SaveStorageBatch bool `yaml:"SaveStorageBatch"`
+	// StateRetention is the number of the latest blocks (and hence state
+	// roots) MPT states are kept for, older ones are garbage-collected.
+	// Zero value means all states are kept (unless RemoveUntraceableBlocks
+	// is used). Enabling or disabling it requires DB resynchronization.
+	StateRetention uint32 `yaml:"StateRetention"`
 	// SkipBlockVerification allows to disable verification of received
 	// blocks (including cryptographic checks).
 	SkipBlockVerification bool `yaml:"SkipBlockVerification"`
+	// StorageStatsInterval sets the period of DB statistics collection for
+	// Prometheus metrics, zero value disables it.
+	StorageStatsInterval time.Duration `yaml:"StorageStatsInterval"`
 }
 
 // Blockchain is a set of settings for core.Blockchain to use, it includes protocol
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/fixedn"
 )
 
@@ -47,6 +48,10 @@ type (
 		P2PSigExtensions bool `yaml:"P2PSigExtensions"`
 		// P2PStateExchangeExtensions enables additional P2P MPT state data exchange logic.
 		P2PStateExchangeExtensions bool `yaml:"P2PStateExchangeExtensions"`
+		// PermissionedDeployment restricts contract deployment, update and
+		// destruction to the committee, designated ContractDeployer nodes and
+		// allowed deployer accounts managed by the native Management contract.
+		PermissionedDeployment bool `yaml:"PermissionedDeployment"`
 		// ReservedAttributes allows to have reserved attributes range for experimental or private purposes.
 		ReservedAttributes bool `yaml:"ReservedAttributes"`
 
@@ -113,6 +118,14 @@ func (p *ProtocolConfiguration) Validate() error {
 	if len(p.StandbyCommittee) == 0 {
 		return errors.New("configuration should include StandbyCommittee")
 	}
+	for i, c := range p.Genesis.Contracts {
+		if len(c.NEF) == 0 || len(c.Manifest) == 0 {
+			return fmt.Errorf("genesis contract #%d should have both NEF and Manifest paths specified", i)
+		}
+	}
+	if _, ok := p.Genesis.Roles[noderoles.ContractDeployer]; ok && !p.PermissionedDeployment {
+		return fmt.Errorf("%s role can't be designated with PermissionedDeployment disabled", noderoles.ContractDeployer)
+	}
 	if len(p.StandbyCommittee) < int(p.ValidatorsCount) {
 		return errors.New("validators count can't exceed the size of StandbyCommittee")
 	}
@@ -225,6 +238,7 @@ func (p *ProtocolConfiguration) Equals(o *ProtocolConfiguration) bool {
 		p.P2PNotaryRequestPayloadPoolSize != o.P2PNotaryRequestPayloadPoolSize ||
 		p.P2PSigExtensions != o.P2PSigExtensions ||
 		p.P2PStateExchangeExtensions != o.P2PStateExchangeExtensions ||
+		p.PermissionedDeployment != o.PermissionedDeployment ||
 		p.ReservedAttributes != o.ReservedAttributes ||
 		p.StateRootInHeader != o.StateRootInHeader ||
 		p.StateSyncInterval != o.StateSyncInterval ||
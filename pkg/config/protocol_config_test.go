@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/base64"
 	"fmt"
+	"math/big"
 	"path/filepath"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/internal/testserdes"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -148,6 +150,28 @@ func TestProtocolConfigurationValidation(t *testing.T) {
 	err = p.Validate()
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "configuration should either have one of ValidatorsCount or ValidatorsHistory, not both")
+	p = &ProtocolConfiguration{
+		StandbyCommittee: []string{"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"},
+		ValidatorsCount:  1,
+		Genesis: Genesis{
+			Contracts: []GenesisContract{{NEF: "contract.nef"}},
+		},
+	}
+	err = p.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "genesis contract #0 should have both NEF and Manifest paths specified")
+	p = &ProtocolConfiguration{
+		StandbyCommittee: []string{"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"},
+		ValidatorsCount:  1,
+		Genesis: Genesis{
+			Roles: map[noderoles.Role]keys.PublicKeys{noderoles.ContractDeployer: {}},
+		},
+	}
+	err = p.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ContractDeployer role can't be designated with PermissionedDeployment disabled")
+	p.PermissionedDeployment = true
+	require.NoError(t, p.Validate())
 }
 
 func TestProtocolConfigurationValidation_Hardforks(t *testing.T) {
@@ -313,6 +337,26 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 				Script:    []byte{1, 2, 3, 4},
 				SystemFee: 123,
 			},
+			Contracts: []GenesisContract{
+				{
+					NEF:      "contract.nef",
+					Manifest: "contract.manifest.json",
+					Data: &smartcontract.Parameter{
+						Type: smartcontract.ArrayType,
+						Value: []smartcontract.Parameter{
+							{Type: smartcontract.IntegerType, Value: big.NewInt(42)},
+							{Type: smartcontract.StringType, Value: "str"},
+						},
+					},
+					Storage: []GenesisStorageItem{
+						{Key: []byte{1}, Value: []byte{2, 3}},
+					},
+				},
+				{
+					NEF:      "another.nef",
+					Manifest: "another.manifest.json",
+				},
+			},
 		}
 		testserdes.MarshalUnmarshalYAML(t, g, new(Genesis))
 	})
@@ -352,6 +396,52 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 			require.Empty(t, cfg.ProtocolConfiguration.Genesis.Roles)
 		})
 
+		t.Run("contracts", func(t *testing.T) {
+			cfgYml := `ProtocolConfiguration:
+  Genesis:
+    Contracts:
+      - NEF: contract.nef
+        Manifest: contract.manifest.json
+        Data: {"type": "Integer", "value": 42}
+        Storage:
+          - Key: AQ==
+            Value: AgM=`
+			cfg := new(Config)
+			require.NoError(t, yaml.Unmarshal([]byte(cfgYml), cfg))
+			require.Equal(t, []GenesisContract{{
+				NEF:      "contract.nef",
+				Manifest: "contract.manifest.json",
+				Data:     &smartcontract.Parameter{Type: smartcontract.IntegerType, Value: big.NewInt(42)},
+				Storage:  []GenesisStorageItem{{Key: []byte{1}, Value: []byte{2, 3}}},
+			}}, cfg.ProtocolConfiguration.Genesis.Contracts)
+		})
+
+		t.Run("bad contract data", func(t *testing.T) {
+			cfgYml := `ProtocolConfiguration:
+  Genesis:
+    Contracts:
+      - NEF: contract.nef
+        Manifest: contract.manifest.json
+        Data: {"type": "Unknown"}`
+			cfg := new(Config)
+			require.Error(t, yaml.Unmarshal([]byte(cfgYml), cfg))
+		})
+
+		t.Run("bad storage key", func(t *testing.T) {
+			cfgYml := `ProtocolConfiguration:
+  Genesis:
+    Contracts:
+      - NEF: contract.nef
+        Manifest: contract.manifest.json
+        Storage:
+          - Key: "not a base64"
+            Value: AgM=`
+			cfg := new(Config)
+			err := yaml.Unmarshal([]byte(cfgYml), cfg)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "failed to decode key of storage item #0 of genesis contract #0")
+		})
+
 		t.Run("unknown role", func(t *testing.T) {
 			pubStr := pub.StringCompressed()
 			cfgYml := fmt.Sprintf(`ProtocolConfiguration:
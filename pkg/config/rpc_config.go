@@ -11,20 +11,22 @@ type (
 		EnableCORSWorkaround bool `yaml:"EnableCORSWorkaround"`
 		// MaxGasInvoke is the maximum amount of GAS which
 		// can be spent during an RPC call.
-		MaxGasInvoke              fixedn.Fixed8 `yaml:"MaxGasInvoke"`
-		MaxIteratorResultItems    int           `yaml:"MaxIteratorResultItems"`
-		MaxFindResultItems        int           `yaml:"MaxFindResultItems"`
-		MaxFindStorageResultItems int           `yaml:"MaxFindStoragePageSize"`
-		MaxNEP11Tokens            int           `yaml:"MaxNEP11Tokens"`
-		MaxRequestBodyBytes       int           `yaml:"MaxRequestBodyBytes"`
-		MaxRequestHeaderBytes     int           `yaml:"MaxRequestHeaderBytes"`
-		MaxWebSocketClients       int           `yaml:"MaxWebSocketClients"`
-		SessionEnabled            bool          `yaml:"SessionEnabled"`
-		SessionExpirationTime     int           `yaml:"SessionExpirationTime"`
-		SessionBackedByMPT        bool          `yaml:"SessionBackedByMPT"`
-		SessionPoolSize           int           `yaml:"SessionPoolSize"`
-		StartWhenSynchronized     bool          `yaml:"StartWhenSynchronized"`
-		TLSConfig                 TLS           `yaml:"TLSConfig"`
+		MaxGasInvoke                fixedn.Fixed8 `yaml:"MaxGasInvoke"`
+		MaxConsensusStatsBlocks     int           `yaml:"MaxConsensusStatsBlocks"`
+		MaxIteratorResultItems      int           `yaml:"MaxIteratorResultItems"`
+		MaxFindResultItems          int           `yaml:"MaxFindResultItems"`
+		MaxFindStorageResultItems   int           `yaml:"MaxFindStoragePageSize"`
+		MaxNEP11Tokens              int           `yaml:"MaxNEP11Tokens"`
+		MaxRequestBodyBytes         int           `yaml:"MaxRequestBodyBytes"`
+		MaxRequestHeaderBytes       int           `yaml:"MaxRequestHeaderBytes"`
+		MaxSubscriptionReplayBlocks int           `yaml:"MaxSubscriptionReplayBlocks"`
+		MaxWebSocketClients         int           `yaml:"MaxWebSocketClients"`
+		SessionEnabled              bool          `yaml:"SessionEnabled"`
+		SessionExpirationTime       int           `yaml:"SessionExpirationTime"`
+		SessionBackedByMPT          bool          `yaml:"SessionBackedByMPT"`
+		SessionPoolSize             int           `yaml:"SessionPoolSize"`
+		StartWhenSynchronized       bool          `yaml:"StartWhenSynchronized"`
+		TLSConfig                   TLS           `yaml:"TLSConfig"`
 	}
 
 	// TLS describes SSL/TLS configuration.
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// StateCheck contains configuration of the service comparing local state roots
+// with the ones of reference RPC nodes.
+type StateCheck struct {
+	Enabled bool `yaml:"Enabled"`
+	// Nodes is a list of reference RPC node addresses.
+	Nodes []string `yaml:"Nodes"`
+	// Interval is the time between checks.
+	Interval time.Duration `yaml:"Interval"`
+	// RequestTimeout is the timeout for a single RPC request.
+	RequestTimeout time.Duration `yaml:"RequestTimeout"`
+	// MaxItems is the maximum number of differing storage items reported.
+	MaxItems int `yaml:"MaxItems"`
+	// DumpPath is the directory divergence reports are written to, they're
+	// only logged if it's empty.
+	DumpPath string `yaml:"DumpPath"`
+}
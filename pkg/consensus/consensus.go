@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mempool"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
@@ -78,6 +80,13 @@ type Service interface {
 	OnPayload(p *npayload.Extensible) error
 	// OnTransaction is a callback to notify the Service about a newly received transaction.
 	OnTransaction(tx *transaction.Transaction)
+	// GetState returns a snapshot of the current dBFT round state.
+	GetState() State
+	// ForEachRoundRecord iterates over persisted round records for the given
+	// range of block indexes (inclusive) in ascending order until f returns
+	// false. ErrRoundsNotPersisted is returned if the service doesn't persist
+	// round records.
+	ForEachRoundRecord(start, end uint32, f func(*RoundRecord) bool) error
 }
 
 type service struct {
@@ -97,11 +106,16 @@ type service struct {
 	blockEvents  chan *coreb.Block
 	lastProposal []util.Uint256
 	wallet       *wallet.Wallet
+	// tracker keeps dBFT round state for monitoring purposes.
+	tracker *roundTracker
 	// started is a flag set with Start method that runs an event handling
 	// goroutine.
 	started  atomic.Bool
 	quit     chan struct{}
 	finished chan struct{}
+	// closeStore ensures Store is closed once irrespective of whether the
+	// service was started.
+	closeStore sync.Once
 	// lastTimestamp contains timestamp for the last processed block.
 	// We can't rely on timestamp from dbft context because it is changed
 	// before the block is accepted. So, in case of change view, it will contain
@@ -133,6 +147,13 @@ type Config struct {
 	// Wallet is a local-node wallet configuration. If the path is empty, then
 	// no wallet will be initialized and the service will be in watch-only mode.
 	Wallet config.Wallet
+	// WatchOnly forces the service to be in watch-only mode, the wallet is
+	// not opened in this case.
+	WatchOnly bool
+	// Store is used to persist round records (see RoundRecord). If nil, round
+	// records are not persisted. It must be a separate Store not used by the
+	// chain, the service takes ownership of it and closes it on Shutdown.
+	Store storage.Store
 }
 
 // NewService returns a new consensus.Service instance.
@@ -154,13 +175,14 @@ func NewService(cfg Config) (Service, error) {
 
 		transactions: make(chan *transaction.Transaction, 100),
 		blockEvents:  make(chan *coreb.Block, 1),
+		tracker:      newRoundTracker(cfg.WatchOnly || len(cfg.Wallet.Path) == 0),
 		quit:         make(chan struct{}),
 		finished:     make(chan struct{}),
 	}
 
 	var err error
 
-	if len(cfg.Wallet.Path) > 0 {
+	if len(cfg.Wallet.Path) > 0 && !cfg.WatchOnly {
 		if srv.wallet, err = wallet.NewWalletFromFile(cfg.Wallet.Path); err != nil {
 			return nil, err
 		}
@@ -192,7 +214,7 @@ func NewService(cfg Config) (Service, error) {
 		dbft.WithProcessBlock[util.Uint256](srv.processBlock),
 		dbft.WithVerifyBlock[util.Uint256](srv.verifyBlock),
 		dbft.WithGetBlock[util.Uint256](srv.getBlock),
-		dbft.WithWatchOnly[util.Uint256](func() bool { return false }),
+		dbft.WithWatchOnly[util.Uint256](func() bool { return cfg.WatchOnly }),
 		dbft.WithNewBlockFromContext[util.Uint256](srv.newBlockFromContext),
 		dbft.WithCurrentHeight[util.Uint256](cfg.Chain.BlockHeight),
 		dbft.WithCurrentBlockHash(cfg.Chain.CurrentBlockHash),
@@ -313,6 +335,7 @@ func (s *service) Start() {
 		b, _ := s.Chain.GetBlock(s.Chain.CurrentBlockHash()) // Can't fail, we have some current block!
 		s.lastTimestamp = b.Timestamp
 		s.dbft.Start(s.lastTimestamp * nsInMs)
+		s.tracker.newRound(&s.dbft.Context)
 		go s.eventLoop()
 	}
 }
@@ -327,6 +350,13 @@ func (s *service) Shutdown() {
 			s.wallet.Close()
 		}
 	}
+	s.closeStore.Do(func() {
+		if s.Store != nil {
+			if err := s.Store.Close(); err != nil {
+				s.log.Warn("failed to close round records store", zap.Error(err))
+			}
+		}
+	})
 	_ = s.log.Sync()
 }
 
@@ -351,7 +381,9 @@ events:
 			s.log.Debug("timer fired",
 				zap.Uint32("height", h),
 				zap.Uint("view", uint(v)))
+			requested := s.dbft.RequestSentOrReceived()
 			s.dbft.OnTimeout(h, v)
+			s.tracker.checkView(&s.dbft.Context, requested)
 		case msg := <-s.messages:
 			fields := []zap.Field{
 				zap.Uint8("from", msg.message.ValidatorIndex),
@@ -377,7 +409,10 @@ events:
 			}
 
 			s.log.Debug("received message", fields...)
+			s.tracker.onMessage(&msg)
+			requested := s.dbft.RequestSentOrReceived()
 			s.dbft.OnReceive(&msg)
+			s.tracker.checkView(&s.dbft.Context, requested)
 		case tx := <-s.transactions:
 			s.dbft.OnTransaction(tx)
 		case b := <-s.blockEvents:
@@ -421,7 +456,9 @@ func (s *service) handleChainBlock(b *coreb.Block) {
 			zap.Uint32("dbft index", s.dbft.BlockIndex),
 			zap.Uint32("chain index", s.Chain.BlockHeight()))
 		s.postBlock(b)
+		s.finishRound(b)
 		s.dbft.Reset(b.Timestamp * nsInMs)
+		s.tracker.newRound(&s.dbft.Context)
 	}
 }
 
@@ -498,10 +535,42 @@ func (s *service) OnTransaction(tx *transaction.Transaction) {
 	}
 }
 
+// GetState implements the Service interface.
+func (s *service) GetState() State {
+	return s.tracker.get()
+}
+
+// ForEachRoundRecord implements the Service interface.
+func (s *service) ForEachRoundRecord(start, end uint32, f func(*RoundRecord) bool) error {
+	if s.Store == nil {
+		return ErrRoundsNotPersisted
+	}
+	return forEachRoundRecord(s.Store, start, end, f)
+}
+
+// finishRound completes the current round record for the given block, updates
+// metrics and persists it if needed.
+func (s *service) finishRound(b *coreb.Block) {
+	rec := s.tracker.finishRound(b.Index, &s.dbft.Context)
+	if rec == nil {
+		return
+	}
+	updateRoundRecordMetrics(rec)
+	if s.Store == nil {
+		return
+	}
+	if err := putRoundRecord(s.Store, rec); err != nil {
+		s.log.Warn("failed to persist consensus round record",
+			zap.Uint32("index", rec.Index),
+			zap.Error(err))
+	}
+}
+
 func (s *service) broadcast(p dbft.ConsensusPayload[util.Uint256]) {
 	if err := p.(*Payload).Sign(s.dbft.Priv.(*privateKey)); err != nil {
 		s.log.Warn("can't sign consensus payload", zap.Error(err))
 	}
+	s.tracker.onSent(p.(*Payload))
 
 	ep := &p.(*Payload).Extensible
 	s.Config.Broadcast(ep)
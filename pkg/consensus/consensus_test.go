@@ -61,6 +61,40 @@ func TestNewWatchingService(t *testing.T) {
 	require.NotPanics(t, srv.Shutdown)
 }
 
+func TestNewWatchOnlyService(t *testing.T) {
+	bc := newTestChain(t, false)
+	srv, err := NewService(Config{
+		Logger:                zaptest.NewLogger(t),
+		Broadcast:             func(*npayload.Extensible) { t.Fatal("unexpected broadcast") },
+		Chain:                 bc,
+		BlockQueue:            testBlockQueuer{bc: bc},
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		RequestTx:             func(...util.Uint256) {},
+		StopTxFlow:            func() {},
+		TimePerBlock:          bc.GetConfig().TimePerBlock,
+		Wallet: config.Wallet{
+			Path:     "./testdata/wallet1.json",
+			Password: "one",
+		},
+		WatchOnly: true,
+	})
+	require.NoError(t, err)
+	require.Nil(t, srv.(*service).wallet)
+
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	st := srv.GetState()
+	require.True(t, st.WatchOnly)
+	require.Equal(t, bc.BlockHeight()+1, st.Height)
+	require.Equal(t, byte(0), st.View)
+	vals, err := bc.GetNextBlockValidators()
+	require.NoError(t, err)
+	require.Equal(t, keys.PublicKeys(vals), st.Validators)
+	require.NotNil(t, st.PrimaryKey)
+	require.Equal(t, st.Validators[st.Primary], st.PrimaryKey)
+}
+
 func collectBlock(t *testing.T, bc *core.Blockchain, srv *service) {
 	h := bc.BlockHeight()
 	srv.dbft.OnTimeout(srv.dbft.Context.BlockIndex, 0) // Collect and add block to the chain.
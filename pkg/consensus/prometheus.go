@@ -0,0 +1,158 @@
+package consensus
+
+import (
+	"github.com/nspcc-dev/dbft"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring service.
+var (
+	// roundHeight prometheus metric.
+	roundHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Index of the block being agreed upon by dBFT",
+			Name:      "consensus_height",
+			Namespace: "neogo",
+		},
+	)
+	// roundView prometheus metric.
+	roundView = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Current dBFT view number",
+			Name:      "consensus_view",
+			Namespace: "neogo",
+		},
+	)
+	// viewChanges prometheus metric.
+	viewChanges = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of dBFT view changes",
+			Name:      "consensus_view_changes_total",
+			Namespace: "neogo",
+		},
+	)
+	// primaryTimeouts prometheus metric.
+	primaryTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of dBFT views changed without PrepareRequest from primary",
+			Name:      "consensus_primary_timeouts_total",
+			Namespace: "neogo",
+		},
+	)
+	// changeViewReasons prometheus metric.
+	changeViewReasons = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of received ChangeView messages per reason",
+			Name:      "consensus_change_view_messages_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
+	// validatorCommits prometheus metric.
+	validatorCommits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of accepted blocks committed by validator",
+			Name:      "consensus_validator_commits_total",
+			Namespace: "neogo",
+		},
+		[]string{"validator"},
+	)
+	// validatorMissedCommits prometheus metric.
+	validatorMissedCommits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of accepted blocks not committed by validator",
+			Name:      "consensus_validator_missed_commits_total",
+			Namespace: "neogo",
+		},
+		[]string{"validator"},
+	)
+	// validatorPrimaryFailures prometheus metric.
+	validatorPrimaryFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of views changed while validator was primary",
+			Name:      "consensus_validator_primary_failures_total",
+			Namespace: "neogo",
+		},
+		[]string{"validator"},
+	)
+	// roundPhases prometheus metric.
+	roundPhases = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Help:      "Time from consensus round start to the phase completion",
+			Name:      "consensus_round_phase_seconds",
+			Namespace: "neogo",
+		},
+		[]string{"phase"},
+	)
+	// recoveryMessages prometheus metric.
+	recoveryMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of received recovery messages per type",
+			Name:      "consensus_recovery_messages_total",
+			Namespace: "neogo",
+		},
+		[]string{"type"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		roundHeight,
+		roundView,
+		viewChanges,
+		primaryTimeouts,
+		changeViewReasons,
+		recoveryMessages,
+		validatorCommits,
+		validatorMissedCommits,
+		validatorPrimaryFailures,
+		roundPhases,
+	)
+}
+
+func updateRoundMetrics(height uint32, view byte) {
+	roundHeight.Set(float64(height))
+	roundView.Set(float64(view))
+}
+
+func addViewChangeMetric() {
+	viewChanges.Inc()
+}
+
+func addPrimaryTimeoutMetric() {
+	primaryTimeouts.Inc()
+}
+
+func addChangeViewMetric(reason dbft.ChangeViewReason) {
+	changeViewReasons.WithLabelValues(reason.String()).Inc()
+}
+
+func addRecoveryMetric(typ dbft.MessageType) {
+	recoveryMessages.WithLabelValues(typ.String()).Inc()
+}
+
+func updateRoundRecordMetrics(r *RoundRecord) {
+	for i := range r.Validators {
+		key := r.Validators[i].Key.StringCompressed()
+		if r.Validators[i].Committed {
+			validatorCommits.WithLabelValues(key).Inc()
+		} else {
+			validatorMissedCommits.WithLabelValues(key).Inc()
+		}
+	}
+	for v := byte(0); v < r.View; v++ {
+		if p := r.PrimaryOf(v); p < len(r.Validators) {
+			validatorPrimaryFailures.WithLabelValues(r.Validators[p].Key.StringCompressed()).Inc()
+		}
+	}
+	if t, ok := r.PrepareRequestAt(); ok {
+		roundPhases.WithLabelValues("prepare_request").Observe(float64(t) / 1000)
+	}
+	if t, ok := r.PreparedAt(); ok {
+		roundPhases.WithLabelValues("prepared").Observe(float64(t) / 1000)
+	}
+	if t, ok := r.CommittedAt(); ok {
+		roundPhases.WithLabelValues("committed").Observe(float64(t) / 1000)
+	}
+	roundPhases.WithLabelValues("accepted").Observe(float64(r.Duration) / 1000)
+}
@@ -0,0 +1,229 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+)
+
+// maxRoundRecordItems is the maximum number of validators or ChangeView
+// messages stored in a single round record.
+const maxRoundRecordItems = 1024
+
+// ErrRoundsNotPersisted is returned when round records are requested from the
+// service that doesn't persist them.
+var ErrRoundsNotPersisted = errors.New("consensus round records are not persisted")
+
+// roundRecordPrefix is the key prefix used for round records in the
+// service's Store.
+const roundRecordPrefix = 0x01
+
+// RoundRecord contains metadata of a finished consensus round, that is the
+// round which ended with the block accepted by the chain.
+type RoundRecord struct {
+	// Index is the block index.
+	Index uint32
+	// View is the view number the block was accepted at.
+	View byte
+	// Primary is the index of the primary validator of the final view.
+	Primary byte
+	// Started is the round start time (Unix milliseconds).
+	Started uint64
+	// Duration is the time from the round start to the block acceptance
+	// in milliseconds.
+	Duration uint32
+	// Validators contains activity data for every validator of the round
+	// in the order of their indexes.
+	Validators []ValidatorActivity
+	// ChangeViews contains all ChangeView messages seen during the round.
+	ChangeViews []ChangeViewRecord
+}
+
+// ValidatorActivity describes messages seen from a validator during the round.
+// Times are in milliseconds relative to the round start.
+type ValidatorActivity struct {
+	Key *keys.PublicKey
+	// Prepared is true if PrepareRequest (for the primary) or PrepareResponse
+	// (for backups) of the final view was seen.
+	Prepared   bool
+	PreparedAt uint32
+	// Committed is true if Commit of the final view was seen.
+	Committed   bool
+	CommittedAt uint32
+}
+
+// ChangeViewRecord describes ChangeView message seen during the round.
+type ChangeViewRecord struct {
+	Validator byte
+	NewView   byte
+	Reason    dbft.ChangeViewReason
+	// At is the time in milliseconds relative to the round start.
+	At uint32
+}
+
+// M returns the number of validators that must function correctly in the round.
+func (r *RoundRecord) M() int {
+	return len(r.Validators) - (len(r.Validators)-1)/3
+}
+
+// PrimaryOf returns the index of the primary validator for the given view
+// of the round.
+func (r *RoundRecord) PrimaryOf(view byte) int {
+	n := len(r.Validators)
+	if n == 0 {
+		return 0
+	}
+	p := (int(r.Index) - int(view)) % n
+	if p < 0 {
+		p += n
+	}
+	return p
+}
+
+// PrepareRequestAt returns the time PrepareRequest of the final view was seen
+// at and a flag denoting whether it was seen at all.
+func (r *RoundRecord) PrepareRequestAt() (uint32, bool) {
+	if int(r.Primary) >= len(r.Validators) || !r.Validators[r.Primary].Prepared {
+		return 0, false
+	}
+	return r.Validators[r.Primary].PreparedAt, true
+}
+
+// PreparedAt returns the time M preparations of the final view were collected
+// at and a flag denoting whether they were collected at all.
+func (r *RoundRecord) PreparedAt() (uint32, bool) {
+	var times []uint32
+	for i := range r.Validators {
+		if r.Validators[i].Prepared {
+			times = append(times, r.Validators[i].PreparedAt)
+		}
+	}
+	return r.quorumTime(times)
+}
+
+// CommittedAt returns the time M commits of the final view were collected at
+// and a flag denoting whether they were collected at all.
+func (r *RoundRecord) CommittedAt() (uint32, bool) {
+	var times []uint32
+	for i := range r.Validators {
+		if r.Validators[i].Committed {
+			times = append(times, r.Validators[i].CommittedAt)
+		}
+	}
+	return r.quorumTime(times)
+}
+
+func (r *RoundRecord) quorumTime(times []uint32) (uint32, bool) {
+	m := r.M()
+	if m == 0 || len(times) < m {
+		return 0, false
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times[m-1], true
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (r *RoundRecord) EncodeBinary(w *io.BinWriter) {
+	w.WriteU32LE(r.Index)
+	w.WriteB(r.View)
+	w.WriteB(r.Primary)
+	w.WriteU64LE(r.Started)
+	w.WriteU32LE(r.Duration)
+	w.WriteArray(r.Validators)
+	w.WriteArray(r.ChangeViews)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (r *RoundRecord) DecodeBinary(br *io.BinReader) {
+	r.Index = br.ReadU32LE()
+	r.View = br.ReadB()
+	r.Primary = br.ReadB()
+	r.Started = br.ReadU64LE()
+	r.Duration = br.ReadU32LE()
+	br.ReadArray(&r.Validators, maxRoundRecordItems)
+	br.ReadArray(&r.ChangeViews, maxRoundRecordItems)
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (v *ValidatorActivity) EncodeBinary(w *io.BinWriter) {
+	v.Key.EncodeBinary(w)
+	w.WriteBool(v.Prepared)
+	w.WriteU32LE(v.PreparedAt)
+	w.WriteBool(v.Committed)
+	w.WriteU32LE(v.CommittedAt)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (v *ValidatorActivity) DecodeBinary(r *io.BinReader) {
+	v.Key = new(keys.PublicKey)
+	v.Key.DecodeBinary(r)
+	v.Prepared = r.ReadBool()
+	v.PreparedAt = r.ReadU32LE()
+	v.Committed = r.ReadBool()
+	v.CommittedAt = r.ReadU32LE()
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (c *ChangeViewRecord) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(c.Validator)
+	w.WriteB(c.NewView)
+	w.WriteB(byte(c.Reason))
+	w.WriteU32LE(c.At)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (c *ChangeViewRecord) DecodeBinary(r *io.BinReader) {
+	c.Validator = r.ReadB()
+	c.NewView = r.ReadB()
+	c.Reason = dbft.ChangeViewReason(r.ReadB())
+	c.At = r.ReadU32LE()
+}
+
+func makeRoundKey(index uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = roundRecordPrefix
+	binary.BigEndian.PutUint32(key[1:], index)
+	return key
+}
+
+// putRoundRecord stores the round record into the given Store. It's called
+// from the consensus goroutine only, the Store is owned by the service and
+// must not be shared with the chain (see Config.Store).
+func putRoundRecord(s storage.Store, r *RoundRecord) error {
+	w := io.NewBufBinWriter()
+	r.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	return s.PutChangeSet(map[string][]byte{string(makeRoundKey(r.Index)): w.Bytes()}, nil)
+}
+
+// forEachRoundRecord iterates over round records stored in the given Store for
+// the [start, end] range of block indexes in ascending order until f returns
+// false.
+func forEachRoundRecord(s storage.Store, start, end uint32, f func(*RoundRecord) bool) error {
+	var err error
+	s.Seek(storage.SeekRange{
+		Prefix: []byte{roundRecordPrefix},
+		Start:  makeRoundKey(start)[1:],
+	}, func(k, v []byte) bool {
+		if len(k) != 5 || binary.BigEndian.Uint32(k[1:]) > end {
+			return false
+		}
+		r := new(RoundRecord)
+		br := io.NewBinReaderFromBuf(v)
+		r.DecodeBinary(br)
+		if br.Err != nil {
+			err = fmt.Errorf("failed to decode round record %d: %w", binary.BigEndian.Uint32(k[1:]), br.Err)
+			return false
+		}
+		return f(r)
+	})
+	return err
+}
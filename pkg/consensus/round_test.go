@@ -0,0 +1,132 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/epicchainlabs/epicchain-go/internal/testserdes"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestRoundRecord(index uint32) *RoundRecord {
+	r := &RoundRecord{
+		Index:    index,
+		View:     1,
+		Started:  1700000000000,
+		Duration: 15000,
+		ChangeViews: []ChangeViewRecord{
+			{Validator: 0, NewView: 1, Reason: dbft.CVTimeout, At: 10000},
+			{Validator: 3, NewView: 1, Reason: dbft.CVChangeAgreement, At: 10005},
+		},
+	}
+	for i := 0; i < 4; i++ {
+		_, pub := getTestValidator(i)
+		r.Validators = append(r.Validators, ValidatorActivity{Key: pub.PublicKey})
+	}
+	r.Primary = byte(r.PrimaryOf(r.View))
+	return r
+}
+
+func TestRoundRecord_Serializable(t *testing.T) {
+	r := getTestRoundRecord(10)
+	r.Validators[1] = ValidatorActivity{
+		Key:         r.Validators[1].Key,
+		Prepared:    true,
+		PreparedAt:  11000,
+		Committed:   true,
+		CommittedAt: 12000,
+	}
+	testserdes.EncodeDecodeBinary(t, r, new(RoundRecord))
+}
+
+func TestRoundRecord_Phases(t *testing.T) {
+	r := getTestRoundRecord(10)
+	require.Equal(t, 3, r.M())
+	require.Equal(t, 2, r.PrimaryOf(0))
+	require.Equal(t, 1, r.PrimaryOf(1))
+	require.Equal(t, 3, r.PrimaryOf(3))
+	require.Equal(t, byte(1), r.Primary)
+
+	_, ok := r.PrepareRequestAt()
+	require.False(t, ok)
+	_, ok = r.PreparedAt()
+	require.False(t, ok)
+	_, ok = r.CommittedAt()
+	require.False(t, ok)
+
+	for i, at := range []uint32{300, 100, 200} {
+		r.Validators[i].Prepared = true
+		r.Validators[i].PreparedAt = at
+		r.Validators[i].Committed = true
+		r.Validators[i].CommittedAt = at * 2
+	}
+	at, ok := r.PrepareRequestAt()
+	require.True(t, ok)
+	require.Equal(t, uint32(100), at)
+	at, ok = r.PreparedAt()
+	require.True(t, ok)
+	require.Equal(t, uint32(300), at)
+	at, ok = r.CommittedAt()
+	require.True(t, ok)
+	require.Equal(t, uint32(600), at)
+}
+
+func TestRoundRecord_Storage(t *testing.T) {
+	s := storage.NewMemoryStore()
+	for i := uint32(1); i <= 5; i++ {
+		require.NoError(t, putRoundRecord(s, getTestRoundRecord(i)))
+	}
+
+	var indexes []uint32
+	require.NoError(t, forEachRoundRecord(s, 2, 4, func(r *RoundRecord) bool {
+		require.Equal(t, getTestRoundRecord(r.Index), r)
+		indexes = append(indexes, r.Index)
+		return true
+	}))
+	require.Equal(t, []uint32{2, 3, 4}, indexes)
+
+	indexes = indexes[:0]
+	require.NoError(t, forEachRoundRecord(s, 0, 10, func(r *RoundRecord) bool {
+		indexes = append(indexes, r.Index)
+		return len(indexes) < 2
+	}))
+	require.Equal(t, []uint32{1, 2}, indexes)
+
+	require.NoError(t, s.PutChangeSet(map[string][]byte{string(makeRoundKey(6)): {1, 2, 3}}, nil))
+	require.Error(t, forEachRoundRecord(s, 6, 6, func(*RoundRecord) bool { return true }))
+}
+
+type closeCountingStore struct {
+	storage.Store
+	closed int
+}
+
+func (s *closeCountingStore) Close() error {
+	s.closed++
+	return s.Store.Close()
+}
+
+func TestService_RoundRecordsStore(t *testing.T) {
+	srv := newTestService(t)
+	require.ErrorIs(t, srv.ForEachRoundRecord(0, 10, func(*RoundRecord) bool { return true }), ErrRoundsNotPersisted)
+
+	s := &closeCountingStore{Store: storage.NewMemoryStore()}
+	require.NoError(t, putRoundRecord(s, getTestRoundRecord(1)))
+	cfg := srv.Config
+	cfg.Store = s
+	sr, err := NewService(cfg)
+	require.NoError(t, err)
+
+	var indexes []uint32
+	require.NoError(t, sr.ForEachRoundRecord(0, 10, func(r *RoundRecord) bool {
+		indexes = append(indexes, r.Index)
+		return true
+	}))
+	require.Equal(t, []uint32{1}, indexes)
+
+	// The store is owned by the service and closed even if it wasn't started.
+	sr.Shutdown()
+	sr.Shutdown()
+	require.Equal(t, 1, s.closed)
+}
@@ -0,0 +1,230 @@
+package consensus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// State is a snapshot of dBFT round state as observed by the consensus service.
+// It's available for both active and watch-only services.
+type State struct {
+	// WatchOnly is true if the service doesn't take an active part in consensus.
+	WatchOnly bool
+	// Height is the index of the block being agreed upon.
+	Height uint32
+	// View is the current view number.
+	View byte
+	// Primary is the index of the primary validator for the current view.
+	Primary uint
+	// PrimaryKey is the public key of the primary validator for the current view.
+	PrimaryKey *keys.PublicKey
+	// Validators is the list of validators for the current round.
+	Validators keys.PublicKeys
+	// RoundStarted is the time when the current round (height) started.
+	RoundStarted time.Time
+	// ViewStarted is the time when the current view started.
+	ViewStarted time.Time
+	// ViewChanges is the number of view changes observed since the service start.
+	ViewChanges uint64
+	// ChangeViewReasons contains the number of received ChangeView messages
+	// per reason since the service start.
+	ChangeViewReasons map[dbft.ChangeViewReason]uint64
+	// PrimaryTimeouts is the number of views that were changed without
+	// receiving a PrepareRequest from the primary.
+	PrimaryTimeouts uint64
+	// RecoveryRequests is the number of received RecoveryRequest messages.
+	RecoveryRequests uint64
+	// RecoveryMessages is the number of received RecoveryMessage messages.
+	RecoveryMessages uint64
+}
+
+// roundTracker accumulates dBFT round state. It's updated from the service
+// event loop and can be read concurrently.
+type roundTracker struct {
+	lock  sync.RWMutex
+	state State
+
+	// round is the record of the current round, it's nil until the first
+	// round is started.
+	round *RoundRecord
+	// prepViews and commitViews contain view numbers of the latest
+	// preparation and commit seen from every validator of the current round.
+	prepViews   []byte
+	commitViews []byte
+}
+
+func newRoundTracker(watchOnly bool) *roundTracker {
+	return &roundTracker{
+		state: State{
+			WatchOnly:         watchOnly,
+			ChangeViewReasons: make(map[dbft.ChangeViewReason]uint64),
+		},
+	}
+}
+
+// newRound records the start of a new consensus round (height).
+func (r *roundTracker) newRound(c *dbft.Context[util.Uint256]) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	r.state.WatchOnly = c.WatchOnly()
+	r.state.Height = c.BlockIndex
+	r.state.RoundStarted = now
+	r.setView(c, now)
+	updateRoundMetrics(r.state.Height, r.state.View)
+
+	r.round = &RoundRecord{
+		Index:      c.BlockIndex,
+		Started:    uint64(now.UnixMilli()),
+		Validators: make([]ValidatorActivity, len(r.state.Validators)),
+	}
+	for i := range r.state.Validators {
+		r.round.Validators[i].Key = r.state.Validators[i]
+	}
+	r.prepViews = make([]byte, len(r.state.Validators))
+	r.commitViews = make([]byte, len(r.state.Validators))
+}
+
+// finishRound completes the record of the current round if it's the one for
+// the block with the given index. The view and primary are taken from the
+// context. Nil is returned if there is no matching round.
+func (r *roundTracker) finishRound(index uint32, c *dbft.Context[util.Uint256]) *RoundRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rec := r.round
+	if rec == nil || rec.Index != index {
+		return nil
+	}
+	r.round = nil
+	rec.View = c.ViewNumber
+	rec.Primary = byte(c.PrimaryIndex)
+	rec.Duration = uint32(time.Since(r.state.RoundStarted).Milliseconds())
+	for i := range rec.Validators {
+		v := &rec.Validators[i]
+		v.Prepared = v.Prepared && r.prepViews[i] == rec.View
+		v.Committed = v.Committed && r.commitViews[i] == rec.View
+		if !v.Prepared {
+			v.PreparedAt = 0
+		}
+		if !v.Committed {
+			v.CommittedAt = 0
+		}
+	}
+	return rec
+}
+
+// checkView records a view change if the context has moved to a new view.
+// requestReceived denotes whether PrepareRequest was received (or sent)
+// before the view change.
+func (r *roundTracker) checkView(c *dbft.Context[util.Uint256], requestReceived bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if c.BlockIndex != r.state.Height || c.ViewNumber <= r.state.View {
+		return
+	}
+	r.state.ViewChanges++
+	addViewChangeMetric()
+	if !requestReceived {
+		r.state.PrimaryTimeouts++
+		addPrimaryTimeoutMetric()
+	}
+	r.setView(c, time.Now())
+	updateRoundMetrics(r.state.Height, r.state.View)
+}
+
+// setView updates view-related state, it must be called with the lock held.
+func (r *roundTracker) setView(c *dbft.Context[util.Uint256], now time.Time) {
+	r.state.View = c.ViewNumber
+	r.state.ViewStarted = now
+	r.state.Primary = c.PrimaryIndex
+	r.state.Validators = convertKeys(c.Validators)
+	r.state.PrimaryKey = nil
+	if int(c.PrimaryIndex) < len(r.state.Validators) {
+		r.state.PrimaryKey = r.state.Validators[c.PrimaryIndex]
+	}
+}
+
+// onSent records the consensus message sent by the node itself.
+func (r *roundTracker) onSent(p *Payload) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.recordMessage(p)
+}
+
+// onMessage records the received consensus message.
+func (r *roundTracker) onMessage(p *Payload) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.recordMessage(p)
+	switch p.Type() {
+	case dbft.ChangeViewType:
+		reason := p.GetChangeView().Reason()
+		r.state.ChangeViewReasons[reason]++
+		addChangeViewMetric(reason)
+	case dbft.RecoveryRequestType:
+		r.state.RecoveryRequests++
+		addRecoveryMetric(dbft.RecoveryRequestType)
+	case dbft.RecoveryMessageType:
+		r.state.RecoveryMessages++
+		addRecoveryMetric(dbft.RecoveryMessageType)
+	}
+}
+
+// recordMessage adds the message to the current round record, it must be
+// called with the lock held.
+func (r *roundTracker) recordMessage(p *Payload) {
+	rec := r.round
+	i := int(p.ValidatorIndex())
+	if rec == nil || p.Height() != rec.Index || i >= len(rec.Validators) {
+		return
+	}
+	at := uint32(time.Since(r.state.RoundStarted).Milliseconds())
+	v := &rec.Validators[i]
+	switch p.Type() {
+	case dbft.PrepareRequestType, dbft.PrepareResponseType:
+		if !v.Prepared || p.ViewNumber() > r.prepViews[i] {
+			v.Prepared = true
+			v.PreparedAt = at
+			r.prepViews[i] = p.ViewNumber()
+		}
+	case dbft.CommitType:
+		if !v.Committed || p.ViewNumber() > r.commitViews[i] {
+			v.Committed = true
+			v.CommittedAt = at
+			r.commitViews[i] = p.ViewNumber()
+		}
+	case dbft.ChangeViewType:
+		if len(rec.ChangeViews) < maxRoundRecordItems {
+			rec.ChangeViews = append(rec.ChangeViews, ChangeViewRecord{
+				Validator: byte(i),
+				NewView:   p.GetChangeView().NewViewNumber(),
+				Reason:    p.GetChangeView().Reason(),
+				At:        at,
+			})
+		}
+	}
+}
+
+// get returns a copy of the current state.
+func (r *roundTracker) get() State {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	res := r.state
+	res.Validators = make(keys.PublicKeys, len(r.state.Validators))
+	copy(res.Validators, r.state.Validators)
+	res.ChangeViewReasons = make(map[dbft.ChangeViewReason]uint64, len(r.state.ChangeViewReasons))
+	for k, v := range r.state.ChangeViewReasons {
+		res.ChangeViewReasons[k] = v
+	}
+	return res
+}
@@ -0,0 +1,147 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTracker(t *testing.T) {
+	c := &dbft.Context[util.Uint256]{
+		Config:       &dbft.Config[util.Uint256]{WatchOnly: func() bool { return true }},
+		BlockIndex:   10,
+		MyIndex:      -1,
+		PrimaryIndex: 2,
+	}
+	for i := 0; i < 4; i++ {
+		_, pub := getTestValidator(i)
+		c.Validators = append(c.Validators, pub)
+	}
+
+	r := newRoundTracker(true)
+	r.newRound(c)
+	st := r.get()
+	require.True(t, st.WatchOnly)
+	require.Equal(t, uint32(10), st.Height)
+	require.Equal(t, byte(0), st.View)
+	require.Equal(t, uint(2), st.Primary)
+	require.Equal(t, c.Validators[2].(*publicKey).PublicKey, st.PrimaryKey)
+
+	cv := new(Payload)
+	cv.message.Type = messageType(dbft.ChangeViewType)
+	cv.payload = &changeView{newViewNumber: 1, reason: dbft.CVTimeout}
+	r.onMessage(cv)
+	r.onMessage(cv)
+	rr := new(Payload)
+	rr.message.Type = messageType(dbft.RecoveryRequestType)
+	rr.payload = &recoveryRequest{}
+	r.onMessage(rr)
+	rm := new(Payload)
+	rm.message.Type = messageType(dbft.RecoveryMessageType)
+	rm.payload = &recoveryMessage{}
+	r.onMessage(rm)
+
+	// View change without PrepareRequest.
+	c.ViewNumber = 1
+	c.PrimaryIndex = 1
+	r.checkView(c, false)
+	// No change.
+	r.checkView(c, true)
+	// View change after PrepareRequest.
+	c.ViewNumber = 2
+	c.PrimaryIndex = 0
+	r.checkView(c, true)
+
+	st = r.get()
+	require.Equal(t, byte(2), st.View)
+	require.Equal(t, uint(0), st.Primary)
+	require.Equal(t, uint64(2), st.ViewChanges)
+	require.Equal(t, uint64(1), st.PrimaryTimeouts)
+	require.Equal(t, map[dbft.ChangeViewReason]uint64{dbft.CVTimeout: 2}, st.ChangeViewReasons)
+	require.Equal(t, uint64(1), st.RecoveryRequests)
+	require.Equal(t, uint64(1), st.RecoveryMessages)
+
+	// Returned state is a copy.
+	st.ChangeViewReasons[dbft.CVTxInvalid] = 1
+	require.Len(t, r.get().ChangeViewReasons, 1)
+
+	// New height resets view.
+	c.BlockIndex = 11
+	c.ViewNumber = 0
+	c.PrimaryIndex = 3
+	r.newRound(c)
+	st = r.get()
+	require.Equal(t, uint32(11), st.Height)
+	require.Equal(t, byte(0), st.View)
+	require.Equal(t, uint64(2), st.ViewChanges)
+}
+
+func TestRoundTracker_Record(t *testing.T) {
+	c := &dbft.Context[util.Uint256]{
+		Config:       &dbft.Config[util.Uint256]{WatchOnly: func() bool { return true }},
+		BlockIndex:   10,
+		MyIndex:      -1,
+		PrimaryIndex: 2,
+	}
+	for i := 0; i < 4; i++ {
+		_, pub := getTestValidator(i)
+		c.Validators = append(c.Validators, pub)
+	}
+	newPayload := func(typ dbft.MessageType, index uint32, validator, view byte, p any) *Payload {
+		res := new(Payload)
+		res.message.Type = messageType(typ)
+		res.message.BlockIndex = index
+		res.message.ValidatorIndex = validator
+		res.message.ViewNumber = view
+		if p != nil {
+			res.payload = p.(io.Serializable)
+		}
+		return res
+	}
+
+	r := newRoundTracker(true)
+	require.Nil(t, r.finishRound(10, c))
+	r.newRound(c)
+
+	// View 0: the primary sends PrepareRequest, validator 3 commits.
+	r.onMessage(newPayload(dbft.PrepareRequestType, 10, 2, 0, nil))
+	r.onMessage(newPayload(dbft.CommitType, 10, 3, 0, nil))
+	// Messages for other heights and unknown validators are ignored.
+	r.onMessage(newPayload(dbft.CommitType, 11, 0, 0, nil))
+	r.onMessage(newPayload(dbft.CommitType, 10, 7, 0, nil))
+	r.onMessage(newPayload(dbft.ChangeViewType, 10, 0, 0, &changeView{newViewNumber: 1, reason: dbft.CVTimeout}))
+
+	// View 1.
+	c.ViewNumber = 1
+	c.PrimaryIndex = 1
+	r.checkView(c, true)
+	r.onMessage(newPayload(dbft.PrepareRequestType, 10, 1, 1, nil))
+	r.onMessage(newPayload(dbft.PrepareResponseType, 10, 0, 1, nil))
+	r.onSent(newPayload(dbft.PrepareResponseType, 10, 2, 1, nil))
+	r.onMessage(newPayload(dbft.CommitType, 10, 0, 1, nil))
+	r.onMessage(newPayload(dbft.CommitType, 10, 1, 1, nil))
+	r.onMessage(newPayload(dbft.CommitType, 10, 2, 1, nil))
+
+	require.Nil(t, r.finishRound(11, c))
+	rec := r.finishRound(10, c)
+	require.NotNil(t, rec)
+	require.Equal(t, uint32(10), rec.Index)
+	require.Equal(t, byte(1), rec.View)
+	require.Equal(t, byte(1), rec.Primary)
+	require.Len(t, rec.Validators, 4)
+	for i, v := range rec.Validators {
+		require.Equal(t, c.Validators[i].(*publicKey).PublicKey, v.Key)
+		require.Equal(t, i != 3, v.Prepared, i)
+		// Validator 3 committed in view 0 only.
+		require.Equal(t, i != 3, v.Committed, i)
+	}
+	require.Equal(t, []ChangeViewRecord{{Validator: 0, NewView: 1, Reason: dbft.CVTimeout, At: rec.ChangeViews[0].At}}, rec.ChangeViews)
+	_, ok := rec.CommittedAt()
+	require.True(t, ok)
+
+	// Round can only be finished once.
+	require.Nil(t, r.finishRound(10, c))
+}
@@ -40,6 +40,7 @@ func BenchmarkBlockchain_ForEachNEP17Transfer(t *testing.B) {
 		},
 		"BoltPS":  newBoltStoreForTesting,
 		"LevelPS": newLevelDBForTesting,
+		"LSMPS":   newLSMStoreForTesting,
 	}
 	startFrom := []int{1, 100, 1000}
 	blocksToTake := []int{100, 1000}
@@ -63,6 +64,7 @@ func BenchmarkNEO_GetGASPerVote(t *testing.B) {
 		},
 		"BoltPS":  newBoltStoreForTesting,
 		"LevelPS": newLevelDBForTesting,
+		"LSMPS":   newLSMStoreForTesting,
 	}
 	for psName, newPS := range stores {
 		for nRewardRecords := 10; nRewardRecords <= 1000; nRewardRecords *= 10 {
@@ -149,6 +151,12 @@ func newBoltStoreForTesting(t testing.TB) storage.Store {
 	return boltDBStore
 }
 
+func newLSMStoreForTesting(t testing.TB) storage.Store {
+	lsmStore, err := storage.NewLSMStore(dbconfig.LSMOptions{DataDirectoryPath: t.TempDir()})
+	require.NoError(t, err)
+	return lsmStore
+}
+
 func benchmarkGasPerVote(t *testing.B, ps storage.Store, nRewardRecords int, rewardDistance int) {
 	bc, validators, committee := chain.NewMultiWithCustomConfigAndStore(t, nil, ps, true)
 	cfg := bc.GetConfig()
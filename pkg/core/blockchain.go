@@ -2,6 +2,8 @@ package core
 
 import (
 	"bytes"
+	"context"
+	"crypto/elliptic"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -35,9 +37,11 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/nef"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/vmstate"
 	"go.uber.org/zap"
@@ -145,6 +149,9 @@ type Blockchain struct {
 	// Underlying persistent store.
 	store storage.Store
 
+	// statsLock prevents concurrent DB statistics collections.
+	statsLock sync.Mutex
+
 	// Current index/height of the highest block.
 	// Read access should always be called by BlockHeight().
 	// Write access should only happen in storeBlock().
@@ -185,6 +192,13 @@ type Blockchain struct {
 
 	stateRoot *stateroot.Module
 
+	// tracer is a block execution tracer wrapped in an atomic.Value, it
+	// stores *Tracer.
+	tracer atomic.Value
+
+	// sigCache contains signature checks performed by PreverifyBlock.
+	sigCache *interop.SignatureCache
+
 	// Notification subsystem.
 	events  chan bcEvent
 	subCh   chan any
@@ -196,14 +210,90 @@ type StateRoot interface {
 	CurrentLocalHeight() uint32
 	CurrentLocalStateRoot() util.Uint256
 	CurrentValidatedHeight() uint32
+	DiffStates(oldRoot, newRoot util.Uint256, prefix, start []byte, f func(k, oldV, newV []byte) bool) error
 	FindStates(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, error)
 	SeekStates(root util.Uint256, prefix []byte, f func(k, v []byte) bool)
 	GetState(root util.Uint256, key []byte) ([]byte, error)
 	GetStateProof(root util.Uint256, key []byte) ([][]byte, error)
+	GetStateMultiProof(root util.Uint256, keys [][]byte) ([][]byte, error)
+	GetStateRangeProof(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, [][]byte, error)
 	GetStateRoot(height uint32) (*state.MPTRoot, error)
 	GetLatestStateHeight(root util.Uint256) (uint32, error)
 }
 
+// Tracer is a block execution tracer, see Blockchain.SetTracer. It gets
+// interop.Tracer events for every script executed during block processing
+// (OnPersist, transactions and PostPersist) along with their results.
+type Tracer interface {
+	interop.Tracer
+	// OnExecuted is called after the script execution with its result, it's
+	// called for faulted transactions as well.
+	OnExecuted(ic *interop.Context, aer *state.AppExecResult)
+}
+
+// Simulation is a set of transactions to be executed by Blockchain.Simulate on
+// top of some historic state along with state overrides applied before the
+// execution.
+type Simulation struct {
+	// Height is the height of the state to simulate the next block for.
+	Height uint32
+	// Timestamp is the simulated block timestamp, the expected next block
+	// timestamp is used if it's zero.
+	Timestamp uint64
+	// FullBlock enables OnPersist and PostPersist scripts execution around
+	// transactions (including network and system fee payments), so that the
+	// simulation works exactly the same way real block processing does.
+	FullBlock bool
+	// Transactions are executed in order, they're not checked in any way
+	// and their witnesses are not verified, system fee is used as a GAS limit.
+	Transactions []*transaction.Transaction
+	// Contracts replace NEF files and manifests of deployed contracts.
+	Contracts []SimulatedContract
+	// Storage items are put to (deleted from if the value is nil) contract
+	// storages after contract overrides are applied.
+	Storage []SimulatedStorageItem
+	// GASBalances are set for the specified accounts.
+	GASBalances map[util.Uint160]*big.Int
+}
+
+// SimulatedContract is a contract override for the simulation, NEF and
+// manifest are replaced only if they're not nil.
+type SimulatedContract struct {
+	Hash     util.Uint160
+	NEF      *nef.File
+	Manifest *manifest.Manifest
+}
+
+// SimulatedStorageItem is a contract storage item override for the simulation.
+type SimulatedStorageItem struct {
+	Hash  util.Uint160
+	Key   []byte
+	Value []byte
+}
+
+// SimulationResult is the result of Blockchain.Simulate.
+type SimulationResult struct {
+	// Block is the simulated block, only its header fields and transactions
+	// are filled.
+	Block *block.Block
+	// Executions contain OnPersist (for FullBlock simulations), transactions and
+	// PostPersist (for FullBlock simulations) execution results in order.
+	Executions []*state.AppExecResult
+	// Changes is a set of contract storage changes made by the simulated
+	// block (overrides are not included), it's sorted by contract ID and key.
+	Changes []SimulatedStorageChange
+}
+
+// SimulatedStorageChange is a single contract storage item change, OldValue is
+// nil for added items and NewValue is nil for deleted ones.
+type SimulatedStorageChange struct {
+	ID       int32
+	Hash     util.Uint160
+	Key      []byte
+	OldValue []byte
+	NewValue []byte
+}
+
 // bcEvent is an internal event generated by the Blockchain and then
 // broadcasted to other parties. It joins the new block and associated
 // invocation logs, all the other events visible from outside can be produced
@@ -213,6 +303,32 @@ type bcEvent struct {
 	appExecResults []*state.AppExecResult
 }
 
+// replayQueueSize is the maximum number of new events queued for the replaying
+// subscription, if it's exceeded the queue is dropped and these events are
+// read from the DB later.
+const replayQueueSize = 64
+
+// replaySubscription is a subscription that gets past events read from the
+// DB before the new ones. New events are queued by the notification
+// dispatcher (so that it's never blocked by replaying) and then sent by the
+// replaying routine once all past events are sent. The queue is limited by
+// replayQueueSize, events dropped from it are read from the DB the same way
+// past ones are.
+type replaySubscription struct {
+	// ch is a subscriber's channel, one of the types accepted by subCh.
+	ch any
+	// next is the index of the next block to send events for.
+	next uint32
+
+	lock    sync.Mutex
+	pending []bcEvent
+	closed  bool
+	newEv   chan struct{}
+
+	stop chan struct{}
+	done chan struct{}
+}
+
 // transferData is used for transfer caching during storeBlock.
 type transferData struct {
 	Info  state.TokenTransferInfo
@@ -302,7 +418,10 @@ func NewBlockchain(s storage.Store, cfg config.Blockchain, log *zap.Logger) (*Bl
 	}
 
 	// Local config consistency checks.
-	if cfg.Ledger.RemoveUntraceableBlocks && cfg.Ledger.GarbageCollectionPeriod == 0 {
+	if cfg.Ledger.StateRetention != 0 && cfg.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("StateRetention can't be used with KeepOnlyLatestState")
+	}
+	if (cfg.Ledger.RemoveUntraceableBlocks || cfg.Ledger.StateRetention != 0) && cfg.Ledger.GarbageCollectionPeriod == 0 {
 		cfg.Ledger.GarbageCollectionPeriod = defaultGCPeriod
 		log.Info("GarbageCollectionPeriod is not set or wrong, using default value", zap.Uint32("GarbageCollectionPeriod", cfg.Ledger.GarbageCollectionPeriod))
 	}
@@ -319,6 +438,7 @@ func NewBlockchain(s storage.Store, cfg config.Blockchain, log *zap.Logger) (*Bl
 		subCh:       make(chan any),
 		unsubCh:     make(chan any),
 		contracts:   *native.NewContracts(cfg.ProtocolConfiguration),
+		sigCache:    interop.NewSignatureCache(),
 	}
 
 	bc.stateRoot = stateroot.NewModule(cfg, bc.VerifyWitness, bc.log, bc.dao.Store)
@@ -404,6 +524,22 @@ func (bc *Blockchain) SetNotary(mod native.NotaryService) {
 	bc.contracts.Designate.NotaryService.Store(&mod)
 }
 
+// SetTracer sets block execution tracer. It can safely be called on the running
+// blockchain, the tracer is used starting from the next block processed. To
+// disable tracing use SetTracer(nil). Test invocations are not traced by this
+// tracer, use interop.Context.SetTracer for them.
+func (bc *Blockchain) SetTracer(t Tracer) {
+	bc.tracer.Store(&t)
+}
+
+func (bc *Blockchain) getTracer() Tracer {
+	t, _ := bc.tracer.Load().(*Tracer)
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
 func (bc *Blockchain) init() error {
 	// If we could not find the version in the Store, we know that there is nothing stored.
 	ver, err := bc.dao.GetVersion()
@@ -415,13 +551,18 @@ func (bc *Blockchain) init() error {
 			P2PSigExtensions:           bc.config.P2PSigExtensions,
 			P2PStateExchangeExtensions: bc.config.P2PStateExchangeExtensions,
 			KeepOnlyLatestState:        bc.config.Ledger.KeepOnlyLatestState,
+			StateRetention:             bc.config.Ledger.StateRetention != 0,
 			Magic:                      uint32(bc.config.Magic),
 			Value:                      version,
 		}
 		bc.dao.PutVersion(ver)
 		bc.dao.Version = ver
 		bc.persistent.Version = ver
-		genesisBlock, err := CreateGenesisBlock(bc.config.ProtocolConfiguration)
+		bc.contracts.Management.GenesisContracts, err = loadGenesisContracts(bc.config.Genesis)
+		if err != nil {
+			return fmt.Errorf("failed to load genesis contracts: %w", err)
+		}
+		genesisBlock, err := createGenesisBlock(bc.config.ProtocolConfiguration, bc.contracts.Management.GenesisContracts)
 		if err != nil {
 			return err
 		}
@@ -450,6 +591,10 @@ func (bc *Blockchain) init() error {
 		return fmt.Errorf("KeepOnlyLatestState setting mismatch (old=%v, new=%v)",
 			ver.KeepOnlyLatestState, bc.config.Ledger.KeepOnlyLatestState)
 	}
+	if ver.StateRetention != (bc.config.Ledger.StateRetention != 0) {
+		return fmt.Errorf("StateRetention setting mismatch (old=%v, new=%v)",
+			ver.StateRetention, bc.config.Ledger.StateRetention != 0)
+	}
 	if ver.Magic != uint32(bc.config.Magic) {
 		return fmt.Errorf("protocol configuration Magic mismatch (old=%v, new=%v)",
 			ver.Magic, bc.config.Magic)
@@ -714,6 +859,9 @@ func (bc *Blockchain) resetStateInternal(height uint32, stage stateChangeStage)
 		if bc.config.Ledger.RemoveUntraceableBlocks && currHeight >= bc.config.MaxTraceableBlocks {
 			return fmt.Errorf("RemoveUntraceableBlocks is enabled, a necessary batch of traceable blocks has already been removed")
 		}
+		if err := bc.checkStateRetention(height); err != nil {
+			return err
+		}
 	}
 
 	// Retrieve necessary state before the DB modification.
@@ -863,11 +1011,7 @@ func (bc *Blockchain) resetStateInternal(height uint32, stage stateChangeStage)
 		pStorageStart := p
 
 		p = time.Now()
-		var mode = mpt.ModeAll
-		if bc.config.Ledger.RemoveUntraceableBlocks {
-			mode |= mpt.ModeGCFlag
-		}
-		trieStore := mpt.NewTrieStore(sr.Root, mode, upperCache.Store)
+		trieStore := mpt.NewTrieStore(sr.Root, bc.historicTrieMode(), upperCache.Store)
 		oldStoragePrefix := v.StoragePrefix
 		newStoragePrefix := statesync.TemporaryPrefix(oldStoragePrefix)
 
@@ -1094,6 +1238,13 @@ func (bc *Blockchain) Run() {
 		close(bc.runToExitCh)
 	}()
 	go bc.notificationDispatcher()
+	if bc.config.Ledger.StorageStatsInterval > 0 {
+		statsDone := make(chan struct{})
+		go bc.storageStatsLoop(statsDone)
+		// Statistics collection should be finished before the DB is closed
+		// and deferred functions are executed in LIFO order.
+		defer func() { <-statsDone }()
+	}
 	var nextSync bool
 	for {
 		select {
@@ -1103,14 +1254,14 @@ func (bc *Blockchain) Run() {
 			var oldPersisted uint32
 			var gcDur time.Duration
 
-			if bc.config.Ledger.RemoveUntraceableBlocks {
+			if bc.config.Ledger.RemoveUntraceableBlocks || bc.config.Ledger.StateRetention != 0 {
 				oldPersisted = atomic.LoadUint32(&bc.persistedHeight)
 			}
 			dur, err := bc.persist(nextSync)
 			if err != nil {
 				bc.log.Warn("failed to persist blockchain", zap.Error(err))
 			}
-			if bc.config.Ledger.RemoveUntraceableBlocks {
+			if bc.config.Ledger.RemoveUntraceableBlocks || bc.config.Ledger.StateRetention != 0 {
 				gcDur = bc.tryRunGC(oldPersisted)
 			}
 			nextSync = dur > persistInterval*2
@@ -1123,15 +1274,61 @@ func (bc *Blockchain) Run() {
 	}
 }
 
+// storageStatsLoop periodically collects DB statistics to update metrics
+// until the Blockchain is stopped.
+func (bc *Blockchain) storageStatsLoop(done chan struct{}) {
+	defer close(done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-bc.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	ticker := time.NewTicker(bc.config.Ledger.StorageStatsInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := bc.GetStorageStats(ctx); err != nil && ctx.Err() == nil {
+			bc.log.Warn("failed to collect DB statistics", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (bc *Blockchain) tryRunGC(oldHeight uint32) time.Duration {
 	var dur time.Duration
 
 	newHeight := atomic.LoadUint32(&bc.persistedHeight)
-	var tgtBlock = int64(newHeight)
+	// Count periods.
+	if oldHeight/bc.config.Ledger.GarbageCollectionPeriod == newHeight/bc.config.Ledger.GarbageCollectionPeriod {
+		return dur
+	}
+	if tgtBlock, ok := bc.gcTarget(newHeight, bc.stateRetentionWindow()); ok {
+		dur = bc.stateRoot.GC(tgtBlock, bc.store)
+	}
+	if bc.config.Ledger.RemoveUntraceableBlocks {
+		if tgtBlock, ok := bc.gcTarget(newHeight, bc.config.MaxTraceableBlocks); ok {
+			dur += bc.removeOldTransfers(tgtBlock)
+		}
+	}
+	return dur
+}
+
+// gcTarget returns the height data below which can be removed by GC at the
+// given chain height if the data of the last window blocks is to be kept. It
+// returns false if there is nothing to remove yet.
+func (bc *Blockchain) gcTarget(height uint32, window uint32) (uint32, bool) {
+	var tgtBlock = int64(height)
 
-	tgtBlock -= int64(bc.config.MaxTraceableBlocks)
+	tgtBlock -= int64(window)
 	if bc.config.P2PStateExchangeExtensions {
-		syncP := newHeight / uint32(bc.config.StateSyncInterval)
+		syncP := height / uint32(bc.config.StateSyncInterval)
 		syncP--
 		syncP *= uint32(bc.config.StateSyncInterval)
 		if tgtBlock > int64(syncP) {
@@ -1141,16 +1338,40 @@ func (bc *Blockchain) tryRunGC(oldHeight uint32) time.Duration {
 	// Always round to the GCP.
 	tgtBlock /= int64(bc.config.Ledger.GarbageCollectionPeriod)
 	tgtBlock *= int64(bc.config.Ledger.GarbageCollectionPeriod)
-	// Count periods.
-	oldHeight /= bc.config.Ledger.GarbageCollectionPeriod
-	newHeight /= bc.config.Ledger.GarbageCollectionPeriod
-	if tgtBlock > int64(bc.config.Ledger.GarbageCollectionPeriod) && newHeight != oldHeight {
-		tgtBlock /= int64(bc.config.Ledger.GarbageCollectionPeriod)
-		tgtBlock *= int64(bc.config.Ledger.GarbageCollectionPeriod)
-		dur = bc.stateRoot.GC(uint32(tgtBlock), bc.store)
-		dur += bc.removeOldTransfers(uint32(tgtBlock))
+	return uint32(tgtBlock), tgtBlock > int64(bc.config.Ledger.GarbageCollectionPeriod)
+}
+
+// stateRetentionWindow returns the number of the latest blocks MPT states are
+// kept for if MPT garbage collection is enabled. It's StateRetention or
+// MaxTraceableBlocks for RemoveUntraceableBlocks, whichever is smaller.
+func (bc *Blockchain) stateRetentionWindow() uint32 {
+	var window = bc.config.Ledger.StateRetention
+	if bc.config.Ledger.RemoveUntraceableBlocks && (window == 0 || window > bc.config.MaxTraceableBlocks) {
+		window = bc.config.MaxTraceableBlocks
 	}
-	return dur
+	return window
+}
+
+// checkStateRetention returns an error if the MPT state for the given height is
+// no longer kept because of the StateRetention setting. The height must not
+// exceed the current chain height.
+func (bc *Blockchain) checkStateRetention(height uint32) error {
+	if r := bc.config.Ledger.StateRetention; r != 0 && bc.BlockHeight()-height >= r {
+		return fmt.Errorf("state for height %d is outside of StateRetention window (%d blocks) and removed from the storage", height, r)
+	}
+	return nil
+}
+
+// historicTrieMode returns MPT mode suitable for reading old (not the latest)
+// states.
+func (bc *Blockchain) historicTrieMode() mpt.TrieMode {
+	if bc.config.Ledger.StateRetention != 0 || bc.config.Ledger.RemoveUntraceableBlocks {
+		// Nodes of old states within StateRetention (or MaxTraceableBlocks)
+		// window can be deactivated already, but they're still there until
+		// collected.
+		return mpt.ModeGC &^ mpt.ModeGCFlag
+	}
+	return mpt.ModeAll
 }
 
 // resetTransfers is a helper function that strips the top newest NEP17 and NEP11 transfer logs
@@ -1356,6 +1577,8 @@ func (bc *Blockchain) notificationDispatcher() {
 		txFeed           = make(map[chan *transaction.Transaction]bool)
 		notificationFeed = make(map[chan *state.ContainedNotificationEvent]bool)
 		executionFeed    = make(map[chan *state.AppExecResult]bool)
+		// replayFeed is indexed by subscriber's channels.
+		replayFeed = make(map[any]*replaySubscription)
 	)
 	for {
 		select {
@@ -1363,6 +1586,8 @@ func (bc *Blockchain) notificationDispatcher() {
 			return
 		case sub := <-bc.subCh:
 			switch ch := sub.(type) {
+			case *replaySubscription:
+				replayFeed[ch.ch] = ch
 			case chan *block.Header:
 				headerFeed[ch] = true
 			case chan *block.Block:
@@ -1377,6 +1602,12 @@ func (bc *Blockchain) notificationDispatcher() {
 				panic(fmt.Sprintf("bad subscription: %T", sub))
 			}
 		case unsub := <-bc.unsubCh:
+			if rs, ok := replayFeed[unsub]; ok {
+				close(rs.stop)
+				<-rs.done
+				delete(replayFeed, unsub)
+				continue
+			}
 			switch ch := unsub.(type) {
 			case chan *block.Header:
 				delete(headerFeed, ch)
@@ -1392,6 +1623,9 @@ func (bc *Blockchain) notificationDispatcher() {
 				panic(fmt.Sprintf("bad unsubscription: %T", unsub))
 			}
 		case event := <-bc.events:
+			for _, rs := range replayFeed {
+				rs.push(event)
+			}
 			// We don't want to waste time looping through transactions when there are no
 			// subscribers.
 			if len(txFeed) != 0 || len(notificationFeed) != 0 || len(executionFeed) != 0 {
@@ -1479,6 +1713,12 @@ func (bc *Blockchain) Close() {
 func (bc *Blockchain) AddBlock(block *block.Block) error {
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
+	// Checks for blocks at or below the current height are never used again,
+	// even if these blocks were never passed to AddBlock.
+	defer func() {
+		bc.sigCache.Remove(block.Index)
+		bc.sigCache.RemoveUpTo(bc.BlockHeight())
+	}()
 
 	var mp *mempool.Pool
 	expectedHeight := bc.BlockHeight() + 1
@@ -1526,6 +1766,43 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 	return bc.storeBlock(block, mp)
 }
 
+// ImportContract adds the given contract with its storage items to the chain
+// state. It's only possible for a chain that has nothing but the genesis
+// block and it's intended to be used for private networks and tests that
+// need some real contract state. The contract keeps its hash and ID, they
+// must not be used by other contracts. Note that it changes the genesis
+// state, so every node of the network needs to import the same contracts.
+func (bc *Blockchain) ImportContract(cs *state.Contract, items []storage.KeyValue) error {
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+
+	if h := bc.HeaderHeight(); h != 0 {
+		return fmt.Errorf("contracts can only be imported into a chain at genesis height, chain height is %d", h)
+	}
+	cache := bc.dao.GetPrivate()
+	if err := bc.contracts.Management.ImportContract(cache, cs); err != nil {
+		return fmt.Errorf("failed to import contract %s: %w", cs.Hash.StringLE(), err)
+	}
+	for _, kv := range items {
+		cache.PutStorageItem(cs.ID, kv.Key, kv.Value)
+	}
+	b := mpt.MapToMPTBatch(cache.Store.GetStorageChanges())
+	tr, sr, err := bc.stateRoot.AddMPTBatch(0, b, cache.Store)
+	if err != nil {
+		return fmt.Errorf("error while trying to apply MPT changes: %w", err)
+	}
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+	_, err = cache.Persist()
+	if err != nil {
+		return err
+	}
+	tr.Store = bc.dao.Store
+	bc.stateRoot.UpdateCurrentLocal(tr, sr)
+	return nil
+}
+
 // AddHeaders processes the given headers and add them to the
 // HeaderHashList. It expects headers to be sorted by index.
 func (bc *Blockchain) AddHeaders(headers ...*block.Header) error {
@@ -1586,6 +1863,21 @@ func (bc *Blockchain) GetStateModule() StateRoot {
 	return bc.stateRoot
 }
 
+// GetStorageStats iterates over the persistent DB and returns the number of
+// keys and their sizes per key prefix and per contract ID. Not yet persisted
+// changes are not taken into account. DB statistics metrics are updated with
+// the result.
+func (bc *Blockchain) GetStorageStats(ctx context.Context) (*storage.Stats, error) {
+	bc.statsLock.Lock()
+	defer bc.statsLock.Unlock()
+	stats, err := storage.GetStats(ctx, bc.store)
+	if err != nil {
+		return nil, err
+	}
+	updateStorageMetrics(stats)
+	return stats, nil
+}
+
 // GetStateSyncModule returns new state sync service instance.
 func (bc *Blockchain) GetStateSyncModule() *statesync.Module {
 	return statesync.NewModule(bc, bc.stateRoot, bc.log, bc.dao, bc.jumpToState)
@@ -1601,6 +1893,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		appExecResults = make([]*state.AppExecResult, 0, 2+len(block.Transactions))
 		aerchan        = make(chan *state.AppExecResult, len(block.Transactions)/8) // Tested 8 and 4 with no practical difference, but feel free to test more and tune.
 		aerdone        = make(chan error)
+		tracer         = bc.getTracer()
 	)
 	go func() {
 		var (
@@ -1681,7 +1974,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		close(aerdone)
 	}()
 	_ = cache.GetItemCtx() // Prime serialization context cache (it'll be reused by upper layer DAOs).
-	aer, v, err := bc.runPersist(bc.contracts.GetPersistScript(), block, cache, trigger.OnPersist, nil)
+	aer, v, err := bc.runPersist(bc.contracts.GetPersistScript(), block, cache, trigger.OnPersist, nil, tracer)
 	if err != nil {
 		// Release goroutines, don't care about errors, we already have one.
 		close(aerchan)
@@ -1692,44 +1985,24 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	aerchan <- aer
 
 	for _, tx := range block.Transactions {
-		systemInterop := bc.newInteropContext(trigger.Application, cache, block, tx)
-		systemInterop.ReuseVM(v)
-		v.LoadScriptWithFlags(tx.Script, callflag.All)
-		v.GasLimit = tx.SystemFee
-
-		err := systemInterop.Exec()
-		var faultException string
-		if !v.HasFailed() {
-			_, err := systemInterop.DAO.Persist()
-			if err != nil {
-				// Release goroutines, don't care about errors, we already have one.
-				close(aerchan)
-				<-aerdone
-				return fmt.Errorf("failed to persist invocation results: %w", err)
-			}
-		} else {
+		aer, err := bc.runTransaction(tx, block, cache, v, tracer)
+		if err != nil {
+			// Release goroutines, don't care about errors, we already have one.
+			close(aerchan)
+			<-aerdone
+			return err
+		}
+		if aer.VMState == vmstate.Fault {
 			bc.log.Warn("contract invocation failed",
 				zap.String("tx", tx.Hash().StringLE()),
 				zap.Uint32("block", block.Index),
-				zap.Error(err))
-			faultException = err.Error()
-		}
-		aer := &state.AppExecResult{
-			Container: tx.Hash(),
-			Execution: state.Execution{
-				Trigger:        trigger.Application,
-				VMState:        v.State(),
-				GasConsumed:    v.GasConsumed(),
-				Stack:          v.Estack().ToArray(),
-				Events:         systemInterop.Notifications,
-				FaultException: faultException,
-			},
+				zap.String("error", aer.FaultException))
 		}
 		appExecResults = append(appExecResults, aer)
 		aerchan <- aer
 	}
 
-	aer, _, err = bc.runPersist(bc.contracts.GetPostPersistScript(), block, cache, trigger.PostPersist, v)
+	aer, _, err = bc.runPersist(bc.contracts.GetPostPersistScript(), block, cache, trigger.PostPersist, v, tracer)
 	if err != nil {
 		// Release goroutines, don't care about errors, we already have one.
 		close(aerchan)
@@ -1864,8 +2137,49 @@ func (bc *Blockchain) IsExtensibleAllowed(u util.Uint160) bool {
 	return n < len(us)
 }
 
-func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.Simple, trig trigger.Type, v *vm.VM) (*state.AppExecResult, *vm.VM, error) {
+// runTransaction executes the transaction script using the given VM and
+// persists its changes into the cache if it's successful.
+func (bc *Blockchain) runTransaction(tx *transaction.Transaction, block *block.Block, cache *dao.Simple, v *vm.VM, tracer Tracer) (*state.AppExecResult, error) {
+	systemInterop := bc.newInteropContext(trigger.Application, cache, block, tx)
+	if tracer != nil {
+		systemInterop.SetTracer(tracer)
+	}
+	systemInterop.ReuseVM(v)
+	v.LoadScriptWithFlags(tx.Script, callflag.All)
+	v.GasLimit = tx.SystemFee
+
+	err := systemInterop.Exec()
+	var faultException string
+	if !v.HasFailed() {
+		_, err := systemInterop.DAO.Persist()
+		if err != nil {
+			return nil, fmt.Errorf("failed to persist invocation results: %w", err)
+		}
+	} else {
+		faultException = err.Error()
+	}
+	aer := &state.AppExecResult{
+		Container: tx.Hash(),
+		Execution: state.Execution{
+			Trigger:        trigger.Application,
+			VMState:        v.State(),
+			GasConsumed:    v.GasConsumed(),
+			Stack:          v.Estack().ToArray(),
+			Events:         systemInterop.Notifications,
+			FaultException: faultException,
+		},
+	}
+	if tracer != nil {
+		tracer.OnExecuted(systemInterop, aer)
+	}
+	return aer, nil
+}
+
+func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.Simple, trig trigger.Type, v *vm.VM, tracer Tracer) (*state.AppExecResult, *vm.VM, error) {
 	systemInterop := bc.newInteropContext(trig, cache, block, nil)
+	if tracer != nil {
+		systemInterop.SetTracer(tracer)
+	}
 	if v == nil {
 		v = systemInterop.SpawnVM()
 	} else {
@@ -1877,7 +2191,7 @@ func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.S
 	} else if _, err := systemInterop.DAO.Persist(); err != nil {
 		return nil, v, fmt.Errorf("can't save changes: %w", err)
 	}
-	return &state.AppExecResult{
+	aer := &state.AppExecResult{
 		Container: block.Hash(), // application logs can be retrieved by block hash
 		Execution: state.Execution{
 			Trigger:     trig,
@@ -1886,7 +2200,11 @@ func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.S
 			Stack:       v.Estack().ToArray(),
 			Events:      systemInterop.Notifications,
 		},
-	}, v, nil
+	}
+	if tracer != nil {
+		tracer.OnExecuted(systemInterop, aer)
+	}
+	return aer, v, nil
 }
 
 func (bc *Blockchain) handleNotification(note *state.NotificationEvent, d *dao.Simple,
@@ -2440,6 +2758,261 @@ unsubloop:
 	}
 }
 
+// SubscribeForBlocksFrom is similar to SubscribeForBlocks, but before the new
+// blocks it sends all blocks of the chain starting from the specified index
+// (the genesis block is never sent). Past blocks are sent at the pace they're
+// read from the channel, new blocks are queued until all past ones are sent,
+// so there are no gaps or duplicates in the stream. An error is returned if
+// the start index is above the next block index or if blocks are not
+// available since this index. Use UnsubscribeFromBlocks to unsubscribe.
+func (bc *Blockchain) SubscribeForBlocksFrom(ch chan *block.Block, start uint32) error {
+	return bc.subscribeFrom(ch, start)
+}
+
+// SubscribeForHeadersOfAddedBlocksFrom is similar to
+// SubscribeForHeadersOfAddedBlocks, but it sends headers of past blocks
+// starting from the specified index first, see SubscribeForBlocksFrom for
+// details. Use UnsubscribeFromHeadersOfAddedBlocks to unsubscribe.
+func (bc *Blockchain) SubscribeForHeadersOfAddedBlocksFrom(ch chan *block.Header, start uint32) error {
+	return bc.subscribeFrom(ch, start)
+}
+
+// SubscribeForTransactionsFrom is similar to SubscribeForTransactions, but it
+// sends transactions of past blocks starting from the specified index first,
+// see SubscribeForBlocksFrom for details. Use UnsubscribeFromTransactions to
+// unsubscribe.
+func (bc *Blockchain) SubscribeForTransactionsFrom(ch chan *transaction.Transaction, start uint32) error {
+	return bc.subscribeFrom(ch, start)
+}
+
+// SubscribeForNotificationsFrom is similar to SubscribeForNotifications, but
+// it sends notifications of past blocks starting from the specified index
+// first, see SubscribeForBlocksFrom for details. Use
+// UnsubscribeFromNotifications to unsubscribe.
+func (bc *Blockchain) SubscribeForNotificationsFrom(ch chan *state.ContainedNotificationEvent, start uint32) error {
+	return bc.subscribeFrom(ch, start)
+}
+
+// SubscribeForExecutionsFrom is similar to SubscribeForExecutions, but it
+// sends execution results of past blocks starting from the specified index
+// first, see SubscribeForBlocksFrom for details. Use UnsubscribeFromExecutions
+// to unsubscribe.
+func (bc *Blockchain) SubscribeForExecutionsFrom(ch chan *state.AppExecResult, start uint32) error {
+	return bc.subscribeFrom(ch, start)
+}
+
+// subscribeFrom registers replaying subscription for the given channel and
+// starts the replaying routine.
+func (bc *Blockchain) subscribeFrom(ch any, start uint32) error {
+	if start == 0 {
+		start = 1
+	}
+	height := bc.BlockHeight()
+	if start > height+1 {
+		return fmt.Errorf("start index %d is above the next block index %d", start, height+1)
+	}
+	if bc.config.Ledger.RemoveUntraceableBlocks && height >= bc.config.MaxTraceableBlocks &&
+		start <= height-bc.config.MaxTraceableBlocks {
+		return fmt.Errorf("block %d is untraceable", start)
+	}
+	rs := &replaySubscription{
+		ch:    ch,
+		next:  start,
+		newEv: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	// Start replaying after registration, every block that is not
+	// queued is stored at this point.
+	bc.subCh <- rs
+	go bc.replayEvents(rs)
+	return nil
+}
+
+// push queues the new event for the replaying subscription. If the queue is
+// full, queued events are dropped, the replaying routine reads them from the
+// DB (they're always stored at this point).
+func (rs *replaySubscription) push(ev bcEvent) {
+	rs.lock.Lock()
+	if !rs.closed {
+		if len(rs.pending) >= replayQueueSize {
+			rs.pending = nil
+		}
+		rs.pending = append(rs.pending, ev)
+	}
+	rs.lock.Unlock()
+	select {
+	case rs.newEv <- struct{}{}:
+	default:
+	}
+}
+
+// replayEvents sends past events from the DB and queued new events to the
+// replaying subscription until it's unsubscribed or the chain is stopped.
+func (bc *Blockchain) replayEvents(rs *replaySubscription) {
+	defer close(rs.done)
+	defer func() {
+		rs.lock.Lock()
+		rs.closed = true
+		rs.pending = nil
+		rs.lock.Unlock()
+	}()
+	for {
+		rs.lock.Lock()
+		pending := rs.pending
+		rs.pending = nil
+		rs.lock.Unlock()
+		for _, ev := range pending {
+			// Fill the gap between the blocks read from the DB and
+			// the queued ones.
+			for rs.next < ev.block.Index {
+				if !bc.replayStoredEvent(rs) {
+					return
+				}
+			}
+			if ev.block.Index == rs.next {
+				if !bc.sendReplayed(rs, ev) {
+					return
+				}
+				rs.next++
+			}
+		}
+		if len(pending) != 0 {
+			continue
+		}
+		if rs.next <= bc.BlockHeight() {
+			if !bc.replayStoredEvent(rs) {
+				return
+			}
+			continue
+		}
+		select {
+		case <-rs.newEv:
+		case <-rs.stop:
+			return
+		case <-bc.stopCh:
+			return
+		}
+	}
+}
+
+// replayStoredEvent reads the next block with its execution results from the
+// DB and sends it to the replaying subscription. It returns false if the
+// subscription is to be stopped.
+func (bc *Blockchain) replayStoredEvent(rs *replaySubscription) bool {
+	b, err := bc.GetBlock(bc.GetHeaderHash(rs.next))
+	if err != nil {
+		bc.log.Error("failed to get block for replaying subscription",
+			zap.Uint32("index", rs.next), zap.Error(err))
+		return false
+	}
+	ev := bcEvent{block: b}
+	switch rs.ch.(type) {
+	case chan *state.AppExecResult, chan *state.ContainedNotificationEvent:
+		ev.appExecResults, err = bc.getBlockAppExecResults(b)
+		if err != nil {
+			bc.log.Error("failed to get execution results for replaying subscription",
+				zap.Uint32("index", rs.next), zap.Error(err))
+			return false
+		}
+	}
+	if !bc.sendReplayed(rs, ev) {
+		return false
+	}
+	rs.next++
+	return true
+}
+
+// getBlockAppExecResults returns the execution results of the stored block in
+// the same order they're broadcasted: OnPersist, transactions, PostPersist.
+func (bc *Blockchain) getBlockAppExecResults(b *block.Block) ([]*state.AppExecResult, error) {
+	blockAERs, err := bc.GetAppExecResults(b.Hash(), trigger.OnPersist|trigger.PostPersist)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockAERs) != 2 {
+		return nil, fmt.Errorf("unexpected number of block execution results: %d", len(blockAERs))
+	}
+	res := make([]*state.AppExecResult, 0, len(b.Transactions)+2)
+	res = append(res, &blockAERs[0])
+	for _, tx := range b.Transactions {
+		aers, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return nil, err
+		}
+		if len(aers) != 1 {
+			return nil, fmt.Errorf("no execution result for transaction %s", tx.Hash().StringLE())
+		}
+		res = append(res, &aers[0])
+	}
+	return append(res, &blockAERs[1]), nil
+}
+
+// sendReplayed sends the event to the replaying subscription channel the same
+// way notificationDispatcher does it. It returns false if the subscription
+// is to be stopped.
+func (bc *Blockchain) sendReplayed(rs *replaySubscription, ev bcEvent) bool {
+	switch ch := rs.ch.(type) {
+	case chan *block.Block:
+		select {
+		case ch <- ev.block:
+		case <-rs.stop:
+			return false
+		case <-bc.stopCh:
+			return false
+		}
+	case chan *block.Header:
+		select {
+		case ch <- &ev.block.Header:
+		case <-rs.stop:
+			return false
+		case <-bc.stopCh:
+			return false
+		}
+	case chan *transaction.Transaction:
+		for _, tx := range ev.block.Transactions {
+			select {
+			case ch <- tx:
+			case <-rs.stop:
+				return false
+			case <-bc.stopCh:
+				return false
+			}
+		}
+	case chan *state.AppExecResult:
+		for _, aer := range ev.appExecResults {
+			select {
+			case ch <- aer:
+			case <-rs.stop:
+				return false
+			case <-bc.stopCh:
+				return false
+			}
+		}
+	case chan *state.ContainedNotificationEvent:
+		for _, aer := range ev.appExecResults {
+			if aer.Trigger == trigger.Application && aer.VMState != vmstate.Halt {
+				continue
+			}
+			for i := range aer.Events {
+				select {
+				case ch <- &state.ContainedNotificationEvent{
+					Container:         aer.Container,
+					NotificationEvent: aer.Events[i],
+				}:
+				case <-rs.stop:
+					return false
+				case <-bc.stopCh:
+					return false
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("bad subscription: %T", rs.ch))
+	}
+	return true
+}
+
 // CalculateClaimable calculates the amount of GAS generated by owning specified
 // amount of NEO between specified blocks.
 func (bc *Blockchain) CalculateClaimable(acc util.Uint160, endHeight uint32) (*big.Int, error) {
@@ -2845,33 +3418,235 @@ func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transact
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fake block for height %d: %w", nextBlockHeight, err)
 	}
-	var mode = mpt.ModeAll
+	if err := bc.checkHistoricHeight(b.Index); err != nil {
+		return nil, err
+	}
+	dTrie, err := bc.getHistoricDAO(b.Index)
+	if err != nil {
+		return nil, err
+	}
+	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
+	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
+	return systemInterop, nil
+}
+
+// checkHistoricHeight returns an error if the state required to process the
+// block with the given index is not available (state of index-1 block is used).
+func (bc *Blockchain) checkHistoricHeight(index uint32) error {
 	if bc.config.Ledger.RemoveUntraceableBlocks {
-		if b.Index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
-			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", b.Index)
+		if height := bc.BlockHeight(); height > bc.config.MaxTraceableBlocks && index < height-bc.config.MaxTraceableBlocks {
+			return fmt.Errorf("state for height %d is outdated and removed from the storage", index)
 		}
-		mode |= mpt.ModeGCFlag
 	}
-	if b.Index < 1 || b.Index > bc.BlockHeight()+1 {
-		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", b.Index, bc.blockHeight)
+	if index < 1 || index > bc.BlockHeight()+1 {
+		return fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", index, bc.BlockHeight())
+	}
+	return nil
+}
+
+// getHistoricDAO returns DAO backed by the state of height-1 block with native
+// cache initialized for the height block processing.
+func (bc *Blockchain) getHistoricDAO(height uint32) (*dao.Simple, error) {
+	if err := bc.checkStateRetention(height - 1); err != nil {
+		return nil, err
 	}
 	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
-	sr, err := bc.stateRoot.GetStateRoot(b.Index - 1)
+	sr, err := bc.stateRoot.GetStateRoot(height - 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index, err)
+		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", height, err)
 	}
-	s := mpt.NewTrieStore(sr.Root, mode, storage.NewPrivateMemCachedStore(bc.dao.Store))
+	s := mpt.NewTrieStore(sr.Root, bc.historicTrieMode(), storage.NewPrivateMemCachedStore(bc.dao.Store))
 	dTrie := dao.NewSimple(s, bc.config.StateRootInHeader)
 	dTrie.Version = bc.dao.Version
 	// Initialize native cache before passing DAO to interop context constructor, because
 	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
-	err = bc.initializeNativeCache(b.Index, dTrie)
+	err = bc.initializeNativeCache(height, dTrie)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
 	}
-	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
-	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
-	return systemInterop, nil
+	return dTrie, nil
+}
+
+// ReplayTransaction re-executes the persisted transaction on the state it was
+// originally executed with (the state of the previous block with the block's
+// OnPersist and preceding transactions applied) and returns its execution
+// result. The tracer (if not nil) gets the transaction execution events only.
+// Nothing is saved to the DB, the historic state must be available for the
+// previous block (see GetTestHistoricVM).
+func (bc *Blockchain) ReplayTransaction(h util.Uint256, tracer Tracer) (*state.AppExecResult, error) {
+	if bc.config.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	tx, height, err := bc.dao.GetTransaction(h)
+	if err != nil {
+		return nil, err
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	dTrie, err := bc.getHistoricDAO(b.Index)
+	if err != nil {
+		return nil, err
+	}
+	cache := dTrie.GetPrivate()
+	_, v, err := bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("onPersist failed: %w", err)
+	}
+	for _, btx := range b.Transactions {
+		if btx.Hash() == tx.Hash() {
+			return bc.runTransaction(btx, b, cache, v, tracer)
+		}
+		if _, err := bc.runTransaction(btx, b, cache, v, nil); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not found in block %d", h.StringLE(), b.Index)
+}
+
+// Simulate executes the given transactions in a fake block following the
+// block at the given height with the specified state overrides and returns
+// execution results along with the contract storage changes made. Nothing is
+// saved to the DB, the historic state must be available for the given height
+// (see GetTestHistoricVM), only the latest height is supported if
+// KeepOnlyLatestState is enabled.
+func (bc *Blockchain) Simulate(sim *Simulation) (*SimulationResult, error) {
+	var latest = bc.config.Ledger.KeepOnlyLatestState
+	if latest && sim.Height != bc.BlockHeight() {
+		return nil, errors.New("only latest state is supported")
+	}
+	if err := bc.checkHistoricHeight(sim.Height + 1); err != nil {
+		return nil, err
+	}
+	b, err := bc.getFakeNextBlock(sim.Height + 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fake block for height %d: %w", sim.Height+1, err)
+	}
+	b.PrevHash = bc.GetHeaderHash(sim.Height)
+	if sim.Timestamp != 0 {
+		b.Timestamp = sim.Timestamp
+	}
+	b.Transactions = sim.Transactions
+	var d = bc.dao
+	if !latest {
+		d, err = bc.getHistoricDAO(b.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	base := d.GetPrivate()
+	if err := bc.applySimulationOverrides(base, sim); err != nil {
+		return nil, err
+	}
+
+	var (
+		cache = base.GetPrivate()
+		res   = &SimulationResult{Block: b}
+		v     *vm.VM
+		aer   *state.AppExecResult
+	)
+	if sim.FullBlock {
+		aer, v, err = bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("onPersist failed: %w", err)
+		}
+		res.Executions = append(res.Executions, aer)
+	} else {
+		v = bc.newInteropContext(trigger.Application, cache, b, nil).SpawnVM()
+	}
+	for _, tx := range b.Transactions {
+		aer, err = bc.runTransaction(tx, b, cache, v, nil)
+		if err != nil {
+			return nil, err
+		}
+		res.Executions = append(res.Executions, aer)
+	}
+	if sim.FullBlock {
+		aer, _, err = bc.runPersist(bc.contracts.GetPostPersistScript(), b, cache, trigger.PostPersist, v, nil)
+		if err != nil {
+			return nil, fmt.Errorf("postPersist failed: %w", err)
+		}
+		res.Executions = append(res.Executions, aer)
+	}
+
+	for k, newV := range cache.Store.GetStorageChanges() {
+		var (
+			id   = int32(binary.LittleEndian.Uint32([]byte(k[1:5])))
+			key  = []byte(k[5:])
+			oldV = base.GetStorageItem(id, key)
+		)
+		if bytes.Equal(oldV, newV) {
+			continue
+		}
+		h, err := native.GetContractScriptHash(cache, id)
+		if err != nil {
+			// The contract can be destroyed by the simulated block.
+			h, err = native.GetContractScriptHash(base, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get contract hash for ID %d: %w", id, err)
+			}
+		}
+		res.Changes = append(res.Changes, SimulatedStorageChange{
+			ID:       id,
+			Hash:     h,
+			Key:      key,
+			OldValue: oldV,
+			NewValue: newV,
+		})
+	}
+	sort.Slice(res.Changes, func(i, j int) bool {
+		if res.Changes[i].ID != res.Changes[j].ID {
+			return res.Changes[i].ID < res.Changes[j].ID
+		}
+		return bytes.Compare(res.Changes[i].Key, res.Changes[j].Key) < 0
+	})
+	return res, nil
+}
+
+// applySimulationOverrides applies the simulation contract, storage and GAS
+// balance overrides to the given DAO.
+func (bc *Blockchain) applySimulationOverrides(d *dao.Simple, sim *Simulation) error {
+	for _, c := range sim.Contracts {
+		cs, err := native.GetContract(d, c.Hash)
+		if err != nil {
+			return fmt.Errorf("contract %s: %w", c.Hash.StringLE(), err)
+		}
+		if cs.ID < 0 {
+			return fmt.Errorf("contract %s: native contracts can't be replaced", c.Hash.StringLE())
+		}
+		ncs := *cs // Don't ruin cached contract.
+		if c.NEF != nil {
+			ncs.NEF = *c.NEF
+		}
+		if c.Manifest != nil {
+			if err := c.Manifest.IsValid(ncs.Hash, true); err != nil {
+				return fmt.Errorf("contract %s: invalid manifest: %w", c.Hash.StringLE(), err)
+			}
+			ncs.Manifest = *c.Manifest
+		}
+		if err := native.PutContractState(d, &ncs); err != nil {
+			return fmt.Errorf("contract %s: %w", c.Hash.StringLE(), err)
+		}
+	}
+	for _, si := range sim.Storage {
+		cs, err := native.GetContract(d, si.Hash)
+		if err != nil {
+			return fmt.Errorf("storage item of contract %s: %w", si.Hash.StringLE(), err)
+		}
+		if si.Value == nil {
+			d.DeleteStorageItem(cs.ID, si.Key)
+		} else {
+			d.PutStorageItem(cs.ID, si.Key, si.Value)
+		}
+	}
+	for acc, amount := range sim.GASBalances {
+		if amount.Sign() < 0 {
+			return fmt.Errorf("negative GAS balance for %s", acc.StringLE())
+		}
+		bc.contracts.GAS.SetBalance(d, acc, amount)
+	}
+	return nil
 }
 
 // getFakeNextBlock returns fake block with the specified index and pre-filled Timestamp field.
@@ -2946,6 +3721,7 @@ func (bc *Blockchain) InitVerificationContext(ic *interop.Context, hash util.Uin
 func (bc *Blockchain) VerifyWitness(h util.Uint160, c hash.Hashable, w *transaction.Witness, gas int64) (int64, error) {
 	ic := bc.newInteropContext(trigger.Verification, bc.dao, nil, nil)
 	ic.Container = c
+	ic.SigCache = bc.sigCache
 	if tx, ok := c.(*transaction.Transaction); ok {
 		ic.Tx = tx
 	}
@@ -2997,6 +3773,7 @@ func (bc *Blockchain) verifyHashAgainstScript(hash util.Uint160, witness *transa
 // Golang implementation of VerifyWitnesses method in C# (https://github.com/neo-project/neo/blob/master/neo/SmartContract/Helper.cs#L87).
 func (bc *Blockchain) verifyTxWitnesses(t *transaction.Transaction, block *block.Block, isPartialTx bool, verificationFee ...int64) error {
 	interopCtx := bc.newInteropContext(trigger.Verification, bc.dao, block, t)
+	interopCtx.SigCache = bc.sigCache
 	var gasLimit int64
 	if len(verificationFee) == 0 {
 		gasLimit = t.NetworkFee - int64(t.Size())*bc.FeePerByte() - bc.CalculateAttributesFee(t)
@@ -3027,6 +3804,93 @@ func (bc *Blockchain) verifyHeaderWitnesses(currHeader, prevHeader *block.Header
 	return err
 }
 
+// PreverifyBlock checks signatures of the standard (signature and multisignature)
+// block and transaction witnesses and caches successful results, so that
+// subsequent AddBlock call for this block doesn't need to check them again.
+// Witnesses are not fully verified by this method, it only allows to move
+// expensive cryptographic operations out of the block processing, therefore
+// it can be called concurrently for several blocks that are yet to be added.
+// It does nothing if block verification is disabled or the block is already
+// processed.
+func (bc *Blockchain) PreverifyBlock(b *block.Block) {
+	if bc.config.SkipBlockVerification || b.Index <= bc.BlockHeight() {
+		return
+	}
+	net := uint32(bc.config.Magic)
+	bc.preverifyWitness(b.Index, hash.NetSha256(net, &b.Header).BytesBE(), &b.Script)
+	for _, tx := range b.Transactions {
+		msg := hash.NetSha256(net, tx).BytesBE()
+		for i := range tx.Scripts {
+			bc.preverifyWitness(b.Index, msg, &tx.Scripts[i])
+		}
+	}
+}
+
+// preverifyWitness checks signatures of the standard witness and adds them
+// to the signature cache if they're correct. Signatures and keys are added in
+// the same order they're passed to the crypto interops.
+func (bc *Blockchain) preverifyWitness(index uint32, msg []byte, w *transaction.Witness) {
+	var (
+		pkeys [][]byte
+		nsigs = 1
+	)
+	if pub, ok := vm.ParseSignatureContract(w.VerificationScript); ok {
+		pkeys = [][]byte{pub}
+	} else if m, pubs, ok := vm.ParseMultiSigContract(w.VerificationScript); ok {
+		nsigs = m
+		pkeys = pubs
+	} else {
+		return
+	}
+	sigs, ok := parseSignatures(w.InvocationScript, nsigs)
+	if !ok {
+		return
+	}
+	pubs := make([]*keys.PublicKey, len(pkeys))
+	for i := range pkeys {
+		pub, err := keys.NewPublicKeyFromBytes(pkeys[i], elliptic.P256())
+		if err != nil {
+			return
+		}
+		pubs[i] = pub
+	}
+	if len(pkeys) == 1 {
+		if pubs[0].Verify(sigs[0], msg) {
+			bc.sigCache.Add(index, msg, pkeys, sigs)
+		}
+		return
+	}
+	// CheckMultisig pops both keys and signatures from the stack,
+	// so they're reversed.
+	for i, j := 0, len(pkeys)-1; i < j; i, j = i+1, j-1 {
+		pkeys[i], pkeys[j] = pkeys[j], pkeys[i]
+	}
+	for i, j := 0, len(sigs)-1; i < j; i, j = i+1, j-1 {
+		sigs[i], sigs[j] = sigs[j], sigs[i]
+	}
+	if vm.CheckMultisigPar(nil, elliptic.P256(), msg, pkeys, sigs) {
+		bc.sigCache.Add(index, msg, pkeys, sigs)
+	}
+}
+
+// parseSignatures returns signatures pushed by the standard invocation script
+// if it contains exactly n of them.
+func parseSignatures(script []byte, n int) ([][]byte, bool) {
+	const pushLen = 2 + keys.SignatureLen
+	if len(script) != n*pushLen {
+		return nil, false
+	}
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		push := script[i*pushLen : (i+1)*pushLen]
+		if push[0] != byte(opcode.PUSHDATA1) || push[1] != keys.SignatureLen {
+			return nil, false
+		}
+		sigs[i] = push[2:]
+	}
+	return sigs, true
+}
+
 // GoverningTokenHash returns the governing token (NEO) native contract hash.
 func (bc *Blockchain) GoverningTokenHash() util.Uint160 {
 	return bc.contracts.NEO.Hash
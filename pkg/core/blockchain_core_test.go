@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"strings"
@@ -12,13 +13,17 @@ import (
 	"github.com/epicchainlabs/epicchain-go/internal/testchain"
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -360,6 +365,30 @@ func TestBlockchain_IsRunning(t *testing.T) {
 	require.False(t, chain.isRunning.Load().(bool))
 }
 
+func TestBlockchain_GetStorageStats(t *testing.T) {
+	dbKeys.Reset()
+	bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+		c.ApplicationConfiguration.StorageStatsInterval = 10 * time.Millisecond
+	})
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(dbKeys.WithLabelValues(storage.SYSVersion.String())) == 1
+	}, 2*persistInterval, 10*time.Millisecond)
+
+	stats, err := bc.GetStorageStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats.Prefixes[storage.SYSVersion].Keys)
+	require.NotZero(t, stats.Prefixes[storage.DataExecutable].Keys)
+
+	var storageKeys uint64
+	for id, cs := range stats.Contracts {
+		_, err := bc.GetContractScriptHash(id)
+		require.NoError(t, err)
+		storageKeys += cs.Keys
+	}
+	require.Equal(t, stats.Prefixes[storage.STStorage].Keys, storageKeys)
+	require.Equal(t, float64(storageKeys), testutil.ToFloat64(dbKeys.WithLabelValues(storage.STStorage.String())))
+}
+
 func TestNewBlockchain_InitHardforks(t *testing.T) {
 	t.Run("nil set", func(t *testing.T) {
 		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
@@ -370,6 +399,7 @@ func TestNewBlockchain_InitHardforks(t *testing.T) {
 			config.HFAspidochelone.String(): 0,
 			config.HFBasilisk.String():      0,
 			config.HFCockatrice.String():    0,
+			config.HFDomovoi.String():       0,
 		}, bc.GetConfig().Hardforks)
 	})
 	t.Run("empty set", func(t *testing.T) {
@@ -400,13 +430,136 @@ func TestNewBlockchain_InitHardforks(t *testing.T) {
 	})
 	t.Run("all present", func(t *testing.T) {
 		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
-			c.ProtocolConfiguration.Hardforks = map[string]uint32{config.HFAspidochelone.String(): 5, config.HFBasilisk.String(): 10, config.HFCockatrice.String(): 15}
+			c.ProtocolConfiguration.Hardforks = map[string]uint32{config.HFAspidochelone.String(): 5, config.HFBasilisk.String(): 10, config.HFCockatrice.String(): 15, config.HFDomovoi.String(): 20}
 			require.NoError(t, c.ProtocolConfiguration.Validate())
 		})
 		require.Equal(t, map[string]uint32{
 			config.HFAspidochelone.String(): 5,
 			config.HFBasilisk.String():      10,
 			config.HFCockatrice.String():    15,
+			config.HFDomovoi.String():       20,
 		}, bc.GetConfig().Hardforks)
 	})
 }
+
+func TestBlockchain_HistoricTrieMode(t *testing.T) {
+	check := func(t *testing.T, rub bool, retention uint32, expected mpt.TrieMode) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ApplicationConfiguration.RemoveUntraceableBlocks = rub
+			c.ApplicationConfiguration.StateRetention = retention
+			c.ApplicationConfiguration.GarbageCollectionPeriod = 10
+		})
+		require.Equal(t, expected, bc.historicTrieMode())
+	}
+	t.Run("archive", func(t *testing.T) { check(t, false, 0, mpt.ModeAll) })
+	t.Run("RemoveUntraceableBlocks", func(t *testing.T) { check(t, true, 0, mpt.ModeLatest) })
+	t.Run("StateRetention", func(t *testing.T) { check(t, false, 5, mpt.ModeLatest) })
+	t.Run("both", func(t *testing.T) { check(t, true, 5, mpt.ModeLatest) })
+}
+
+func TestBlockchain_PreverifyBlock(t *testing.T) {
+	bc := newTestChain(t)
+	tx, err := testchain.NewTransferFromOwner(bc, bc.contracts.GAS.Hash, util.Uint160{1, 2, 3}, 1, 0, bc.BlockHeight()+2)
+	require.NoError(t, err)
+	b := bc.newBlock(tx)
+
+	check := func(t *testing.T, h hash.Hashable, w *transaction.Witness) {
+		var (
+			msg   = hash.NetSha256(uint32(bc.config.Magic), h).BytesBE()
+			pkeys [][]byte
+			nsigs = 1
+		)
+		if pub, ok := vm.ParseSignatureContract(w.VerificationScript); ok {
+			pkeys = [][]byte{pub}
+		} else {
+			var ok bool
+			nsigs, pkeys, ok = vm.ParseMultiSigContract(w.VerificationScript)
+			require.True(t, ok)
+		}
+		sigs, ok := parseSignatures(w.InvocationScript, nsigs)
+		require.True(t, ok)
+		if len(pkeys) > 1 {
+			// Keys and signatures are reversed by CheckMultisig.
+			for i, j := 0, len(pkeys)-1; i < j; i, j = i+1, j-1 {
+				pkeys[i], pkeys[j] = pkeys[j], pkeys[i]
+			}
+			for i, j := 0, len(sigs)-1; i < j; i, j = i+1, j-1 {
+				sigs[i], sigs[j] = sigs[j], sigs[i]
+			}
+		}
+		require.True(t, bc.sigCache.Has(msg, pkeys, sigs))
+	}
+
+	bc.PreverifyBlock(b)
+	require.Equal(t, 2, bc.sigCache.Len())
+	check(t, &b.Header, &b.Script)
+	check(t, tx, &tx.Scripts[0])
+
+	t.Run("invalid signature", func(t *testing.T) {
+		bad := bc.newBlock()
+		bad.Script.InvocationScript = bytes.Clone(bad.Script.InvocationScript)
+		bad.Script.InvocationScript[10] ^= 0xff
+		bc.PreverifyBlock(bad)
+		require.Equal(t, 2, bc.sigCache.Len())
+	})
+
+	require.NoError(t, bc.AddBlock(b))
+	require.Equal(t, 0, bc.sigCache.Len())
+
+	t.Run("stale blocks", func(t *testing.T) {
+		// Already processed blocks are not checked.
+		bc.PreverifyBlock(b)
+		require.Equal(t, 0, bc.sigCache.Len())
+
+		// Checks made for stale blocks concurrently with block addition
+		// are evicted with the next block.
+		bc.sigCache.Add(bc.BlockHeight(), []byte{1}, [][]byte{{2}}, [][]byte{{3}})
+		require.Equal(t, 1, bc.sigCache.Len())
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		require.Equal(t, 0, bc.sigCache.Len())
+	})
+
+	t.Run("verification disabled", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ApplicationConfiguration.SkipBlockVerification = true
+		})
+		bc.PreverifyBlock(bc.newBlock())
+		require.Equal(t, 0, bc.sigCache.Len())
+	})
+}
+
+func TestBlockchain_ReplayQueueOverflow(t *testing.T) {
+	t.Run("bounded queue", func(t *testing.T) {
+		rs := &replaySubscription{newEv: make(chan struct{}, 1)}
+		for i := 1; i <= replayQueueSize+5; i++ {
+			rs.push(bcEvent{block: &block.Block{Header: block.Header{Index: uint32(i)}}})
+			require.LessOrEqual(t, len(rs.pending), replayQueueSize)
+		}
+		require.Equal(t, 5, len(rs.pending))
+		require.Equal(t, uint32(replayQueueSize+1), rs.pending[0].block.Index)
+	})
+
+	bc := newTestChain(t)
+	// The subscriber doesn't read anything until all blocks are added.
+	ch := make(chan *block.Block)
+	require.NoError(t, bc.SubscribeForBlocksFrom(ch, 1))
+	t.Cleanup(func() { bc.UnsubscribeFromBlocks(ch) })
+	_, err := bc.genBlocks(2*replayQueueSize + 10)
+	require.NoError(t, err)
+
+	// All blocks are still delivered in order without gaps or duplicates.
+	for i := uint32(1); i <= bc.BlockHeight(); i++ {
+		select {
+		case b := <-ch:
+			require.Equal(t, i, b.Index)
+			require.Equal(t, bc.GetHeaderHash(i), b.Hash())
+		case <-time.After(time.Second):
+			t.Fatalf("no block %d", i)
+		}
+	}
+	select {
+	case b := <-ch:
+		t.Fatalf("unexpected block %d", b.Index)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -1,10 +1,13 @@
 package core_test
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -22,6 +25,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/dao"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/fee"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop/interopnames"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mempool"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
@@ -43,6 +47,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/emit"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
@@ -145,6 +150,15 @@ func TestBlockchain_StartFromExistingDB(t *testing.T) {
 		require.Error(t, err)
 		require.True(t, strings.Contains(err.Error(), "KeepOnlyLatestState setting mismatch"), err)
 	})
+	t.Run("mismatch StateRetention", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.StateRetention = 10
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "StateRetention setting mismatch"), err)
+	})
 	t.Run("Magic mismatch", func(t *testing.T) {
 		ps = newPS(t)
 		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
@@ -1044,6 +1058,119 @@ func TestBlockchain_Subscriptions(t *testing.T) {
 	e.GenerateNewBlocks(t, 2*chBufSize)
 }
 
+func TestBlockchain_SubscriptionsFrom(t *testing.T) {
+	const chBufSize = 64
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	addBlocks := func(t *testing.T, n int) {
+		for i := 0; i < n; i++ {
+			script := io.NewBufBinWriter()
+			emit.Bytes(script.BinWriter, []byte(fmt.Sprintf("yay %d", i)))
+			emit.Syscall(script.BinWriter, interopnames.SystemRuntimeNotify)
+			txGood := e.PrepareInvocation(t, script.Bytes(), []neotest.Signer{acc})
+			// Bytes() reuses the script buffer, so a new one is needed.
+			script = io.NewBufBinWriter()
+			emit.Opcodes(script.BinWriter, opcode.THROW)
+			txBad := e.PrepareInvocation(t, script.Bytes(), []neotest.Signer{acc})
+			e.AddNewBlock(t, txGood, txBad)
+		}
+	}
+	addBlocks(t, 1)
+
+	// Reference live subscriptions get events starting from block 2.
+	refBlockCh := make(chan *block.Block, chBufSize)
+	refTxCh := make(chan *transaction.Transaction, chBufSize)
+	refNotificationCh := make(chan *state.ContainedNotificationEvent, chBufSize)
+	refExecutionCh := make(chan *state.AppExecResult, chBufSize)
+	bc.SubscribeForBlocks(refBlockCh)
+	bc.SubscribeForTransactions(refTxCh)
+	bc.SubscribeForNotifications(refNotificationCh)
+	bc.SubscribeForExecutions(refExecutionCh)
+	addBlocks(t, 2)
+
+	t.Run("bad start", func(t *testing.T) {
+		require.Error(t, bc.SubscribeForBlocksFrom(make(chan *block.Block), bc.BlockHeight()+2))
+	})
+
+	// Unbuffered block channel is not read until new blocks are added.
+	blockCh := make(chan *block.Block)
+	headerCh := make(chan *block.Header, chBufSize)
+	txCh := make(chan *transaction.Transaction, chBufSize)
+	notificationCh := make(chan *state.ContainedNotificationEvent, chBufSize)
+	executionCh := make(chan *state.AppExecResult, chBufSize)
+	require.NoError(t, bc.SubscribeForBlocksFrom(blockCh, 2))
+	require.NoError(t, bc.SubscribeForHeadersOfAddedBlocksFrom(headerCh, 2))
+	require.NoError(t, bc.SubscribeForTransactionsFrom(txCh, 2))
+	require.NoError(t, bc.SubscribeForNotificationsFrom(notificationCh, 2))
+	require.NoError(t, bc.SubscribeForExecutionsFrom(executionCh, 2))
+	addBlocks(t, 2)
+	require.Eventually(t, func() bool { return len(refBlockCh) == 4 }, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		ref := <-refBlockCh
+		select {
+		case b := <-blockCh:
+			require.Equal(t, ref.Hash(), b.Hash())
+		case <-time.After(time.Second):
+			t.Fatalf("no block %d", ref.Index)
+		}
+		require.Eventually(t, func() bool { return len(headerCh) != 0 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, ref.Hash(), (<-headerCh).Hash())
+	}
+	require.Eventually(t, func() bool {
+		return len(txCh) == len(refTxCh) && len(notificationCh) == len(refNotificationCh) &&
+			len(executionCh) == len(refExecutionCh)
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 8, len(txCh))
+	for len(refTxCh) != 0 {
+		require.Equal(t, (<-refTxCh).Hash(), (<-txCh).Hash())
+	}
+	for len(refNotificationCh) != 0 {
+		ref, ntf := <-refNotificationCh, <-notificationCh
+		require.Equal(t, ref.Container, ntf.Container)
+		require.Equal(t, ref.ScriptHash, ntf.ScriptHash)
+		require.Equal(t, ref.Item, ntf.Item)
+	}
+	for len(refExecutionCh) != 0 {
+		ref, aer := <-refExecutionCh, <-executionCh
+		require.Equal(t, ref.Container, aer.Container)
+		require.Equal(t, ref.Trigger, aer.Trigger)
+		require.Equal(t, ref.VMState, aer.VMState)
+		require.Equal(t, len(ref.Events), len(aer.Events))
+	}
+
+	// Live events after replaying.
+	b := e.AddNewBlock(t)
+	select {
+	case actual := <-blockCh:
+		require.Equal(t, b.Hash(), actual.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("no new block")
+	}
+	require.Eventually(t, func() bool { return len(headerCh) == 1 && len(executionCh) == 2 }, time.Second, 10*time.Millisecond)
+
+	bc.UnsubscribeFromBlocks(blockCh)
+	bc.UnsubscribeFromHeadersOfAddedBlocks(headerCh)
+	bc.UnsubscribeFromTransactions(txCh)
+	bc.UnsubscribeFromNotifications(notificationCh)
+	bc.UnsubscribeFromExecutions(executionCh)
+	bc.UnsubscribeFromBlocks(refBlockCh)
+	bc.UnsubscribeFromTransactions(refTxCh)
+	bc.UnsubscribeFromNotifications(refNotificationCh)
+	bc.UnsubscribeFromExecutions(refExecutionCh)
+
+	t.Run("unsubscribe while replaying", func(t *testing.T) {
+		ch := make(chan *block.Block)
+		require.NoError(t, bc.SubscribeForBlocksFrom(ch, 0))
+		require.Equal(t, uint32(1), (<-ch).Index)
+		bc.UnsubscribeFromBlocks(ch)
+	})
+
+	// Ensure that new blocks are processed correctly after unsubscription.
+	e.GenerateNewBlocks(t, 2*chBufSize)
+}
+
 func TestBlockchain_RemoveUntraceable(t *testing.T) {
 	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
 	check := func(t *testing.T, bc *core.Blockchain, tHash, bHash, sHash util.Uint256, errorExpected bool) {
@@ -1149,6 +1276,96 @@ func TestBlockchain_RemoveUntraceable(t *testing.T) {
 	})
 }
 
+func TestBlockchain_HistoricRemoveUntraceableBlocks(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.MaxTraceableBlocks = 10
+		c.Ledger.RemoveUntraceableBlocks = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+	receiver := util.Uint160{1, 2, 3}
+
+	neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 1, nil)
+	txHeight := bc.BlockHeight()
+	// Update the value, so that the old one is only available via the old state.
+	neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 1, nil)
+
+	ic, err := bc.GetTestHistoricVM(trigger.Application, nil, txHeight+1)
+	require.NoError(t, err)
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, e.NativeHash(t, nativenames.Neo), "balanceOf", callflag.All, receiver)
+	require.NoError(t, w.Err)
+	ic.VM.LoadScriptWithFlags(w.Bytes(), callflag.All)
+	require.NoError(t, ic.VM.Run())
+	require.Equal(t, 1, ic.VM.Estack().Len())
+	require.Equal(t, big.NewInt(1), ic.VM.Estack().Pop().BigInt())
+}
+
+func TestBlockchain_StateRetention(t *testing.T) {
+	const retention = 4
+	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
+
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			c.Ledger.KeepOnlyLatestState = true
+			c.Ledger.StateRetention = retention
+		}, nil)
+		require.Error(t, err)
+	})
+
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.Ledger.GarbageCollectionPeriod = 2
+		c.Ledger.StateRetention = retention
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+	receiver := util.Uint160{1, 2, 3}
+
+	txHash := neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 1, nil)
+	txHeight := bc.BlockHeight()
+	sRoot, err := bc.GetStateModule().GetStateRoot(txHeight)
+	require.NoError(t, err)
+	// Update the value, so that the old one is only available via the old state.
+	neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 1, nil)
+
+	checkBalance := func(t *testing.T, height uint32, expected int64) error {
+		ic, err := bc.GetTestHistoricVM(trigger.Application, nil, height+1)
+		if err != nil {
+			return err
+		}
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, e.NativeHash(t, nativenames.Neo), "balanceOf", callflag.All, receiver)
+		require.NoError(t, w.Err)
+		ic.VM.LoadScriptWithFlags(w.Bytes(), callflag.All)
+		require.NoError(t, ic.VM.Run())
+		require.Equal(t, 1, ic.VM.Estack().Len())
+		require.Equal(t, big.NewInt(expected), ic.VM.Estack().Pop().BigInt())
+		return nil
+	}
+	require.NoError(t, checkBalance(t, txHeight, 1))
+	require.NoError(t, checkBalance(t, bc.BlockHeight(), 2))
+
+	e.GenerateNewBlocks(t, retention)
+	err = checkBalance(t, txHeight, 1)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "outside of StateRetention window"), err)
+	require.NoError(t, checkBalance(t, bc.BlockHeight()-retention+1, 2))
+
+	// GC is performed once per GarbageCollectionPeriod.
+	e.GenerateNewBlocks(t, 4)
+	sm := bc.GetStateModule()
+	require.Eventually(t, func() bool {
+		_, err = sm.GetState(sRoot.Root, neoCommitteeKey)
+		return err != nil
+	}, 2*bcPersistInterval, 10*time.Millisecond)
+
+	// Blocks and transactions are still there.
+	_, h, err := bc.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, txHeight, h)
+	_, err = bc.GetBlock(bc.GetHeaderHash(txHeight))
+	require.NoError(t, err)
+}
 func TestBlockchain_InvalidNotification(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
@@ -2318,6 +2535,111 @@ func TestBlockchain_Bug1728(t *testing.T) {
 	managementInvoker.DeployContract(t, c, nil)
 }
 
+func TestBlockchain_GenesisContracts(t *testing.T) {
+	src := `package genesiscontract
+	import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+	func _deploy(data any, isUpdate bool) {
+		storage.Put(storage.GetContext(), "data", data)
+	}
+	func Get(key string) any {
+		return storage.Get(storage.GetReadOnlyContext(), key)
+	}`
+	c := neotest.CompileSource(t, util.Uint160{}, strings.NewReader(src), &compiler.Options{Name: "GenesisContract"})
+	dir := t.TempDir()
+	nefPath := filepath.Join(dir, "contract.nef")
+	manifestPath := filepath.Join(dir, "contract.manifest.json")
+	nefBytes, err := c.NEF.Bytes()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(nefPath, nefBytes, os.ModePerm))
+	manifestBytes, err := json.Marshal(c.Manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, manifestBytes, os.ModePerm))
+
+	genesisContract := config.GenesisContract{
+		NEF:      nefPath,
+		Manifest: manifestPath,
+		Data:     &smartcontract.Parameter{Type: smartcontract.StringType, Value: "genesis"},
+		Storage: []config.GenesisStorageItem{
+			{Key: []byte("seed"), Value: []byte("value")},
+		},
+	}
+
+	t.Run("good", func(t *testing.T) {
+		bc, validators, committee := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+			c.ProtocolConfiguration.Genesis.Contracts = []config.GenesisContract{genesisContract}
+		})
+		e := neotest.NewExecutor(t, bc, validators, committee)
+
+		genesis, err := bc.GetBlock(bc.GetHeaderHash(0))
+		require.NoError(t, err)
+		h := state.CreateContractHash(genesis.NextConsensus, c.NEF.Checksum, c.Manifest.Name)
+		cs := bc.GetContractState(h)
+		require.NotNil(t, cs)
+		require.Equal(t, int32(1), cs.ID)
+
+		aers, err := bc.GetAppExecResults(genesis.Hash(), trigger.OnPersist)
+		require.NoError(t, err)
+		var deployed []stackitem.Item
+		for _, ntf := range aers[0].Events {
+			if ntf.ScriptHash == nativehashes.ContractManagement && ntf.Name == "Deploy" {
+				deployed = append(deployed, ntf.Item)
+			}
+		}
+		// Native contracts are deployed first.
+		require.Equal(t, stackitem.NewArray([]stackitem.Item{stackitem.NewByteArray(h.BytesBE())}), deployed[len(deployed)-1])
+
+		inv := e.ValidatorInvoker(h)
+		inv.Invoke(t, "genesis", "get", "data")
+		inv.Invoke(t, "value", "get", "seed")
+
+		// Contracts set is bound to the genesis block.
+		require.Equal(t, 1, len(genesis.Transactions))
+		aers, err = bc.GetAppExecResults(genesis.Transactions[0].Hash(), trigger.Application)
+		require.NoError(t, err)
+		require.Equal(t, vmstate.Halt, aers[0].VMState)
+		require.Equal(t, 1, len(aers[0].Stack))
+		contractsHash, err := aers[0].Stack[0].TryBytes()
+		require.NoError(t, err)
+		require.Equal(t, util.Uint256Size, len(contractsHash))
+
+		other := genesisContract
+		other.Storage = []config.GenesisStorageItem{{Key: []byte("seed"), Value: []byte("other")}}
+		otherBC, _, _ := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+			c.ProtocolConfiguration.Genesis.Contracts = []config.GenesisContract{other}
+		})
+		require.NotEqual(t, genesis.Hash(), otherBC.GetHeaderHash(0))
+		noContractsBC, _, _ := chain.NewMulti(t)
+		require.NotEqual(t, genesis.Hash(), noContractsBC.GetHeaderHash(0))
+
+		cfg := bc.GetConfig()
+		expected, err := core.CreateGenesisBlock(cfg.ProtocolConfiguration)
+		require.NoError(t, err)
+		require.Equal(t, genesis.Hash(), expected.Hash())
+	})
+	t.Run("missing file", func(t *testing.T) {
+		bad := genesisContract
+		bad.NEF = filepath.Join(dir, "unknown.nef")
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			c.ProtocolConfiguration.Genesis.Contracts = []config.GenesisContract{bad}
+		}, storage.NewMemoryStore())
+		require.ErrorContains(t, err, "can't read NEF file")
+	})
+	t.Run("bad storage key", func(t *testing.T) {
+		bad := genesisContract
+		bad.Storage = []config.GenesisStorageItem{{Key: make([]byte, 65), Value: []byte{1}}}
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			c.ProtocolConfiguration.Genesis.Contracts = []config.GenesisContract{bad}
+		}, storage.NewMemoryStore())
+		require.ErrorContains(t, err, "invalid key length")
+	})
+	t.Run("duplicate contract", func(t *testing.T) {
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			c.ProtocolConfiguration.Genesis.Contracts = []config.GenesisContract{genesisContract, genesisContract}
+		}, storage.NewMemoryStore())
+		require.ErrorContains(t, err, "contract already exists")
+	})
+}
+
 func TestBlockchain_ResetStateErrors(t *testing.T) {
 	chainHeight := 3
 	checkResetErr := func(t *testing.T, cfg func(c *config.Blockchain), h uint32, errText string) {
@@ -2620,3 +2942,330 @@ func TestBlockchain_StoreAsTransaction_ExecutableConflict(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, len(aer))
 }
+
+type testTracer struct {
+	enter    []util.Uint160
+	exit     int
+	gas      map[*interop.Context]int64
+	syscalls []string
+	puts     map[string][]byte
+	gets     map[string][]byte
+	executed []*state.AppExecResult
+	execGas  []int64
+}
+
+func (t *testTracer) OnEnter(_ *interop.Context, ctx *vm.Context) {
+	t.enter = append(t.enter, ctx.ScriptHash())
+}
+
+func (t *testTracer) OnExit(_ *interop.Context, _ *vm.Context, _ bool) {
+	t.exit++
+}
+
+func (t *testTracer) OnGas(ic *interop.Context, gas int64) {
+	t.gas[ic] += gas
+}
+
+func (t *testTracer) OnSyscall(_ *interop.Context, f *interop.Function) {
+	t.syscalls = append(t.syscalls, f.Name)
+}
+
+func (t *testTracer) OnStorageGet(_ *interop.Context, _ int32, key []byte, value []byte) {
+	t.gets[string(key)] = value
+}
+
+func (t *testTracer) OnStoragePut(_ *interop.Context, _ int32, key []byte, value []byte) {
+	t.puts[string(key)] = value
+}
+
+func (t *testTracer) OnExecuted(ic *interop.Context, aer *state.AppExecResult) {
+	t.executed = append(t.executed, aer)
+	t.execGas = append(t.execGas, t.gas[ic])
+}
+
+func TestBlockchain_Tracer(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package tracer
+	import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+	func Put(v []byte) {
+		storage.Put(storage.GetContext(), "key", v)
+	}
+	func Get() []byte {
+		return storage.Get(storage.GetReadOnlyContext(), "key").([]byte)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Tracer"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+
+	tr := &testTracer{
+		gas:  make(map[*interop.Context]int64),
+		puts: make(map[string][]byte),
+		gets: make(map[string][]byte),
+	}
+	bc.SetTracer(tr)
+	txPut := inv.PrepareInvoke(t, "put", []byte{1, 2, 3})
+	txGet := inv.PrepareInvoke(t, "get")
+	txFault := inv.PrepareInvoke(t, "unknown")
+	e.AddNewBlock(t, txPut, txGet, txFault)
+	e.CheckHalt(t, txPut.Hash())
+	e.CheckHalt(t, txGet.Hash(), stackitem.NewBuffer([]byte{1, 2, 3}))
+	e.CheckFault(t, txFault.Hash(), "method not found")
+
+	// OnPersist, three transactions and PostPersist.
+	require.Equal(t, 5, len(tr.executed))
+	require.Equal(t, trigger.OnPersist, tr.executed[0].Trigger)
+	require.Equal(t, txPut.Hash(), tr.executed[1].Container)
+	require.Equal(t, txGet.Hash(), tr.executed[2].Container)
+	require.Equal(t, txFault.Hash(), tr.executed[3].Container)
+	require.Equal(t, trigger.PostPersist, tr.executed[4].Trigger)
+	for i, aer := range tr.executed {
+		require.Equal(t, aer.GasConsumed, tr.execGas[i])
+	}
+	require.Contains(t, tr.enter, c.Hash)
+	require.Contains(t, tr.syscalls, interopnames.SystemContractCall)
+	require.Contains(t, tr.syscalls, interopnames.SystemStoragePut)
+	require.Contains(t, tr.syscalls, interopnames.SystemStorageGet)
+	require.Equal(t, map[string][]byte{"key": {1, 2, 3}}, tr.puts)
+	require.Equal(t, map[string][]byte{"key": {1, 2, 3}}, tr.gets)
+
+	// Disabled tracer gets nothing.
+	bc.SetTracer(nil)
+	e.AddNewBlock(t)
+	require.Equal(t, 5, len(tr.executed))
+}
+
+func TestBlockchain_ReplayTransaction(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package tracer
+	import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+	func Put(v []byte) {
+		storage.Put(storage.GetContext(), "key", v)
+	}
+	func Get() []byte {
+		return storage.Get(storage.GetReadOnlyContext(), "key").([]byte)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Tracer"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+
+	txPut := inv.PrepareInvoke(t, "put", []byte{1, 2, 3})
+	txGet := inv.PrepareInvoke(t, "get")
+	txFault := inv.PrepareInvoke(t, "unknown")
+	e.AddNewBlock(t, txPut, txGet, txFault)
+	inv.Invoke(t, stackitem.Null{}, "put", []byte{4, 5, 6})
+
+	tr := &testTracer{
+		gas:  make(map[*interop.Context]int64),
+		puts: make(map[string][]byte),
+		gets: make(map[string][]byte),
+	}
+	// Preceding transactions of the same block are applied.
+	aer, err := bc.ReplayTransaction(txGet.Hash(), tr)
+	require.NoError(t, err)
+	expected, err := bc.GetAppExecResults(txGet.Hash(), trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, expected[0].VMState, aer.VMState)
+	require.Equal(t, expected[0].GasConsumed, aer.GasConsumed)
+	require.Equal(t, []stackitem.Item{stackitem.NewBuffer([]byte{1, 2, 3})}, aer.Stack)
+	require.Equal(t, 1, len(tr.executed))
+	require.Equal(t, map[string][]byte{"key": {1, 2, 3}}, tr.gets)
+	require.Equal(t, 0, len(tr.puts))
+
+	aer, err = bc.ReplayTransaction(txFault.Hash(), nil)
+	require.NoError(t, err)
+	expected, err = bc.GetAppExecResults(txFault.Hash(), trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, vmstate.Fault, aer.VMState)
+	require.Equal(t, expected[0].GasConsumed, aer.GasConsumed)
+	require.Equal(t, expected[0].FaultException, aer.FaultException)
+
+	_, err = bc.ReplayTransaction(util.Uint256{1, 2, 3}, nil)
+	require.Error(t, err)
+}
+
+func TestBlockchain_Simulate(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package simulated
+	import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+	func Put(v []byte) {
+		storage.Put(storage.GetContext(), "key", v)
+	}
+	func Get() []byte {
+		return storage.Get(storage.GetReadOnlyContext(), "key").([]byte)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Simulated"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+	inv.Invoke(t, stackitem.Null{}, "put", []byte{1})
+	cs := bc.GetContractState(c.Hash)
+	require.NotNil(t, cs)
+	h := bc.BlockHeight()
+
+	txPut := inv.PrepareInvoke(t, "put", []byte{2})
+	txGet := inv.PrepareInvoke(t, "get")
+	t.Run("transactions", func(t *testing.T) {
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       h,
+			Transactions: []*transaction.Transaction{txPut, txGet},
+		})
+		require.NoError(t, err)
+		require.Equal(t, h+1, res.Block.Index)
+		require.Equal(t, 2, len(res.Executions))
+		require.Equal(t, txPut.Hash(), res.Executions[0].Container)
+		require.Equal(t, vmstate.Halt, res.Executions[0].VMState)
+		require.Equal(t, txGet.Hash(), res.Executions[1].Container)
+		require.Equal(t, []stackitem.Item{stackitem.NewBuffer([]byte{2})}, res.Executions[1].Stack)
+		require.Equal(t, []core.SimulatedStorageChange{{
+			ID:       cs.ID,
+			Hash:     c.Hash,
+			Key:      []byte("key"),
+			OldValue: []byte{1},
+			NewValue: []byte{2},
+		}}, res.Changes)
+
+		// Nothing is changed.
+		require.Equal(t, h, bc.BlockHeight())
+		require.Equal(t, state.StorageItem{1}, bc.GetStorageItem(cs.ID, []byte("key")))
+	})
+	t.Run("storage and timestamp", func(t *testing.T) {
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       h,
+			Timestamp:    123,
+			Transactions: []*transaction.Transaction{txGet},
+			Storage:      []core.SimulatedStorageItem{{Hash: c.Hash, Key: []byte("key"), Value: []byte{5}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(123), res.Block.Timestamp)
+		require.Equal(t, []stackitem.Item{stackitem.NewBuffer([]byte{5})}, res.Executions[0].Stack)
+		require.Empty(t, res.Changes)
+	})
+	t.Run("historic", func(t *testing.T) {
+		// New item is more expensive than the txPut system fee.
+		tx := e.SignTx(t, inv.PrepareInvokeNoSign(t, "put", []byte{2}), 1_0000_0000, inv.Signers...)
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       h - 1,
+			Transactions: []*transaction.Transaction{tx},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vmstate.Halt, res.Executions[0].VMState)
+		// No value stored yet.
+		require.Equal(t, []core.SimulatedStorageChange{{
+			ID:       cs.ID,
+			Hash:     c.Hash,
+			Key:      []byte("key"),
+			NewValue: []byte{2},
+		}}, res.Changes)
+	})
+	t.Run("contract", func(t *testing.T) {
+		src := `package simulated
+		func Get() []byte {
+			return []byte{7}
+		}`
+		upd := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Simulated"})
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       h,
+			Transactions: []*transaction.Transaction{txGet},
+			Contracts:    []core.SimulatedContract{{Hash: c.Hash, NEF: upd.NEF, Manifest: upd.Manifest}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.NewBuffer([]byte{7})}, res.Executions[0].Stack)
+		require.Equal(t, cs, bc.GetContractState(c.Hash))
+
+		_, err = bc.Simulate(&core.Simulation{
+			Height:       h,
+			Transactions: []*transaction.Transaction{txGet},
+			Contracts:    []core.SimulatedContract{{Hash: nativehashes.GasToken, NEF: upd.NEF}},
+		})
+		require.Error(t, err)
+	})
+	t.Run("full block", func(t *testing.T) {
+		sender := txGet.Sender()
+		_, err := bc.Simulate(&core.Simulation{
+			Height:       h,
+			FullBlock:    true,
+			Transactions: []*transaction.Transaction{txGet},
+			GASBalances:  map[util.Uint160]*big.Int{sender: big.NewInt(0)},
+		})
+		require.Error(t, err) // Can't pay fees.
+
+		balance := big.NewInt(100_0000_0000)
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       h,
+			FullBlock:    true,
+			Transactions: []*transaction.Transaction{txGet},
+			GASBalances:  map[util.Uint160]*big.Int{sender: balance},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, len(res.Executions))
+		require.Equal(t, trigger.OnPersist, res.Executions[0].Trigger)
+		require.Equal(t, []stackitem.Item{stackitem.NewBuffer([]byte{1})}, res.Executions[1].Stack)
+		require.Equal(t, trigger.PostPersist, res.Executions[2].Trigger)
+		var found bool
+		for _, ch := range res.Changes {
+			if ch.Hash == nativehashes.GasToken && bytes.Equal(ch.Key, append([]byte{20}, sender.BytesBE()...)) {
+				oldB, err := state.NEP17BalanceFromBytes(ch.OldValue)
+				require.NoError(t, err)
+				require.Equal(t, 0, balance.Cmp(&oldB.Balance))
+				newB, err := state.NEP17BalanceFromBytes(ch.NewValue)
+				require.NoError(t, err)
+				expected := new(big.Int).Sub(balance, big.NewInt(txGet.SystemFee+txGet.NetworkFee))
+				require.Equal(t, 0, expected.Cmp(&newB.Balance))
+				found = true
+			}
+		}
+		require.True(t, found)
+	})
+	t.Run("unknown height", func(t *testing.T) {
+		_, err := bc.Simulate(&core.Simulation{
+			Height:       h + 1,
+			Transactions: []*transaction.Transaction{txGet},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestBlockchain_SimulateStateAvailability(t *testing.T) {
+	check := func(t *testing.T, f func(*config.Blockchain), blocks int, okHeight, badHeight uint32, errText string) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, f)
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.GenerateNewBlocks(t, blocks)
+		tx := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo)).PrepareInvoke(t, "symbol")
+
+		res, err := bc.Simulate(&core.Simulation{
+			Height:       okHeight,
+			Transactions: []*transaction.Transaction{tx},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vmstate.Halt, res.Executions[0].VMState)
+		require.Equal(t, []stackitem.Item{stackitem.Make("NEO")}, res.Executions[0].Stack)
+
+		_, err = bc.Simulate(&core.Simulation{
+			Height:       badHeight,
+			Transactions: []*transaction.Transaction{tx},
+		})
+		require.ErrorContains(t, err, errText)
+	}
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		check(t, func(c *config.Blockchain) {
+			c.Ledger.KeepOnlyLatestState = true
+		}, 3, 3, 2, "only latest state is supported")
+	})
+	t.Run("RemoveUntraceableBlocks", func(t *testing.T) {
+		check(t, func(c *config.Blockchain) {
+			c.MaxTraceableBlocks = 2
+			c.Ledger.RemoveUntraceableBlocks = true
+		}, 5, 4, 1, "is outdated and removed from the storage")
+	})
+	t.Run("StateRetention", func(t *testing.T) {
+		check(t, func(c *config.Blockchain) {
+			c.Ledger.StateRetention = 2
+			c.Ledger.GarbageCollectionPeriod = 2
+		}, 5, 4, 2, "is outside of StateRetention window")
+	})
+}
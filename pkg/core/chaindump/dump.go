@@ -1,6 +1,7 @@
 package chaindump
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
@@ -41,39 +42,25 @@ func Dump(bc DumperRestorer, w *io.BinWriter, start, count uint32) error {
 // Restore restores blocks from the provided reader.
 // f is called after addition of every block.
 func Restore(bc DumperRestorer, r *io.BinReader, skip, count uint32, f func(b *block.Block) error) error {
-	readBlock := func(r *io.BinReader) ([]byte, error) {
-		var size = r.ReadU32LE()
-		buf := make([]byte, size)
-		r.ReadBytes(buf)
-		return buf, r.Err
-	}
+	return NewImporter(bc, 1, f).Restore(r, skip, count)
+}
 
-	i := uint32(0)
-	for ; i < skip; i++ {
-		_, err := readBlock(r)
-		if err != nil {
-			return err
-		}
+// Copy adds count blocks starting from start taken from src to dst. It's
+// used to resynchronize node from another local database, so blocks are
+// processed by dst in a regular manner with its own settings. f is called
+// after addition of every block.
+func Copy(src, dst DumperRestorer, start, count uint32, f func(b *block.Block) error) error {
+	if src.GetHeaderHash(0) != dst.GetHeaderHash(0) {
+		return errors.New("genesis block mismatch")
 	}
-
-	stateRootInHeader := bc.GetConfig().StateRootInHeader
-
-	for ; i < skip+count; i++ {
-		buf, err := readBlock(r)
+	for i := start; i < start+count; i++ {
+		b, err := src.GetBlock(src.GetHeaderHash(i))
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to get block %d: %w", i, err)
 		}
-		b := block.New(stateRootInHeader)
-		r := io.NewBinReaderFromBuf(buf)
-		b.DecodeBinary(r)
-		if r.Err != nil {
-			return r.Err
-		}
-		if b.Index != 0 || i != 0 || skip != 0 {
-			err = bc.AddBlock(b)
-			if err != nil {
-				return fmt.Errorf("failed to add block %d: %w", i, err)
-			}
+		err = dst.AddBlock(b)
+		if err != nil {
+			return fmt.Errorf("failed to add block %d: %w", i, err)
 		}
 		if f != nil {
 			if err := f(b); err != nil {
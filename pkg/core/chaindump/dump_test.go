@@ -39,6 +39,35 @@ func TestBlockchain_DumpAndRestore(t *testing.T) {
 	})
 }
 
+func TestCopy(t *testing.T) {
+	bc, validators, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	for i := 0; i < 5; i++ {
+		e.AddNewBlock(t)
+	}
+
+	t.Run("genesis mismatch", func(t *testing.T) {
+		bc2, _, _ := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+			c.StateRootInHeader = true
+		})
+		require.Error(t, chaindump.Copy(bc, bc2, 1, 1, nil))
+	})
+	t.Run("good", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		require.Error(t, chaindump.Copy(bc, bc2, 2, 1, nil))
+		require.NoError(t, chaindump.Copy(bc, bc2, 1, 2, nil))
+		require.Equal(t, uint32(2), bc2.BlockHeight())
+
+		var lastIndex uint32
+		require.NoError(t, chaindump.Copy(bc, bc2, 3, bc.BlockHeight()-2, func(b *block.Block) error {
+			lastIndex = b.Index
+			return nil
+		}))
+		require.Equal(t, bc.BlockHeight(), lastIndex)
+		require.Equal(t, bc.GetHeaderHash(bc.BlockHeight()), bc2.GetHeaderHash(bc2.BlockHeight()))
+	})
+}
+
 func testDumpAndRestore(t *testing.T, dumpF, restoreF func(c *config.Blockchain)) {
 	if restoreF == nil {
 		restoreF = dumpF
@@ -0,0 +1,226 @@
+package chaindump
+
+import (
+	"errors"
+	"fmt"
+	stdio "io"
+	"sync"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+)
+
+// Preverifier is an optional interface of DumperRestorer allowing to check
+// some block data before the block is added. PreverifyBlock can be called
+// concurrently for blocks that are yet to be added.
+type Preverifier interface {
+	PreverifyBlock(b *block.Block)
+}
+
+// Importer adds blocks from dumps to the chain. Blocks are always added in
+// order, but if more than one worker is used, they're decoded and
+// preverified (if the chain implements Preverifier) ahead of time by worker
+// goroutines.
+type Importer struct {
+	bc      DumperRestorer
+	workers int
+	f       func(b *block.Block) error
+}
+
+// importJob is a single block processed by the Importer.
+type importJob struct {
+	data  []byte
+	index uint32
+	b     *block.Block
+	err   error
+	done  chan struct{}
+}
+
+// NewImporter creates an Importer for the given chain using the given number
+// of workers, blocks are processed sequentially if it's less than 2. f is
+// called after addition of every block.
+func NewImporter(bc DumperRestorer, workers int, f func(b *block.Block) error) *Importer {
+	return &Importer{
+		bc:      bc,
+		workers: workers,
+		f:       f,
+	}
+}
+
+// Restore restores blocks from the provided v1 dump reader, see Restore.
+func (imp *Importer) Restore(r *io.BinReader, skip, count uint32) error {
+	readBlock := func(r *io.BinReader) ([]byte, error) {
+		var size = r.ReadU32LE()
+		buf := make([]byte, size)
+		r.ReadBytes(buf)
+		return buf, r.Err
+	}
+
+	i := uint32(0)
+	for ; i < skip; i++ {
+		_, err := readBlock(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	stateRootInHeader := imp.bc.GetConfig().StateRootInHeader
+	next := func() ([]byte, uint32, error) {
+		if i == skip+count {
+			return nil, 0, stdio.EOF
+		}
+		buf, err := readBlock(r)
+		i++
+		return buf, i - 1, err
+	}
+	decode := func(buf []byte, _ uint32) (*block.Block, error) {
+		b := block.New(stateRootInHeader)
+		r := io.NewBinReaderFromBuf(buf)
+		b.DecodeBinary(r)
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return b, nil
+	}
+	add := func(b *block.Block, i uint32) error {
+		if b.Index != 0 || i != 0 || skip != 0 {
+			err := imp.bc.AddBlock(b)
+			if err != nil {
+				return fmt.Errorf("failed to add block %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+	return imp.run(next, decode, add)
+}
+
+// RestoreV2 restores blocks from the provided v2 dump reader, see RestoreV2.
+func (imp *Importer) RestoreV2(r *Reader, count uint32) error {
+	if m := imp.bc.GetConfig().Magic; m != r.Magic() {
+		return fmt.Errorf("dump network magic %d doesn't match %d", r.Magic(), m)
+	}
+	var n uint32
+	next := func() ([]byte, uint32, error) {
+		if count != 0 && n == count {
+			return nil, 0, stdio.EOF
+		}
+		data, index, err := r.rawBlock()
+		if errors.Is(err, stdio.EOF) && count != 0 {
+			return nil, 0, fmt.Errorf("dump ended at block %d", index)
+		}
+		n++
+		return data, index, err
+	}
+	add := func(b *block.Block, _ uint32) error {
+		if b.Index != 0 {
+			err := imp.bc.AddBlock(b)
+			if err != nil {
+				return fmt.Errorf("failed to add block %d: %w", b.Index, err)
+			}
+		}
+		return nil
+	}
+	return imp.run(next, r.decodeBlock, add)
+}
+
+// run processes blocks returned by next until it returns io.EOF.
+func (imp *Importer) run(next func() ([]byte, uint32, error), decode func([]byte, uint32) (*block.Block, error),
+	add func(*block.Block, uint32) error) error {
+	process := func(j *importJob) error {
+		if err := add(j.b, j.index); err != nil {
+			return err
+		}
+		if imp.f != nil {
+			return imp.f(j.b)
+		}
+		return nil
+	}
+	if imp.workers < 2 {
+		for {
+			data, index, err := next()
+			if err != nil {
+				if errors.Is(err, stdio.EOF) {
+					return nil
+				}
+				return err
+			}
+			j := &importJob{index: index}
+			j.b, err = decode(data, index)
+			if err != nil {
+				return err
+			}
+			if err := process(j); err != nil {
+				return err
+			}
+		}
+	}
+
+	var (
+		pv, _ = imp.bc.(Preverifier)
+		jobs  = make(chan *importJob, imp.workers)
+		// queue keeps the order of blocks and limits the number of
+		// blocks processed ahead of time.
+		queue = make(chan *importJob, 2*imp.workers)
+		stop  = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < imp.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.b, j.err = decode(j.data, j.index)
+				if j.err == nil && pv != nil {
+					pv.PreverifyBlock(j.b)
+				}
+				j.data = nil
+				close(j.done)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		defer close(queue)
+		for {
+			data, index, err := next()
+			j := &importJob{data: data, index: index, err: err, done: make(chan struct{})}
+			if err != nil {
+				if errors.Is(err, stdio.EOF) {
+					return
+				}
+				close(j.done)
+				select {
+				case queue <- j:
+				case <-stop:
+				}
+				return
+			}
+			select {
+			case queue <- j:
+			case <-stop:
+				return
+			}
+			select {
+			case jobs <- j:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var err error
+	for j := range queue {
+		<-j.done
+		if err = j.err; err == nil {
+			err = process(j)
+		}
+		if err != nil {
+			break
+		}
+	}
+	close(stop)
+	wg.Wait()
+	return err
+}
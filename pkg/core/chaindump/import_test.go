@@ -0,0 +1,110 @@
+package chaindump
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// countingChain counts PreverifyBlock calls.
+type countingChain struct {
+	*core.Blockchain
+	preverified atomic.Int32
+}
+
+func (c *countingChain) PreverifyBlock(b *block.Block) {
+	c.preverified.Add(1)
+	c.Blockchain.PreverifyBlock(b)
+}
+
+func TestImporter(t *testing.T) {
+	bc, validators, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	for i := 0; i < 20; i++ {
+		gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	}
+	count := bc.BlockHeight() + 1
+
+	w := io.NewBufBinWriter()
+	require.NoError(t, Dump(bc, w.BinWriter, 0, count))
+	require.NoError(t, w.Err)
+	v1 := w.Bytes()
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, DumpV2(bc, buf, 0, count))
+	v2 := buf.Bytes()
+
+	checkChain := func(t *testing.T, bc2 *core.Blockchain) {
+		require.Equal(t, bc.BlockHeight(), bc2.BlockHeight())
+		for i := uint32(0); i <= bc.BlockHeight(); i++ {
+			require.Equal(t, bc.GetHeaderHash(i), bc2.GetHeaderHash(i))
+		}
+	}
+	t.Run("v1", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		c := &countingChain{Blockchain: bc2}
+		var lastIndex uint32
+		imp := NewImporter(c, 4, func(b *block.Block) error {
+			require.Equal(t, lastIndex+1, b.Index)
+			lastIndex = b.Index
+			return nil
+		})
+		require.NoError(t, imp.Restore(io.NewBinReaderFromBuf(v1), 1, count-1))
+		checkChain(t, bc2)
+		require.Equal(t, int32(count-1), c.preverified.Load())
+	})
+	t.Run("v2", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		r, err := NewReader(bytes.NewReader(v2), bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		require.NoError(t, NewImporter(bc2, 4, nil).RestoreV2(r, 5))
+		require.Equal(t, uint32(4), bc2.BlockHeight())
+		require.NoError(t, NewImporter(bc2, 3, nil).RestoreV2(r, 0))
+		checkChain(t, bc2)
+	})
+	t.Run("sequential", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		c := &countingChain{Blockchain: bc2}
+		require.NoError(t, NewImporter(c, 1, nil).Restore(io.NewBinReaderFromBuf(v1), 0, count))
+		checkChain(t, bc2)
+		require.Equal(t, int32(0), c.preverified.Load())
+	})
+	t.Run("handler error", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		errStop := errors.New("stop")
+		imp := NewImporter(bc2, 4, func(b *block.Block) error {
+			if b.Index == 7 {
+				return errStop
+			}
+			return nil
+		})
+		require.ErrorIs(t, imp.Restore(io.NewBinReaderFromBuf(v1), 0, count), errStop)
+		require.Equal(t, uint32(7), bc2.BlockHeight())
+	})
+	t.Run("truncated", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		require.Error(t, NewImporter(bc2, 4, nil).Restore(io.NewBinReaderFromBuf(v1[:len(v1)/2]), 0, count))
+		require.True(t, bc2.BlockHeight() < bc.BlockHeight())
+	})
+	t.Run("corrupted block", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		r, err := NewReader(bytes.NewReader(v2), bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		// Break some witness, the block is still decodable.
+		require.NoError(t, r.Seek(0))
+		r.blocks[10] = bytes.Clone(r.blocks[10])
+		r.blocks[10][len(r.blocks[10])-10] ^= 0xff
+		require.Error(t, NewImporter(bc2, 4, nil).RestoreV2(r, 0))
+		require.Equal(t, uint32(9), bc2.BlockHeight())
+	})
+}
@@ -0,0 +1,517 @@
+package chaindump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	stdio "io"
+	"sort"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/pierrec/lz4"
+)
+
+// Dump format v2 layout (all integers are little-endian):
+//
+//	header: magic "NGD2" | network magic u32 | first block index u32
+//	chunks: type u8 | first block index u32 | block count u32 |
+//	        payload size u32 | raw size u32 | payload CRC32-C u32 | payload
+//	end:    type u8 (chunkEnd)
+//	index:  chunk count u32 | (first block index u32 | block count u32 |
+//	        chunk offset u64) * chunk count | index CRC32-C u32
+//	footer: index offset u64 | magic "NGDI"
+//
+// Raw chunk payload is a sequence of blocks, every block is prefixed with its
+// size u32 and its CRC32-C u32. Payload is either raw or lz4-compressed
+// depending on the chunk type. The index allows to find a chunk containing any
+// block without reading the whole file, but the file can also be read
+// sequentially (from a pipe, for example) up to the end marker.
+
+// DumpV2Magic is the magic prefix of dump files in v2 format.
+var DumpV2Magic = [4]byte{'N', 'G', 'D', '2'}
+
+// ErrCorruptedDump is returned when dump file checksums don't match its
+// contents or the file has invalid structure.
+var ErrCorruptedDump = errors.New("corrupted dump")
+
+var dumpV2IndexMagic = [4]byte{'N', 'G', 'D', 'I'}
+
+const (
+	chunkEnd byte = iota
+	chunkRaw
+	chunkLZ4
+)
+
+// dumpV2ChunkSize is the (uncompressed) size of the chunk after which it's
+// written to the file.
+var dumpV2ChunkSize = 1 << 20
+
+const (
+	// dumpV2MaxChunkSize is the maximum (uncompressed) chunk size accepted
+	// by Reader.
+	dumpV2MaxChunkSize = 256 << 20
+	dumpV2HeaderSize   = 12
+	dumpV2FooterSize   = 12
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type chunkInfo struct {
+	first  uint32
+	count  uint32
+	offset uint64
+}
+
+// countingWriter tracks the current offset in the output.
+type countingWriter struct {
+	w   stdio.Writer
+	off uint64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.off += uint64(n)
+	return n, err
+}
+
+// DumpV2 writes count blocks from start to the provided writer in v2 format.
+// Unlike Dump, it writes the complete file including header.
+func DumpV2(bc DumperRestorer, w stdio.Writer, start, count uint32) error {
+	var (
+		cw    = &countingWriter{w: w}
+		bw    = io.NewBinWriterFromIO(cw)
+		raw   = bytes.NewBuffer(nil)
+		first = start
+		n     uint32
+		index []chunkInfo
+		hdr   [8]byte
+	)
+	bw.WriteBytes(DumpV2Magic[:])
+	bw.WriteU32LE(uint32(bc.GetConfig().Magic))
+	bw.WriteU32LE(start)
+	flush := func() error {
+		if n == 0 {
+			return nil
+		}
+		var (
+			payload = raw.Bytes()
+			typ     = chunkRaw
+			buf     = make([]byte, lz4.CompressBlockBound(len(payload)))
+		)
+		size, err := lz4.CompressBlock(payload, buf, nil)
+		if err != nil {
+			return err
+		}
+		// Zero size means the data is not compressible.
+		if size != 0 && size < len(payload) {
+			payload = buf[:size]
+			typ = chunkLZ4
+		}
+		index = append(index, chunkInfo{first: first, count: n, offset: cw.off})
+		bw.WriteB(typ)
+		bw.WriteU32LE(first)
+		bw.WriteU32LE(n)
+		bw.WriteU32LE(uint32(len(payload)))
+		bw.WriteU32LE(uint32(raw.Len()))
+		bw.WriteU32LE(crc32.Checksum(payload, crcTable))
+		bw.WriteBytes(payload)
+		first += n
+		n = 0
+		raw.Reset()
+		return bw.Err
+	}
+	for i := start; i < start+count; i++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(i))
+		if err != nil {
+			return err
+		}
+		buf := io.NewBufBinWriter()
+		b.EncodeBinary(buf.BinWriter)
+		if buf.Err != nil {
+			return buf.Err
+		}
+		data := buf.Bytes()
+		binary.LittleEndian.PutUint32(hdr[:4], uint32(len(data)))
+		binary.LittleEndian.PutUint32(hdr[4:], crc32.Checksum(data, crcTable))
+		raw.Write(hdr[:])
+		raw.Write(data)
+		n++
+		if raw.Len() >= dumpV2ChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	bw.WriteB(chunkEnd)
+
+	indexOffset := cw.off
+	ib := io.NewBufBinWriter()
+	ib.WriteU32LE(uint32(len(index)))
+	for _, c := range index {
+		ib.WriteU32LE(c.first)
+		ib.WriteU32LE(c.count)
+		ib.WriteU64LE(c.offset)
+	}
+	idx := ib.Bytes()
+	bw.WriteBytes(idx)
+	bw.WriteU32LE(crc32.Checksum(idx, crcTable))
+	bw.WriteU64LE(indexOffset)
+	bw.WriteBytes(dumpV2IndexMagic[:])
+	return bw.Err
+}
+
+// IsDumpV2 checks whether the given file prefix is the one of v2 dump.
+func IsDumpV2(prefix []byte) bool {
+	return len(prefix) >= len(DumpV2Magic) && bytes.Equal(prefix[:len(DumpV2Magic)], DumpV2Magic[:])
+}
+
+// Reader reads blocks from dumps in v2 format. Blocks are read sequentially,
+// but if the underlying reader implements io.Seeker, Reader can also jump to
+// any block using the dump index.
+type Reader struct {
+	stateRootInHeader bool
+	src               stdio.Reader
+	br                *bufio.Reader
+	magic             netmode.Magic
+	start             uint32
+
+	index []chunkInfo
+	// Blocks of the current chunk.
+	blocks [][]byte
+	next   uint32
+	ended  bool
+}
+
+// NewReader reads the dump header from r and returns a Reader positioned at
+// the first block of the dump.
+func NewReader(r stdio.Reader, stateRootInHeader bool) (*Reader, error) {
+	d := &Reader{
+		stateRootInHeader: stateRootInHeader,
+		src:               r,
+		br:                bufio.NewReader(r),
+	}
+	var hdr [dumpV2HeaderSize]byte
+	if _, err := stdio.ReadFull(d.br, hdr[:]); err != nil {
+		return nil, fmt.Errorf("failed to read dump header: %w", err)
+	}
+	if !IsDumpV2(hdr[:]) {
+		return nil, errors.New("not a v2 dump")
+	}
+	d.magic = netmode.Magic(binary.LittleEndian.Uint32(hdr[4:]))
+	d.start = binary.LittleEndian.Uint32(hdr[8:])
+	d.next = d.start
+	return d, nil
+}
+
+// Magic returns the network magic of the chain the dump was made from.
+func (d *Reader) Magic() netmode.Magic {
+	return d.magic
+}
+
+// Start returns the index of the first block in the dump.
+func (d *Reader) Start() uint32 {
+	return d.start
+}
+
+// Next returns the index of the block that is to be returned by the next
+// Block call.
+func (d *Reader) Next() uint32 {
+	return d.next
+}
+
+// Block reads the next block from the dump, io.EOF is returned if there are
+// no more blocks.
+func (d *Reader) Block() (*block.Block, error) {
+	data, index, err := d.rawBlock()
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeBlock(data, index)
+}
+
+// rawBlock returns the next serialized block and its expected index.
+func (d *Reader) rawBlock() ([]byte, uint32, error) {
+	if len(d.blocks) == 0 {
+		if err := d.readChunk(); err != nil {
+			return nil, d.next, err
+		}
+	}
+	data, index := d.blocks[0], d.next
+	d.blocks = d.blocks[1:]
+	d.next++
+	return data, index, nil
+}
+
+// decodeBlock decodes the block returned by rawBlock, it can be called
+// concurrently.
+func (d *Reader) decodeBlock(data []byte, index uint32) (*block.Block, error) {
+	b := block.New(d.stateRootInHeader)
+	r := io.NewBinReaderFromBuf(data)
+	b.DecodeBinary(r)
+	if r.Err != nil {
+		return nil, fmt.Errorf("%w: failed to decode block %d: %s", ErrCorruptedDump, index, r.Err)
+	}
+	if b.Index != index {
+		return nil, fmt.Errorf("%w: expected block %d, got %d", ErrCorruptedDump, index, b.Index)
+	}
+	return b, nil
+}
+
+// readChunk reads the next chunk, checks its checksums and splits it into
+// blocks.
+func (d *Reader) readChunk() error {
+	if d.ended {
+		return stdio.EOF
+	}
+	var hdr [21]byte
+	if _, err := stdio.ReadFull(d.br, hdr[:1]); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	if hdr[0] == chunkEnd {
+		d.ended = true
+		return stdio.EOF
+	}
+	if _, err := stdio.ReadFull(d.br, hdr[1:]); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	var (
+		typ     = hdr[0]
+		first   = binary.LittleEndian.Uint32(hdr[1:])
+		count   = binary.LittleEndian.Uint32(hdr[5:])
+		size    = binary.LittleEndian.Uint32(hdr[9:])
+		rawSize = binary.LittleEndian.Uint32(hdr[13:])
+		crc     = binary.LittleEndian.Uint32(hdr[17:])
+	)
+	if (typ != chunkRaw && typ != chunkLZ4) || count == 0 || size > dumpV2MaxChunkSize || rawSize > dumpV2MaxChunkSize {
+		return fmt.Errorf("%w: invalid chunk header at block %d", ErrCorruptedDump, d.next)
+	}
+	if first != d.next {
+		return fmt.Errorf("%w: expected chunk starting at %d, got %d", ErrCorruptedDump, d.next, first)
+	}
+	payload := make([]byte, size)
+	if _, err := stdio.ReadFull(d.br, payload); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	if crc32.Checksum(payload, crcTable) != crc {
+		return fmt.Errorf("%w: chunk %d checksum mismatch", ErrCorruptedDump, first)
+	}
+	if typ == chunkLZ4 {
+		raw := make([]byte, rawSize)
+		n, err := lz4.UncompressBlock(payload, raw)
+		if err != nil || n != len(raw) {
+			return fmt.Errorf("%w: failed to decompress chunk %d", ErrCorruptedDump, first)
+		}
+		payload = raw
+	}
+	blocks := make([][]byte, 0, count)
+	for i := uint32(0); i < count; i++ {
+		if len(payload) < 8 {
+			return fmt.Errorf("%w: chunk %d is truncated", ErrCorruptedDump, first)
+		}
+		var (
+			l = binary.LittleEndian.Uint32(payload)
+			c = binary.LittleEndian.Uint32(payload[4:])
+		)
+		payload = payload[8:]
+		if uint32(len(payload)) < l {
+			return fmt.Errorf("%w: chunk %d is truncated", ErrCorruptedDump, first)
+		}
+		if crc32.Checksum(payload[:l], crcTable) != c {
+			return fmt.Errorf("%w: block %d checksum mismatch", ErrCorruptedDump, first+i)
+		}
+		blocks = append(blocks, payload[:l])
+		payload = payload[l:]
+	}
+	if len(payload) != 0 {
+		return fmt.Errorf("%w: chunk %d has trailing data", ErrCorruptedDump, first)
+	}
+	d.blocks = blocks
+	return nil
+}
+
+// Count returns the number of blocks in the dump, it reads the dump index, so
+// the underlying reader must implement io.Seeker. Reader position is changed,
+// so Seek must be used after this call to read blocks.
+func (d *Reader) Count() (uint32, error) {
+	if err := d.loadIndex(); err != nil {
+		return 0, err
+	}
+	if len(d.index) == 0 {
+		return 0, nil
+	}
+	last := d.index[len(d.index)-1]
+	return last.first + last.count - d.start, nil
+}
+
+// Seek positions the Reader at the block with the given index. If the
+// underlying reader implements io.Seeker, the dump index is used and only the
+// chunk containing the block is read. Otherwise, only forward seeks are
+// possible and all chunks before the block are read (but blocks are not
+// decoded).
+func (d *Reader) Seek(index uint32) error {
+	if _, ok := d.src.(stdio.Seeker); !ok {
+		return d.skip(index)
+	}
+	if err := d.loadIndex(); err != nil {
+		return err
+	}
+	i := sort.Search(len(d.index), func(i int) bool {
+		return d.index[i].first+d.index[i].count > index
+	})
+	if index < d.start || i == len(d.index) {
+		return fmt.Errorf("block %d is not in the dump", index)
+	}
+	if err := d.seek(d.index[i].offset); err != nil {
+		return err
+	}
+	d.next = d.index[i].first
+	d.blocks = nil
+	d.ended = false
+	if err := d.readChunk(); err != nil {
+		return err
+	}
+	skip := index - d.next
+	d.blocks = d.blocks[skip:]
+	d.next = index
+	return nil
+}
+
+// skip reads chunks sequentially until the block with the given index.
+func (d *Reader) skip(index uint32) error {
+	if index < d.next {
+		return fmt.Errorf("can't seek back to block %d in non-seekable dump", index)
+	}
+	for d.next+uint32(len(d.blocks)) <= index {
+		d.next += uint32(len(d.blocks))
+		d.blocks = nil
+		if err := d.readChunk(); err != nil {
+			if errors.Is(err, stdio.EOF) {
+				return fmt.Errorf("block %d is not in the dump", index)
+			}
+			return err
+		}
+	}
+	d.blocks = d.blocks[index-d.next:]
+	d.next = index
+	return nil
+}
+
+func (d *Reader) seek(offset uint64) error {
+	s, ok := d.src.(stdio.Seeker)
+	if !ok {
+		return errors.New("dump reader is not seekable")
+	}
+	if _, err := s.Seek(int64(offset), stdio.SeekStart); err != nil {
+		return err
+	}
+	d.br.Reset(d.src)
+	return nil
+}
+
+// loadIndex reads and checks the dump index if it's not yet loaded. Reader
+// position is not preserved.
+func (d *Reader) loadIndex() error {
+	if d.index != nil {
+		return nil
+	}
+	s, ok := d.src.(stdio.Seeker)
+	if !ok {
+		return errors.New("dump reader is not seekable")
+	}
+	end, err := s.Seek(-dumpV2FooterSize, stdio.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	var footer [dumpV2FooterSize]byte
+	if _, err := stdio.ReadFull(d.src, footer[:]); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	offset := binary.LittleEndian.Uint64(footer[:])
+	if !bytes.Equal(footer[8:], dumpV2IndexMagic[:]) || offset+8 > uint64(end) {
+		return fmt.Errorf("%w: invalid index footer", ErrCorruptedDump)
+	}
+	data := make([]byte, uint64(end)-offset)
+	if err := d.seek(offset); err != nil {
+		return err
+	}
+	if _, err := stdio.ReadFull(d.br, data); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorruptedDump, err)
+	}
+	var (
+		idx = data[:len(data)-4]
+		crc = binary.LittleEndian.Uint32(data[len(data)-4:])
+	)
+	if crc32.Checksum(idx, crcTable) != crc {
+		return fmt.Errorf("%w: index checksum mismatch", ErrCorruptedDump)
+	}
+	r := io.NewBinReaderFromBuf(idx)
+	n := r.ReadU32LE()
+	if r.Err != nil || uint64(len(idx)) != 4+uint64(n)*16 {
+		return fmt.Errorf("%w: invalid index size", ErrCorruptedDump)
+	}
+	index := make([]chunkInfo, n)
+	next := d.start
+	for i := range index {
+		index[i].first = r.ReadU32LE()
+		index[i].count = r.ReadU32LE()
+		index[i].offset = r.ReadU64LE()
+		if index[i].first != next || index[i].count == 0 || index[i].offset >= offset {
+			return fmt.Errorf("%w: invalid index entry %d", ErrCorruptedDump, i)
+		}
+		next += index[i].count
+	}
+	d.index = index
+	return nil
+}
+
+// Verify reads the whole dump checking all checksums and the index. Blocks
+// are not decoded. The underlying reader must implement io.Seeker, Reader is
+// positioned at the first block after successful verification.
+func (d *Reader) Verify() error {
+	if err := d.loadIndex(); err != nil {
+		return err
+	}
+	if err := d.seek(dumpV2HeaderSize); err != nil {
+		return err
+	}
+	d.next = d.start
+	d.blocks = nil
+	d.ended = false
+	for i := 0; ; i++ {
+		if err := d.readChunk(); err != nil {
+			if errors.Is(err, stdio.EOF) {
+				if i != len(d.index) {
+					return fmt.Errorf("%w: index has %d chunks, dump has %d", ErrCorruptedDump, len(d.index), i)
+				}
+				break
+			}
+			return err
+		}
+		if i >= len(d.index) || d.index[i].first != d.next || d.index[i].count != uint32(len(d.blocks)) {
+			return fmt.Errorf("%w: chunk %d doesn't match the index", ErrCorruptedDump, i)
+		}
+		d.next += uint32(len(d.blocks))
+		d.blocks = nil
+	}
+	if err := d.seek(dumpV2HeaderSize); err != nil {
+		return err
+	}
+	d.next = d.start
+	d.ended = false
+	return nil
+}
+
+// RestoreV2 adds count blocks read from r starting from its current position
+// to bc, zero count means all remaining blocks. f is called after addition of
+// every block.
+func RestoreV2(bc DumperRestorer, r *Reader, count uint32, f func(b *block.Block) error) error {
+	return NewImporter(bc, 1, f).RestoreV2(r, count)
+}
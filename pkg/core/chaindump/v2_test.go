@@ -0,0 +1,148 @@
+package chaindump
+
+import (
+	"bytes"
+	stdio "io"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/stretchr/testify/require"
+)
+
+// streamReader hides io.Seeker implementation of the underlying reader.
+type streamReader struct {
+	r stdio.Reader
+}
+
+func (s streamReader) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+func newTestChain(t *testing.T, height int) *core.Blockchain {
+	bc, validators, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	for int(bc.BlockHeight()) < height {
+		e.AddNewBlock(t)
+	}
+	return bc
+}
+
+func TestDumpV2(t *testing.T) {
+	old := dumpV2ChunkSize
+	dumpV2ChunkSize = 1024
+	t.Cleanup(func() { dumpV2ChunkSize = old })
+
+	bc := newTestChain(t, 20)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, DumpV2(bc, buf, 0, bc.BlockHeight()+1))
+	dump := buf.Bytes()
+	require.True(t, IsDumpV2(dump))
+	require.False(t, IsDumpV2(dump[4:]))
+
+	newReader := func(t *testing.T, data []byte) *Reader {
+		r, err := NewReader(bytes.NewReader(data), bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		return r
+	}
+	r := newReader(t, dump)
+	require.Equal(t, bc.GetConfig().Magic, r.Magic())
+	require.Equal(t, uint32(0), r.Start())
+	count, err := r.Count()
+	require.NoError(t, err)
+	require.Equal(t, bc.BlockHeight()+1, count)
+	require.True(t, len(r.index) > 1, "test is invalid: single chunk")
+	require.NoError(t, r.Verify())
+
+	t.Run("restore", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		r := newReader(t, dump)
+		require.NoError(t, RestoreV2(bc2, r, 5, nil))
+		require.Equal(t, uint32(4), bc2.BlockHeight())
+
+		// Jump to the block following the current height.
+		r = newReader(t, dump)
+		require.NoError(t, r.Seek(bc2.BlockHeight()+1))
+		var lastIndex uint32
+		require.NoError(t, RestoreV2(bc2, r, bc.BlockHeight()-bc2.BlockHeight(), func(b *block.Block) error {
+			lastIndex = b.Index
+			return nil
+		}))
+		require.Equal(t, bc.BlockHeight(), lastIndex)
+		require.Equal(t, bc.GetHeaderHash(bc.BlockHeight()), bc2.GetHeaderHash(bc2.BlockHeight()))
+		require.Error(t, RestoreV2(bc2, r, 1, nil))
+	})
+	t.Run("stream", func(t *testing.T) {
+		bc2, _, _ := chain.NewMulti(t)
+		r, err := NewReader(streamReader{bytes.NewReader(dump)}, bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		require.Error(t, r.Verify())
+		require.NoError(t, RestoreV2(bc2, r, 3, nil))
+		require.Equal(t, uint32(2), bc2.BlockHeight())
+		require.Error(t, r.Seek(1))
+		require.NoError(t, r.Seek(3))
+		require.NoError(t, r.Seek(17))
+		b, err := r.Block()
+		require.NoError(t, err)
+		require.Equal(t, bc.GetHeaderHash(17), b.Hash())
+		require.Error(t, r.Seek(bc.BlockHeight()+1))
+
+		r, err = NewReader(streamReader{bytes.NewReader(dump)}, bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		require.NoError(t, r.Seek(3))
+		require.NoError(t, RestoreV2(bc2, r, 0, nil))
+		require.Equal(t, bc.BlockHeight(), bc2.BlockHeight())
+		_, err = r.Block()
+		require.ErrorIs(t, err, stdio.EOF)
+	})
+	t.Run("partial", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, DumpV2(bc, buf, 7, 5))
+		r := newReader(t, buf.Bytes())
+		require.Equal(t, uint32(7), r.Start())
+		count, err := r.Count()
+		require.NoError(t, err)
+		require.Equal(t, uint32(5), count)
+		require.Error(t, r.Seek(6))
+		require.Error(t, r.Seek(12))
+		require.NoError(t, r.Seek(11))
+		b, err := r.Block()
+		require.NoError(t, err)
+		require.Equal(t, bc.GetHeaderHash(11), b.Hash())
+	})
+	t.Run("magic mismatch", func(t *testing.T) {
+		bc2, _, _ := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+			c.Magic++
+		})
+		require.Error(t, RestoreV2(bc2, newReader(t, dump), 1, nil))
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		check := func(t *testing.T, off int) {
+			data := bytes.Clone(dump)
+			data[off] ^= 0xff
+			r, err := NewReader(bytes.NewReader(data), bc.GetConfig().StateRootInHeader)
+			if err == nil {
+				err = r.Verify()
+			}
+			require.Error(t, err)
+		}
+		t.Run("chunk", func(t *testing.T) { check(t, int(r.index[1].offset)+30) })
+		t.Run("chunk header", func(t *testing.T) { check(t, int(r.index[1].offset)+2) })
+		t.Run("index", func(t *testing.T) { check(t, len(dump)-dumpV2FooterSize-10) })
+		t.Run("footer", func(t *testing.T) { check(t, len(dump)-1) })
+		t.Run("header", func(t *testing.T) { check(t, 0) })
+
+		data := bytes.Clone(dump)
+		data[r.index[1].offset+30] ^= 0xff
+		r2, err := NewReader(bytes.NewReader(data), bc.GetConfig().StateRootInHeader)
+		require.NoError(t, err)
+		require.ErrorIs(t, r2.Verify(), ErrCorruptedDump)
+		// The first chunk is still readable.
+		require.NoError(t, r2.Seek(0))
+		_, err = r2.Block()
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Package contractdump implements contract snapshots, that is contract states
+with all of their storage items taken at some height, that can be exported
+from one chain and imported into another one.
+*/
+package contractdump
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+)
+
+// Snapshot is a contract state with all of its storage items taken
+// at some height. It's encoded as JSON.
+type Snapshot struct {
+	// Magic is the magic of the network the snapshot is taken from.
+	Magic netmode.Magic `json:"magic"`
+	// Height is the height of the state the snapshot is taken from.
+	Height uint32 `json:"height"`
+	// StateRoot is the state root hash at Height.
+	StateRoot util.Uint256 `json:"stateroot"`
+	// Contract is the contract state.
+	Contract state.Contract `json:"contract"`
+	// Storage contains all contract's storage items ordered by key.
+	Storage []StorageItem `json:"storage"`
+}
+
+// StorageItem is a single contract storage item, the key doesn't include
+// contract ID.
+type StorageItem struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// StateReader is an interface to get historic states from (it's implemented
+// by the state root module).
+type StateReader interface {
+	GetStateRoot(height uint32) (*state.MPTRoot, error)
+	GetState(root util.Uint256, key []byte) ([]byte, error)
+	SeekStates(root util.Uint256, prefix []byte, cont func(k, v []byte) bool)
+}
+
+// Importer is an interface to import contract snapshots into.
+type Importer interface {
+	ImportContract(cs *state.Contract, items []storage.KeyValue) error
+}
+
+// Export takes a snapshot of the contract with the given hash at the
+// given height. The state for this height must be available in sr, so it's
+// not possible for nodes with KeepOnlyLatestState enabled to export anything
+// but the latest state and StateRetention limits the set of available
+// heights.
+func Export(sr StateReader, magic netmode.Magic, height uint32, hash util.Uint160) (*Snapshot, error) {
+	root, err := sr.GetStateRoot(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state root for height %d: %w", height, err)
+	}
+	csBytes, err := sr.GetState(root.Root, makeStorageKey(native.ManagementContractID, native.MakeContractKey(hash)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract %s state: %w", hash.StringLE(), err)
+	}
+	snap := &Snapshot{
+		Magic:     magic,
+		Height:    height,
+		StateRoot: root.Root,
+		Storage:   []StorageItem{},
+	}
+	err = stackitem.DeserializeConvertible(csBytes, &snap.Contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode contract %s state: %w", hash.StringLE(), err)
+	}
+	sr.SeekStates(root.Root, makeStorageKey(snap.Contract.ID, nil), func(k, v []byte) bool {
+		snap.Storage = append(snap.Storage, StorageItem{
+			Key:   bytes.Clone(k),
+			Value: bytes.Clone(v),
+		})
+		return true
+	})
+	return snap, nil
+}
+
+// Import adds the contract from the given snapshot into the chain.
+func Import(ci Importer, snap *Snapshot) error {
+	items := make([]storage.KeyValue, len(snap.Storage))
+	for i := range snap.Storage {
+		items[i] = storage.KeyValue{Key: snap.Storage[i].Key, Value: snap.Storage[i].Value}
+	}
+	return ci.ImportContract(&snap.Contract, items)
+}
+
+// WriteSnapshot writes the given snapshot to w.
+func WriteSnapshot(w io.Writer, snap *Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(snap)
+}
+
+// ReadSnapshot reads a snapshot from r.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	snap := new(Snapshot)
+	err := json.NewDecoder(r).Decode(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode contract snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// makeStorageKey returns an MPT key for the given contract storage key.
+func makeStorageKey(id int32, key []byte) []byte {
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(id))
+	copy(skey[4:], key)
+	return skey
+}
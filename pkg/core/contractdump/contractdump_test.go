@@ -0,0 +1,107 @@
+package contractdump_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/compiler"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/contractdump"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+const storageContractSrc = `package storagectr
+import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+func Put(k, v []byte) {
+	storage.Put(storage.GetContext(), k, v)
+}
+func Delete(k []byte) {
+	storage.Delete(storage.GetContext(), k)
+}
+func Get(k []byte) any {
+	return storage.Get(storage.GetContext(), k)
+}`
+
+func TestExportImport(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+	c.Invoke(t, stackitem.Null{}, "put", []byte{1}, []byte("one"))
+	c.Invoke(t, stackitem.Null{}, "put", []byte{2}, []byte("two"))
+	h := bc.BlockHeight()
+	c.Invoke(t, stackitem.Null{}, "delete", []byte{1})
+	c.Invoke(t, stackitem.Null{}, "put", []byte{3}, []byte("three"))
+
+	snap, err := contractdump.Export(bc.GetStateModule(), bc.GetConfig().Magic, h, ctr.Hash)
+	require.NoError(t, err)
+	require.Equal(t, h, snap.Height)
+	require.Equal(t, bc.GetConfig().Magic, snap.Magic)
+	root, err := bc.GetStateModule().GetStateRoot(h)
+	require.NoError(t, err)
+	require.Equal(t, root.Root, snap.StateRoot)
+	cs := bc.GetContractState(ctr.Hash)
+	require.Equal(t, cs.ID, snap.Contract.ID)
+	require.Equal(t, cs.Hash, snap.Contract.Hash)
+	require.Equal(t, cs.NEF, snap.Contract.NEF)
+	require.Equal(t, cs.Manifest.ABI, snap.Contract.Manifest.ABI)
+	require.Equal(t, []contractdump.StorageItem{
+		{Key: []byte{1}, Value: []byte("one")},
+		{Key: []byte{2}, Value: []byte("two")},
+	}, snap.Storage)
+
+	t.Run("missing contract", func(t *testing.T) {
+		_, err := contractdump.Export(bc.GetStateModule(), bc.GetConfig().Magic, h, util.Uint160{1, 2, 3})
+		require.Error(t, err)
+	})
+	t.Run("missing height", func(t *testing.T) {
+		_, err := contractdump.Export(bc.GetStateModule(), bc.GetConfig().Magic, bc.BlockHeight()+1, ctr.Hash)
+		require.Error(t, err)
+	})
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, contractdump.WriteSnapshot(buf, snap))
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), os.ModePerm))
+	actual, err := contractdump.ReadSnapshot(buf)
+	require.NoError(t, err)
+	require.Equal(t, snap, actual)
+
+	t.Run("bad snapshot", func(t *testing.T) {
+		_, err := contractdump.ReadSnapshot(strings.NewReader(`{"contract": 1}`))
+		require.Error(t, err)
+	})
+
+	newBC, newAcc := chain.NewSingle(t)
+	imported := chain.ImportContractSnapshot(t, newBC, path)
+	require.Equal(t, snap.Contract, *imported)
+	require.Error(t, contractdump.Import(newBC, snap)) // Already imported.
+
+	newE := neotest.NewExecutor(t, newBC, newAcc, newAcc)
+	newC := newE.CommitteeInvoker(ctr.Hash)
+	newC.Invoke(t, []byte("one"), "get", []byte{1})
+	newC.Invoke(t, []byte("two"), "get", []byte{2})
+	newC.Invoke(t, stackitem.Null{}, "get", []byte{3})
+	newC.Invoke(t, stackitem.Null{}, "put", []byte{3}, []byte("three"))
+	newC.Invoke(t, []byte("three"), "get", []byte{3})
+	require.Equal(t, imported, newBC.GetContractState(ctr.Hash))
+
+	// Imported contract ID is not reused.
+	ctr2 := neotest.CompileSource(t, newE.CommitteeHash, strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage2"})
+	newE.DeployContract(t, ctr2, nil)
+	require.Equal(t, imported.ID+1, newBC.GetContractState(ctr2.Hash).ID)
+
+	t.Run("not at genesis", func(t *testing.T) {
+		snap := *snap
+		snap.Contract.ID = 100
+		snap.Contract.Hash = util.Uint160{1, 2, 3}
+		require.Error(t, contractdump.Import(newBC, &snap))
+	})
+}
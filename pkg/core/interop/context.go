@@ -66,7 +66,11 @@ type Context struct {
 	baseStorageFee   int64
 	loadToken        func(ic *Context, id int32) error
 	GetRandomCounter uint32
-	signers          []transaction.Signer
+	// SigCache contains signature checks performed in advance, it can be
+	// nil.
+	SigCache *SignatureCache
+	signers  []transaction.Signer
+	tracer   Tracer
 }
 
 // NewContext returns new interop context.
@@ -291,7 +295,7 @@ func (c *ContractMD) buildHFSpecificMD(hf config.Hardfork) {
 	w := io.NewBufBinWriter()
 	for i := range c.methods {
 		m := c.methods[i]
-		if !(m.ActiveFrom == nil || (hf != config.HFDefault && (*m.ActiveFrom).Cmp(hf) >= 0)) ||
+		if !(m.ActiveFrom == nil || (hf != config.HFDefault && hf.Cmp(*m.ActiveFrom) >= 0)) ||
 			(m.ActiveTill != nil && (*m.ActiveTill).Cmp(hf) <= 0) {
 			continue
 		}
@@ -314,7 +318,7 @@ func (c *ContractMD) buildHFSpecificMD(hf config.Hardfork) {
 	}
 	for i := range c.events {
 		e := c.events[i]
-		if !(e.ActiveFrom == nil || (hf != config.HFDefault && (*e.ActiveFrom).Cmp(hf) >= 0)) ||
+		if !(e.ActiveFrom == nil || (hf != config.HFDefault && hf.Cmp(*e.ActiveFrom) >= 0)) ||
 			(e.ActiveTill != nil && (*e.ActiveTill).Cmp(hf) <= 0) {
 			continue
 		}
@@ -472,6 +476,9 @@ func (ic *Context) SyscallHandler(_ *vm.VM, id uint32) error {
 	if !ic.VM.AddGas(f.Price * ic.BaseExecFee()) {
 		return errors.New("insufficient amount of gas")
 	}
+	if ic.tracer != nil {
+		ic.tracer.OnSyscall(ic, f)
+	}
 	return f.Func(ic)
 }
 
@@ -487,6 +494,9 @@ func (ic *Context) initVM(v *vm.VM) {
 	v.GasLimit = -1
 	v.SyscallHandler = ic.SyscallHandler
 	v.SetPriceGetter(ic.GetPrice)
+	if ic.tracer != nil {
+		v.SetTracer(vmTracer{ic})
+	}
 	ic.VM = v
 }
 
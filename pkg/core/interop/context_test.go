@@ -5,6 +5,7 @@ import (
 
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,46 @@ func TestIsHardforkEnabled(t *testing.T) {
 		require.True(t, ic.IsHardforkEnabled(config.HFAspidochelone))
 	})
 }
+
+func TestBuildHFSpecificMD(t *testing.T) {
+	var (
+		basilisk   = config.HFBasilisk
+		cockatrice = config.HFCockatrice
+		domovoi    = config.HFDomovoi
+	)
+	c := NewContractMD("Test", -100)
+	addMethod := func(name string, from, till *config.Hardfork) {
+		c.AddMethod(&MethodAndPrice{ActiveFrom: from, ActiveTill: till}, &manifest.Method{Name: name})
+	}
+	addMethod("always", nil, nil)
+	addMethod("basilisk", &basilisk, nil)
+	addMethod("cockatrice", &cockatrice, nil)
+	addMethod("preCockatrice", nil, &cockatrice)
+	addMethod("domovoi", &domovoi, nil)
+	c.AddEvent(Event{HFSpecificEvent: HFSpecificEvent{MD: &manifest.Event{Name: "Always"}}})
+	c.AddEvent(Event{HFSpecificEvent: HFSpecificEvent{MD: &manifest.Event{Name: "Basilisk"}}, ActiveFrom: &basilisk})
+	c.AddEvent(Event{HFSpecificEvent: HFSpecificEvent{MD: &manifest.Event{Name: "Cockatrice"}}, ActiveFrom: &cockatrice})
+	c.AddEvent(Event{HFSpecificEvent: HFSpecificEvent{MD: &manifest.Event{Name: "Domovoi"}}, ActiveFrom: &domovoi})
+	c.BuildHFSpecificMD(nil)
+
+	check := func(t *testing.T, hf config.Hardfork, methods []string, events []string) {
+		md := c.HFSpecificContractMD(&hf)
+		actualMethods := make([]string, 0, len(md.Methods))
+		for _, m := range md.Methods {
+			actualMethods = append(actualMethods, m.MD.Name)
+		}
+		actualEvents := make([]string, 0, len(md.Events))
+		for _, e := range md.Events {
+			actualEvents = append(actualEvents, e.MD.Name)
+		}
+		require.ElementsMatch(t, methods, actualMethods, hf)
+		require.ElementsMatch(t, events, actualEvents, hf)
+		require.Equal(t, len(methods), len(md.Manifest.ABI.Methods), hf)
+		require.Equal(t, len(events), len(md.Manifest.ABI.Events), hf)
+	}
+	check(t, config.HFDefault, []string{"always", "preCockatrice"}, []string{"Always"})
+	check(t, config.HFAspidochelone, []string{"always", "preCockatrice"}, []string{"Always"})
+	check(t, config.HFBasilisk, []string{"always", "basilisk", "preCockatrice"}, []string{"Always", "Basilisk"})
+	check(t, config.HFCockatrice, []string{"always", "basilisk", "cockatrice"}, []string{"Always", "Basilisk", "Cockatrice"})
+	check(t, config.HFDomovoi, []string{"always", "basilisk", "cockatrice", "domovoi"}, []string{"Always", "Basilisk", "Cockatrice", "Domovoi"})
+}
@@ -32,7 +32,8 @@ func ECDSASecp256r1CheckMultisig(ic *interop.Context) error {
 	if len(pkeys) < len(sigs) {
 		return errors.New("more signatures than there are keys")
 	}
-	sigok := vm.CheckMultisigPar(ic.VM, elliptic.P256(), hash.NetSha256(ic.Network, ic.Container).BytesBE(), pkeys, sigs)
+	msg := hash.NetSha256(ic.Network, ic.Container).BytesBE()
+	sigok := ic.SigCache.Has(msg, pkeys, sigs) || vm.CheckMultisigPar(ic.VM, elliptic.P256(), msg, pkeys, sigs)
 	ic.VM.Estack().PushItem(stackitem.Bool(sigok))
 	return nil
 }
@@ -45,7 +46,8 @@ func ECDSASecp256r1CheckSig(ic *interop.Context) error {
 	if err != nil {
 		return err
 	}
-	res := pkey.VerifyHashable(signature, ic.Network, ic.Container)
+	msg := hash.NetSha256(ic.Network, ic.Container).BytesBE()
+	res := ic.SigCache.Has(msg, [][]byte{keyb}, [][]byte{signature}) || pkey.Verify(signature, msg)
 	ic.VM.Estack().PushItem(stackitem.Bool(res))
 	return nil
 }
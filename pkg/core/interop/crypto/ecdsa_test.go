@@ -15,9 +15,12 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/emit"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/assert"
@@ -231,4 +234,59 @@ func TestCheckSig(t *testing.T) {
 		pub[0] = 0xFF // invalid prefix
 		runCase(t, true, false, sign, pub)
 	})
+
+	t.Run("cached", func(t *testing.T) {
+		sign := make([]byte, keys.SignatureLen)
+		pub := priv.PublicKey().Bytes()
+		ic.SigCache = interop.NewSignatureCache()
+		t.Cleanup(func() { ic.SigCache = nil })
+		runCase(t, false, false, sign, pub)
+		msg := hash.NetSha256(uint32(netmode.UnitTestNet), tx).BytesBE()
+		ic.SigCache.Add(1, msg, [][]byte{pub}, [][]byte{sign})
+		runCase(t, false, true, sign, pub)
+		ic.SigCache.Remove(1)
+		runCase(t, false, false, sign, pub)
+	})
+}
+
+func TestECDSASecp256r1CheckMultisigCached(t *testing.T) {
+	tx := transaction.New([]byte{1, 2, 3}, 1)
+	pubs := make(keys.PublicKeys, 3)
+	for i := range pubs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i] = priv.PublicKey()
+	}
+	verif, err := smartcontract.CreateMultiSigRedeemScript(2, pubs)
+	require.NoError(t, err)
+	_, pkeys, ok := vm.ParseMultiSigContract(verif)
+	require.True(t, ok)
+	sigs := [][]byte{make([]byte, keys.SignatureLen), make([]byte, keys.SignatureLen)}
+	sigs[1][0] = 1
+	invoc := io.NewBufBinWriter()
+	for _, sig := range sigs {
+		emit.Bytes(invoc.BinWriter, sig)
+	}
+	script := append(invoc.Bytes(), verif...)
+	run := func(t *testing.T, cache *interop.SignatureCache) bool {
+		ic := interop.NewContext(trigger.Verification, fakechain.NewFakeChain(),
+			dao.NewSimple(storage.NewMemoryStore(), false),
+			interop.DefaultBaseExecFee, native.DefaultStoragePrice, nil, nil, nil, nil,
+			tx, nil)
+		ic.Container = tx
+		ic.Functions = Interops
+		ic.SigCache = cache
+		v := ic.SpawnVM()
+		v.LoadScript(script)
+		require.NoError(t, v.Run())
+		require.Equal(t, 1, v.Estack().Len())
+		return v.Estack().Pop().Bool()
+	}
+
+	cache := interop.NewSignatureCache()
+	require.False(t, run(t, cache))
+	// Keys and signatures are popped from the stack in the reverse order.
+	msg := hash.NetSha256(uint32(netmode.UnitTestNet), tx).BytesBE()
+	cache.Add(1, msg, [][]byte{pkeys[2], pkeys[1], pkeys[0]}, [][]byte{sigs[1], sigs[0]})
+	require.True(t, run(t, cache))
 }
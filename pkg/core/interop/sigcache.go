@@ -0,0 +1,106 @@
+package interop
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// SignatureCache contains the results of successful ECDSA signature checks
+// made in advance, before the corresponding witnesses are verified by the VM.
+// It allows to perform these checks concurrently for blocks that are yet to be
+// processed. Entries are bound to block indexes, so that they can be removed
+// once the block is processed. It's safe for concurrent use, nil cache is
+// always empty.
+type SignatureCache struct {
+	lock    sync.RWMutex
+	entries map[string]struct{}
+	blocks  map[uint32][]string
+}
+
+// NewSignatureCache returns an empty SignatureCache.
+func NewSignatureCache() *SignatureCache {
+	return &SignatureCache{
+		entries: make(map[string]struct{}),
+		blocks:  make(map[uint32][]string),
+	}
+}
+
+// Add stores the fact that sigs are valid signatures of msg made by pkeys
+// (in the same way System.Crypto.CheckMultisig checks them, single signature
+// checks have one key and one signature). index is the block this check
+// belongs to.
+func (c *SignatureCache) Add(index uint32, msg []byte, pkeys, sigs [][]byte) {
+	k := sigCacheKey(msg, pkeys, sigs)
+	c.lock.Lock()
+	c.entries[k] = struct{}{}
+	c.blocks[index] = append(c.blocks[index], k)
+	c.lock.Unlock()
+}
+
+// Has checks whether sigs are known to be valid signatures of msg made by
+// pkeys.
+func (c *SignatureCache) Has(msg []byte, pkeys, sigs [][]byte) bool {
+	if c == nil {
+		return false
+	}
+	k := sigCacheKey(msg, pkeys, sigs)
+	c.lock.RLock()
+	_, ok := c.entries[k]
+	c.lock.RUnlock()
+	return ok
+}
+
+// Remove drops all entries added for the block with the given index.
+func (c *SignatureCache) Remove(index uint32) {
+	c.lock.Lock()
+	for _, k := range c.blocks[index] {
+		delete(c.entries, k)
+	}
+	delete(c.blocks, index)
+	c.lock.Unlock()
+}
+
+// RemoveUpTo drops all entries added for blocks with indexes up to (and
+// including) the given one.
+func (c *SignatureCache) RemoveUpTo(index uint32) {
+	c.lock.Lock()
+	for i, ks := range c.blocks {
+		if i > index {
+			continue
+		}
+		for _, k := range ks {
+			delete(c.entries, k)
+		}
+		delete(c.blocks, i)
+	}
+	c.lock.Unlock()
+}
+
+// Len returns the number of entries in the cache.
+func (c *SignatureCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.entries)
+}
+
+// sigCacheKey returns an unambiguous cache key for the given check.
+func sigCacheKey(msg []byte, pkeys, sigs [][]byte) string {
+	var n = 3*binary.MaxVarintLen64 + len(msg)
+	for _, b := range pkeys {
+		n += binary.MaxVarintLen64 + len(b)
+	}
+	for _, b := range sigs {
+		n += binary.MaxVarintLen64 + len(b)
+	}
+	k := make([]byte, 0, n)
+	k = binary.AppendUvarint(k, uint64(len(msg)))
+	k = append(k, msg...)
+	for _, elems := range [][][]byte{pkeys, sigs} {
+		k = binary.AppendUvarint(k, uint64(len(elems)))
+		for _, b := range elems {
+			k = binary.AppendUvarint(k, uint64(len(b)))
+			k = append(k, b...)
+		}
+	}
+	return string(k)
+}
@@ -0,0 +1,48 @@
+package interop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureCache(t *testing.T) {
+	var (
+		nilCache *SignatureCache
+		msg      = []byte{1, 2, 3}
+		keys     = [][]byte{{4, 5}, {6}}
+		sigs     = [][]byte{{7}}
+	)
+	require.False(t, nilCache.Has(msg, keys, sigs))
+
+	c := NewSignatureCache()
+	require.False(t, c.Has(msg, keys, sigs))
+	c.Add(1, msg, keys, sigs)
+	c.Add(2, msg, keys[:1], sigs)
+	require.True(t, c.Has(msg, keys, sigs))
+	require.True(t, c.Has(msg, keys[:1], sigs))
+	require.Equal(t, 2, c.Len())
+
+	// Element boundaries matter.
+	require.False(t, c.Has(msg, [][]byte{{4}, {5, 6}}, sigs))
+	require.False(t, c.Has(msg, [][]byte{{4, 5}}, [][]byte{{6}, {7}}))
+	require.False(t, c.Has(msg, keys, nil))
+	require.False(t, c.Has(msg[:2], keys, sigs))
+
+	c.Remove(1)
+	require.False(t, c.Has(msg, keys, sigs))
+	require.True(t, c.Has(msg, keys[:1], sigs))
+	c.Remove(3)
+	c.Remove(2)
+	require.Equal(t, 0, c.Len())
+
+	c.Add(1, msg, keys, sigs)
+	c.Add(2, msg, keys[:1], sigs)
+	c.Add(4, msg, keys, nil)
+	c.RemoveUpTo(2)
+	require.False(t, c.Has(msg, keys, sigs))
+	require.False(t, c.Has(msg, keys[:1], sigs))
+	require.True(t, c.Has(msg, keys, nil))
+	c.RemoveUpTo(4)
+	require.Equal(t, 0, c.Len())
+}
@@ -35,6 +35,9 @@ func Delete(ic *interop.Context) error {
 	}
 	key := ic.VM.Estack().Pop().Bytes()
 	ic.DAO.DeleteStorageItem(stc.ID, key)
+	if t := ic.Tracer(); t != nil {
+		t.OnStoragePut(ic, stc.ID, key, nil)
+	}
 	return nil
 }
 
@@ -47,6 +50,9 @@ func Get(ic *interop.Context) error {
 	}
 	key := ic.VM.Estack().Pop().Bytes()
 	si := ic.DAO.GetStorageItem(stc.ID, key)
+	if t := ic.Tracer(); t != nil {
+		t.OnStorageGet(ic, stc.ID, key, si)
+	}
 	if si != nil {
 		ic.VM.Estack().PushItem(stackitem.NewByteArray([]byte(si)))
 	} else {
@@ -105,6 +111,12 @@ func putWithContext(ic *interop.Context, stc *Context, key []byte, value []byte)
 		return ErrGasLimitExceeded
 	}
 	ic.DAO.PutStorageItem(stc.ID, key, value)
+	if t := ic.Tracer(); t != nil {
+		if value == nil {
+			value = []byte{} // nil is for deleted items.
+		}
+		t.OnStoragePut(ic, stc.ID, key, value)
+	}
 	return nil
 }
 
@@ -0,0 +1,69 @@
+package interop
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+)
+
+// Tracer receives execution events of the interop context, it extends
+// vm.Tracer events with interop-specific ones. Every callback gets the
+// context, so the trigger, block and transaction of the execution are
+// available to it. Callbacks are called synchronously, they should return
+// quickly and must not change the execution state.
+type Tracer interface {
+	// OnEnter is called after a new script context is loaded into the VM,
+	// it's either an entry script or a contract call.
+	OnEnter(ic *Context, ctx *vm.Context)
+	// OnExit is called after the script context is unloaded, commit is false
+	// if it's unloaded because of an exception. It's not called for contexts
+	// left in the invocation stack when the VM faults.
+	OnExit(ic *Context, ctx *vm.Context, commit bool)
+	// OnGas is called for every GAS charge with the amount charged.
+	OnGas(ic *Context, gas int64)
+	// OnSyscall is called before the syscall function is invoked (after the
+	// call flags check and syscall price charge).
+	OnSyscall(ic *Context, f *Function)
+	// OnStorageGet is called after a contract storage item is read with
+	// System.Storage.Get, value is nil if there is no item.
+	OnStorageGet(ic *Context, id int32, key []byte, value []byte)
+	// OnStoragePut is called after a contract storage item is changed with
+	// System.Storage.Put (or System.Storage.Delete, then value is nil).
+	OnStoragePut(ic *Context, id int32, key []byte, value []byte)
+}
+
+// vmTracer passes VM events to the context tracer.
+type vmTracer struct {
+	ic *Context
+}
+
+// SetTracer sets the tracer for the context (and its current VM if any), nil
+// disables tracing. VMs spawned or reused by the context after this call are
+// traced as well.
+func (ic *Context) SetTracer(t Tracer) {
+	ic.tracer = t
+	if ic.VM == nil {
+		return
+	}
+	if t != nil {
+		ic.VM.SetTracer(vmTracer{ic})
+	} else {
+		ic.VM.SetTracer(nil)
+	}
+}
+
+// Tracer returns the tracer set for the context, it's nil if tracing is
+// disabled.
+func (ic *Context) Tracer() Tracer {
+	return ic.tracer
+}
+
+func (t vmTracer) OnEnter(_ *vm.VM, ctx *vm.Context) {
+	t.ic.tracer.OnEnter(t.ic, ctx)
+}
+
+func (t vmTracer) OnExit(_ *vm.VM, ctx *vm.Context, commit bool) {
+	t.ic.tracer.OnExit(t.ic, ctx, commit)
+}
+
+func (t vmTracer) OnGas(_ *vm.VM, gas int64) {
+	t.ic.tracer.OnGas(t.ic, gas)
+}
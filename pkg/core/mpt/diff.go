@@ -0,0 +1,180 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// diffView is a node at some path in the trie. Extension nodes can be
+// partially traversed, so key contains the rest of the extension key in this
+// case.
+type diffView struct {
+	node Node
+	key  []byte
+}
+
+// differ holds Diff parameters.
+type differ struct {
+	t      *Trie
+	prefix []byte
+	from   []byte
+	f      func(key, oldValue, newValue []byte) bool
+}
+
+// Diff calls f for every key with the given prefix that has different values
+// in t and in the trie with the given root stored in the same Store. oldValue
+// is nil for keys missing from t, newValue is nil for keys missing from the
+// other trie. Keys are passed in ascending order. If from is not nil, only
+// keys following prefix+from are processed. Subtries that are the same in
+// both tries are never traversed, so the cost of this operation depends on
+// the number of changes rather than on the number of keys. Traversal is
+// stopped when f returns false.
+func (t *Trie) Diff(newRoot util.Uint256, prefix, from []byte, f func(key, oldValue, newValue []byte) bool) error {
+	if len(prefix) > MaxKeyLength {
+		return errors.New("invalid prefix length")
+	}
+	if len(from) > MaxKeyLength-len(prefix) {
+		return errors.New("invalid from length")
+	}
+	d := &differ{
+		t:      t,
+		prefix: toNibbles(prefix),
+		f:      f,
+	}
+	if from != nil {
+		d.from = toNibbles(append(bytes.Clone(prefix), from...))
+	}
+	var newNode Node = EmptyNode{}
+	if !newRoot.Equals(util.Uint256{}) {
+		newNode = NewHashNode(newRoot)
+	}
+	oldV, err := d.resolve(t.root)
+	if err != nil {
+		return err
+	}
+	newV, err := d.resolve(newNode)
+	if err != nil {
+		return err
+	}
+	err = d.diff(oldV, newV, []byte{}, d.from == nil)
+	if errors.Is(err, errStop) {
+		return nil
+	}
+	return err
+}
+
+// resolve returns a view of the given node fetching it from the store if
+// needed.
+func (d *differ) resolve(n Node) (diffView, error) {
+	if h, ok := n.(*HashNode); ok {
+		r, err := d.t.getFromStore(h.Hash())
+		if err != nil {
+			return diffView{}, fmt.Errorf("failed to get node %s: %w", h.Hash().StringLE(), err)
+		}
+		n = r
+	}
+	var v = diffView{node: n}
+	if e, ok := n.(*ExtensionNode); ok {
+		v.key = e.key
+	}
+	return v, nil
+}
+
+// same returns true if both views are known to represent the same subtrie.
+func (a diffView) same(b diffView) bool {
+	if isEmpty(a.node) || isEmpty(b.node) {
+		return isEmpty(a.node) && isEmpty(b.node)
+	}
+	return len(a.key) == len(b.key) && a.node.Hash().Equals(b.node.Hash())
+}
+
+// value returns the value stored exactly at the view's path (if any).
+func (d *differ) value(v diffView) ([]byte, error) {
+	switch n := v.node.(type) {
+	case *LeafNode:
+		return n.value, nil
+	case *BranchNode:
+		if isEmpty(n.Children[lastChild]) {
+			return nil, nil
+		}
+		c, err := d.resolve(n.Children[lastChild])
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := c.node.(*LeafNode); ok {
+			return l.value, nil
+		}
+	}
+	return nil, nil
+}
+
+// child returns a view of the i-th child of the given view.
+func (d *differ) child(v diffView, i byte) (diffView, error) {
+	switch n := v.node.(type) {
+	case *BranchNode:
+		return d.resolve(n.Children[i])
+	case *ExtensionNode:
+		if v.key[0] != i {
+			break
+		}
+		if len(v.key) > 1 {
+			return diffView{node: n, key: v.key[1:]}, nil
+		}
+		return d.resolve(n.next)
+	}
+	return diffView{node: EmptyNode{}}, nil
+}
+
+// diff compares two views located at the given path. after is true if the
+// path is known to follow d.from.
+func (d *differ) diff(oldV, newV diffView, path []byte, after bool) error {
+	if oldV.same(newV) {
+		return nil
+	}
+	if after && len(path) >= len(d.prefix) {
+		oldVal, err := d.value(oldV)
+		if err != nil {
+			return err
+		}
+		newVal, err := d.value(newV)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(oldVal, newVal) || (oldVal == nil) != (newVal == nil) {
+			if !d.f(fromNibbles(path), bytes.Clone(oldVal), bytes.Clone(newVal)) {
+				return errStop
+			}
+		}
+	}
+	for i := byte(0); i < lastChild; i++ {
+		if len(path) < len(d.prefix) && d.prefix[len(path)] != i {
+			continue
+		}
+		childAfter := after
+		if !after {
+			if len(path) >= len(d.from) {
+				childAfter = true
+			} else if i < d.from[len(path)] {
+				continue
+			} else if i > d.from[len(path)] {
+				childAfter = true
+			}
+		}
+		oldC, err := d.child(oldV, i)
+		if err != nil {
+			return err
+		}
+		newC, err := d.child(newV, i)
+		if err != nil {
+			return err
+		}
+		err = d.diff(oldC, newC, append(path, i), childAfter)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
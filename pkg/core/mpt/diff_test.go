@@ -0,0 +1,128 @@
+package mpt
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type testChange struct {
+	key, oldValue, newValue []byte
+}
+
+func TestTrie_Diff(t *testing.T) {
+	const keys = 300
+	var (
+		rnd    = rand.New(rand.NewSource(0))
+		store  = storage.NewMemCachedStore(storage.NewMemoryStore())
+		oldMap = make(map[string][]byte)
+		newMap = make(map[string][]byte)
+		tr     = NewTrie(nil, ModeAll, store)
+	)
+	randKey := func() []byte {
+		k := make([]byte, 1+rnd.Intn(3))
+		rnd.Read(k)
+		k[0] %= 4 // Make keys share prefixes.
+		return k
+	}
+	for i := 0; i < keys; i++ {
+		k, v := randKey(), []byte{byte(i)}
+		require.NoError(t, tr.Put(k, v))
+		oldMap[string(k)] = v
+		newMap[string(k)] = v
+	}
+	tr.Flush(0)
+	oldRoot := tr.StateRoot()
+	for i := 0; i < keys/10; i++ {
+		k := randKey()
+		if _, ok := newMap[string(k)]; ok && i%2 == 0 {
+			require.NoError(t, tr.Delete(k))
+			delete(newMap, string(k))
+			continue
+		}
+		v := []byte{byte(i), 0xff}
+		require.NoError(t, tr.Put(k, v))
+		newMap[string(k)] = v
+	}
+	tr.Flush(1)
+	newRoot := tr.StateRoot()
+
+	expected := func(prefix, from []byte) []testChange {
+		var (
+			res     []testChange
+			allKeys = make(map[string]bool)
+		)
+		for k := range oldMap {
+			allKeys[k] = true
+		}
+		for k := range newMap {
+			allKeys[k] = true
+		}
+		for k := range allKeys {
+			key := []byte(k)
+			if !bytes.HasPrefix(key, prefix) || (from != nil && bytes.Compare(key, append(bytes.Clone(prefix), from...)) <= 0) {
+				continue
+			}
+			if !bytes.Equal(oldMap[k], newMap[k]) {
+				res = append(res, testChange{key, oldMap[k], newMap[k]})
+			}
+		}
+		sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i].key, res[j].key) < 0 })
+		return res
+	}
+	diff := func(t *testing.T, from, to util.Uint256, prefix, start []byte, limit int) []testChange {
+		var res []testChange
+		tr := NewTrie(NewHashNode(from), ModeAll, store)
+		if from.Equals(util.Uint256{}) {
+			tr = NewTrie(nil, ModeAll, store)
+		}
+		require.NoError(t, tr.Diff(to, prefix, start, func(k, o, n []byte) bool {
+			res = append(res, testChange{k, o, n})
+			return len(res) != limit
+		}))
+		return res
+	}
+
+	exp := expected(nil, nil)
+	require.NotEmpty(t, exp)
+	require.Equal(t, exp, diff(t, oldRoot, newRoot, nil, nil, -1))
+	require.Empty(t, diff(t, oldRoot, oldRoot, nil, nil, -1))
+	require.Equal(t, exp[:3], diff(t, oldRoot, newRoot, nil, nil, 3))
+
+	t.Run("reverse", func(t *testing.T) {
+		res := diff(t, newRoot, oldRoot, nil, nil, -1)
+		require.Equal(t, len(exp), len(res))
+		for i := range res {
+			require.Equal(t, testChange{exp[i].key, exp[i].newValue, exp[i].oldValue}, res[i])
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		res := diff(t, util.Uint256{}, oldRoot, nil, nil, -1)
+		require.Equal(t, len(oldMap), len(res))
+		for _, c := range res {
+			require.Nil(t, c.oldValue)
+			require.Equal(t, oldMap[string(c.key)], c.newValue)
+		}
+	})
+	t.Run("prefix and start", func(t *testing.T) {
+		for _, prefix := range [][]byte{{}, {1}, {2}, {3, exp[len(exp)-1].key[1]}, {5}} {
+			require.Equal(t, expected(prefix, nil), diff(t, oldRoot, newRoot, prefix, nil, -1), "prefix %x", prefix)
+			for _, c := range exp {
+				if !bytes.HasPrefix(c.key, prefix) {
+					continue
+				}
+				start := c.key[len(prefix):]
+				require.Equal(t, expected(prefix, start), diff(t, oldRoot, newRoot, prefix, start, -1), "prefix %x, start %x", prefix, start)
+			}
+		}
+	})
+	t.Run("missing node", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(oldRoot), ModeAll, store)
+		require.Error(t, tr.Diff(util.Uint256{1, 2, 3}, nil, nil, func(k, o, n []byte) bool { return true }))
+	})
+}
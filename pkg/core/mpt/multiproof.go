@@ -0,0 +1,279 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// errMissingNode is returned when proof doesn't contain some node that is
+// required to be there.
+var errMissingNode = errors.New("missing proof node")
+
+// proofCollector resolves trie nodes and collects their serialized forms
+// (every node only once).
+type proofCollector struct {
+	t     *Trie
+	seen  map[util.Uint256]bool
+	proof [][]byte
+}
+
+// rangeWalker holds range traversal parameters.
+type rangeWalker struct {
+	c      *proofCollector
+	prefix []byte
+	from   []byte
+	f      func(path []byte, value []byte) bool
+}
+
+// GetMultiProof returns a proof for the given set of keys. It contains every
+// node on the paths from the root to all of the keys (each node is included
+// only once), so it proves both presence and absence of these keys. Use
+// VerifyMultiProof to check it.
+func (t *Trie) GetMultiProof(keys [][]byte) ([][]byte, error) {
+	c := newProofCollector(t)
+	for _, key := range keys {
+		if len(key) > MaxKeyLength {
+			return nil, errors.New("key is too big")
+		}
+		if _, err := c.get(t.root, toNibbles(key)); err != nil {
+			return nil, err
+		}
+	}
+	return c.proof, nil
+}
+
+// VerifyMultiProof verifies the given proof of the given keys against the MPT
+// with the specified root hash. It returns values for all keys, nil values
+// are returned for keys that are proven to be missing from the MPT.
+func VerifyMultiProof(rh util.Uint256, keys [][]byte, proof [][]byte) ([][]byte, bool) {
+	c := newProofCollector(newVerificationTrie(proof))
+	res := make([][]byte, len(keys))
+	for i, key := range keys {
+		if len(key) > MaxKeyLength {
+			return nil, false
+		}
+		val, err := c.get(NewHashNode(rh), toNibbles(key))
+		if err != nil {
+			return nil, false
+		}
+		res[i] = val
+	}
+	return res, true
+}
+
+// GetRangeProof returns up to max key-value pairs with the given prefix in
+// ascending key order along with the proof for them. If from is not nil, only
+// keys following prefix+from are returned (so empty from excludes the key
+// equal to prefix). The proof contains all nodes traversed to get these
+// pairs, so it proves that there are no other keys in the range from the
+// start to the last key returned (or to the end of the prefix range if less
+// than max pairs are returned). Use VerifyRangeProof to check it.
+func (t *Trie) GetRangeProof(prefix, from []byte, max int) ([]storage.KeyValue, [][]byte, error) {
+	var res []storage.KeyValue
+	w, err := newRangeWalker(newProofCollector(t), prefix, from, func(path []byte, value []byte) bool {
+		res = append(res, storage.KeyValue{
+			Key:   fromNibbles(path),
+			Value: bytes.Clone(value),
+		})
+		return len(res) < max
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if max > 0 {
+		err = w.walk(t.root, []byte{}, w.from == nil)
+		if err != nil && !errors.Is(err, errStop) {
+			return nil, nil, err
+		}
+	}
+	return res, w.c.proof, nil
+}
+
+// VerifyRangeProof verifies the proof for the given range of keys (see
+// GetRangeProof) against the MPT with the specified root hash. It checks
+// that kvs are exactly the first len(kvs) pairs of the range. The first
+// value returned is true if kvs are proven to contain all of the pairs from
+// the range (no pairs following the last one), the second one is true if the
+// proof is valid.
+func VerifyRangeProof(rh util.Uint256, prefix, from []byte, kvs []storage.KeyValue, proof [][]byte) (bool, bool) {
+	var i int
+	w, err := newRangeWalker(newProofCollector(newVerificationTrie(proof)), prefix, from, func(path []byte, value []byte) bool {
+		if i == len(kvs) {
+			return false // There is one more pair.
+		}
+		if !bytes.Equal(fromNibbles(path), kvs[i].Key) || !bytes.Equal(value, kvs[i].Value) {
+			return false
+		}
+		i++
+		return true
+	})
+	if err != nil {
+		return false, false
+	}
+	err = w.walk(NewHashNode(rh), []byte{}, w.from == nil)
+	switch {
+	case err == nil:
+		return i == len(kvs), i == len(kvs)
+	case errors.Is(err, errStop), errors.Is(err, errMissingNode):
+		// Traversal can only be stopped by the next pair or by the end of
+		// the proof and it's OK if all of kvs are checked already.
+		return false, i == len(kvs)
+	default:
+		return false, false
+	}
+}
+
+func newProofCollector(t *Trie) *proofCollector {
+	return &proofCollector{
+		t:    t,
+		seen: make(map[util.Uint256]bool),
+	}
+}
+
+// newVerificationTrie creates a trie with an in-memory store containing the given
+// proof nodes.
+func newVerificationTrie(proof [][]byte) *Trie {
+	tr := NewTrie(nil, ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	for i := range proof {
+		h := hash.DoubleSha256(proof[i])
+		tr.Store.Put(makeStorageKey(h), proof[i])
+	}
+	return tr
+}
+
+// resolve returns the node fetching it from the store if needed and adds it
+// to the proof.
+func (c *proofCollector) resolve(n Node) (Node, error) {
+	if h, ok := n.(*HashNode); ok {
+		r, err := c.t.getFromStore(h.Hash())
+		if err != nil {
+			if errors.Is(err, storage.ErrKeyNotFound) {
+				return nil, fmt.Errorf("%w: %s", errMissingNode, h.Hash().StringLE())
+			}
+			return nil, err
+		}
+		n = r
+	}
+	if isEmpty(n) {
+		return n, nil
+	}
+	if h := n.Hash(); !c.seen[h] {
+		c.seen[h] = true
+		c.proof = append(c.proof, bytes.Clone(n.Bytes()))
+	}
+	return n, nil
+}
+
+// get returns the value stored at the given path (nil if there is no such
+// path in the trie).
+func (c *proofCollector) get(curr Node, path []byte) ([]byte, error) {
+	curr, err := c.resolve(curr)
+	if err != nil {
+		return nil, err
+	}
+	switch n := curr.(type) {
+	case *LeafNode:
+		if len(path) == 0 {
+			return n.value, nil
+		}
+	case *BranchNode:
+		i, path := splitPath(path)
+		return c.get(n.Children[i], path)
+	case *ExtensionNode:
+		if bytes.HasPrefix(path, n.key) {
+			return c.get(n.next, path[len(n.key):])
+		}
+	}
+	return nil, nil
+}
+
+func newRangeWalker(c *proofCollector, prefix, from []byte, f func(path []byte, value []byte) bool) (*rangeWalker, error) {
+	if len(prefix) > MaxKeyLength {
+		return nil, errors.New("invalid prefix length")
+	}
+	if len(from) > MaxKeyLength-len(prefix) {
+		return nil, errors.New("invalid from length")
+	}
+	w := &rangeWalker{
+		c:      c,
+		prefix: toNibbles(prefix),
+		f:      f,
+	}
+	if from != nil {
+		w.from = toNibbles(append(bytes.Clone(prefix), from...))
+	}
+	return w, nil
+}
+
+// check returns true if there can be some keys from the range with the given
+// path prefix. The second value returned is true if the path is known to
+// follow w.from.
+func (w *rangeWalker) check(path []byte, after bool) (bool, bool) {
+	n := len(path)
+	if n > len(w.prefix) {
+		n = len(w.prefix)
+	}
+	if !bytes.Equal(path[:n], w.prefix[:n]) {
+		return false, after
+	}
+	if !after {
+		n = len(path)
+		if n > len(w.from) {
+			n = len(w.from)
+		}
+		switch cmp := bytes.Compare(path[:n], w.from[:n]); {
+		case cmp < 0:
+			return false, after
+		case cmp > 0 || len(path) > len(w.from):
+			after = true
+		}
+	}
+	return true, after
+}
+
+// walk traverses the subtrie located at the given path calling w.f for every
+// leaf in the range. after is true if the path is known to follow w.from.
+func (w *rangeWalker) walk(curr Node, path []byte, after bool) error {
+	curr, err := w.c.resolve(curr)
+	if err != nil {
+		return err
+	}
+	switch n := curr.(type) {
+	case *LeafNode:
+		if after && len(path) >= len(w.prefix) && !w.f(path, n.value) {
+			return errStop
+		}
+	case *BranchNode:
+		// The value at the branch path precedes all of its children.
+		if !isEmpty(n.Children[lastChild]) {
+			if err := w.walk(n.Children[lastChild], path, after); err != nil {
+				return err
+			}
+		}
+		for i := byte(0); i < lastChild; i++ {
+			if isEmpty(n.Children[i]) {
+				continue
+			}
+			childPath := append(bytes.Clone(path), i)
+			ok, childAfter := w.check(childPath, after)
+			if !ok {
+				continue
+			}
+			if err := w.walk(n.Children[i], childPath, childAfter); err != nil {
+				return err
+			}
+		}
+	case *ExtensionNode:
+		nextPath := append(bytes.Clone(path), n.key...)
+		ok, nextAfter := w.check(nextPath, after)
+		if ok {
+			return w.walk(n.next, nextPath, nextAfter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package mpt
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func newRandomProofTrie(t *testing.T, rnd *rand.Rand, count int) (*Trie, map[string][]byte) {
+	var (
+		tr = NewTrie(nil, ModeAll, newTestStore())
+		m  = make(map[string][]byte)
+	)
+	for i := 0; i < count; i++ {
+		k := make([]byte, 1+rnd.Intn(3))
+		rnd.Read(k)
+		k[0] %= 4 // Make keys share prefixes.
+		v := []byte{byte(i), byte(i >> 8)}
+		require.NoError(t, tr.Put(k, v))
+		m[string(k)] = v
+	}
+	tr.Flush(0)
+	tr.Collapse(0)
+	return tr, m
+}
+
+func TestTrie_MultiProof(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	tr, m := newRandomProofTrie(t, rnd, 300)
+	root := tr.StateRoot()
+
+	keys := [][]byte{{0x01}, {0x02, 0x03}, {0x07, 0xff, 0xff}, {}}
+	for k := range m {
+		if len(keys) == 20 {
+			break
+		}
+		keys = append(keys, []byte(k))
+	}
+	proof, err := tr.GetMultiProof(keys)
+	require.NoError(t, err)
+
+	vals, ok := VerifyMultiProof(root, keys, proof)
+	require.True(t, ok)
+	for i := range keys {
+		require.Equal(t, m[string(keys[i])], vals[i], "key %x", keys[i])
+	}
+
+	t.Run("no duplicates", func(t *testing.T) {
+		var (
+			size int
+			seen = make(map[string]bool)
+		)
+		for _, k := range keys {
+			p, err := tr.GetProof(k)
+			if err == nil {
+				size += len(p)
+			}
+		}
+		for _, n := range proof {
+			require.False(t, seen[string(n)])
+			seen[string(n)] = true
+		}
+		require.Less(t, len(proof), size)
+	})
+	t.Run("bad root", func(t *testing.T) {
+		_, ok := VerifyMultiProof(root.Reverse(), keys, proof)
+		require.False(t, ok)
+	})
+	t.Run("missing node", func(t *testing.T) {
+		for i := range proof {
+			p := append(append([][]byte{}, proof[:i]...), proof[i+1:]...)
+			_, ok := VerifyMultiProof(root, keys, p)
+			require.False(t, ok)
+		}
+	})
+	t.Run("long key", func(t *testing.T) {
+		_, err := tr.GetMultiProof([][]byte{make([]byte, MaxKeyLength+1)})
+		require.Error(t, err)
+	})
+}
+
+func TestTrie_RangeProof(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tr, m := newRandomProofTrie(t, rnd, 300)
+	root := tr.StateRoot()
+
+	expected := func(prefix, from []byte, max int) []storage.KeyValue {
+		var res []storage.KeyValue
+		for k, v := range m {
+			key := []byte(k)
+			if !bytes.HasPrefix(key, prefix) || (from != nil && bytes.Compare(key, append(bytes.Clone(prefix), from...)) <= 0) {
+				continue
+			}
+			res = append(res, storage.KeyValue{Key: key, Value: v})
+		}
+		sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i].Key, res[j].Key) < 0 })
+		if len(res) > max {
+			res = res[:max]
+		}
+		return res
+	}
+	check := func(t *testing.T, prefix, from []byte, max int) {
+		exp := expected(prefix, from, max)
+		kvs, proof, err := tr.GetRangeProof(prefix, from, max)
+		require.NoError(t, err)
+		require.Equal(t, len(exp), len(kvs), "prefix %x, from %x, max %d", prefix, from, max)
+		for i := range exp {
+			require.Equal(t, exp[i], kvs[i])
+		}
+		complete, ok := VerifyRangeProof(root, prefix, from, kvs, proof)
+		require.True(t, ok, "prefix %x, from %x, max %d", prefix, from, max)
+		if len(kvs) < max {
+			require.True(t, complete)
+		} else if complete {
+			// The proof may cover the end of the range even if exactly max
+			// pairs are returned, but it can't be complete if there are more.
+			require.Equal(t, len(kvs), len(expected(prefix, from, max+1)))
+		}
+
+		if len(kvs) == 0 {
+			return
+		}
+		if len(kvs) > 1 {
+			// Skipped item.
+			_, ok = VerifyRangeProof(root, prefix, from, kvs[1:], proof)
+			require.False(t, ok)
+		}
+		// Changed value.
+		bad := append([]storage.KeyValue{}, kvs...)
+		bad[len(bad)-1] = storage.KeyValue{Key: bad[len(bad)-1].Key, Value: []byte{0xff, 0xff, 0xff}}
+		_, ok = VerifyRangeProof(root, prefix, from, bad, proof)
+		require.False(t, ok)
+		// Less items than proven is still a valid, but incomplete result.
+		complete, ok = VerifyRangeProof(root, prefix, from, kvs[:len(kvs)-1], proof)
+		require.True(t, ok)
+		require.False(t, complete)
+		// Extra item.
+		_, ok = VerifyRangeProof(root, prefix, from, append(kvs, storage.KeyValue{Key: []byte{0x05}, Value: []byte{1}}), proof)
+		require.False(t, ok)
+	}
+
+	for _, prefix := range [][]byte{{}, {0}, {1}, {2, 0x80}, {5}} {
+		for _, max := range []int{1, 5, 1000} {
+			check(t, prefix, nil, max)
+			check(t, prefix, []byte{}, max)
+			for k := range m {
+				key := []byte(k)
+				if bytes.HasPrefix(key, prefix) && len(key) > len(prefix) && rnd.Intn(10) == 0 {
+					check(t, prefix, key[len(prefix):], max)
+				}
+			}
+		}
+	}
+
+	t.Run("missing nodes", func(t *testing.T) {
+		kvs, proof, err := tr.GetRangeProof([]byte{1}, nil, 1000)
+		require.NoError(t, err)
+		require.NotEmpty(t, kvs)
+		for i := range proof {
+			p := append(append([][]byte{}, proof[:i]...), proof[i+1:]...)
+			complete, ok := VerifyRangeProof(root, []byte{1}, nil, kvs, p)
+			require.False(t, ok && complete)
+		}
+	})
+	t.Run("zero max", func(t *testing.T) {
+		kvs, proof, err := tr.GetRangeProof(nil, nil, 0)
+		require.NoError(t, err)
+		require.Empty(t, kvs)
+		require.Empty(t, proof)
+	})
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, _, err := tr.GetRangeProof(make([]byte, MaxKeyLength+1), nil, 1)
+		require.Error(t, err)
+		_, _, err = tr.GetRangeProof([]byte{1}, make([]byte, MaxKeyLength), 1)
+		require.Error(t, err)
+	})
+}
@@ -56,7 +56,7 @@ func (cs *Contracts) ByName(name string) interop.Contract {
 func NewContracts(cfg config.ProtocolConfiguration) *Contracts {
 	cs := new(Contracts)
 
-	mgmt := newManagement()
+	mgmt := newManagement(cfg.PermissionedDeployment)
 	cs.Management = mgmt
 	cs.Contracts = append(cs.Contracts, mgmt)
 
@@ -88,8 +88,9 @@ func NewContracts(cfg config.ProtocolConfiguration) *Contracts {
 	cs.Policy = policy
 	cs.Contracts = append(cs.Contracts, neo, gas, policy)
 
-	desig := newDesignate(cfg.Genesis.Roles)
+	desig := newDesignate(cfg.Genesis.Roles, cfg.PermissionedDeployment)
 	desig.NEO = neo
+	mgmt.Designate = desig
 	cs.Designate = desig
 	cs.Contracts = append(cs.Contracts, desig)
 
@@ -1,6 +1,7 @@
 package native
 
 import (
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"encoding/binary"
 	"errors"
@@ -8,13 +9,17 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/dao"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/poseidon2"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
@@ -24,6 +29,16 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
+// mimcElementPrice is an additional CPU price of every field element hashed
+// by mimcBls12381 (on top of the method price), see BenchmarkCryptoLib for
+// details.
+const mimcElementPrice = 1 << 15
+
+// poseidon2ElementPrice is an additional CPU price of every field element
+// hashed by poseidon2Bls12381 (on top of the method price), see
+// BenchmarkCryptoLib for details.
+const poseidon2ElementPrice = 1 << 14
+
 // Crypto represents CryptoLib contract.
 type Crypto struct {
 	interop.ContractMD
@@ -120,6 +135,64 @@ func newCrypto() *Crypto {
 		manifest.NewParameter("data", smartcontract.ByteArrayType))
 	md = newMethodAndPrice(c.keccak256, 1<<15, callflag.NoneFlag, config.HFCockatrice)
 	c.AddMethod(md, desc)
+
+	desc = newDescriptor("recoverSecp256K1", smartcontract.ByteArrayType,
+		manifest.NewParameter("messageHash", smartcontract.ByteArrayType),
+		manifest.NewParameter("signature", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(c.recoverSecp256K1, 1<<15, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("verifyWithEd25519", smartcontract.BoolType,
+		manifest.NewParameter("message", smartcontract.ByteArrayType),
+		manifest.NewParameter("pubkey", smartcontract.ByteArrayType),
+		manifest.NewParameter("signature", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(c.verifyWithEd25519, 1<<15, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Serialize", smartcontract.ByteArrayType,
+		manifest.NewParameter("g", smartcontract.InteropInterfaceType))
+	md = newMethodAndPrice(c.bn254Serialize, 1<<19, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Deserialize", smartcontract.InteropInterfaceType,
+		manifest.NewParameter("data", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(c.bn254Deserialize, 1<<18, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Equal", smartcontract.BoolType,
+		manifest.NewParameter("x", smartcontract.InteropInterfaceType),
+		manifest.NewParameter("y", smartcontract.InteropInterfaceType))
+	md = newMethodAndPrice(c.bn254Equal, 1<<4, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Add", smartcontract.InteropInterfaceType,
+		manifest.NewParameter("x", smartcontract.InteropInterfaceType),
+		manifest.NewParameter("y", smartcontract.InteropInterfaceType))
+	md = newMethodAndPrice(c.bn254Add, 1<<18, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Mul", smartcontract.InteropInterfaceType,
+		manifest.NewParameter("x", smartcontract.InteropInterfaceType),
+		manifest.NewParameter("mul", smartcontract.ByteArrayType),
+		manifest.NewParameter("neg", smartcontract.BoolType))
+	md = newMethodAndPrice(c.bn254Mul, 1<<20, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("bn254Pairing", smartcontract.InteropInterfaceType,
+		manifest.NewParameter("g1", smartcontract.InteropInterfaceType),
+		manifest.NewParameter("g2", smartcontract.InteropInterfaceType))
+	md = newMethodAndPrice(c.bn254Pairing, 1<<22, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("mimcBls12381", smartcontract.ByteArrayType,
+		manifest.NewParameter("data", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(c.mimcBls12381, 1<<15, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
+
+	desc = newDescriptor("poseidon2Bls12381", smartcontract.ByteArrayType,
+		manifest.NewParameter("data", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(c.poseidon2Bls12381, 1<<15, callflag.NoneFlag, config.HFDomovoi)
+	c.AddMethod(md, desc)
 	return c
 }
 
@@ -185,6 +258,77 @@ func verifyWithECDsaGeneric(args []stackitem.Item, allowKeccak bool) stackitem.I
 	return stackitem.NewBool(res)
 }
 
+// recoverSecp256K1 recovers compressed secp256k1 public key from the given
+// 32-byte message hash and either 65-byte (r || s || v) or 64-byte compact
+// EIP-2098 (r || yParityAndS) signature. Null is returned if the public key
+// can't be recovered.
+func (c *Crypto) recoverSecp256K1(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	msgHash, err := args[0].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid message hash stackitem: %w", err))
+	}
+	sig, err := args[1].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid signature stackitem: %w", err))
+	}
+	pub, err := recoverSecp256K1(msgHash, sig)
+	if err != nil {
+		return stackitem.Null{}
+	}
+	return stackitem.NewByteArray(pub.SerializeCompressed())
+}
+
+// recoverSecp256K1 converts the given Ethereum-style signature into a compact
+// one and recovers the public key from it.
+func recoverSecp256K1(msgHash []byte, sig []byte) (*secp256k1.PublicKey, error) {
+	if len(msgHash) != 32 {
+		return nil, errors.New("invalid message hash length")
+	}
+	var (
+		compact = make([]byte, 65)
+		v       byte
+	)
+	switch len(sig) {
+	case 65:
+		v = sig[64]
+		if v >= 27 {
+			v -= 27
+		}
+		copy(compact[1:], sig[:64])
+	case 64:
+		v = sig[32] >> 7
+		copy(compact[1:], sig)
+		compact[33] &= 0x7f
+	default:
+		return nil, errors.New("invalid signature length")
+	}
+	if v > 3 {
+		return nil, errors.New("invalid recovery ID")
+	}
+	compact[0] = 27 + v
+	pub, _, err := ecdsa.RecoverCompact(compact, msgHash)
+	return pub, err
+}
+
+func (c *Crypto) verifyWithEd25519(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	msg, err := args[0].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid message stackitem: %w", err))
+	}
+	pubkey, err := args[1].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid pubkey stackitem: %w", err))
+	}
+	signature, err := args[2].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid signature stackitem: %w", err))
+	}
+	if len(pubkey) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return stackitem.NewBool(false)
+	}
+	return stackitem.NewBool(ed25519.Verify(pubkey, msg, signature))
+}
+
 func curveHasherFromStackitem(si stackitem.Item, allowKeccak bool) (elliptic.Curve, HashFunc, error) {
 	curve, err := si.TryInteger()
 	if err != nil {
@@ -322,6 +466,150 @@ func (c *Crypto) bls12381Pairing(_ *interop.Context, args []stackitem.Item) stac
 	return stackitem.NewInterop(p)
 }
 
+func (c *Crypto) bn254Serialize(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	val, ok := args[0].(*stackitem.Interop).Value().(bn254Point)
+	if !ok {
+		panic(errors.New("not a bn254 point"))
+	}
+	return stackitem.NewByteArray(val.Bytes())
+}
+
+func (c *Crypto) bn254Deserialize(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	buf, err := args[0].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid serialized bn254 point: %w", err))
+	}
+	p := new(bn254Point)
+	err = p.FromBytes(buf)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewInterop(*p)
+}
+
+func (c *Crypto) bn254Equal(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	a, okA := args[0].(*stackitem.Interop).Value().(bn254Point)
+	b, okB := args[1].(*stackitem.Interop).Value().(bn254Point)
+	if !(okA && okB) {
+		panic("some of the arguments are not a bn254 point")
+	}
+	res, err := a.EqualsCheckType(b)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewBool(res)
+}
+
+func (c *Crypto) bn254Add(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	a, okA := args[0].(*stackitem.Interop).Value().(bn254Point)
+	b, okB := args[1].(*stackitem.Interop).Value().(bn254Point)
+	if !(okA && okB) {
+		panic("some of the arguments are not a bn254 point")
+	}
+
+	p, err := bn254PointAdd(a, b)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewInterop(p)
+}
+
+// bn254ScalarFromBytes is the same as scalarFromBytes, but for BN254 scalar
+// field elements.
+func bn254ScalarFromBytes(bytes []byte, neg bool) (*bn254fr.Element, error) {
+	alpha := new(bn254fr.Element)
+	if len(bytes) != bn254fr.Bytes {
+		return nil, fmt.Errorf("invalid multiplier: 32-bytes scalar is expected, got %d", len(bytes))
+	}
+	v, err := bn254fr.LittleEndian.Element((*[bn254fr.Bytes]byte)(bytes))
+	if err != nil {
+		return nil, fmt.Errorf("invalid multiplier: failed to decode scalar: %w", err)
+	}
+	*alpha = v
+	if neg {
+		alpha.Neg(alpha)
+	}
+	return alpha, nil
+}
+
+func (c *Crypto) bn254Mul(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	a, okA := args[0].(*stackitem.Interop).Value().(bn254Point)
+	if !okA {
+		panic("multiplier is not a bn254 point")
+	}
+	mulBytes, err := args[1].TryBytes()
+	if err != nil {
+		panic(fmt.Errorf("invalid multiplier: %w", err))
+	}
+	neg, err := args[2].TryBool()
+	if err != nil {
+		panic(fmt.Errorf("invalid negative argument: %w", err))
+	}
+	alpha, err := bn254ScalarFromBytes(mulBytes, neg)
+	if err != nil {
+		panic(err)
+	}
+	alphaBi := new(big.Int)
+	alpha.BigInt(alphaBi)
+
+	p, err := bn254PointMul(a, alphaBi)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewInterop(p)
+}
+
+func (c *Crypto) bn254Pairing(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+	a, okA := args[0].(*stackitem.Interop).Value().(bn254Point)
+	b, okB := args[1].(*stackitem.Interop).Value().(bn254Point)
+	if !(okA && okB) {
+		panic("some of the arguments are not a bn254 point")
+	}
+
+	p, err := bn254PointPairing(a, b)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewInterop(p)
+}
+
+func (c *Crypto) mimcBls12381(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	bs, err := args[0].TryBytes()
+	if err != nil {
+		panic(err)
+	}
+	if len(bs)%fr.Bytes != 0 {
+		panic(fmt.Errorf("invalid data length: must be a multiple of %d", fr.Bytes))
+	}
+	if !ic.VM.AddGas(int64(len(bs)/fr.Bytes) * mimcElementPrice * ic.BaseExecFee()) {
+		panic(errGasLimitExceeded)
+	}
+	h := mimc.NewMiMC()
+	_, err = h.Write(bs)
+	if err != nil {
+		panic(fmt.Errorf("invalid field element: %w", err))
+	}
+	return stackitem.NewByteArray(h.Sum(nil))
+}
+
+func (c *Crypto) poseidon2Bls12381(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	bs, err := args[0].TryBytes()
+	if err != nil {
+		panic(err)
+	}
+	if len(bs)%poseidon2.ElementSize != 0 {
+		panic(fmt.Errorf("invalid data length: must be a multiple of %d", poseidon2.ElementSize))
+	}
+	if !ic.VM.AddGas(int64(len(bs)/poseidon2.ElementSize) * poseidon2ElementPrice * ic.BaseExecFee()) {
+		panic(errGasLimitExceeded)
+	}
+	res, err := poseidon2.Hash(bs)
+	if err != nil {
+		panic(err)
+	}
+	return stackitem.NewByteArray(res)
+}
+
 func (c *Crypto) keccak256(_ *interop.Context, args []stackitem.Item) stackitem.Item {
 	bs, err := args[0].TryBytes()
 	if err != nil {
@@ -0,0 +1,117 @@
+package native
+
+import (
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkCryptoLib measures CryptoLib methods execution time. Prices of
+// BN254 operations are derived from it using BLS12-381 ones as a reference:
+// every BN254 method is priced proportionally to the execution time of its
+// most expensive case relative to the same BLS12-381 method, rounded to the
+// nearest power of two. Per-element prices of field element hashing methods
+// are derived the same way from the time of hashing one element relative to
+// the most expensive bls12381Mul case (both are field arithmetic), the base
+// price of these methods is the same as for other hashing methods.
+func BenchmarkCryptoLib(b *testing.B) {
+	var (
+		c  = newCrypto()
+		ic = &interop.Context{VM: vm.New()}
+
+		blsG1Jac, blsG2Jac, blsG1, blsG2 = bls12381.Generators()
+		bnG1Jac, bnG2Jac, bnG1, bnG2     = bn254.Generators()
+	)
+	blsGT, err := bls12381.Pair([]bls12381.G1Affine{blsG1}, []bls12381.G2Affine{blsG2})
+	require.NoError(b, err)
+	bnGT, err := bn254.Pair([]bn254.G1Affine{bnG1}, []bn254.G2Affine{bnG2})
+	require.NoError(b, err)
+
+	var (
+		bls = func(p any) stackitem.Item { return stackitem.NewInterop(blsPoint{p}) }
+		bn  = func(p any) stackitem.Item { return stackitem.NewInterop(bn254Point{p}) }
+		neg = stackitem.NewBool(false)
+	)
+	// Maximum scalars (r-1) to make multiplication cost maximal.
+	var (
+		blsMax [fr.Bytes]byte
+		bnMax  [bn254fr.Bytes]byte
+		e      fr.Element
+		bnE    bn254fr.Element
+	)
+	e.SetInt64(-1)
+	fr.LittleEndian.PutElement(&blsMax, e)
+	bnE.SetInt64(-1)
+	bn254fr.LittleEndian.PutElement(&bnMax, bnE)
+	blsScalar, bnScalar := stackitem.NewByteArray(blsMax[:]), stackitem.NewByteArray(bnMax[:])
+
+	elements := func(n int) stackitem.Item {
+		var res = make([]byte, 0, n*fr.Bytes)
+		for i := 0; i < n; i++ {
+			e.SetInt64(-int64(i))
+			bs := e.Bytes()
+			res = append(res, bs[:]...)
+		}
+		return stackitem.NewByteArray(res)
+	}
+	blsG1Bytes, blsG2Bytes, blsGTBytes := blsG1.Bytes(), blsG2.Bytes(), blsGT.Bytes()
+	bnG1Bytes, bnG2Bytes, bnGTBytes := bnG1.Bytes(), bnG2.Bytes(), bnGT.Bytes()
+
+	for _, tc := range []struct {
+		name string
+		f    func(*interop.Context, []stackitem.Item) stackitem.Item
+		args []stackitem.Item
+	}{
+		{"bls12381Serialize/G2Jac", c.bls12381Serialize, []stackitem.Item{bls(&blsG2Jac)}},
+		{"bls12381Serialize/GT", c.bls12381Serialize, []stackitem.Item{bls(&blsGT)}},
+		{"bls12381Deserialize/G1", c.bls12381Deserialize, []stackitem.Item{stackitem.NewByteArray(blsG1Bytes[:])}},
+		{"bls12381Deserialize/G2", c.bls12381Deserialize, []stackitem.Item{stackitem.NewByteArray(blsG2Bytes[:])}},
+		{"bls12381Deserialize/GT", c.bls12381Deserialize, []stackitem.Item{stackitem.NewByteArray(blsGTBytes[:])}},
+		{"bls12381Equal/G2Jac", c.bls12381Equal, []stackitem.Item{bls(&blsG2Jac), bls(&blsG2Jac)}},
+		{"bls12381Equal/GT", c.bls12381Equal, []stackitem.Item{bls(&blsGT), bls(&blsGT)}},
+		{"bls12381Add/G1", c.bls12381Add, []stackitem.Item{bls(&blsG1Jac), bls(&blsG1Jac)}},
+		{"bls12381Add/G2", c.bls12381Add, []stackitem.Item{bls(&blsG2Jac), bls(&blsG2Jac)}},
+		{"bls12381Add/GT", c.bls12381Add, []stackitem.Item{bls(&blsGT), bls(&blsGT)}},
+		{"bls12381Mul/G1", c.bls12381Mul, []stackitem.Item{bls(&blsG1), blsScalar, neg}},
+		{"bls12381Mul/G2", c.bls12381Mul, []stackitem.Item{bls(&blsG2), blsScalar, neg}},
+		{"bls12381Mul/GT", c.bls12381Mul, []stackitem.Item{bls(&blsGT), blsScalar, neg}},
+		{"bls12381Pairing", c.bls12381Pairing, []stackitem.Item{bls(&blsG1Jac), bls(&blsG2Jac)}},
+
+		{"bn254Serialize/G2Jac", c.bn254Serialize, []stackitem.Item{bn(&bnG2Jac)}},
+		{"bn254Serialize/GT", c.bn254Serialize, []stackitem.Item{bn(&bnGT)}},
+		{"bn254Deserialize/G1", c.bn254Deserialize, []stackitem.Item{stackitem.NewByteArray(bnG1Bytes[:])}},
+		{"bn254Deserialize/G2", c.bn254Deserialize, []stackitem.Item{stackitem.NewByteArray(bnG2Bytes[:])}},
+		{"bn254Deserialize/GT", c.bn254Deserialize, []stackitem.Item{stackitem.NewByteArray(bnGTBytes[:])}},
+		{"bn254Equal/G2Jac", c.bn254Equal, []stackitem.Item{bn(&bnG2Jac), bn(&bnG2Jac)}},
+		{"bn254Equal/GT", c.bn254Equal, []stackitem.Item{bn(&bnGT), bn(&bnGT)}},
+		{"bn254Add/G1", c.bn254Add, []stackitem.Item{bn(&bnG1Jac), bn(&bnG1Jac)}},
+		{"bn254Add/G2", c.bn254Add, []stackitem.Item{bn(&bnG2Jac), bn(&bnG2Jac)}},
+		{"bn254Add/GT", c.bn254Add, []stackitem.Item{bn(&bnGT), bn(&bnGT)}},
+		{"bn254Mul/G1", c.bn254Mul, []stackitem.Item{bn(&bnG1), bnScalar, neg}},
+		{"bn254Mul/G2", c.bn254Mul, []stackitem.Item{bn(&bnG2), bnScalar, neg}},
+		{"bn254Mul/GT", c.bn254Mul, []stackitem.Item{bn(&bnGT), bnScalar, neg}},
+		{"bn254Pairing", c.bn254Pairing, []stackitem.Item{bn(&bnG1Jac), bn(&bnG2Jac)}},
+
+		{"mimcBls12381/0", c.mimcBls12381, []stackitem.Item{elements(0)}},
+		{"mimcBls12381/1", c.mimcBls12381, []stackitem.Item{elements(1)}},
+		{"mimcBls12381/32", c.mimcBls12381, []stackitem.Item{elements(32)}},
+		{"poseidon2Bls12381/0", c.poseidon2Bls12381, []stackitem.Item{elements(0)}},
+		{"poseidon2Bls12381/1", c.poseidon2Bls12381, []stackitem.Item{elements(1)}},
+		{"poseidon2Bls12381/32", c.poseidon2Bls12381, []stackitem.Item{elements(32)}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = tc.f(ic, tc.args)
+			}
+		})
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
 )
 
+// blsCurve is the curve name used in BLS12-381 point errors.
+const blsCurve = "bls12381"
+
 // blsPoint is a wrapper around bls12381 point types that must be used as
 // stackitem.Interop values and implement stackitem.Equatable interface.
 type blsPoint struct {
@@ -30,78 +33,41 @@ func (p blsPoint) EqualsCheckType(other stackitem.Equatable) (bool, error) {
 	if !ok {
 		return false, errors.New("not a bls12-381 point")
 	}
-	var (
-		res bool
-		err error
-	)
 	switch x := p.point.(type) {
 	case *bls12381.G1Affine:
-		y, ok := b.point.(*bls12381.G1Affine)
-		if !ok {
-			err = fmt.Errorf("equal: unexpected y bls12381 point type: %T vs G1Affine", y)
-			break
-		}
-		res = x.Equal(y)
+		return pointsEqual(blsCurve, "G1Affine", x, b.point)
 	case *bls12381.G1Jac:
-		y, ok := b.point.(*bls12381.G1Jac)
-		if !ok {
-			err = fmt.Errorf("equal: unexpected y bls12381 point type: %T vs G1Jac", y)
-			break
-		}
-		res = x.Equal(y)
+		return pointsEqual(blsCurve, "G1Jac", x, b.point)
 	case *bls12381.G2Affine:
-		y, ok := b.point.(*bls12381.G2Affine)
-		if !ok {
-			err = fmt.Errorf("equal: unexpected y bls12381 point type: %T vs G2Affine", y)
-			break
-		}
-		res = x.Equal(y)
+		return pointsEqual(blsCurve, "G2Affine", x, b.point)
 	case *bls12381.G2Jac:
-		y, ok := b.point.(*bls12381.G2Jac)
-		if !ok {
-			err = fmt.Errorf("equal: unexpected y bls12381 point type: %T vs G2Jac", y)
-			break
-		}
-		res = x.Equal(y)
+		return pointsEqual(blsCurve, "G2Jac", x, b.point)
 	case *bls12381.GT:
-		y, ok := b.point.(*bls12381.GT)
-		if !ok {
-			err = fmt.Errorf("equal: unexpected y bls12381 point type: %T vs GT", y)
-			break
-		}
-		res = x.Equal(y)
+		return pointsEqual(blsCurve, "GT", x, b.point)
 	default:
-		err = fmt.Errorf("equal: unexpected x bls12381 point type: %T", x)
+		return false, fmt.Errorf("equal: unexpected x bls12381 point type: %T", x)
 	}
-
-	return res, err
 }
 
 // Bytes returns serialized representation of the provided point in compressed form.
 func (p blsPoint) Bytes() []byte {
+	var compressed []byte
 	switch p := p.point.(type) {
-	case *bls12381.G1Affine:
-		compressed := p.Bytes()
-		return compressed[:]
-	case *bls12381.G1Jac:
-		g1Affine := new(bls12381.G1Affine)
-		g1Affine.FromJacobian(p)
-		compressed := g1Affine.Bytes()
-		return compressed[:]
-	case *bls12381.G2Affine:
-		compressed := p.Bytes()
-		return compressed[:]
-	case *bls12381.G2Jac:
-		g2Affine := new(bls12381.G2Affine)
-		g2Affine.FromJacobian(p)
-		compressed := g2Affine.Bytes()
-		return compressed[:]
+	case *bls12381.G1Affine, *bls12381.G1Jac:
+		g1Affine, _ := groupAffine[bls12381.G1Affine, bls12381.G1Jac](p)
+		b := g1Affine.Bytes()
+		compressed = b[:]
+	case *bls12381.G2Affine, *bls12381.G2Jac:
+		g2Affine, _ := groupAffine[bls12381.G2Affine, bls12381.G2Jac](p)
+		b := g2Affine.Bytes()
+		compressed = b[:]
 	case *bls12381.GT:
-		compressed := p.Bytes()
-		return compressed[:]
+		b := p.Bytes()
+		compressed = b[:]
 	default:
 		panic(errors.New("unknown bls12381 point type"))
 	}
+	return compressed
 }
 
 // FromBytes deserializes BLS12-381 point from the given byte slice in compressed form.
@@ -140,148 +106,41 @@ func blsPointAdd(a, b blsPoint) (blsPoint, error) {
 		err error
 	)
 	switch x := a.point.(type) {
-	case *bls12381.G1Affine:
-		switch y := b.point.(type) {
-		case *bls12381.G1Affine:
-			xJac := new(bls12381.G1Jac)
-			xJac.FromAffine(x)
-			xJac.AddMixed(y)
-			res = xJac
-		case *bls12381.G1Jac:
-			yJac := new(bls12381.G1Jac)
-			yJac.Set(y)
-			yJac.AddMixed(x)
-			res = yJac
-		default:
-			err = fmt.Errorf("add: inconsistent bls12381 point types: %T and %T", x, y)
-		}
-	case *bls12381.G1Jac:
-		resJac := new(bls12381.G1Jac)
-		resJac.Set(x)
-		switch y := b.point.(type) {
-		case *bls12381.G1Affine:
-			resJac.AddMixed(y)
-		case *bls12381.G1Jac:
-			resJac.AddAssign(y)
-		default:
-			err = fmt.Errorf("add: inconsistent bls12381 point types: %T and %T", x, y)
-		}
-		res = resJac
-	case *bls12381.G2Affine:
-		switch y := b.point.(type) {
-		case *bls12381.G2Affine:
-			xJac := new(bls12381.G2Jac)
-			xJac.FromAffine(x)
-			xJac.AddMixed(y)
-			res = xJac
-		case *bls12381.G2Jac:
-			yJac := new(bls12381.G2Jac)
-			yJac.Set(y)
-			yJac.AddMixed(x)
-			res = yJac
-		default:
-			err = fmt.Errorf("add: inconsistent bls12381 point types: %T and %T", x, y)
-		}
-	case *bls12381.G2Jac:
-		resJac := new(bls12381.G2Jac)
-		resJac.Set(x)
-		switch y := b.point.(type) {
-		case *bls12381.G2Affine:
-			resJac.AddMixed(y)
-		case *bls12381.G2Jac:
-			resJac.AddAssign(y)
-		default:
-			err = fmt.Errorf("add: inconsistent bls12381 point types: %T and %T", x, y)
-		}
-		res = resJac
+	case *bls12381.G2Affine, *bls12381.G2Jac:
+		res, err = groupAdd[bls12381.G2Affine, bls12381.G2Jac](blsCurve, x, b.point)
 	case *bls12381.GT:
-		resGT := new(bls12381.GT)
-		resGT.Set(x)
-		switch y := b.point.(type) {
-		case *bls12381.GT:
-			// It's multiplication, see https://github.com/neo-project/Neo.Cryptography.BLS12_381/issues/4.
-			resGT.Mul(x, y)
-		default:
-			err = fmt.Errorf("add: inconsistent bls12381 point types: %T and %T", x, y)
-		}
-		res = resGT
+		res, err = gtAdd(blsCurve, x, b.point)
 	default:
-		err = fmt.Errorf("add: unexpected bls12381 point type: %T", x)
+		res, err = groupAdd[bls12381.G1Affine, bls12381.G1Jac](blsCurve, x, b.point)
 	}
-
 	return blsPoint{point: res}, err
 }
 
-// blsPointAdd performs scalar multiplication of two BLS12-381 points.
+// blsPointMul performs scalar multiplication of BLS12-381 point.
 func blsPointMul(a blsPoint, alphaBi *big.Int) (blsPoint, error) {
 	var (
 		res any
 		err error
 	)
 	switch x := a.point.(type) {
-	case *bls12381.G1Affine:
-		// The result is in Jacobian form in the reference implementation.
-		g1Jac := new(bls12381.G1Jac)
-		g1Jac.FromAffine(x)
-		g1Jac.ScalarMultiplication(g1Jac, alphaBi)
-		res = g1Jac
-	case *bls12381.G1Jac:
-		g1Jac := new(bls12381.G1Jac)
-		g1Jac.ScalarMultiplication(x, alphaBi)
-		res = g1Jac
-	case *bls12381.G2Affine:
-		// The result is in Jacobian form in the reference implementation.
-		g2Jac := new(bls12381.G2Jac)
-		g2Jac.FromAffine(x)
-		g2Jac.ScalarMultiplication(g2Jac, alphaBi)
-		res = g2Jac
-	case *bls12381.G2Jac:
-		g2Jac := new(bls12381.G2Jac)
-		g2Jac.ScalarMultiplication(x, alphaBi)
-		res = g2Jac
+	case *bls12381.G2Affine, *bls12381.G2Jac:
+		res, err = groupMul[bls12381.G2Affine, bls12381.G2Jac](blsCurve, x, alphaBi)
 	case *bls12381.GT:
-		gt := new(bls12381.GT)
-
-		// C# implementation differs a bit from go's. They use double-and-add algorithm, see
-		// https://github.com/neo-project/Neo.Cryptography.BLS12_381/blob/844bc3a4f7d8ba2c545ace90ca124f8ada4c8d29/src/Neo.Cryptography.BLS12_381/Gt.cs#L102
-		// and https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Double-and-add,
-		// Pay attention that C#'s Gt.Double() squares (not doubles!) the initial GT point.
-		// Thus.C#'s scalar multiplication operation over Gt and Scalar is effectively an exponent.
-		// Go's exponent algorithm differs a bit from the C#'s double-and-add in that go's one
-		// uses 2-bits windowed method for multiplication. However, the resulting GT point is
-		// absolutely the same between two implementations.
-		gt.Exp(*x, alphaBi)
-
-		res = gt
+		res = gtMul(x, alphaBi)
 	default:
-		err = fmt.Errorf("mul: unexpected bls12381 point type: %T", x)
+		res, err = groupMul[bls12381.G1Affine, bls12381.G1Jac](blsCurve, x, alphaBi)
 	}
-
 	return blsPoint{point: res}, err
 }
 
 func blsPointPairing(a, b blsPoint) (blsPoint, error) {
-	var (
-		x *bls12381.G1Affine
-		y *bls12381.G2Affine
-	)
-	switch p := a.point.(type) {
-	case *bls12381.G1Affine:
-		x = p
-	case *bls12381.G1Jac:
-		x = new(bls12381.G1Affine)
-		x.FromJacobian(p)
-	default:
-		return blsPoint{}, fmt.Errorf("pairing: unexpected bls12381 point type (g1): %T", p)
+	x, ok := groupAffine[bls12381.G1Affine, bls12381.G1Jac](a.point)
+	if !ok {
+		return blsPoint{}, fmt.Errorf("pairing: unexpected bls12381 point type (g1): %T", a.point)
 	}
-	switch p := b.point.(type) {
-	case *bls12381.G2Affine:
-		y = p
-	case *bls12381.G2Jac:
-		y = new(bls12381.G2Affine)
-		y.FromJacobian(p)
-	default:
-		return blsPoint{}, fmt.Errorf("pairing: unexpected bls12381 point type (g2): %T", p)
+	y, ok := groupAffine[bls12381.G2Affine, bls12381.G2Jac](b.point)
+	if !ok {
+		return blsPoint{}, fmt.Errorf("pairing: unexpected bls12381 point type (g2): %T", b.point)
 	}
 
 	gt, err := bls12381.Pair([]bls12381.G1Affine{*x}, []bls12381.G2Affine{*y})
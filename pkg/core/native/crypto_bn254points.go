@@ -0,0 +1,154 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+)
+
+// bn254Curve is the curve name used in BN254 point errors.
+const bn254Curve = "bn254"
+
+// bn254Point is a wrapper around bn254 point types that must be used as
+// stackitem.Interop values and implement stackitem.Equatable interface.
+type bn254Point struct {
+	point any
+}
+
+var _ = stackitem.Equatable(bn254Point{})
+
+// Equals implements stackitem.Equatable interface.
+func (p bn254Point) Equals(other stackitem.Equatable) bool {
+	res, err := p.EqualsCheckType(other)
+	return err == nil && res
+}
+
+// EqualsCheckType checks whether other is of the same type as p and returns an error if not.
+// It also returns whether other and p are equal.
+func (p bn254Point) EqualsCheckType(other stackitem.Equatable) (bool, error) {
+	b, ok := other.(bn254Point)
+	if !ok {
+		return false, errors.New("not a bn254 point")
+	}
+	switch x := p.point.(type) {
+	case *bn254.G1Affine:
+		return pointsEqual(bn254Curve, "G1Affine", x, b.point)
+	case *bn254.G1Jac:
+		return pointsEqual(bn254Curve, "G1Jac", x, b.point)
+	case *bn254.G2Affine:
+		return pointsEqual(bn254Curve, "G2Affine", x, b.point)
+	case *bn254.G2Jac:
+		return pointsEqual(bn254Curve, "G2Jac", x, b.point)
+	case *bn254.GT:
+		return pointsEqual(bn254Curve, "GT", x, b.point)
+	default:
+		return false, fmt.Errorf("equal: unexpected x bn254 point type: %T", x)
+	}
+}
+
+// Bytes returns serialized representation of the provided point in compressed form.
+func (p bn254Point) Bytes() []byte {
+	var compressed []byte
+	switch p := p.point.(type) {
+	case *bn254.G1Affine, *bn254.G1Jac:
+		g1Affine, _ := groupAffine[bn254.G1Affine, bn254.G1Jac](p)
+		b := g1Affine.Bytes()
+		compressed = b[:]
+	case *bn254.G2Affine, *bn254.G2Jac:
+		g2Affine, _ := groupAffine[bn254.G2Affine, bn254.G2Jac](p)
+		b := g2Affine.Bytes()
+		compressed = b[:]
+	case *bn254.GT:
+		b := p.Bytes()
+		compressed = b[:]
+	default:
+		panic(errors.New("unknown bn254 point type"))
+	}
+	return compressed
+}
+
+// FromBytes deserializes BN254 point from the given byte slice in compressed form.
+func (p *bn254Point) FromBytes(buf []byte) error {
+	switch l := len(buf); l {
+	case bn254.SizeOfG1AffineCompressed:
+		g1Affine := new(bn254.G1Affine)
+		_, err := g1Affine.SetBytes(buf)
+		if err != nil {
+			return fmt.Errorf("failed to decode bn254 G1Affine point: %w", err)
+		}
+		p.point = g1Affine
+	case bn254.SizeOfG2AffineCompressed:
+		g2Affine := new(bn254.G2Affine)
+		_, err := g2Affine.SetBytes(buf)
+		if err != nil {
+			return fmt.Errorf("failed to decode bn254 G2Affine point: %w", err)
+		}
+		p.point = g2Affine
+	case bn254.SizeOfGT:
+		gt := new(bn254.GT)
+		err := gt.SetBytes(buf)
+		if err != nil {
+			return fmt.Errorf("failed to decode bn254 GT point: %w", err)
+		}
+		p.point = gt
+	default:
+		return fmt.Errorf("invalid serialized bn254 point length: %d", l)
+	}
+
+	return nil
+}
+
+// bn254PointAdd performs addition of two BN254 points.
+func bn254PointAdd(a, b bn254Point) (bn254Point, error) {
+	var (
+		res any
+		err error
+	)
+	switch x := a.point.(type) {
+	case *bn254.G2Affine, *bn254.G2Jac:
+		res, err = groupAdd[bn254.G2Affine, bn254.G2Jac](bn254Curve, x, b.point)
+	case *bn254.GT:
+		res, err = gtAdd(bn254Curve, x, b.point)
+	default:
+		res, err = groupAdd[bn254.G1Affine, bn254.G1Jac](bn254Curve, x, b.point)
+	}
+	return bn254Point{point: res}, err
+}
+
+// bn254PointMul performs scalar multiplication of BN254 point.
+func bn254PointMul(a bn254Point, alphaBi *big.Int) (bn254Point, error) {
+	var (
+		res any
+		err error
+	)
+	switch x := a.point.(type) {
+	case *bn254.G2Affine, *bn254.G2Jac:
+		res, err = groupMul[bn254.G2Affine, bn254.G2Jac](bn254Curve, x, alphaBi)
+	case *bn254.GT:
+		res = gtMul(x, alphaBi)
+	default:
+		res, err = groupMul[bn254.G1Affine, bn254.G1Jac](bn254Curve, x, alphaBi)
+	}
+	return bn254Point{point: res}, err
+}
+
+func bn254PointPairing(a, b bn254Point) (bn254Point, error) {
+	x, ok := groupAffine[bn254.G1Affine, bn254.G1Jac](a.point)
+	if !ok {
+		return bn254Point{}, fmt.Errorf("pairing: unexpected bn254 point type (g1): %T", a.point)
+	}
+	y, ok := groupAffine[bn254.G2Affine, bn254.G2Jac](b.point)
+	if !ok {
+		return bn254Point{}, fmt.Errorf("pairing: unexpected bn254 point type (g2): %T", b.point)
+	}
+
+	gt, err := bn254.Pair([]bn254.G1Affine{*x}, []bn254.G2Affine{*y})
+	if err != nil {
+		return bn254Point{}, fmt.Errorf("failed to perform pairing operation: %w", err)
+	}
+
+	return bn254Point{&gt}, nil
+}
@@ -0,0 +1,146 @@
+package native
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Generic helpers implementing point operations shared by pairing-friendly
+// curves supported by CryptoLib (BLS12-381 and BN254). gnark-crypto provides
+// the same set of types and methods for every curve, so G1 and G2 groups are
+// handled by the same code parameterized with affine (A) and Jacobian (J)
+// point types and GT operations are parameterized with GT type.
+
+// affinePoint is a constraint for gnark-crypto G1/G2 affine point types.
+type affinePoint[A, J any] interface {
+	*A
+	Equal(*A) bool
+	FromJacobian(*J) *A
+}
+
+// jacobianPoint is a constraint for gnark-crypto G1/G2 Jacobian point types.
+type jacobianPoint[A, J any] interface {
+	*J
+	Set(*J) *J
+	Equal(*J) bool
+	FromAffine(*A) *J
+	AddMixed(*A) *J
+	AddAssign(*J) *J
+	ScalarMultiplication(*J, *big.Int) *J
+}
+
+// gtPoint is a constraint for gnark-crypto GT point types.
+type gtPoint[T any] interface {
+	*T
+	Equal(*T) bool
+	Set(*T) *T
+	Mul(*T, *T) *T
+	Exp(T, *big.Int) *T
+}
+
+// pointsEqual compares x with y that must be of the same type, curve and form
+// are used in error messages.
+func pointsEqual[T any, PT interface {
+	*T
+	Equal(*T) bool
+}](curve string, form string, x PT, y any) (bool, error) {
+	b, ok := y.(PT)
+	if !ok {
+		return false, fmt.Errorf("equal: unexpected y %s point type: %T vs %s", curve, b, form)
+	}
+	return x.Equal(b), nil
+}
+
+// groupAdd adds two points of the same G1 or G2 group given in affine or
+// Jacobian form, the result is in Jacobian form.
+func groupAdd[A, J any, PA affinePoint[A, J], PJ jacobianPoint[A, J]](curve string, x, y any) (any, error) {
+	var res = PJ(new(J))
+	switch x := x.(type) {
+	case PA:
+		switch y := y.(type) {
+		case PA:
+			res.FromAffine(x)
+			res.AddMixed(y)
+		case PJ:
+			res.Set(y)
+			res.AddMixed(x)
+		default:
+			return nil, fmt.Errorf("add: inconsistent %s point types: %T and %T", curve, x, y)
+		}
+	case PJ:
+		res.Set(x)
+		switch y := y.(type) {
+		case PA:
+			res.AddMixed(y)
+		case PJ:
+			res.AddAssign(y)
+		default:
+			return nil, fmt.Errorf("add: inconsistent %s point types: %T and %T", curve, x, y)
+		}
+	default:
+		return nil, fmt.Errorf("add: unexpected %s point type: %T", curve, x)
+	}
+	return res, nil
+}
+
+// groupMul multiplies G1 or G2 point given in affine or Jacobian form by k,
+// the result is in Jacobian form (like in the reference implementation).
+func groupMul[A, J any, PA affinePoint[A, J], PJ jacobianPoint[A, J]](curve string, x any, k *big.Int) (any, error) {
+	var res = PJ(new(J))
+	switch x := x.(type) {
+	case PA:
+		res.FromAffine(x)
+		res.ScalarMultiplication(res, k)
+	case PJ:
+		res.ScalarMultiplication(x, k)
+	default:
+		return nil, fmt.Errorf("mul: unexpected %s point type: %T", curve, x)
+	}
+	return res, nil
+}
+
+// groupAffine returns G1 or G2 point given in affine or Jacobian form in
+// affine form. It returns false if x is not a point of this group.
+func groupAffine[A, J any, PA affinePoint[A, J], PJ jacobianPoint[A, J]](x any) (PA, bool) {
+	switch x := x.(type) {
+	case PA:
+		return x, true
+	case PJ:
+		res := PA(new(A))
+		res.FromJacobian(x)
+		return res, true
+	default:
+		return nil, false
+	}
+}
+
+// gtAdd performs addition of two GT points, it's multiplication since GT is a
+// multiplicative group, see
+// https://github.com/neo-project/Neo.Cryptography.BLS12_381/issues/4.
+func gtAdd[T any, PT gtPoint[T]](curve string, x PT, y any) (any, error) {
+	res := PT(new(T))
+	res.Set(x)
+	b, ok := y.(PT)
+	if !ok {
+		return nil, fmt.Errorf("add: inconsistent %s point types: %T and %T", curve, x, y)
+	}
+	res.Mul(x, b)
+	return res, nil
+}
+
+// gtMul performs scalar multiplication of GT point, it's an exponent since GT is
+// a multiplicative group.
+//
+// C# implementation differs a bit from go's. They use double-and-add algorithm, see
+// https://github.com/neo-project/Neo.Cryptography.BLS12_381/blob/844bc3a4f7d8ba2c545ace90ca124f8ada4c8d29/src/Neo.Cryptography.BLS12_381/Gt.cs#L102
+// and https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Double-and-add,
+// Pay attention that C#'s Gt.Double() squares (not doubles!) the initial GT point.
+// Thus.C#'s scalar multiplication operation over Gt and Scalar is effectively an exponent.
+// Go's exponent algorithm differs a bit from the C#'s double-and-add in that go's one
+// uses 2-bits windowed method for multiplication. However, the resulting GT point is
+// absolutely the same between two implementations.
+func gtMul[T any, PT gtPoint[T]](x PT, k *big.Int) any {
+	res := PT(new(T))
+	res.Exp(*x, k)
+	return res
+}
@@ -35,6 +35,8 @@ type Designate struct {
 	// initialNodeRoles defines a set of node roles that should be defined at the contract
 	// deployment (initialization).
 	initialNodeRoles map[noderoles.Role]keys.PublicKeys
+	// permissionedDeployment defines whether ContractDeployer role is valid.
+	permissionedDeployment bool
 
 	OracleService atomic.Value
 	// NotaryService represents a Notary node module.
@@ -57,6 +59,7 @@ type DesignationCache struct {
 	stateVals        roleData
 	neofsAlphabet    roleData
 	notaries         roleData
+	deployers        roleData
 }
 
 const (
@@ -97,14 +100,16 @@ func copyDesignationCache(src, dst *DesignationCache) {
 
 func (s *Designate) isValidRole(r noderoles.Role) bool {
 	return r == noderoles.Oracle || r == noderoles.StateValidator ||
-		r == noderoles.NeoFSAlphabet || r == noderoles.P2PNotary
+		r == noderoles.NeoFSAlphabet || r == noderoles.P2PNotary ||
+		(r == noderoles.ContractDeployer && s.permissionedDeployment)
 }
 
-func newDesignate(initialNodeRoles map[noderoles.Role]keys.PublicKeys) *Designate {
+func newDesignate(initialNodeRoles map[noderoles.Role]keys.PublicKeys, permissionedDeployment bool) *Designate {
 	s := &Designate{ContractMD: *interop.NewContractMD(nativenames.Designation, designateContractID)}
 	defer s.BuildHFSpecificMD(s.ActiveIn())
 
 	s.initialNodeRoles = initialNodeRoles
+	s.permissionedDeployment = permissionedDeployment
 
 	desc := newDescriptor("getDesignatedByRole", smartcontract.ArrayType,
 		manifest.NewParameter("role", smartcontract.IntegerType),
@@ -158,6 +163,9 @@ func (s *Designate) Initialize(ic *interop.Context, hf *config.Hardfork, newMD *
 func (s *Designate) InitializeCache(blockHeight uint32, d *dao.Simple) error {
 	cache := &DesignationCache{}
 	roles := []noderoles.Role{noderoles.Oracle, noderoles.NeoFSAlphabet, noderoles.StateValidator, noderoles.P2PNotary}
+	if s.permissionedDeployment {
+		roles = append(roles, noderoles.ContractDeployer)
+	}
 	for _, r := range roles {
 		err := s.updateCachedRoleData(cache, d, r)
 		if err != nil {
@@ -246,6 +254,8 @@ func (s *Designate) updateCachedRoleData(cache *DesignationCache, d *dao.Simple,
 		v = &cache.neofsAlphabet
 	case noderoles.P2PNotary:
 		v = &cache.notaries
+	case noderoles.ContractDeployer:
+		v = &cache.deployers
 	}
 	nodeKeys, height, err := s.getDesignatedByRoleFromStorage(d, r, math.MaxUint32)
 	if err != nil {
@@ -285,6 +295,8 @@ func getCachedRoleData(cache *DesignationCache, r noderoles.Role) *roleData {
 		return &cache.neofsAlphabet
 	case noderoles.P2PNotary:
 		return &cache.notaries
+	case noderoles.ContractDeployer:
+		return &cache.deployers
 	}
 	return nil
 }
@@ -14,8 +14,10 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/dao"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop/contract"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop/runtime"
 	istorage "github.com/epicchainlabs/epicchain-go/pkg/core/interop/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/bigint"
@@ -32,8 +34,26 @@ import (
 // Management is a contract-managing native contract.
 type Management struct {
 	interop.ContractMD
-	NEO    *NEO
-	Policy *Policy
+	NEO       *NEO
+	Policy    *Policy
+	Designate *Designate
+	// GenesisContracts is a list of contracts to be deployed during genesis
+	// block processing.
+	GenesisContracts []GenesisContract
+
+	// permissionedDeployment defines whether contract deployment, update and
+	// destruction are restricted to authorized accounts only.
+	permissionedDeployment bool
+}
+
+// GenesisContract is a non-native contract deployed by Management in the
+// genesis block (see config.GenesisContract).
+type GenesisContract struct {
+	NEF      nef.File
+	Manifest manifest.Manifest
+	// Data is passed to _deploy method, stackitem.Null is used if it's nil.
+	Data    stackitem.Item
+	Storage []config.GenesisStorageItem
 }
 
 type ManagementCache struct {
@@ -50,11 +70,16 @@ const (
 	// PrefixContract is a prefix used to store contract states inside Management native contract.
 	PrefixContract     = 8
 	prefixContractHash = 12
+	// prefixDeployer is a prefix used to store accounts allowed to manage
+	// contracts in permissioned deployment mode.
+	prefixDeployer = 21
 
 	defaultMinimumDeploymentFee     = 10_00000000
 	contractDeployNotificationName  = "Deploy"
 	contractUpdateNotificationName  = "Update"
 	contractDestroyNotificationName = "Destroy"
+	deployerAddedNotificationName   = "DeployerAdded"
+	deployerRemovedNotificationName = "DeployerRemoved"
 )
 
 var (
@@ -97,9 +122,10 @@ func MakeContractKey(h util.Uint160) []byte {
 }
 
 // newManagement creates a new Management native contract.
-func newManagement() *Management {
+func newManagement(permissionedDeployment bool) *Management {
 	var m = &Management{
-		ContractMD: *interop.NewContractMD(nativenames.Management, ManagementContractID),
+		ContractMD:             *interop.NewContractMD(nativenames.Management, ManagementContractID),
+		permissionedDeployment: permissionedDeployment,
 	}
 	defer m.BuildHFSpecificMD(m.ActiveIn())
 
@@ -163,6 +189,23 @@ func newManagement() *Management {
 	md = newMethodAndPrice(m.getContractHashes, 1<<15, callflag.ReadStates)
 	m.AddMethod(md, desc)
 
+	if permissionedDeployment {
+		desc = newDescriptor("isDeployer", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(m.isDeployer, 1<<15, callflag.ReadStates)
+		m.AddMethod(md, desc)
+
+		desc = newDescriptor("addDeployer", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(m.addDeployer, 1<<15, callflag.States|callflag.AllowNotify)
+		m.AddMethod(md, desc)
+
+		desc = newDescriptor("removeDeployer", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(m.removeDeployer, 1<<15, callflag.States|callflag.AllowNotify)
+		m.AddMethod(md, desc)
+	}
+
 	hashParam := manifest.NewParameter("Hash", smartcontract.Hash160Type)
 	eDesc := newEventDescriptor(contractDeployNotificationName, hashParam)
 	eMD := newEvent(eDesc)
@@ -175,6 +218,17 @@ func newManagement() *Management {
 	eDesc = newEventDescriptor(contractDestroyNotificationName, hashParam)
 	eMD = newEvent(eDesc)
 	m.AddEvent(eMD)
+
+	if permissionedDeployment {
+		accountParam := manifest.NewParameter("Account", smartcontract.Hash160Type)
+		eDesc = newEventDescriptor(deployerAddedNotificationName, accountParam)
+		eMD = newEvent(eDesc)
+		m.AddEvent(eMD)
+
+		eDesc = newEventDescriptor(deployerRemovedNotificationName, accountParam)
+		eMD = newEvent(eDesc)
+		m.AddEvent(eMD)
+	}
 	return m
 }
 
@@ -367,6 +421,10 @@ func (m *Management) deployWithData(ic *interop.Context, args []stackitem.Item)
 	if ic.Tx == nil {
 		panic(errors.New("no transaction provided"))
 	}
+	err = m.checkDeploymentPermission(ic)
+	if err != nil {
+		panic(err)
+	}
 	newcontract, err := m.Deploy(ic, ic.Tx.Sender(), neff, manif)
 	if err != nil {
 		panic(err)
@@ -439,6 +497,10 @@ func (m *Management) updateWithData(ic *interop.Context, args []stackitem.Item)
 	if neff == nil && manif == nil {
 		panic(errors.New("both NEF and manifest are nil"))
 	}
+	err = m.checkDeploymentPermission(ic)
+	if err != nil {
+		panic(err)
+	}
 	contract, err := m.Update(ic, ic.VM.GetCallingScriptHash(), neff, manif)
 	if err != nil {
 		panic(err)
@@ -492,8 +554,12 @@ func (m *Management) Update(ic *interop.Context, hash util.Uint160, neff *nef.Fi
 // destroy is an implementation of destroy update method, it's run under
 // VM protections, so it's OK for it to panic instead of returning errors.
 func (m *Management) destroy(ic *interop.Context, sis []stackitem.Item) stackitem.Item {
+	err := m.checkDeploymentPermission(ic)
+	if err != nil {
+		panic(err)
+	}
 	hash := ic.VM.GetCallingScriptHash()
-	err := m.Destroy(ic.DAO, hash)
+	err = m.Destroy(ic.DAO, hash)
 	if err != nil {
 		panic(err)
 	}
@@ -521,6 +587,33 @@ func (m *Management) Destroy(d *dao.Simple, hash util.Uint160) error {
 	return nil
 }
 
+// ImportContract saves the given contract state into the given DAO as if it
+// was deployed with its ID and hash and ensures that subsequent deployments
+// won't reuse this ID. It's intended to be used for chain state seeding, so
+// the contract is not checked and no notifications are emitted.
+func (m *Management) ImportContract(d *dao.Simple, cs *state.Contract) error {
+	if cs.ID <= 0 {
+		return fmt.Errorf("invalid contract ID %d", cs.ID)
+	}
+	if _, err := GetContract(d, cs.Hash); err == nil {
+		return fmt.Errorf("contract %s already exists", cs.Hash.StringLE())
+	}
+	if _, err := GetContractScriptHash(d, cs.ID); err == nil {
+		return fmt.Errorf("contract with ID %d already exists", cs.ID)
+	}
+	key := MakeContractKey(cs.Hash)
+	if err := putConvertibleToDAO(m.ID, d, key, cs); err != nil {
+		return err
+	}
+	markUpdated(d, cs.Hash, cs)
+	key = putHashKey(key, cs.ID)
+	d.PutStorageItem(m.ID, key, cs.Hash.BytesBE())
+	if getIntWithKey(m.ID, d, keyNextAvailableID) <= int64(cs.ID) {
+		setIntWithKey(m.ID, d, keyNextAvailableID, int64(cs.ID)+1)
+	}
+	return nil
+}
+
 func (m *Management) getMinimumDeploymentFee(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	return stackitem.NewBigInteger(big.NewInt(m.minimumDeploymentFee(ic.DAO)))
 }
@@ -542,6 +635,84 @@ func (m *Management) setMinimumDeploymentFee(ic *interop.Context, args []stackit
 	return stackitem.Null{}
 }
 
+// checkDeploymentPermission checks whether the current invocation is allowed
+// to deploy, update or destroy contracts. It always succeeds if permissioned
+// deployment mode is disabled, otherwise it requires a witness of the
+// committee, of designated ContractDeployer nodes or of one of the transaction
+// signers that is in the list of allowed deployers. Note that update and
+// destroy are always called by the contract being changed (not by the entry
+// script), so CalledByEntry witnesses can't pass this check for them, the
+// witness scope must cover Management contract (Global or CustomContracts).
+func (m *Management) checkDeploymentPermission(ic *interop.Context) error {
+	if !m.permissionedDeployment {
+		return nil
+	}
+	if m.NEO.checkCommittee(ic) {
+		return nil
+	}
+	h, err := m.Designate.GetLastDesignatedHash(ic.DAO, noderoles.ContractDeployer)
+	if err == nil && !h.Equals(util.Uint160{}) {
+		ok, err := runtime.CheckHashedWitness(ic, h)
+		if err == nil && ok {
+			return nil
+		}
+	}
+	if ic.Tx != nil {
+		for _, s := range ic.Tx.Signers {
+			if !m.isDeployerInternal(ic.DAO, s.Account) {
+				continue
+			}
+			ok, err := runtime.CheckHashedWitness(ic, s.Account)
+			if err == nil && ok {
+				return nil
+			}
+		}
+	}
+	return errors.New("contract management is not permitted for the current signers")
+}
+
+// isDeployer is a Management contract method that checks whether the given
+// account is allowed to manage contracts in permissioned deployment mode.
+func (m *Management) isDeployer(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	return stackitem.NewBool(m.isDeployerInternal(ic.DAO, toUint160(args[0])))
+}
+
+func (m *Management) isDeployerInternal(d *dao.Simple, h util.Uint160) bool {
+	return d.GetStorageItem(m.ID, makeUint160Key(prefixDeployer, h)) != nil
+}
+
+// addDeployer is a Management contract method that adds the given account to
+// the list of allowed deployers and emits DeployerAdded notification. It returns
+// false if the account is already there.
+func (m *Management) addDeployer(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	if !m.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	h := toUint160(args[0])
+	if m.isDeployerInternal(ic.DAO, h) {
+		return stackitem.NewBool(false)
+	}
+	ic.DAO.PutStorageItem(m.ID, makeUint160Key(prefixDeployer, h), state.StorageItem{})
+	m.emitNotification(ic, deployerAddedNotificationName, h)
+	return stackitem.NewBool(true)
+}
+
+// removeDeployer is a Management contract method that removes the given account
+// from the list of allowed deployers and emits DeployerRemoved notification. It
+// returns false if there is no such account.
+func (m *Management) removeDeployer(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	if !m.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	h := toUint160(args[0])
+	if !m.isDeployerInternal(ic.DAO, h) {
+		return stackitem.NewBool(false)
+	}
+	ic.DAO.DeleteStorageItem(m.ID, makeUint160Key(prefixDeployer, h))
+	m.emitNotification(ic, deployerRemovedNotificationName, h)
+	return stackitem.NewBool(true)
+}
+
 func (m *Management) callDeploy(ic *interop.Context, cs *state.Contract, data stackitem.Item, isUpdate bool) {
 	md := cs.Manifest.ABI.GetMethod(manifest.MethodDeploy, 2)
 	if md != nil {
@@ -683,7 +854,39 @@ func (m *Management) OnPersist(ic *interop.Context) error {
 		}
 		m.emitNotification(ic, ntfName, cs.Hash)
 	}
+	if ic.Block.Index == 0 {
+		return m.deployGenesisContracts(ic)
+	}
+
+	return nil
+}
 
+// deployGenesisContracts deploys contracts from GenesisContracts list using
+// the genesis block's NextConsensus (standby validators multisignature) as
+// a sender, runs their _deploy methods and puts the configured storage items.
+func (m *Management) deployGenesisContracts(ic *interop.Context) error {
+	for i := range m.GenesisContracts {
+		gc := &m.GenesisContracts[i]
+		cs, err := m.Deploy(ic, ic.Block.NextConsensus, &gc.NEF, &gc.Manifest)
+		if err != nil {
+			return fmt.Errorf("failed to deploy genesis contract %s: %w", gc.Manifest.Name, err)
+		}
+		m.emitNotification(ic, contractDeployNotificationName, cs.Hash)
+		if md := cs.Manifest.ABI.GetMethod(manifest.MethodDeploy, 2); md != nil {
+			var data = gc.Data
+			if data == nil {
+				data = stackitem.Null{}
+			}
+			err = contract.CallFromNative(ic, m.Hash, cs, manifest.MethodDeploy,
+				[]stackitem.Item{data, stackitem.NewBool(false)}, false)
+			if err != nil {
+				return fmt.Errorf("failed to call _deploy of genesis contract %s: %w", gc.Manifest.Name, err)
+			}
+		}
+		for _, si := range gc.Storage {
+			ic.DAO.PutStorageItem(cs.ID, si.Key, si.Value)
+		}
+	}
 	return nil
 }
 
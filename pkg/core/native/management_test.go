@@ -16,7 +16,7 @@ import (
 )
 
 func TestDeployGetUpdateDestroyContract(t *testing.T) {
-	mgmt := newManagement()
+	mgmt := newManagement(false)
 	mgmt.Policy = newPolicy(false)
 	d := dao.NewSimple(storage.NewMemoryStore(), false)
 	ic := &interop.Context{DAO: d}
@@ -82,19 +82,19 @@ func TestDeployGetUpdateDestroyContract(t *testing.T) {
 func TestManagement_Initialize(t *testing.T) {
 	t.Run("good", func(t *testing.T) {
 		d := dao.NewSimple(storage.NewMemoryStore(), false)
-		mgmt := newManagement()
+		mgmt := newManagement(false)
 		require.NoError(t, mgmt.InitializeCache(0, d))
 	})
 	t.Run("invalid contract state", func(t *testing.T) {
 		d := dao.NewSimple(storage.NewMemoryStore(), false)
-		mgmt := newManagement()
+		mgmt := newManagement(false)
 		d.PutStorageItem(mgmt.ID, []byte{PrefixContract}, state.StorageItem{0xFF})
 		require.Error(t, mgmt.InitializeCache(0, d))
 	})
 }
 
 func TestManagement_GetNEP17Contracts(t *testing.T) {
-	mgmt := newManagement()
+	mgmt := newManagement(false)
 	mgmt.Policy = newPolicy(false)
 	d := dao.NewSimple(storage.NewMemoryStore(), false)
 	err := mgmt.Initialize(&interop.Context{DAO: d}, nil, nil)
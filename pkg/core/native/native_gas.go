@@ -151,6 +151,25 @@ func (g *GAS) BalanceOf(d *dao.Simple, acc util.Uint160) *big.Int {
 	return g.balanceOfInternal(d, acc)
 }
 
+// SetBalance sets the GAS balance of the account to the given (non-negative)
+// amount adjusting the total supply accordingly. No notifications are emitted,
+// it's intended to be used for state overrides in test invocations only.
+func (g *GAS) SetBalance(d *dao.Simple, acc util.Uint160, amount *big.Int) {
+	var (
+		key = makeAccountKey(acc)
+		old = g.balanceOfInternal(d, acc)
+	)
+	if amount.Sign() == 0 {
+		d.DeleteStorageItem(g.ID, key)
+	} else {
+		bal := state.NEP17Balance{Balance: *amount}
+		d.PutStorageItem(g.ID, key, bal.Bytes(nil))
+	}
+	si, supply := g.getTotalSupply(d)
+	supply.Add(supply, new(big.Int).Sub(amount, old))
+	g.saveTotalSupply(d, si, supply)
+}
+
 func getStandbyValidatorsHash(ic *interop.Context) (util.Uint160, error) {
 	cfg := ic.Chain.GetConfig()
 	committee, err := keys.NewPublicKeysFromStrings(cfg.StandbyCommittee)
@@ -1,14 +1,30 @@
 package native_test
 
 import (
+	"crypto/ed25519"
 	"encoding/hex"
+	"math/big"
 	"strconv"
 	"strings"
 	"testing"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	gnarkmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/poseidon2"
+	poseidon2circuit "github.com/epicchainlabs/epicchain-go/pkg/crypto/poseidon2/circuit"
 	"github.com/epicchainlabs/epicchain-go/pkg/io"
 	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
 	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
@@ -470,3 +486,314 @@ func TestVerifyGroth16Proof(t *testing.T) {
 	// Verify.
 	validatorInvoker.Invoke(t, true, "verifyProof", argA, argB, argC, []interface{}{publicWitness})
 }
+
+func TestCryptolib_RecoverSecp256K1(t *testing.T) {
+	c := newCryptolibClient(t)
+
+	pk, err := keys.NewSecp256k1PrivateKey()
+	require.NoError(t, err)
+	msgHash := hash.Sha256([]byte("Ethereum-signed message")).BytesBE()
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(pk.Bytes()), msgHash, true)
+	recID := compact[0] - 27 - 4 // Compressed key flag is set.
+	expected := stackitem.NewByteArray(pk.PublicKey().Bytes())
+
+	// r || s || v, where v is 27 or 28.
+	sig := append(append([]byte{}, compact[1:]...), recID+27)
+	c.Invoke(t, expected, "recoverSecp256K1", msgHash, sig)
+
+	// r || s || v, where v is 0 or 1.
+	sig[64] = recID
+	c.Invoke(t, expected, "recoverSecp256K1", msgHash, sig)
+
+	// EIP-2098 compact signature, r || yParityAndS.
+	sig = append([]byte{}, compact[1:]...)
+	sig[32] |= recID << 7
+	c.Invoke(t, expected, "recoverSecp256K1", msgHash, sig)
+
+	// Bad data.
+	c.Invoke(t, stackitem.Null{}, "recoverSecp256K1", msgHash[1:], sig)
+	c.Invoke(t, stackitem.Null{}, "recoverSecp256K1", msgHash, sig[1:])
+	sig = append(append([]byte{}, compact[1:]...), 42)
+	c.Invoke(t, stackitem.Null{}, "recoverSecp256K1", msgHash, sig)
+	c.Invoke(t, stackitem.Null{}, "recoverSecp256K1", msgHash, make([]byte, 65))
+}
+
+func TestCryptolib_VerifyWithEd25519(t *testing.T) {
+	c := newCryptolibClient(t)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	msg := []byte("Ed25519-signed message")
+	sig := ed25519.Sign(priv, msg)
+
+	c.Invoke(t, true, "verifyWithEd25519", msg, []byte(pub), sig)
+	c.Invoke(t, false, "verifyWithEd25519", append(msg, 1), []byte(pub), sig)
+	c.Invoke(t, false, "verifyWithEd25519", msg, []byte(pub)[1:], sig)
+	c.Invoke(t, false, "verifyWithEd25519", msg, []byte(pub), sig[1:])
+	sig[0] ^= 0xff
+	c.Invoke(t, false, "verifyWithEd25519", msg, []byte(pub), sig)
+}
+
+func TestCryptolib_DomovoiMethods(t *testing.T) {
+	const domovoiHeight = 6
+	c := newCustomNativeClient(t, nativenames.CryptoLib, func(cfg *config.Blockchain) {
+		cfg.Hardforks = map[string]uint32{
+			config.HFAspidochelone.String(): 0,
+			config.HFBasilisk.String():      0,
+			config.HFCockatrice.String():    0,
+			config.HFDomovoi.String():       domovoiHeight,
+		}
+	})
+
+	// Invoke Domovoi-dependant methods before Domovoi should fail.
+	c.InvokeFail(t, "method not found: verifyWithEd25519/3", "verifyWithEd25519", []byte{}, []byte{}, []byte{})
+	c.InvokeFail(t, "method not found: recoverSecp256K1/2", "recoverSecp256K1", []byte{}, []byte{})
+	c.InvokeFail(t, "method not found: bn254Deserialize/1", "bn254Deserialize", []byte{})
+	c.InvokeFail(t, "method not found: mimcBls12381/1", "mimcBls12381", []byte{})
+	c.InvokeFail(t, "method not found: poseidon2Bls12381/1", "poseidon2Bls12381", []byte{})
+
+	// Invoke Domovoi-dependant methods at Domovoi should be OK.
+	tx := c.NewUnsignedTx(t, c.Hash, "verifyWithEd25519", []byte{}, []byte{}, []byte{})
+	c.SignTx(t, tx, 1_0000_0000, c.Signers...)
+	c.AddNewBlock(t, tx)
+	c.CheckHalt(t, tx.Hash(), stackitem.NewBool(false))
+
+	// Invoke Domovoi-dependant methods after Domovoi should be OK.
+	c.Invoke(t, stackitem.Null{}, "recoverSecp256K1", []byte{}, []byte{})
+}
+
+func TestCryptolib_Bn254(t *testing.T) {
+	c := newCryptolibClient(t)
+
+	_, _, g1Aff, g2Aff := bn254.Generators()
+	g1Bytes := g1Aff.Bytes()
+	g2Bytes := g2Aff.Bytes()
+
+	var k bn254fr.Element
+	k.SetUint64(42)
+	var kBytes [bn254fr.Bytes]byte // 32-bytes LE scalar, as CryptoLib accepts it.
+	bn254fr.LittleEndian.PutElement(&kBytes, k)
+	kBi := k.BigInt(new(big.Int))
+
+	var kG1 bn254.G1Affine
+	kG1.ScalarMultiplication(&g1Aff, kBi)
+	kG1Bytes := kG1.Bytes()
+	var kG2 bn254.G2Affine
+	kG2.ScalarMultiplication(&g2Aff, kBi)
+	kG2Bytes := kG2.Bytes()
+	var twoG1 bn254.G1Affine
+	twoG1.Add(&g1Aff, &g1Aff)
+	twoG1Bytes := twoG1.Bytes()
+
+	// checkBytes runs the given script that leaves a single point on stack and
+	// checks its serialized form.
+	checkBytes := func(t *testing.T, script []byte, expected []byte) {
+		stack, err := c.TestInvokeScript(t, script, c.Signers)
+		require.NoError(t, err)
+		require.Equal(t, 1, stack.Len())
+		itm := stack.Pop().Item()
+		require.Equal(t, stackitem.InteropT, itm.Type())
+		actual, ok := itm.(*stackitem.Interop).Value().(serializable)
+		require.True(t, ok)
+		require.Equal(t, expected, actual.Bytes())
+	}
+	checkBool := func(t *testing.T, script []byte, expected bool) {
+		stack, err := c.TestInvokeScript(t, script, c.Signers)
+		require.NoError(t, err)
+		require.Equal(t, 1, stack.Len())
+		itm := stack.Pop().Item()
+		require.Equal(t, stackitem.BooleanT, itm.Type())
+		require.Equal(t, expected, itm.Value().(bool))
+	}
+
+	t.Run("serialize", func(t *testing.T) {
+		script := io.NewBufBinWriter()
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g2Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH1, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Serialize", callflag.All)
+		stack, err := c.TestInvokeScript(t, script.Bytes(), c.Signers)
+		require.NoError(t, err)
+		require.Equal(t, 1, stack.Len())
+		require.Equal(t, g2Bytes[:], stack.Pop().Bytes())
+	})
+	t.Run("deserialize", func(t *testing.T) {
+		checkBytes(t, func() []byte {
+			script := io.NewBufBinWriter()
+			emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+			return script.Bytes()
+		}(), g1Bytes[:])
+
+		// Find the compressed X coordinate that doesn't belong to the curve.
+		bad := append([]byte{}, g1Bytes[:]...)
+		for {
+			bad[len(bad)-1]++
+			if _, err := new(bn254.G1Affine).SetBytes(bad); err != nil {
+				break
+			}
+		}
+		c.InvokeFail(t, "failed to decode bn254 G1Affine point", "bn254Deserialize", bad)
+		c.InvokeFail(t, "invalid serialized bn254 point length: 31", "bn254Deserialize", g1Bytes[1:])
+	})
+	t.Run("add", func(t *testing.T) {
+		script := io.NewBufBinWriter()
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Add", callflag.All)
+		checkBytes(t, script.Bytes(), twoG1Bytes[:])
+
+		script = io.NewBufBinWriter()
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g2Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Add", callflag.All)
+		_, err := c.TestInvokeScript(t, script.Bytes(), c.Signers)
+		require.ErrorContains(t, err, "inconsistent bn254 point types")
+	})
+	t.Run("mul", func(t *testing.T) {
+		mul := func(point []byte, neg bool) []byte {
+			script := io.NewBufBinWriter()
+			emit.Bool(script.BinWriter, neg)
+			emit.Bytes(script.BinWriter, kBytes[:])
+			emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, point)
+			emit.Opcodes(script.BinWriter, opcode.PUSH3, opcode.PACK)
+			emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Mul", callflag.All)
+			return script.Bytes()
+		}
+		checkBytes(t, mul(g1Bytes[:], false), kG1Bytes[:])
+		checkBytes(t, mul(g2Bytes[:], false), kG2Bytes[:])
+
+		var negKG1 bn254.G1Affine
+		negKG1.Neg(&kG1)
+		negKG1Bytes := negKG1.Bytes()
+		checkBytes(t, mul(g1Bytes[:], true), negKG1Bytes[:])
+	})
+	t.Run("pairing and equal", func(t *testing.T) {
+		// e(k*G1, G2) == e(G1, k*G2).
+		script := io.NewBufBinWriter()
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g2Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, kG1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Pairing", callflag.All)
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, kG2Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Pairing", callflag.All)
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Equal", callflag.All)
+		checkBool(t, script.Bytes(), true)
+
+		// e(G1, G2) != e(G1, k*G2).
+		script = io.NewBufBinWriter()
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g2Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Pairing", callflag.All)
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, kG2Bytes[:])
+		emit.AppCall(script.BinWriter, c.Hash, "bn254Deserialize", callflag.All, g1Bytes[:])
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Pairing", callflag.All)
+		emit.Opcodes(script.BinWriter, opcode.PUSH2, opcode.PACK)
+		emit.AppCallNoArgs(script.BinWriter, c.Hash, "bn254Equal", callflag.All)
+		checkBool(t, script.Bytes(), false)
+	})
+}
+
+// mimcCircuit checks that MiMC hash of X and Y equals to Hash.
+type mimcCircuit struct {
+	X, Y frontend.Variable
+	Hash frontend.Variable `gnark:",public"`
+}
+
+// Define implements frontend.Circuit interface.
+func (circuit *mimcCircuit) Define(api frontend.API) error {
+	h, err := gnarkmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h.Write(circuit.X, circuit.Y)
+	api.AssertIsEqual(circuit.Hash, h.Sum())
+	return nil
+}
+
+func TestCryptolib_MimcBls12381(t *testing.T) {
+	c := newCryptolibClient(t)
+
+	var x, y fr.Element
+	x.SetUint64(42)
+	y.SetRandom()
+	xBytes, yBytes := x.Bytes(), y.Bytes()
+	data := append(xBytes[:], yBytes[:]...)
+
+	h := mimc.NewMiMC()
+	_, err := h.Write(data)
+	require.NoError(t, err)
+	expected := h.Sum(nil)
+	c.Invoke(t, expected, "mimcBls12381", data)
+
+	// The result is compatible with gnark circuits.
+	var sum fr.Element
+	sum.SetBytes(expected)
+	err = test.IsSolved(&mimcCircuit{}, &mimcCircuit{X: x, Y: y, Hash: sum}, ecc.BLS12_381.ScalarField())
+	require.NoError(t, err)
+
+	// Invalid data.
+	c.InvokeFail(t, "invalid data length: must be a multiple of 32", "mimcBls12381", data[1:])
+	nonCanonical := make([]byte, fr.Bytes)
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	c.InvokeFail(t, "invalid field element", "mimcBls12381", nonCanonical)
+
+	// Price depends on the number of elements.
+	tx1 := c.PrepareInvoke(t, "mimcBls12381", data[:fr.Bytes])
+	tx2 := c.PrepareInvoke(t, "mimcBls12381", data)
+	require.Less(t, tx1.SystemFee, tx2.SystemFee)
+}
+
+// poseidon2Circuit checks that Poseidon2 hash of X and Y equals to Hash.
+type poseidon2Circuit struct {
+	X, Y frontend.Variable
+	Hash frontend.Variable `gnark:",public"`
+}
+
+// Define implements frontend.Circuit interface.
+func (circuit *poseidon2Circuit) Define(api frontend.API) error {
+	h := poseidon2circuit.NewHasher(api)
+	h.Write(circuit.X, circuit.Y)
+	api.AssertIsEqual(circuit.Hash, h.Sum())
+	return nil
+}
+
+func TestCryptolib_Poseidon2Bls12381(t *testing.T) {
+	c := newCryptolibClient(t)
+
+	var x, y fr.Element
+	x.SetUint64(42)
+	y.SetRandom()
+	xBytes, yBytes := x.Bytes(), y.Bytes()
+	data := append(xBytes[:], yBytes[:]...)
+
+	expected, err := poseidon2.Hash(data)
+	require.NoError(t, err)
+	c.Invoke(t, expected, "poseidon2Bls12381", data)
+
+	// The result is compatible with the circuit gadget.
+	var sum fr.Element
+	sum.SetBytes(expected)
+	err = test.IsSolved(&poseidon2Circuit{}, &poseidon2Circuit{X: x, Y: y, Hash: sum}, ecc.BLS12_381.ScalarField())
+	require.NoError(t, err)
+
+	// Invalid data.
+	c.InvokeFail(t, "invalid data length: must be a multiple of 32", "poseidon2Bls12381", data[1:])
+	nonCanonical := make([]byte, fr.Bytes)
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	c.InvokeFail(t, "invalid field element", "poseidon2Bls12381", nonCanonical)
+
+	// Price depends on the number of elements.
+	tx1 := c.PrepareInvoke(t, "poseidon2Bls12381", data[:fr.Bytes])
+	tx2 := c.PrepareInvoke(t, "poseidon2Bls12381", data)
+	require.Less(t, tx1.SystemFee, tx2.SystemFee)
+}
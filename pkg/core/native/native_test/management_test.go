@@ -14,6 +14,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativehashes"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
@@ -27,10 +28,12 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/nef"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/emit"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/vmstate"
+	"github.com/epicchainlabs/epicchain-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,6 +58,11 @@ var (
 		nativenames.CryptoLib: `{"id":-3,"hash":"0x726cb6e0cd8628a1350a611384688911ab75f51b","nef":{"magic":860243278,"compiler":"neo-core-v3.0","source":"","tokens":[],"script":"EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=","checksum":1094259016},"manifest":{"name":"CryptoLib","abi":{"methods":[{"name":"bls12381Add","offset":0,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Deserialize","offset":7,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Equal","offset":14,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"Boolean","safe":true},{"name":"bls12381Mul","offset":21,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"mul","type":"ByteArray"},{"name":"neg","type":"Boolean"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Pairing","offset":28,"parameters":[{"name":"g1","type":"InteropInterface"},{"name":"g2","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Serialize","offset":35,"parameters":[{"name":"g","type":"InteropInterface"}],"returntype":"ByteArray","safe":true},{"name":"keccak256","offset":42,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"murmur32","offset":49,"parameters":[{"name":"data","type":"ByteArray"},{"name":"seed","type":"Integer"}],"returntype":"ByteArray","safe":true},{"name":"ripemd160","offset":56,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"sha256","offset":63,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"verifyWithECDsa","offset":70,"parameters":[{"name":"message","type":"ByteArray"},{"name":"pubkey","type":"ByteArray"},{"name":"signature","type":"ByteArray"},{"name":"curveHash","type":"Integer"}],"returntype":"Boolean","safe":true}],"events":[]},"features":{},"groups":[],"permissions":[{"contract":"*","methods":"*"}],"supportedstandards":[],"trusts":[],"extra":null},"updatecounter":0}`,
 		nativenames.Neo:       `{"id":-5,"hash":"0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5","nef":{"magic":860243278,"compiler":"neo-core-v3.0","source":"","tokens":[],"script":"EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=","checksum":1325686241},"manifest":{"name":"NeoToken","abi":{"methods":[{"name":"balanceOf","offset":0,"parameters":[{"name":"account","type":"Hash160"}],"returntype":"Integer","safe":true},{"name":"decimals","offset":7,"parameters":[],"returntype":"Integer","safe":true},{"name":"getAccountState","offset":14,"parameters":[{"name":"account","type":"Hash160"}],"returntype":"Array","safe":true},{"name":"getAllCandidates","offset":21,"parameters":[],"returntype":"InteropInterface","safe":true},{"name":"getCandidateVote","offset":28,"parameters":[{"name":"pubKey","type":"PublicKey"}],"returntype":"Integer","safe":true},{"name":"getCandidates","offset":35,"parameters":[],"returntype":"Array","safe":true},{"name":"getCommittee","offset":42,"parameters":[],"returntype":"Array","safe":true},{"name":"getCommitteeAddress","offset":49,"parameters":[],"returntype":"Hash160","safe":true},{"name":"getGasPerBlock","offset":56,"parameters":[],"returntype":"Integer","safe":true},{"name":"getNextBlockValidators","offset":63,"parameters":[],"returntype":"Array","safe":true},{"name":"getRegisterPrice","offset":70,"parameters":[],"returntype":"Integer","safe":true},{"name":"registerCandidate","offset":77,"parameters":[{"name":"pubkey","type":"PublicKey"}],"returntype":"Boolean","safe":false},{"name":"setGasPerBlock","offset":84,"parameters":[{"name":"gasPerBlock","type":"Integer"}],"returntype":"Void","safe":false},{"name":"setRegisterPrice","offset":91,"parameters":[{"name":"registerPrice","type":"Integer"}],"returntype":"Void","safe":false},{"name":"symbol","offset":98,"parameters":[],"returntype":"String","safe":true},{"name":"totalSupply","offset":105,"parameters":[],"returntype":"Integer","safe":true},{"name":"transfer","offset":112,"parameters":[{"name":"from","type":"Hash160"},{"name":"to","type":"Hash160"},{"name":"amount","type":"Integer"},{"name":"data","type":"Any"}],"returntype":"Boolean","safe":false},{"name":"unclaimedGas","offset":119,"parameters":[{"name":"account","type":"Hash160"},{"name":"end","type":"Integer"}],"returntype":"Integer","safe":true},{"name":"unregisterCandidate","offset":126,"parameters":[{"name":"pubkey","type":"PublicKey"}],"returntype":"Boolean","safe":false},{"name":"vote","offset":133,"parameters":[{"name":"account","type":"Hash160"},{"name":"voteTo","type":"PublicKey"}],"returntype":"Boolean","safe":false}],"events":[{"name":"Transfer","parameters":[{"name":"from","type":"Hash160"},{"name":"to","type":"Hash160"},{"name":"amount","type":"Integer"}]},{"name":"CandidateStateChanged","parameters":[{"name":"pubkey","type":"PublicKey"},{"name":"registered","type":"Boolean"},{"name":"votes","type":"Integer"}]},{"name":"Vote","parameters":[{"name":"account","type":"Hash160"},{"name":"from","type":"PublicKey"},{"name":"to","type":"PublicKey"},{"name":"amount","type":"Integer"}]},{"name":"CommitteeChanged","parameters":[{"name":"old","type":"Array"},{"name":"new","type":"Array"}]}]},"features":{},"groups":[],"permissions":[{"contract":"*","methods":"*"}],"supportedstandards":["NEP-17"],"trusts":[],"extra":null},"updatecounter":0}`,
 	}
+	// domovoiCSS holds serialized native contract states built for genesis block (with UpdateCounter 0)
+	// under assumption that hardforks from Aspidochelone to Domovoi (included) are enabled.
+	domovoiCSS = map[string]string{
+		nativenames.CryptoLib: `{"id":-3,"hash":"0x726cb6e0cd8628a1350a611384688911ab75f51b","nef":{"magic":860243278,"compiler":"neo-core-v3.0","source":"","tokens":[],"script":"EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0AQQRr3e2dA","checksum":1991619121},"manifest":{"name":"CryptoLib","abi":{"methods":[{"name":"bls12381Add","offset":0,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Deserialize","offset":7,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Equal","offset":14,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"Boolean","safe":true},{"name":"bls12381Mul","offset":21,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"mul","type":"ByteArray"},{"name":"neg","type":"Boolean"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Pairing","offset":28,"parameters":[{"name":"g1","type":"InteropInterface"},{"name":"g2","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bls12381Serialize","offset":35,"parameters":[{"name":"g","type":"InteropInterface"}],"returntype":"ByteArray","safe":true},{"name":"bn254Add","offset":42,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bn254Deserialize","offset":49,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"InteropInterface","safe":true},{"name":"bn254Equal","offset":56,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"y","type":"InteropInterface"}],"returntype":"Boolean","safe":true},{"name":"bn254Mul","offset":63,"parameters":[{"name":"x","type":"InteropInterface"},{"name":"mul","type":"ByteArray"},{"name":"neg","type":"Boolean"}],"returntype":"InteropInterface","safe":true},{"name":"bn254Pairing","offset":70,"parameters":[{"name":"g1","type":"InteropInterface"},{"name":"g2","type":"InteropInterface"}],"returntype":"InteropInterface","safe":true},{"name":"bn254Serialize","offset":77,"parameters":[{"name":"g","type":"InteropInterface"}],"returntype":"ByteArray","safe":true},{"name":"keccak256","offset":84,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"mimcBls12381","offset":91,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"murmur32","offset":98,"parameters":[{"name":"data","type":"ByteArray"},{"name":"seed","type":"Integer"}],"returntype":"ByteArray","safe":true},{"name":"poseidon2Bls12381","offset":105,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"recoverSecp256K1","offset":112,"parameters":[{"name":"messageHash","type":"ByteArray"},{"name":"signature","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"ripemd160","offset":119,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"sha256","offset":126,"parameters":[{"name":"data","type":"ByteArray"}],"returntype":"ByteArray","safe":true},{"name":"verifyWithECDsa","offset":133,"parameters":[{"name":"message","type":"ByteArray"},{"name":"pubkey","type":"ByteArray"},{"name":"signature","type":"ByteArray"},{"name":"curveHash","type":"Integer"}],"returntype":"Boolean","safe":true},{"name":"verifyWithEd25519","offset":140,"parameters":[{"name":"message","type":"ByteArray"},{"name":"pubkey","type":"ByteArray"},{"name":"signature","type":"ByteArray"}],"returntype":"Boolean","safe":true}],"events":[]},"features":{},"groups":[],"permissions":[{"contract":"*","methods":"*"}],"supportedstandards":[],"trusts":[],"extra":null},"updatecounter":0}`,
+	}
 )
 
 func init() {
@@ -63,6 +71,11 @@ func init() {
 			cockatriceCSS[k] = v
 		}
 	}
+	for k, v := range cockatriceCSS {
+		if _, ok := domovoiCSS[k]; !ok {
+			domovoiCSS[k] = v
+		}
+	}
 }
 
 func newManagementClient(t *testing.T) *neotest.ContractInvoker {
@@ -113,6 +126,7 @@ func TestManagement_GenesisNativeState(t *testing.T) {
 				config.HFAspidochelone.String(): 100500,
 				config.HFBasilisk.String():      100500,
 				config.HFCockatrice.String():    100500,
+				config.HFDomovoi.String():       100500,
 			}
 			cfg.P2PSigExtensions = true
 		})
@@ -148,6 +162,18 @@ func TestManagement_GenesisNativeState(t *testing.T) {
 		})
 		check(t, mgmt, cockatriceCSS)
 	})
+	t.Run("Domovoi enabled", func(t *testing.T) {
+		mgmt := newCustomManagementClient(t, func(cfg *config.Blockchain) {
+			cfg.Hardforks = map[string]uint32{
+				config.HFAspidochelone.String(): 0,
+				config.HFBasilisk.String():      0,
+				config.HFCockatrice.String():    0,
+				config.HFDomovoi.String():       0,
+			}
+			cfg.P2PSigExtensions = true
+		})
+		check(t, mgmt, domovoiCSS)
+	})
 }
 
 func TestManagement_NativeDeployUpdateNotifications(t *testing.T) {
@@ -916,3 +942,105 @@ func TestManagement_ContractDestroy(t *testing.T) {
 		managementInvoker.InvokeFail(t, fmt.Sprintf("the contract %s has been blocked", cs1.Hash.StringLE()), "deploy", nefBytes, manifestBytes)
 	})
 }
+
+func TestManagement_PermissionedDeployment(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c := newManagementClient(t)
+		acc := c.NewAccount(t)
+		c.InvokeFail(t, "method not found: addDeployer/1", "addDeployer", acc.ScriptHash())
+
+		designateInvoker := c.CommitteeInvoker(c.NativeHash(t, nativenames.Designation))
+		designateInvoker.InvokeFail(t, native.ErrInvalidRole.Error(), "designateAsRole",
+			int64(noderoles.ContractDeployer), []any{acc.(neotest.SingleSigner).Account().PublicKey().Bytes()})
+	})
+
+	c := newCustomManagementClient(t, func(cfg *config.Blockchain) {
+		cfg.PermissionedDeployment = true
+	})
+	committeeInvoker := c.WithSigners(c.Committee)
+	acc := c.NewAccount(t)
+	accInvoker := c.WithSigners(acc)
+
+	cs1, _ := contracts.GetTestContractState(t, pathToInternalContracts, 1, 2, acc.ScriptHash())
+	cs1.Hash = state.CreateContractHash(acc.ScriptHash(), cs1.NEF.Checksum, cs1.Manifest.Name)
+	// Allow calling management contract.
+	cs1.Manifest.Permissions = []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)}
+	manifestBytes, err := json.Marshal(cs1.Manifest)
+	require.NoError(t, err)
+	nefBytes, err := cs1.NEF.Bytes()
+	require.NoError(t, err)
+	si, err := cs1.ToStackItem()
+	require.NoError(t, err)
+
+	accInvoker.InvokeFail(t, "contract management is not permitted", "deploy", nefBytes, manifestBytes)
+	accInvoker.InvokeFail(t, "invalid committee signature", "addDeployer", acc.ScriptHash())
+	accInvoker.Invoke(t, false, "isDeployer", acc.ScriptHash())
+
+	deployerEvent := func(name string) state.NotificationEvent {
+		return state.NotificationEvent{
+			ScriptHash: c.Hash,
+			Name:       name,
+			Item:       stackitem.NewArray([]stackitem.Item{stackitem.NewByteArray(acc.ScriptHash().BytesBE())}),
+		}
+	}
+	h := committeeInvoker.Invoke(t, true, "addDeployer", acc.ScriptHash())
+	c.CheckTxNotificationEvent(t, h, 0, deployerEvent("DeployerAdded"))
+	h = committeeInvoker.Invoke(t, false, "addDeployer", acc.ScriptHash())
+	require.Empty(t, c.GetTxExecResult(t, h).Events)
+	accInvoker.Invoke(t, true, "isDeployer", acc.ScriptHash())
+	accInvoker.Invoke(t, si, "deploy", nefBytes, manifestBytes)
+
+	accInvoker.InvokeFail(t, "invalid committee signature", "removeDeployer", acc.ScriptHash())
+	h = committeeInvoker.Invoke(t, true, "removeDeployer", acc.ScriptHash())
+	c.CheckTxNotificationEvent(t, h, 0, deployerEvent("DeployerRemoved"))
+	h = committeeInvoker.Invoke(t, false, "removeDeployer", acc.ScriptHash())
+	require.Empty(t, c.GetTxExecResult(t, h).Events)
+	accInvoker.Invoke(t, false, "isDeployer", acc.ScriptHash())
+
+	helperInvoker := c.NewInvoker(cs1.Hash, acc)
+	helperInvoker.InvokeFail(t, "contract management is not permitted", "destroy")
+
+	// Designated ContractDeployer nodes are allowed to manage contracts.
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	designateInvoker := c.CommitteeInvoker(c.NativeHash(t, nativenames.Designation))
+	designateInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(noderoles.ContractDeployer), []any{pk.PublicKey().Bytes()})
+	deployerAcc := wallet.NewAccountFromPrivateKey(pk)
+	require.NoError(t, deployerAcc.ConvertMultisig(1, keys.PublicKeys{pk.PublicKey()}))
+	helperInvoker.WithSigners(acc, neotest.NewMultiSigner(deployerAcc)).Invoke(t, stackitem.Null{}, "destroy")
+	c.Invoke(t, stackitem.Null{}, "getContract", cs1.Hash.BytesBE())
+
+	// Update and destroy are called by the contract itself, so the deployer
+	// witness scope must cover Management contract.
+	committeeInvoker.Invoke(t, true, "addDeployer", acc.ScriptHash())
+	cs1.Manifest.Name = "helper2"
+	cs1.Hash = state.CreateContractHash(acc.ScriptHash(), cs1.NEF.Checksum, cs1.Manifest.Name)
+	manifestBytes, err = json.Marshal(cs1.Manifest)
+	require.NoError(t, err)
+	tx := accInvoker.PrepareInvoke(t, "deploy", nefBytes, manifestBytes)
+	c.AddNewBlock(t, tx)
+	c.CheckHalt(t, tx.Hash())
+	invokeWithSigner := func(signer transaction.Signer, method string, args ...any) util.Uint256 {
+		tx := c.NewUnsignedTx(t, cs1.Hash, method, args...)
+		tx.Signers = []transaction.Signer{signer}
+		neotest.AddNetworkFee(t, c.Chain, tx, acc)
+		neotest.AddSystemFee(c.Chain, tx, -1)
+		require.NoError(t, acc.SignTx(c.Chain.GetConfig().Magic, tx))
+		c.AddNewBlock(t, tx)
+		return tx.Hash()
+	}
+	h = invokeWithSigner(transaction.Signer{Account: acc.ScriptHash(), Scopes: transaction.CalledByEntry}, "update", nefBytes, nil)
+	c.CheckFault(t, h, "contract management is not permitted")
+	h = invokeWithSigner(transaction.Signer{Account: acc.ScriptHash(), Scopes: transaction.CalledByEntry}, "destroy")
+	c.CheckFault(t, h, "contract management is not permitted")
+	h = invokeWithSigner(transaction.Signer{
+		Account:          acc.ScriptHash(),
+		Scopes:           transaction.CustomContracts,
+		AllowedContracts: []util.Uint160{c.Hash},
+	}, "update", nefBytes, nil)
+	c.CheckHalt(t, h)
+	h = invokeWithSigner(transaction.Signer{Account: acc.ScriptHash(), Scopes: transaction.Global}, "destroy")
+	c.CheckHalt(t, h)
+	c.Invoke(t, stackitem.Null{}, "getContract", cs1.Hash.BytesBE())
+}
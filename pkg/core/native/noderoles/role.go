@@ -13,6 +13,9 @@ const (
 	Oracle
 	NeoFSAlphabet
 	P2PNotary
+	// ContractDeployer is allowed to deploy, update and destroy contracts
+	// when permissioned deployment mode is enabled.
+	ContractDeployer
 	// last denotes the end of roles enum. Consider adding new roles before the last.
 	last
 )
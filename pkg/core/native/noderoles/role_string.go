@@ -12,7 +12,8 @@ func _() {
 	_ = x[Oracle-8]
 	_ = x[NeoFSAlphabet-16]
 	_ = x[P2PNotary-32]
-	_ = x[last-64]
+	_ = x[ContractDeployer-64]
+	_ = x[last-128]
 }
 
 const (
@@ -20,7 +21,8 @@ const (
 	_Role_name_1 = "Oracle"
 	_Role_name_2 = "NeoFSAlphabet"
 	_Role_name_3 = "P2PNotary"
-	_Role_name_4 = "last"
+	_Role_name_4 = "ContractDeployer"
+	_Role_name_5 = "last"
 )
 
 func (i Role) String() string {
@@ -35,6 +37,8 @@ func (i Role) String() string {
 		return _Role_name_3
 	case i == 64:
 		return _Role_name_4
+	case i == 128:
+		return _Role_name_5
 	default:
 		return "Role(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
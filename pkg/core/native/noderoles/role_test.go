@@ -8,10 +8,11 @@ import (
 
 func TestFromString(t *testing.T) {
 	valid := map[string]Role{
-		"StateValidator": StateValidator,
-		"Oracle":         Oracle,
-		"NeoFSAlphabet":  NeoFSAlphabet,
-		"P2PNotary":      P2PNotary,
+		"StateValidator":   StateValidator,
+		"Oracle":           Oracle,
+		"NeoFSAlphabet":    NeoFSAlphabet,
+		"P2PNotary":        P2PNotary,
+		"ContractDeployer": ContractDeployer,
 	}
 	for s, expected := range valid {
 		actual, ok := FromString(s)
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"strconv"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -38,6 +41,42 @@ var (
 			Namespace: "neogo",
 		},
 	)
+	// dbKeys prometheus metric.
+	dbKeys = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Number of DB keys per key prefix",
+			Name:      "db_keys",
+			Namespace: "neogo",
+		},
+		[]string{"prefix"},
+	)
+	// dbSize prometheus metric.
+	dbSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Size of DB keys and values per key prefix",
+			Name:      "db_size_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"prefix"},
+	)
+	// dbContractKeys prometheus metric.
+	dbContractKeys = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Number of contract storage items per contract ID",
+			Name:      "db_contract_keys",
+			Namespace: "neogo",
+		},
+		[]string{"contract"},
+	)
+	// dbContractSize prometheus metric.
+	dbContractSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Size of contract storage items per contract ID",
+			Name:      "db_contract_size_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"contract"},
+	)
 )
 
 func init() {
@@ -46,6 +85,10 @@ func init() {
 		persistedHeight,
 		headerHeight,
 		mempoolUnsortedTx,
+		dbKeys,
+		dbSize,
+		dbContractKeys,
+		dbContractSize,
 	)
 }
 
@@ -65,3 +108,20 @@ func updateBlockHeightMetric(bHeight uint32) {
 func updateMempoolMetrics(unsortedTxnLen int) {
 	mempoolUnsortedTx.Set(float64(unsortedTxnLen))
 }
+
+// updateStorageMetrics updates DB statistics metrics.
+func updateStorageMetrics(s *storage.Stats) {
+	dbKeys.Reset()
+	dbSize.Reset()
+	for p, ps := range s.Prefixes {
+		dbKeys.WithLabelValues(p.String()).Set(float64(ps.Keys))
+		dbSize.WithLabelValues(p.String()).Set(float64(ps.Total()))
+	}
+	dbContractKeys.Reset()
+	dbContractSize.Reset()
+	for id, cs := range s.Contracts {
+		label := strconv.FormatInt(int64(id), 10)
+		dbContractKeys.WithLabelValues(label).Set(float64(cs.Keys))
+		dbContractSize.WithLabelValues(label).Set(float64(cs.Total()))
+	}
+}
@@ -60,7 +60,7 @@ func NewModule(cfg config.Blockchain, verif VerifierFunc, log *zap.Logger, s *st
 	if cfg.Ledger.KeepOnlyLatestState {
 		mode |= mpt.ModeLatest
 	}
-	if cfg.Ledger.RemoveUntraceableBlocks {
+	if cfg.Ledger.RemoveUntraceableBlocks || cfg.Ledger.StateRetention != 0 {
 		mode |= mpt.ModeGC
 	}
 	return &Module{
@@ -93,6 +93,17 @@ func (s *Module) FindStates(root util.Uint256, prefix, start []byte, max int) ([
 	return tr.Find(prefix, start, max)
 }
 
+// DiffStates calls f for every key with the given prefix (and following the
+// prefix+start path if start is not nil) that has different values in MPTs
+// with the specified roots. Keys are passed in ascending order, oldV is nil
+// for keys missing from the old MPT and newV is nil for keys missing from the
+// new one. Traversal process is stopped when `false` is returned from f.
+func (s *Module) DiffStates(oldRoot, newRoot util.Uint256, prefix, start []byte, f func(k, oldV, newV []byte) bool) error {
+	// Allow accessing old values, it's RO thing.
+	tr := mpt.NewTrie(mpt.NewHashNode(oldRoot), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	return tr.Diff(newRoot, prefix, start, f)
+}
+
 // SeekStates traverses over contract storage with the state based on the
 // specified root. `prefix` is expected to consist of contract ID and the desired
 // storage items prefix. `cont` is called for every matching key-value pair;
@@ -126,6 +137,24 @@ func (s *Module) GetStateProof(root util.Uint256, key []byte) ([][]byte, error)
 	return tr.GetProof(key)
 }
 
+// GetStateMultiProof returns a proof of presence or absence of all of the keys
+// in the MPT with the specified root (see mpt.Trie.GetMultiProof).
+func (s *Module) GetStateMultiProof(root util.Uint256, keys [][]byte) ([][]byte, error) {
+	// Allow accessing old values, it's RO thing.
+	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	return tr.GetMultiProof(keys)
+}
+
+// GetStateRangeProof returns up to max key-value pairs with the specified
+// prefix (following the `prefix`+`start` path if start is not nil) from the
+// MPT with the specified root along with the proof that there are no other
+// pairs in this range (see mpt.Trie.GetRangeProof).
+func (s *Module) GetStateRangeProof(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, [][]byte, error) {
+	// Allow accessing old values, it's RO thing.
+	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	return tr.GetRangeProof(prefix, start, max)
+}
+
 // GetStateRoot returns state root for a given height.
 func (s *Module) GetStateRoot(height uint32) (*state.MPTRoot, error) {
 	return s.getStateRoot(makeStateRootKey(height))
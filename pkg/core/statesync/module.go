@@ -64,6 +64,7 @@ const (
 type Ledger interface {
 	AddHeaders(...*block.Header) error
 	BlockHeight() uint32
+	GetBlock(hash util.Uint256) (*block.Block, error)
 	GetConfig() config.Blockchain
 	GetHeader(hash util.Uint256) (*block.Header, error)
 	GetHeaderHash(uint32) util.Uint256
@@ -89,6 +90,9 @@ type Module struct {
 	bc       Ledger
 	stateMod *stateroot.Module
 	mptpool  *Pool
+	// progress is the MPT synchronisation progress, it's rebuilt from the
+	// stored MPT nodes on initialization.
+	progress mptProgress
 
 	billet *mpt.Billet
 
@@ -185,7 +189,7 @@ func TemporaryPrefix(currPrefix storage.KeyPrefix) storage.KeyPrefix {
 	case storage.STTempStorage:
 		return storage.STStorage
 	default:
-		panic(fmt.Sprintf("invalid storage prefix: %x", currPrefix))
+		panic(fmt.Sprintf("invalid storage prefix: %x", byte(currPrefix)))
 	}
 }
 
@@ -220,7 +224,8 @@ func (s *Module) defineSyncStage() error {
 		}
 		var mode mpt.TrieMode
 		// No need to enable GC here, it only has latest things.
-		if s.bc.GetConfig().Ledger.KeepOnlyLatestState || s.bc.GetConfig().Ledger.RemoveUntraceableBlocks {
+		if s.bc.GetConfig().Ledger.KeepOnlyLatestState || s.bc.GetConfig().Ledger.RemoveUntraceableBlocks ||
+			s.bc.GetConfig().Ledger.StateRetention != 0 {
 			mode |= mpt.ModeLatest
 		}
 		s.billet = mpt.NewBillet(header.PrevStateRoot, mode,
@@ -230,6 +235,7 @@ func (s *Module) defineSyncStage() error {
 			zap.String("state root", header.PrevStateRoot.StringBE()))
 		pool := NewPool()
 		pool.Add(header.PrevStateRoot, []byte{})
+		s.progress = newMPTProgress()
 		err = s.billet.Traverse(func(_ []byte, n mpt.Node, _ []byte) bool {
 			nPaths, ok := pool.TryGet(n.Hash())
 			if !ok {
@@ -243,6 +249,7 @@ func (s *Module) defineSyncStage() error {
 				for hash, paths := range nChildrenPaths {
 					childrenPaths[hash] = append(childrenPaths[hash], paths...) // it's OK to have duplicates, they'll be handled by mempool
 				}
+				s.progress.restored(path, nChildrenPaths)
 			}
 			pool.Update(nil, childrenPaths)
 			return false
@@ -401,9 +408,11 @@ func (s *Module) restoreNode(n mpt.Node) error {
 		if err != nil {
 			return fmt.Errorf("failed to restore MPT node with hash %s and path %s: %w", n.Hash().StringBE(), hex.EncodeToString(path), err)
 		}
-		for h, paths := range mpt.GetChildrenPaths(path, n) {
+		nChildrenPaths := mpt.GetChildrenPaths(path, n)
+		for h, paths := range nChildrenPaths {
 			childrenPaths[h] = append(childrenPaths[h], paths...) // it's OK to have duplicates, they'll be handled by mempool
 		}
+		s.progress.restored(path, nChildrenPaths)
 	}
 
 	s.mptpool.Update(map[util.Uint256][][]byte{n.Hash(): nPaths}, childrenPaths)
@@ -492,7 +501,8 @@ func (s *Module) Traverse(root util.Uint256, process func(node mpt.Node, nodeByt
 
 	var mode mpt.TrieMode
 	// GC must be turned off here to allow access to the archived nodes.
-	if s.bc.GetConfig().Ledger.KeepOnlyLatestState || s.bc.GetConfig().Ledger.RemoveUntraceableBlocks {
+	if s.bc.GetConfig().Ledger.KeepOnlyLatestState || s.bc.GetConfig().Ledger.RemoveUntraceableBlocks ||
+		s.bc.GetConfig().Ledger.StateRetention != 0 {
 		mode |= mpt.ModeLatest
 	}
 	b := mpt.NewBillet(root, mode, 0, storage.NewMemCachedStore(s.dao.Store))
@@ -508,3 +518,15 @@ func (s *Module) GetUnknownMPTNodesBatch(limit int) []util.Uint256 {
 
 	return s.mptpool.GetBatch(limit)
 }
+
+// GetMPTProgress returns MPT synchronisation statistics. All counters are zero
+// if MPT synchronisation is not started yet.
+func (s *Module) GetMPTProgress() MPTProgress {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mptpool == nil {
+		return MPTProgress{}
+	}
+	return s.progress.get(s.mptpool.Count())
+}
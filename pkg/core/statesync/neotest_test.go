@@ -8,7 +8,10 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/statesync"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
 	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
 	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
@@ -185,6 +188,7 @@ func TestStateSyncModule_Init(t *testing.T) {
 		require.Equal(t, 1, len(unknownNodes))
 		require.Equal(t, expectedHeader.PrevStateRoot, unknownNodes[0])
 		require.Equal(t, uint32(stateSyncPoint), module.BlockHeight())
+		require.Equal(t, statesync.MPTProgress{Pending: 1, Remaining: 1}, module.GetMPTProgress())
 
 		// add a few MPT nodes to create DB state where some of MPT nodes are missing
 		count := 5
@@ -203,6 +207,10 @@ func TestStateSyncModule_Init(t *testing.T) {
 				break
 			}
 		}
+		progress := module.GetMPTProgress()
+		require.True(t, progress.Fetched > 0)
+		require.Equal(t, len(module.GetUnknownMPTNodesBatch(1000)), progress.Pending)
+		require.True(t, progress.Remaining >= uint64(progress.Pending))
 
 		// then create new statesync module with the same DB and check that state is proper
 		// (headers and blocks are in sync, mpt is not yet synced)
@@ -216,6 +224,10 @@ func TestStateSyncModule_Init(t *testing.T) {
 		require.True(t, len(unknownNodes) > 0)
 		require.NotContains(t, unknownNodes, expectedHeader.PrevStateRoot)
 		require.Equal(t, uint32(stateSyncPoint), module.BlockHeight())
+		// progress is restored from the DB
+		restored := module.GetMPTProgress()
+		require.Equal(t, progress.Fetched, restored.Fetched)
+		require.Equal(t, progress.Pending, restored.Pending)
 
 		// add the rest of MPT nodes and jump to state
 		alreadyRequested := make(map[util.Uint256]struct{})
@@ -239,6 +251,10 @@ func TestStateSyncModule_Init(t *testing.T) {
 		}
 
 		// check that module is inactive and statejump is completed
+		restored = module.GetMPTProgress()
+		require.True(t, restored.Fetched >= progress.Fetched+uint64(len(alreadyRequested)))
+		require.Zero(t, restored.Pending)
+		require.Zero(t, restored.Remaining)
 		require.False(t, module.IsActive())
 		require.False(t, module.NeedHeaders())
 		require.False(t, module.NeedMPTNodes())
@@ -471,3 +487,75 @@ func TestStateSyncModule_RestoreBasicChain(t *testing.T) {
 		check(t, true)
 	})
 }
+
+func TestStateSyncModule_Snapshot(t *testing.T) {
+	const (
+		stateSyncInterval = 4
+		maxTraceable      = 6
+		stateSyncPoint    = 12
+	)
+	spoutCfg := func(c *config.Blockchain) {
+		c.StateRootInHeader = true
+		c.P2PStateExchangeExtensions = true
+		c.StateSyncInterval = stateSyncInterval
+		c.MaxTraceableBlocks = maxTraceable
+	}
+	bcSpout, validators, committee := chain.NewMultiWithCustomConfig(t, spoutCfg)
+	e := neotest.NewExecutor(t, bcSpout, validators, committee)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	acc := util.Uint160{1, 2, 3}
+	for bcSpout.BlockHeight() < stateSyncPoint+2 {
+		gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), acc, 1_0000_0000, nil)
+	}
+	expectedBalance := bcSpout.GetUtilityTokenBalance(acc)
+
+	export := func(t *testing.T, p uint32) []byte {
+		w := io.NewBufBinWriter()
+		require.NoError(t, bcSpout.GetStateSyncModule().ExportSnapshot(w.BinWriter, p))
+		return w.Bytes()
+	}
+	t.Run("export errors", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		require.Error(t, bcSpout.GetStateSyncModule().ExportSnapshot(w.BinWriter, stateSyncPoint+1))
+		require.Error(t, bcSpout.GetStateSyncModule().ExportSnapshot(w.BinWriter, 0))
+		require.Error(t, bcSpout.GetStateSyncModule().ExportSnapshot(w.BinWriter, stateSyncPoint+stateSyncInterval))
+	})
+	snapshot := export(t, stateSyncPoint)
+
+	boltCfg := func(c *config.Blockchain) {
+		spoutCfg(c)
+		c.Ledger.KeepOnlyLatestState = true
+		c.Ledger.RemoveUntraceableBlocks = true
+	}
+	t.Run("import errors", func(t *testing.T) {
+		bcBolt, _, _ := chain.NewMultiWithCustomConfig(t, boltCfg)
+		// Wrong magic.
+		bad := bytes.Clone(snapshot)
+		bad[0]++
+		require.Error(t, bcBolt.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(bad)))
+		// Too low point.
+		require.Error(t, bcBolt.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(export(t, stateSyncInterval))))
+		// Truncated.
+		require.Error(t, bcBolt.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(snapshot[:len(snapshot)-100])))
+		require.Equal(t, uint32(0), bcBolt.BlockHeight())
+		// State sync is disabled.
+		bcInactive, _, _ := chain.NewMultiWithCustomConfig(t, spoutCfg)
+		require.Error(t, bcInactive.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(snapshot)))
+	})
+
+	bcBolt, _, _ := chain.NewMultiWithCustomConfig(t, boltCfg)
+	require.NoError(t, bcBolt.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(snapshot)))
+	require.Equal(t, uint32(stateSyncPoint), bcBolt.BlockHeight())
+	h, err := bcSpout.GetHeader(bcSpout.GetHeaderHash(stateSyncPoint + 1))
+	require.NoError(t, err)
+	require.Equal(t, h.PrevStateRoot, bcBolt.GetStateModule().CurrentLocalStateRoot())
+
+	// Regular processing continues from the state sync point.
+	for i := uint32(stateSyncPoint + 1); i <= bcSpout.BlockHeight(); i++ {
+		b, err := bcSpout.GetBlock(bcSpout.GetHeaderHash(i))
+		require.NoError(t, err)
+		require.NoError(t, bcBolt.AddBlock(b))
+	}
+	require.Equal(t, expectedBalance, bcBolt.GetUtilityTokenBalance(acc))
+	require.Error(t, bcBolt.GetStateSyncModule().ImportSnapshot(io.NewBinReaderFromBuf(snapshot)))
+}
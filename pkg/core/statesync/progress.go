@@ -0,0 +1,83 @@
+package statesync
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// MPTProgress contains MPT synchronisation statistics.
+type MPTProgress struct {
+	// Fetched is the number of restored MPT nodes including the ones restored
+	// before the node restart. A node referenced from several MPT paths is
+	// counted once per path.
+	Fetched uint64
+	// Pending is the number of MPT nodes that are known to be missing.
+	Pending int
+	// Remaining is the estimated number of MPT nodes that are still to be
+	// fetched. The estimation is based on the part of the trie that is already
+	// restored, so it's rough at the beginning of the process.
+	Remaining uint64
+}
+
+// mptProgress tracks MPT synchronisation progress. Every missing MPT path is
+// assigned a share of the trie it represents: the root owns the whole trie
+// and branch node shares are split equally between its children. The sum of
+// missing paths shares is the part of the trie that is still to be fetched.
+type mptProgress struct {
+	fetched uint64
+	missing float64
+	shares  map[string]float64
+}
+
+func newMPTProgress() mptProgress {
+	return mptProgress{
+		missing: 1,
+		shares:  map[string]float64{"": 1},
+	}
+}
+
+// restored updates the progress after the node is restored at the specified
+// path, children contains paths to its missing children.
+func (p *mptProgress) restored(path []byte, children map[util.Uint256][][]byte) {
+	share := p.shares[string(path)]
+	delete(p.shares, string(path))
+	p.fetched++
+
+	var n int
+	for _, paths := range children {
+		n += len(paths)
+	}
+	if n == 0 {
+		p.missing -= share
+		return
+	}
+	if p.shares == nil {
+		p.shares = make(map[string]float64)
+	}
+	childShare := share / float64(n)
+	for _, paths := range children {
+		for _, cPath := range paths {
+			p.shares[string(cPath)] += childShare
+		}
+	}
+}
+
+// get returns the progress statistics given the number of pending nodes.
+func (p *mptProgress) get(pending int) MPTProgress {
+	res := MPTProgress{
+		Fetched:   p.fetched,
+		Pending:   pending,
+		Remaining: uint64(pending),
+	}
+	if pending == 0 {
+		return res
+	}
+	done := 1 - p.missing
+	if done <= 0 {
+		return res
+	}
+	total := float64(p.fetched) / done
+	if rest := total - float64(p.fetched); rest > float64(pending) {
+		res.Remaining = uint64(rest)
+	}
+	return res
+}
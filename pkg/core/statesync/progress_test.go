@@ -0,0 +1,42 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPTProgress(t *testing.T) {
+	p := newMPTProgress()
+	require.Equal(t, MPTProgress{Pending: 1, Remaining: 1}, p.get(1))
+
+	// Branch with 4 children.
+	children := map[util.Uint256][][]byte{
+		{1}: {{0}},
+		{2}: {{1}},
+		{3}: {{2}, {3}}, // The same node by two paths.
+	}
+	p.restored([]byte{}, children)
+	require.Equal(t, MPTProgress{Fetched: 1, Pending: 3, Remaining: 3}, p.get(3))
+
+	// Two leaves, a half of the trie is restored.
+	p.restored([]byte{2}, nil)
+	p.restored([]byte{3}, nil)
+	require.Equal(t, MPTProgress{Fetched: 3, Pending: 2, Remaining: 3}, p.get(2))
+
+	// Extension to a branch with 3 children doesn't change anything.
+	p.restored([]byte{0}, map[util.Uint256][][]byte{{4}: {{0, 1, 2}}})
+	p.restored([]byte{0, 1, 2}, map[util.Uint256][][]byte{
+		{5}: {{0, 1, 2, 0}},
+		{6}: {{0, 1, 2, 1}},
+		{7}: {{0, 1, 2, 2}},
+	})
+	require.Equal(t, MPTProgress{Fetched: 5, Pending: 4, Remaining: 5}, p.get(4))
+
+	for i := byte(0); i < 3; i++ {
+		p.restored([]byte{0, 1, 2, i}, nil)
+	}
+	p.restored([]byte{1}, nil)
+	require.Equal(t, MPTProgress{Fetched: 9}, p.get(0))
+}
@@ -0,0 +1,177 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+)
+
+// snapshotBatchSize is the number of headers or MPT nodes processed at once
+// during snapshot import.
+const snapshotBatchSize = 2000
+
+// ExportSnapshot writes the state snapshot for the state sync point p to w.
+// Snapshot contains all the data required by the state sync process: headers
+// from 1 to p+1, blocks that should be stored for the point p (the last
+// MaxTraceableBlocks ones) and MPT nodes for the state of the point p.
+// StateRootInHeader must be enabled and the MPT for the point p must be
+// available in the DB.
+func (s *Module) ExportSnapshot(w *io.BinWriter, p uint32) error {
+	cfg := s.bc.GetConfig()
+	if !cfg.StateRootInHeader {
+		return errors.New("StateRootInHeader is disabled")
+	}
+	if p == 0 || cfg.StateSyncInterval == 0 || p%uint32(cfg.StateSyncInterval) != 0 {
+		return fmt.Errorf("%d is not a state sync point", p)
+	}
+	if p >= s.bc.BlockHeight() {
+		// Header p+1 is required to get the state root.
+		return fmt.Errorf("chain is too low (%d) for state sync point %d", s.bc.BlockHeight(), p)
+	}
+
+	w.WriteU32LE(uint32(cfg.Magic))
+	w.WriteU32LE(p)
+	w.WriteU32LE(p + 1)
+	var root *block.Header
+	for i := uint32(1); i <= p+1; i++ {
+		h, err := s.bc.GetHeader(s.bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		h.EncodeBinary(w)
+		root = h
+	}
+
+	start := uint32(1)
+	if p > cfg.MaxTraceableBlocks {
+		start = p - cfg.MaxTraceableBlocks + 1
+	}
+	w.WriteU32LE(start)
+	w.WriteU32LE(p - start + 1)
+	for i := start; i <= p; i++ {
+		b, err := s.bc.GetBlock(s.bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		b.EncodeBinary(w)
+		if w.Err != nil {
+			return w.Err
+		}
+	}
+
+	err := s.Traverse(root.PrevStateRoot, func(_ mpt.Node, nodeBytes []byte) bool {
+		w.WriteVarBytes(nodeBytes)
+		return w.Err != nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to traverse MPT: %w", err)
+	}
+	w.WriteVarBytes(nil) // MPT nodes terminator.
+	return w.Err
+}
+
+// ImportSnapshot initializes the module using the state sync point from the
+// snapshot written by ExportSnapshot and feeds all the data from the snapshot
+// to it, the data is verified the same way it's verified when it's received
+// from peers. The module must be active and not initialized, the state jump
+// happens once all the data is processed. Import of the same snapshot can be
+// restarted if it was interrupted before the jump.
+func (s *Module) ImportSnapshot(r *io.BinReader) error {
+	cfg := s.bc.GetConfig()
+	magic := r.ReadU32LE()
+	p := r.ReadU32LE()
+	if r.Err != nil {
+		return fmt.Errorf("failed to read snapshot header: %w", r.Err)
+	}
+	if magic != uint32(cfg.Magic) {
+		return fmt.Errorf("snapshot is for network %d, expected %d", magic, cfg.Magic)
+	}
+	if s.IsInitialized() {
+		return errors.New("state sync module is already initialized or inactive")
+	}
+	if p%s.syncInterval != 0 {
+		return fmt.Errorf("%d is not a state sync point", p)
+	}
+	err := s.Init(p)
+	if err != nil {
+		return err
+	}
+	s.lock.RLock()
+	syncPoint := s.syncPoint
+	s.lock.RUnlock()
+	if !s.IsActive() {
+		return fmt.Errorf("state sync point %d is not suitable for the chain", p)
+	}
+	if syncPoint != p {
+		return fmt.Errorf("state sync point %d is already used, snapshot is for %d", syncPoint, p)
+	}
+
+	count := r.ReadU32LE()
+	hdrs := make([]*block.Header, 0, snapshotBatchSize)
+	for i := uint32(0); i < count; i++ {
+		h := &block.Header{StateRootEnabled: cfg.StateRootInHeader}
+		h.DecodeBinary(r)
+		if r.Err != nil {
+			return fmt.Errorf("failed to decode header: %w", r.Err)
+		}
+		if h.Index > s.bc.HeaderHeight() {
+			hdrs = append(hdrs, h)
+		}
+		if len(hdrs) == snapshotBatchSize || i == count-1 && len(hdrs) != 0 {
+			if s.NeedHeaders() {
+				err = s.AddHeaders(hdrs...)
+				if err != nil {
+					return fmt.Errorf("failed to add headers: %w", err)
+				}
+			}
+			hdrs = hdrs[:0]
+		}
+	}
+
+	r.ReadU32LE() // Start block index, blocks contain it.
+	count = r.ReadU32LE()
+	for i := uint32(0); i < count; i++ {
+		b := block.New(cfg.StateRootInHeader)
+		b.DecodeBinary(r)
+		if r.Err != nil {
+			return fmt.Errorf("failed to decode block: %w", r.Err)
+		}
+		if b.Index <= s.BlockHeight() {
+			continue
+		}
+		err = s.AddBlock(b)
+		if err != nil {
+			return fmt.Errorf("failed to add block %d: %w", b.Index, err)
+		}
+	}
+
+	nodes := make([][]byte, 0, snapshotBatchSize)
+	for {
+		n := r.ReadVarBytes()
+		if r.Err != nil {
+			return fmt.Errorf("failed to read MPT node: %w", r.Err)
+		}
+		if len(n) != 0 {
+			nodes = append(nodes, n)
+		}
+		if len(nodes) == snapshotBatchSize || len(n) == 0 && len(nodes) != 0 {
+			if s.NeedMPTNodes() {
+				err = s.AddMPTNodes(nodes)
+				if err != nil {
+					return fmt.Errorf("failed to add MPT nodes: %w", err)
+				}
+			}
+			nodes = nodes[:0]
+		}
+		if len(n) == 0 {
+			break
+		}
+	}
+	if s.IsActive() {
+		return errors.New("snapshot is incomplete")
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
@@ -18,13 +19,24 @@ var Bucket = []byte("DB")
 // BoltDBStore it is the storage implementation for storing and retrieving
 // blockchain data.
 type BoltDBStore struct {
-	db *bbolt.DB
+	// lock protects db from being replaced during compaction.
+	lock sync.RWMutex
+	db   *bbolt.DB
+	opts *bbolt.Options
 }
 
 // defaultOpenTimeout is the default timeout for performing flock on a bbolt database.
 // bbolt does retries every 50ms during this interval.
 const defaultOpenTimeout = 1 * time.Second
 
+// boltCompactTxSize is the maximum size of a single transaction used to copy
+// data during compaction.
+const boltCompactTxSize = 64 << 20
+
+// boltOpen is used to (re)open the database during compaction, it can be
+// replaced in tests.
+var boltOpen = bbolt.Open
+
 // NewBoltDBStore returns a new ready to use BoltDB storage with created bucket.
 func NewBoltDBStore(cfg dbconfig.BoltDBOptions) (*BoltDBStore, error) {
 	cp := *bbolt.DefaultOptions // Do not change bbolt's global variable.
@@ -70,18 +82,20 @@ func NewBoltDBStore(cfg dbconfig.BoltDBOptions) (*BoltDBStore, error) {
 		return nil, err
 	}
 
-	return &BoltDBStore{db: db}, nil
+	return &BoltDBStore{db: db, opts: opts}, nil
 }
 
 // Get implements the Store interface.
 func (s *BoltDBStore) Get(key []byte) (val []byte, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	err = s.db.View(func(tx *bbolt.Tx) error {
 		b := tx.Bucket(Bucket)
 		// Value from Get is only valid for the lifetime of transaction, #1482
 		val = bytes.Clone(b.Get(key))
 		return nil
 	})
-	if val == nil {
+	if err == nil && val == nil {
 		err = ErrKeyNotFound
 	}
 	return
@@ -91,6 +105,8 @@ func (s *BoltDBStore) Get(key []byte) (val []byte, err error) {
 func (s *BoltDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
 	var err error
 
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	return s.db.Update(func(tx *bbolt.Tx) error {
 		b := tx.Bucket(Bucket)
 		for _, m := range []map[string][]byte{puts, stores} {
@@ -111,6 +127,8 @@ func (s *BoltDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]b
 
 // SeekGC implements the Store interface.
 func (s *BoltDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	return boltSeek(s.db.Update, rng, func(c *bbolt.Cursor, k, v []byte) (bool, error) {
 		if !keep(k, v) {
 			if err := c.Delete(); err != nil {
@@ -123,6 +141,8 @@ func (s *BoltDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
 
 // Seek implements the Store interface.
 func (s *BoltDBStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	err := boltSeek(s.db.View, rng, func(_ *bbolt.Cursor, k, v []byte) (bool, error) {
 		return f(k, v), nil
 	})
@@ -168,7 +188,87 @@ func boltSeek(txopener func(func(*bbolt.Tx) error) error, rng SeekRange, f func(
 	})
 }
 
+// Compact implements the Compactor interface. BoltDB can't reclaim the space
+// of its file, so the database is copied into a new file which then replaces
+// the original one. All other operations are blocked during compaction. The
+// original file is kept until the compacted one is successfully opened and
+// restored if anything goes wrong after closing the database.
+func (s *BoltDBStore) Compact() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.db.IsReadOnly() {
+		return errors.New("BoltDB is opened in read-only mode")
+	}
+	var (
+		path       = s.db.Path()
+		tmpPath    = path + ".compact"
+		backupPath = path + ".orig"
+		mode       = os.FileMode(0600)
+	)
+	_ = os.Remove(tmpPath)
+	dst, err := bbolt.Open(tmpPath, mode, &bbolt.Options{Timeout: defaultOpenTimeout, NoSync: true})
+	if err != nil {
+		return fmt.Errorf("failed to create compacted BoltDB: %w", err)
+	}
+	err = bbolt.Compact(dst, s.db, boltCompactTxSize)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact BoltDB: %w", err)
+	}
+	if err = s.db.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close BoltDB: %w", err)
+	}
+	if err = os.Rename(path, backupPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return s.reopen(path, fmt.Errorf("failed to back up BoltDB file: %w", err))
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return s.restore(path, backupPath, fmt.Errorf("failed to replace BoltDB file: %w", err))
+	}
+	db, err := boltOpen(path, mode, s.opts)
+	if err != nil {
+		return s.restore(path, backupPath, fmt.Errorf("failed to reopen compacted BoltDB: %w", err))
+	}
+	s.db = db
+	_ = os.Remove(backupPath)
+	return nil
+}
+
+// restore moves the original database file back from backupPath to path and
+// reopens it after a failed compaction. cause is always returned as a part of
+// the resulting error.
+func (s *BoltDBStore) restore(path, backupPath string, cause error) error {
+	if err := os.Rename(backupPath, path); err != nil {
+		return fmt.Errorf("%w, failed to restore original BoltDB file from %s, the store is unusable: %w", cause, backupPath, err)
+	}
+	return s.reopen(path, cause)
+}
+
+// reopen opens the database at path after a failed compaction. cause is
+// always returned as a part of the resulting error. If the database can't be
+// opened, the store is left with a closed database, so all subsequent
+// operations fail with bbolt.ErrDatabaseNotOpen.
+func (s *BoltDBStore) reopen(path string, cause error) error {
+	db, err := boltOpen(path, os.FileMode(0600), s.opts)
+	if err != nil {
+		return fmt.Errorf("%w, failed to reopen original BoltDB, the store is unusable: %w", cause, err)
+	}
+	s.db = db
+	return cause
+}
+
 // Close releases all db resources.
 func (s *BoltDBStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	return s.db.Close()
 }
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -56,3 +57,61 @@ func TestROBoltDB(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "root bucket does not exist"))
 }
+
+func TestBoltDBStore_CompactReopenFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bolt")
+	s, err := NewBoltDBStore(dbconfig.BoltDBOptions{FilePath: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01key": {1}}, nil))
+
+	checkFiles := func(t *testing.T) {
+		_, err := os.Stat(path + ".compact")
+		require.ErrorIs(t, err, os.ErrNotExist)
+		_, err = os.Stat(path + ".orig")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	}
+	openErr := errors.New("open failure")
+	setOpen := func(t *testing.T, failures int) {
+		boltOpen = func(p string, mode os.FileMode, opts *bbolt.Options) (*bbolt.DB, error) {
+			if failures > 0 {
+				failures--
+				return nil, openErr
+			}
+			return bbolt.Open(p, mode, opts)
+		}
+		t.Cleanup(func() { boltOpen = bbolt.Open })
+	}
+
+	t.Run("original restored", func(t *testing.T) {
+		setOpen(t, 1)
+		err := s.Compact()
+		require.ErrorIs(t, err, openErr)
+		require.NotContains(t, err.Error(), "unusable")
+		checkFiles(t)
+
+		// The store is still usable with the original file.
+		v, err := s.Get([]byte("\x01key"))
+		require.NoError(t, err)
+		require.Equal(t, []byte{1}, v)
+		require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01other": {2}}, nil))
+
+		boltOpen = bbolt.Open
+		require.NoError(t, s.Compact())
+		checkFiles(t)
+		v, err = s.Get([]byte("\x01other"))
+		require.NoError(t, err)
+		require.Equal(t, []byte{2}, v)
+	})
+	t.Run("unusable", func(t *testing.T) {
+		setOpen(t, 2)
+		err := s.Compact()
+		require.ErrorIs(t, err, openErr)
+		require.Contains(t, err.Error(), "unusable")
+
+		// The closed database is never used.
+		_, err = s.Get([]byte("\x01key"))
+		require.ErrorIs(t, err, bbolt.ErrDatabaseNotOpen)
+		require.ErrorIs(t, s.PutChangeSet(map[string][]byte{"\x01key": {3}}, nil), bbolt.ErrDatabaseNotOpen)
+	})
+}
@@ -4,12 +4,17 @@ Package dbconfig is a micropackage that contains storage DB configuration option
 package dbconfig
 
 type (
-	// DBConfiguration describes configuration for DB. Supported types:
-	// [LevelDB], [BoltDB] or [InMemoryDB] (not recommended for production usage).
+	// DBConfiguration describes configuration for DB. Built-in types are
+	// [LevelDB], [BoltDB], [LSMDB] or [InMemoryDB] (not recommended for
+	// production usage), other types can be registered by applications.
 	DBConfiguration struct {
 		Type           string         `yaml:"Type"`
 		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
 		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		LSMOptions     LSMOptions     `yaml:"LSMOptions"`
+		// Options contains arbitrary settings for externally registered
+		// storage types.
+		Options map[string]any `yaml:"Options"`
 	}
 	// LevelDBOptions configuration for LevelDB.
 	LevelDBOptions struct {
@@ -21,4 +26,26 @@ type (
 		FilePath string `yaml:"FilePath"`
 		ReadOnly bool   `yaml:"ReadOnly"`
 	}
+	// LSMOptions configuration for LSM tree storage. Zero values mean
+	// defaults.
+	LSMOptions struct {
+		DataDirectoryPath string `yaml:"DataDirectoryPath"`
+		ReadOnly          bool   `yaml:"ReadOnly"`
+		// MemTableSize is the size of in-memory write buffer in bytes,
+		// it's flushed to disk as a new table once this size is reached.
+		MemTableSize int `yaml:"MemTableSize"`
+		// BlockSize is the size of table data block in bytes.
+		BlockSize int `yaml:"BlockSize"`
+		// PrefixLength is the length of key prefixes added to table
+		// filters, prefix seeks with at least this length skip tables that
+		// don't contain matching keys.
+		PrefixLength int `yaml:"PrefixLength"`
+		// CompactionThreshold is the minimum number of tables merged by a
+		// single compaction.
+		CompactionThreshold int `yaml:"CompactionThreshold"`
+		// NoSync disables syncing the write-ahead log to disk after every
+		// change set. It makes writes faster, but the last change sets can
+		// be lost on OS crash or power failure (not on node crash).
+		NoSync bool `yaml:"NoSync"`
+	}
 )
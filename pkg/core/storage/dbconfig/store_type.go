@@ -8,4 +8,6 @@ const (
 	LevelDB = "leveldb"
 	// InMemoryDB represents in-memory storage name.
 	InMemoryDB = "inmemory"
+	// LSMDB represents LSM tree storage name.
+	LSMDB = "lsm"
 )
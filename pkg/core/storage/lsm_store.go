@@ -0,0 +1,803 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Default LSMStore settings.
+const (
+	DefaultLSMMemTableSize        = 64 << 20
+	DefaultLSMBlockSize           = 32 << 10
+	DefaultLSMPrefixLength        = 5
+	DefaultLSMCompactionThreshold = 4
+)
+
+const (
+	lsmManifestName         = "MANIFEST"
+	lsmWALName              = "wal.log"
+	lsmManifestMagic uint32 = 0x4c534d4d // "LSMM"
+	// lsmSizeRatio is the percentage by which the size of the table joining
+	// compaction run can exceed the total size of the run.
+	lsmSizeRatio = 25
+	// lsmWALHeaderSize is the size of WAL record header (payload length and
+	// its CRC32 checksum).
+	lsmWALHeaderSize = 8
+)
+
+// ErrLSMReadOnly is returned on attempt to change LSMStore opened in
+// read-only mode.
+var ErrLSMReadOnly = errors.New("LSM store is read-only")
+
+// LSMStore is a log-structured merge tree storage tuned for large values and
+// prefix-heavy workloads like contract storage and MPT nodes. Changes are
+// appended to the write-ahead log (synced after every change set unless
+// NoSync option is set) and collected in an in-memory table which is
+// flushed to disk as an immutable sorted table once it becomes big enough.
+// Tables keep key prefix filters, so prefix seeks skip tables that can't
+// contain matching keys. Tables are merged in background using size-tiered
+// strategy that rewrites every value a logarithmic number of times only,
+// which makes compaction much cheaper than leveled one for big values.
+type LSMStore struct {
+	dir      string
+	opts     dbconfig.LSMOptions
+	readOnly bool
+	cache    *lru.Cache[lsmBlockKey, []lsmEntry]
+
+	// compactLock serializes table merges.
+	compactLock sync.Mutex
+
+	lock    sync.RWMutex
+	mem     *memdb.DB
+	memSize int
+	wal     *os.File
+	// walSize is the size of valid WAL contents.
+	walSize int64
+	// tables are ordered from the newest to the oldest one.
+	tables  []*lsmTable
+	nextNum uint64
+	closed  bool
+	// bgErr is the error of the background compaction or WAL sync, it's
+	// returned by all subsequent write operations.
+	bgErr error
+
+	compactCh chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewLSMStore opens (creating it if needed) LSM store in the given directory.
+func NewLSMStore(cfg dbconfig.LSMOptions) (*LSMStore, error) {
+	if cfg.DataDirectoryPath == "" {
+		return nil, errors.New("LSM data directory is not specified")
+	}
+	if cfg.MemTableSize <= 0 {
+		cfg.MemTableSize = DefaultLSMMemTableSize
+	}
+	if cfg.BlockSize <= 0 {
+		cfg.BlockSize = DefaultLSMBlockSize
+	}
+	if cfg.PrefixLength < 0 {
+		cfg.PrefixLength = 0
+	} else if cfg.PrefixLength == 0 {
+		cfg.PrefixLength = DefaultLSMPrefixLength
+	}
+	if cfg.CompactionThreshold < 2 {
+		cfg.CompactionThreshold = DefaultLSMCompactionThreshold
+	}
+	cache, err := lru.New[lsmBlockKey, []lsmEntry](lsmBlockCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	s := &LSMStore{
+		dir:       cfg.DataDirectoryPath,
+		opts:      cfg,
+		readOnly:  cfg.ReadOnly,
+		cache:     cache,
+		mem:       memdb.New(comparer.DefaultComparer, 0),
+		nextNum:   1,
+		compactCh: make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err = s.open(); err != nil {
+		for _, t := range s.tables {
+			t.unref()
+		}
+		if s.wal != nil {
+			s.wal.Close()
+		}
+		return nil, fmt.Errorf("failed to open LSM store: %w", err)
+	}
+	if s.readOnly {
+		close(s.done)
+	} else {
+		go s.compactLoop()
+		s.scheduleCompaction()
+	}
+	return s, nil
+}
+
+func (s *LSMStore) open() error {
+	if !s.readOnly {
+		if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	nums, err := s.readManifest()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) || s.readOnly {
+			return err
+		}
+		if err = s.writeManifest(); err != nil {
+			return err
+		}
+	}
+	live := make(map[string]bool, len(nums))
+	for _, num := range nums {
+		t, err := openLSMTable(filepath.Join(s.dir, lsmTableName(num)), num, s.cache)
+		if err != nil {
+			return err
+		}
+		s.tables = append(s.tables, t)
+		live[lsmTableName(num)] = true
+	}
+	if !s.readOnly {
+		// Remove leftovers of interrupted flushes and compactions.
+		entries, err := os.ReadDir(s.dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".sst") && !live[e.Name()] {
+				os.Remove(filepath.Join(s.dir, e.Name()))
+			}
+		}
+	}
+	return s.replayWAL()
+}
+
+// replayWAL restores memtable contents from the write-ahead log and opens it
+// for writing. Incomplete records at the end of the log (left after crash)
+// are dropped.
+func (s *LSMStore) replayWAL() error {
+	path := filepath.Join(s.dir, lsmWALName)
+	flags := os.O_RDWR | os.O_CREATE
+	if s.readOnly {
+		flags = os.O_RDONLY
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		if s.readOnly && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var (
+		r      = bufio.NewReader(f)
+		hdr    = make([]byte, lsmWALHeaderSize)
+		offset int64
+	)
+	for {
+		if _, err = io.ReadFull(r, hdr); err != nil {
+			break
+		}
+		payload := make([]byte, binary.LittleEndian.Uint32(hdr))
+		if _, err = io.ReadFull(r, payload); err != nil ||
+			crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(hdr[4:]) {
+			break
+		}
+		es, err := decodeLSMEntries(payload)
+		if err != nil {
+			break
+		}
+		for i := range es {
+			s.memPut(es[i].flag, es[i].key, es[i].value)
+		}
+		offset += lsmWALHeaderSize + int64(len(payload))
+	}
+	if s.readOnly {
+		return f.Close()
+	}
+	if err = f.Truncate(offset); err == nil {
+		_, err = f.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.wal = f
+	s.walSize = offset
+	return nil
+}
+
+// readManifest returns the list of live table numbers.
+func (s *LSMStore) readManifest() ([]uint64, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, lsmManifestName))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 16 || binary.LittleEndian.Uint32(data) != lsmManifestMagic ||
+		crc32.ChecksumIEEE(data[:len(data)-4]) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, errors.New("corrupted manifest")
+	}
+	data = data[4 : len(data)-4]
+	s.nextNum = binary.LittleEndian.Uint64(data)
+	data = data[8:]
+	if len(data)%8 != 0 {
+		return nil, errors.New("corrupted manifest")
+	}
+	nums := make([]uint64, 0, len(data)/8)
+	for ; len(data) > 0; data = data[8:] {
+		nums = append(nums, binary.LittleEndian.Uint64(data))
+	}
+	return nums, nil
+}
+
+// writeManifest atomically replaces the manifest with the current list of
+// tables.
+func (s *LSMStore) writeManifest() error {
+	data := binary.LittleEndian.AppendUint32(nil, lsmManifestMagic)
+	data = binary.LittleEndian.AppendUint64(data, s.nextNum)
+	for _, t := range s.tables {
+		data = binary.LittleEndian.AppendUint64(data, t.num)
+	}
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+
+	tmp := filepath.Join(s.dir, lsmManifestName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(s.dir, lsmManifestName))
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if d, err := os.Open(s.dir); err == nil {
+		_ = d.Sync() // Not supported on some platforms.
+		d.Close()
+	}
+	return nil
+}
+
+func (s *LSMStore) memPut(flag byte, key, value []byte) {
+	v := make([]byte, 1+len(value))
+	v[0] = flag
+	copy(v[1:], value)
+	_ = s.mem.Put(key, v) // Never fails.
+	s.memSize += len(key) + len(v)
+}
+
+// Get implements the Store interface.
+func (s *LSMStore) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	if v, err := s.mem.Get(key); err == nil {
+		s.lock.RUnlock()
+		if v[0] == lsmEntryTombstone {
+			return nil, ErrKeyNotFound
+		}
+		return bytes.Clone(v[1:]), nil
+	}
+	tables := s.refTables(nil, nil)
+	s.lock.RUnlock()
+	defer unrefTables(tables)
+
+	for _, t := range tables {
+		e, err := t.get(key)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			if e.flag == lsmEntryTombstone {
+				return nil, ErrKeyNotFound
+			}
+			return bytes.Clone(e.value), nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// refTables returns referenced tables that can contain keys from the given
+// range with the given prefix (nil range means any key), it must be called
+// with the lock held.
+func (s *LSMStore) refTables(rng *util.Range, prefix []byte) []*lsmTable {
+	res := make([]*lsmTable, 0, len(s.tables))
+	for _, t := range s.tables {
+		if rng == nil || t.mayContain(rng, prefix) {
+			t.ref()
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func unrefTables(tables []*lsmTable) {
+	for _, t := range tables {
+		t.unref()
+	}
+}
+
+// PutChangeSet implements the Store interface.
+func (s *LSMStore) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	if s.readOnly {
+		return ErrLSMReadOnly
+	}
+	var payload []byte
+	for _, m := range []map[string][]byte{puts, stores} {
+		for k, v := range m {
+			payload = appendLSMEntry(payload, k, v)
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.write(payload)
+}
+
+func appendLSMEntry(b []byte, k string, v []byte) []byte {
+	flag := lsmEntryValue
+	if v == nil {
+		flag = lsmEntryTombstone
+	}
+	b = append(b, flag)
+	b = binary.AppendUvarint(b, uint64(len(k)))
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	b = append(b, k...)
+	return append(b, v...)
+}
+
+// write logs encoded entries to the WAL and applies them to the memtable, it
+// must be called with the lock held.
+func (s *LSMStore) write(payload []byte) error {
+	if s.closed {
+		return errors.New("LSM store is closed")
+	}
+	if s.bgErr != nil {
+		return s.bgErr
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	es, err := decodeLSMEntries(payload)
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, lsmWALHeaderSize, lsmWALHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(rec, uint32(len(payload)))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(payload))
+	if _, err = s.wal.Write(append(rec, payload...)); err != nil {
+		err = fmt.Errorf("failed to write WAL: %w", err)
+		// Drop partially written record, otherwise it'd hide the
+		// subsequent ones on replay.
+		if s.truncateWAL(s.walSize) != nil {
+			s.bgErr = err
+		}
+		return err
+	}
+	s.walSize += int64(len(rec) + len(payload))
+	if !s.opts.NoSync {
+		if err = s.wal.Sync(); err != nil {
+			// The state of the file is unknown after failed sync, so
+			// it's not safe to continue.
+			s.bgErr = fmt.Errorf("failed to sync WAL: %w", err)
+			return s.bgErr
+		}
+	}
+	for i := range es {
+		s.memPut(es[i].flag, es[i].key, es[i].value)
+	}
+	if s.memSize >= s.opts.MemTableSize {
+		return s.flush()
+	}
+	return nil
+}
+
+// truncateWAL truncates the WAL to the given size, subsequent records are
+// written after it.
+func (s *LSMStore) truncateWAL(size int64) error {
+	err := s.wal.Truncate(size)
+	if err == nil {
+		_, err = s.wal.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		return err
+	}
+	s.walSize = size
+	return nil
+}
+
+// flush writes the memtable to a new table and resets the WAL, it must be
+// called with the lock held.
+func (s *LSMStore) flush() error {
+	if s.mem.Len() == 0 {
+		return nil
+	}
+	var (
+		num  = s.nextNum
+		path = filepath.Join(s.dir, lsmTableName(num))
+		// Tombstones are useless if there are no tables to shadow.
+		dropTombstones = len(s.tables) == 0
+	)
+	w, err := newLSMTableWriter(path, s.opts.BlockSize, s.opts.PrefixLength)
+	if err != nil {
+		return err
+	}
+	it := s.mem.NewIterator(nil)
+	for it.Next() {
+		v := it.Value()
+		if dropTombstones && v[0] == lsmEntryTombstone {
+			continue
+		}
+		if err = w.add(v[0], it.Key(), v[1:]); err != nil {
+			break
+		}
+	}
+	it.Release()
+	if err == nil && w.count == 0 {
+		w.abort()
+	} else {
+		if err == nil {
+			err = w.finish()
+		}
+		var t *lsmTable
+		if err == nil {
+			t, err = openLSMTable(path, num, s.cache)
+		}
+		if err != nil {
+			w.abort()
+			return fmt.Errorf("failed to flush memtable: %w", err)
+		}
+		s.tables = append([]*lsmTable{t}, s.tables...)
+	}
+	s.nextNum++
+	if err = s.writeManifest(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err = s.truncateWAL(0); err != nil {
+		return fmt.Errorf("failed to reset WAL: %w", err)
+	}
+	// Seek snapshots can still use the old memtable, so it's replaced
+	// rather than reset.
+	s.mem = memdb.New(comparer.DefaultComparer, 0)
+	s.memSize = 0
+	s.scheduleCompaction()
+	return nil
+}
+
+// Seek implements the Store interface.
+func (s *LSMStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	s.lock.RLock()
+	iters, tables := s.newIterators(rng)
+	s.lock.RUnlock()
+
+	_ = mergeLSMIterators(iters, rng.Backwards, func(e *lsmEntry) bool {
+		if e.flag == lsmEntryTombstone {
+			return true
+		}
+		return f(e.key, e.value)
+	})
+	unrefTables(tables)
+}
+
+// newIterators returns iterators over the memtable snapshot and referenced
+// tables for the given range, it must be called with the lock held.
+func (s *LSMStore) newIterators(rng SeekRange) ([]lsmIterator, []*lsmTable) {
+	var (
+		r   = seekRangeToPrefixes(rng)
+		mem []lsmEntry
+		it  = s.mem.NewIterator(r)
+	)
+	// Memtable contents are copied to provide a consistent snapshot, it's
+	// limited by memtable size anyway.
+	for it.Next() {
+		v := it.Value()
+		mem = append(mem, lsmEntry{flag: v[0], key: it.Key(), value: v[1:]})
+	}
+	it.Release()
+	if rng.Backwards {
+		for i, j := 0, len(mem)-1; i < j; i, j = i+1, j-1 {
+			mem[i], mem[j] = mem[j], mem[i]
+		}
+	}
+	tables := s.refTables(r, rng.Prefix)
+	iters := make([]lsmIterator, 0, len(tables)+1)
+	iters = append(iters, newLSMSliceIter(mem))
+	for _, t := range tables {
+		iters = append(iters, newLSMTableIter(t, r, rng.Backwards))
+	}
+	return iters, tables
+}
+
+// SeekGC implements the Store interface.
+func (s *LSMStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	if s.readOnly {
+		return ErrLSMReadOnly
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	iters, tables := s.newIterators(rng)
+	defer unrefTables(tables)
+	var payload []byte
+	err := mergeLSMIterators(iters, rng.Backwards, func(e *lsmEntry) bool {
+		if e.flag != lsmEntryTombstone && !keep(e.key, e.value) {
+			payload = appendLSMEntry(payload, string(e.key), nil)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return s.write(payload)
+}
+
+func (s *LSMStore) scheduleCompaction() {
+	select {
+	case s.compactCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *LSMStore) compactLoop() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.compactCh:
+		}
+		for s.compact() {
+		}
+	}
+}
+
+// pickCompaction returns the index and the length of the run of tables to be
+// merged, zero length is returned if there is nothing to compact. It must be
+// called with the lock held.
+//
+// A run starts with some table and is extended with the next older tables
+// while every new table is not much bigger than all the tables already in the
+// run. This makes tables sizes grow exponentially with age, so every entry is
+// rewritten a logarithmic number of times.
+func (s *LSMStore) pickCompaction() (int, int) {
+	for start := 0; start < len(s.tables); start++ {
+		var (
+			sum = s.tables[start].size
+			end = start + 1
+		)
+		for ; end < len(s.tables); end++ {
+			if s.tables[end].size*100 > sum*(100+lsmSizeRatio) {
+				break
+			}
+			sum += s.tables[end].size
+		}
+		if end-start >= s.opts.CompactionThreshold {
+			return start, end - start
+		}
+	}
+	return 0, 0
+}
+
+// compact merges a single run of tables, it returns true if anything was
+// merged.
+func (s *LSMStore) compact() bool {
+	s.compactLock.Lock()
+	defer s.compactLock.Unlock()
+
+	s.lock.Lock()
+	if s.closed || s.bgErr != nil {
+		s.lock.Unlock()
+		return false
+	}
+	start, n := s.pickCompaction()
+	if n == 0 {
+		s.lock.Unlock()
+		return false
+	}
+	err := s.mergeRun(start, n)
+	if errors.Is(err, errLSMCompactionStopped) {
+		return false
+	}
+	if err != nil {
+		s.lock.Lock()
+		s.bgErr = fmt.Errorf("LSM compaction failed: %w", err)
+		s.lock.Unlock()
+		return false
+	}
+	return true
+}
+
+// Compact implements the Compactor interface. The memtable is flushed and all
+// tables are merged into a single one dropping all deleted and overwritten
+// entries. Reads and writes are not blocked while tables are being merged.
+func (s *LSMStore) Compact() error {
+	if s.readOnly {
+		return ErrLSMReadOnly
+	}
+	s.compactLock.Lock()
+	defer s.compactLock.Unlock()
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return errors.New("LSM store is closed")
+	}
+	if s.bgErr != nil {
+		err := s.bgErr
+		s.lock.Unlock()
+		return err
+	}
+	if err := s.flush(); err != nil {
+		s.lock.Unlock()
+		return err
+	}
+	if len(s.tables) < 2 {
+		s.lock.Unlock()
+		return nil
+	}
+	return s.mergeRun(0, len(s.tables))
+}
+
+// mergeRun replaces n tables starting from the given index with a single
+// merged one. It must be called with the lock and compactLock held, the lock
+// is released before merging. Tables can be added by flushes while merging,
+// but the run itself can only be changed by the compactLock holder.
+func (s *LSMStore) mergeRun(start, n int) error {
+	var (
+		run            = make([]*lsmTable, n)
+		num            = s.nextNum
+		path           = filepath.Join(s.dir, lsmTableName(num))
+		dropTombstones = start+n == len(s.tables)
+	)
+	copy(run, s.tables[start:start+n])
+	for _, t := range run {
+		t.ref()
+	}
+	s.nextNum++
+	s.lock.Unlock()
+	defer unrefTables(run)
+
+	t, err := s.mergeTables(run, path, num, dropTombstones)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// New tables are only added to the front of the list by flushes, so
+	// the run is still in place, just shifted.
+	i := 0
+	for s.tables[i] != run[0] {
+		i++
+	}
+	tables := make([]*lsmTable, 0, len(s.tables)-n+1)
+	tables = append(tables, s.tables[:i]...)
+	if t != nil {
+		tables = append(tables, t)
+	}
+	tables = append(tables, s.tables[i+n:]...)
+	old := s.tables
+	s.tables = tables
+	if err = s.writeManifest(); err != nil {
+		s.tables = old
+		if t != nil {
+			t.obsolete.Store(true)
+			t.unref()
+		}
+		return err
+	}
+	for _, r := range run {
+		r.obsolete.Store(true)
+		r.unref()
+	}
+	return nil
+}
+
+var errLSMCompactionStopped = errors.New("compaction stopped")
+
+// mergeTables writes the contents of the given tables into a new table, nil
+// table is returned if there are no entries to write.
+func (s *LSMStore) mergeTables(run []*lsmTable, path string, num uint64, dropTombstones bool) (*lsmTable, error) {
+	w, err := newLSMTableWriter(path, s.opts.BlockSize, s.opts.PrefixLength)
+	if err != nil {
+		return nil, err
+	}
+	iters := make([]lsmIterator, len(run))
+	for i := range run {
+		iters[i] = newLSMTableIter(run[i], &util.Range{}, false)
+	}
+	var (
+		n    int
+		wErr error
+	)
+	err = mergeLSMIterators(iters, false, func(e *lsmEntry) bool {
+		if n++; n%1024 == 0 {
+			select {
+			case <-s.stop:
+				wErr = errLSMCompactionStopped
+				return false
+			default:
+			}
+		}
+		if dropTombstones && e.flag == lsmEntryTombstone {
+			return true
+		}
+		wErr = w.add(e.flag, e.key, e.value)
+		return wErr == nil
+	})
+	if err == nil {
+		err = wErr
+	}
+	if err == nil && w.count == 0 {
+		w.abort()
+		return nil, nil
+	}
+	if err == nil {
+		err = w.finish()
+	}
+	if err != nil {
+		w.abort()
+		return nil, err
+	}
+	t, err := openLSMTable(path, num, s.cache)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return t, nil
+}
+
+// Close implements the Store interface. The memtable is flushed to disk.
+func (s *LSMStore) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+	close(s.stop)
+	<-s.done
+	// Wait for Compact to notice the stop signal.
+	s.compactLock.Lock()
+	defer s.compactLock.Unlock()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var err error
+	if !s.readOnly {
+		if s.bgErr == nil {
+			err = s.flush()
+		}
+		if cErr := s.wal.Close(); err == nil {
+			err = cErr
+		}
+	}
+	unrefTables(s.tables)
+	s.tables = nil
+	return err
+}
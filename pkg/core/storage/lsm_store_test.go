@@ -0,0 +1,362 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/epicchainlabs/epicchain-go/internal/random"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func newLSMStoreForTesting(t testing.TB) Store {
+	s, err := NewLSMStore(dbconfig.LSMOptions{DataDirectoryPath: t.TempDir()})
+	require.NoError(t, err)
+	return s
+}
+
+// newFlushingLSMStoreForTesting returns LSMStore with tiny memtable, so that
+// almost every change set is flushed to a separate table.
+func newFlushingLSMStoreForTesting(t testing.TB) Store {
+	s, err := NewLSMStore(dbconfig.LSMOptions{
+		DataDirectoryPath: t.TempDir(),
+		MemTableSize:      16,
+		BlockSize:         32,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestLSMStore_Reopen(t *testing.T) {
+	opts := dbconfig.LSMOptions{DataDirectoryPath: t.TempDir()}
+	s, err := NewLSMStore(opts)
+	require.NoError(t, err)
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01a": []byte("a"), "\x01b": []byte("b")}, nil))
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01a": nil}, map[string][]byte{"\x70c": []byte("c")}))
+
+	check := func(t *testing.T, s *LSMStore) {
+		_, err := s.Get([]byte("\x01a"))
+		require.ErrorIs(t, err, ErrKeyNotFound)
+		v, err := s.Get([]byte("\x01b"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("b"), v)
+		v, err = s.Get([]byte("\x70c"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("c"), v)
+	}
+	check(t, s)
+
+	// Emulate crash: the data is in the WAL only, its tail is corrupted.
+	require.NoError(t, s.wal.Close())
+	f, err := os.OpenFile(filepath.Join(opts.DataDirectoryPath, lsmWALName), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0xff, 0, 0, 0, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = NewLSMStore(opts)
+	require.NoError(t, err)
+	check(t, s)
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01d": []byte("d")}, nil))
+	require.NoError(t, s.Close())
+	require.Error(t, s.PutChangeSet(map[string][]byte{"\x01e": []byte("e")}, nil))
+
+	// Memtable was flushed on close.
+	s, err = NewLSMStore(opts)
+	require.NoError(t, err)
+	require.Len(t, s.tables, 1)
+	check(t, s)
+	v, err := s.Get([]byte("\x01d"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("d"), v)
+	require.NoError(t, s.Close())
+}
+
+func TestLSMStore_ReadOnly(t *testing.T) {
+	opts := dbconfig.LSMOptions{DataDirectoryPath: filepath.Join(t.TempDir(), "lsm"), ReadOnly: true}
+
+	// DB doesn't exist.
+	_, err := NewLSMStore(opts)
+	require.Error(t, err)
+
+	opts.ReadOnly = false
+	s, err := NewLSMStore(opts)
+	require.NoError(t, err)
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"one": []byte("one")}, nil))
+	require.NoError(t, s.Close())
+
+	opts.ReadOnly = true
+	s, err = NewLSMStore(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+	v, err := s.Get([]byte("one"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), v)
+	require.ErrorIs(t, s.PutChangeSet(map[string][]byte{"two": []byte("two")}, nil), ErrLSMReadOnly)
+	require.ErrorIs(t, s.SeekGC(SeekRange{}, func(k, v []byte) bool { return true }), ErrLSMReadOnly)
+}
+
+func TestLSMStore_Compaction(t *testing.T) {
+	opts := dbconfig.LSMOptions{
+		DataDirectoryPath:   t.TempDir(),
+		MemTableSize:        1024,
+		BlockSize:           128,
+		CompactionThreshold: 2,
+	}
+	s, err := NewLSMStore(opts)
+	require.NoError(t, err)
+
+	expected := make(map[string][]byte)
+	for i := 0; i < 200; i++ {
+		batch := make(map[string][]byte)
+		for j := 0; j < 10; j++ {
+			k := string([]byte{byte(STStorage), byte(j % 3)}) + fmt.Sprint(random.Int(0, 100))
+			v := random.Bytes(random.Int(0, 64))
+			if random.Int(0, 4) == 0 {
+				v = nil
+			}
+			batch[k] = v
+			expected[k] = v
+		}
+		require.NoError(t, s.PutChangeSet(nil, batch))
+	}
+
+	check := func(t *testing.T, s *LSMStore) {
+		for k, v := range expected {
+			actual, err := s.Get([]byte(k))
+			if v == nil {
+				require.ErrorIs(t, err, ErrKeyNotFound)
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, v, actual)
+		}
+		for _, backwards := range []bool{false, true} {
+			var (
+				prefix = []byte{byte(STStorage), 1}
+				last   []byte
+				n      int
+			)
+			s.Seek(SeekRange{Prefix: prefix, Backwards: backwards}, func(k, v []byte) bool {
+				require.True(t, bytes.HasPrefix(k, prefix))
+				if last != nil {
+					require.Equal(t, backwards, bytes.Compare(k, last) < 0)
+				}
+				last = bytes.Clone(k)
+				require.Equal(t, expected[string(k)], v)
+				n++
+				return true
+			})
+			var cnt int
+			for k, v := range expected {
+				if v != nil && bytes.HasPrefix([]byte(k), prefix) {
+					cnt++
+				}
+			}
+			require.Equal(t, cnt, n)
+		}
+	}
+	check(t, s)
+	require.NoError(t, s.Close())
+
+	s, err = NewLSMStore(opts)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		_, n := s.pickCompaction()
+		return n == 0
+	}, time.Second*5, time.Millisecond*10)
+	check(t, s)
+
+	// Obsolete tables are removed.
+	s.lock.RLock()
+	nTables := len(s.tables)
+	s.lock.RUnlock()
+	files, err := filepath.Glob(filepath.Join(opts.DataDirectoryPath, "*.sst"))
+	require.NoError(t, err)
+	require.Equal(t, nTables, len(files))
+	require.NoError(t, s.Close())
+}
+
+func TestLSMStore_PrefixFilter(t *testing.T) {
+	s, err := NewLSMStore(dbconfig.LSMOptions{DataDirectoryPath: t.TempDir(), MemTableSize: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{"\x70\x00\x00\x00\x01a": {1}}))
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{"\x70\x00\x00\x00\x03a": {3}}))
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	require.Len(t, s.tables, 2)
+	_, tables := s.newIterators(SeekRange{Prefix: []byte("\x70\x00\x00\x00\x02")})
+	require.Len(t, tables, 0)
+	_, tables = s.newIterators(SeekRange{Prefix: []byte("\x70\x00\x00\x00\x01")})
+	require.Len(t, tables, 1)
+	unrefTables(tables)
+	_, tables = s.newIterators(SeekRange{Prefix: []byte("\x70")})
+	require.Len(t, tables, 2)
+	unrefTables(tables)
+}
+
+// crashLSMStore emulates node crash by copying store files as they are on disk
+// to a new directory without closing the store, it returns options to open
+// the copy with.
+func crashLSMStore(t *testing.T, s *LSMStore) dbconfig.LSMOptions {
+	s.compactLock.Lock()
+	defer s.compactLock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	opts := s.opts
+	opts.DataDirectoryPath = t.TempDir()
+	entries, err := os.ReadDir(s.dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(opts.DataDirectoryPath, e.Name()), data, 0644))
+	}
+	return opts
+}
+
+// reopenCrashedLSMStore emulates node crash by reopening the store without
+// closing it.
+func reopenCrashedLSMStore(t *testing.T, s *LSMStore) *LSMStore {
+	opts := crashLSMStore(t, s)
+	require.NoError(t, s.Close())
+	r, err := NewLSMStore(opts)
+	require.NoError(t, err)
+	return r
+}
+
+// checkLSMContents checks that the store contains exactly the expected
+// non-deleted keys.
+func checkLSMContents(t *testing.T, s *LSMStore, expected map[string][]byte) {
+	actual := make(map[string][]byte)
+	s.Seek(SeekRange{}, func(k, v []byte) bool {
+		actual[string(k)] = bytes.Clone(v)
+		return true
+	})
+	exp := make(map[string][]byte)
+	for k, v := range expected {
+		if v != nil {
+			exp[k] = v
+		}
+	}
+	require.Equal(t, exp, actual)
+}
+
+func cloneLSMContents(m map[string][]byte) map[string][]byte {
+	res := make(map[string][]byte, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+func TestLSMStore_CrashRecovery(t *testing.T) {
+	for _, noSync := range []bool{false, true} {
+		t.Run(fmt.Sprintf("NoSync=%t", noSync), func(t *testing.T) {
+			s, err := NewLSMStore(dbconfig.LSMOptions{
+				DataDirectoryPath: t.TempDir(),
+				MemTableSize:      512,
+				BlockSize:         64,
+				NoSync:            noSync,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, s.Close()) })
+
+			var (
+				expected  = make(map[string][]byte)
+				snapshots []dbconfig.LSMOptions
+				states    []map[string][]byte
+			)
+			for i := 0; i < 40; i++ {
+				batch := make(map[string][]byte)
+				for j := 0; j < 5; j++ {
+					k := string([]byte{byte(STStorage)}) + fmt.Sprint(random.Int(0, 50))
+					v := random.Bytes(random.Int(1, 32))
+					if random.Int(0, 4) == 0 {
+						v = nil
+					}
+					batch[k] = v
+					expected[k] = v
+				}
+				require.NoError(t, s.PutChangeSet(nil, batch))
+				if i%5 == 4 {
+					snapshots = append(snapshots, crashLSMStore(t, s))
+					states = append(states, cloneLSMContents(expected))
+				}
+			}
+			s.lock.RLock()
+			require.NotEmpty(t, s.tables) // Some change sets are in tables, some are in the WAL.
+			s.lock.RUnlock()
+
+			for i, opts := range snapshots {
+				r, err := NewLSMStore(opts)
+				require.NoError(t, err)
+				checkLSMContents(t, r, states[i])
+				require.NoError(t, r.Close())
+			}
+		})
+	}
+}
+
+func TestLSMStore_TruncatedWAL(t *testing.T) {
+	s, err := NewLSMStore(dbconfig.LSMOptions{DataDirectoryPath: t.TempDir()})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+
+	var (
+		expected = make(map[string][]byte)
+		states   = []map[string][]byte{{}}
+		sizes    = []int64{0}
+	)
+	for i := 0; i < 5; i++ {
+		k := string([]byte{byte(STStorage), byte(i % 3)})
+		v := []byte{byte(i)}
+		if i == 4 {
+			v = nil
+		}
+		expected[k] = v
+		require.NoError(t, s.PutChangeSet(nil, map[string][]byte{k: v, k + "x": {byte(i), byte(i)}}))
+		expected[k+"x"] = []byte{byte(i), byte(i)}
+		states = append(states, cloneLSMContents(expected))
+		sizes = append(sizes, s.walSize)
+	}
+	opts := crashLSMStore(t, s)
+	wal, err := os.ReadFile(filepath.Join(opts.DataDirectoryPath, lsmWALName))
+	require.NoError(t, err)
+	require.Equal(t, sizes[len(sizes)-1], int64(len(wal)))
+	manifest, err := os.ReadFile(filepath.Join(opts.DataDirectoryPath, lsmManifestName))
+	require.NoError(t, err)
+
+	// Every change set is either restored completely or not restored at all
+	// irrespective of where the WAL is cut.
+	var n int
+	for size := 0; size <= len(wal); size++ {
+		for n+1 < len(sizes) && sizes[n+1] <= int64(size) {
+			n++
+		}
+		opts.DataDirectoryPath = t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(opts.DataDirectoryPath, lsmManifestName), manifest, 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(opts.DataDirectoryPath, lsmWALName), wal[:size], 0644))
+		r, err := NewLSMStore(opts)
+		require.NoError(t, err)
+		checkLSMContents(t, r, states[n])
+
+		// Incomplete record is dropped, so subsequent ones are not lost.
+		require.NoError(t, r.PutChangeSet(nil, map[string][]byte{"\x01new": {1}}))
+		r = reopenCrashedLSMStore(t, r)
+		exp := cloneLSMContents(states[n])
+		exp["\x01new"] = []byte{1}
+		checkLSMContents(t, r, exp)
+		require.NoError(t, r.Close())
+	}
+}
@@ -0,0 +1,588 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sort"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LSM table is an immutable sorted file with the following layout:
+//
+//	data blocks | index | filter | footer
+//
+// Every data block is a sequence of entries:
+//
+//	flag (1 byte) | key length (uvarint) | value length (uvarint) | key | value
+//
+// Index contains the number of blocks, the first key, offset and length of
+// every block followed by the last key of the table. Filter is a bloom filter
+// over table keys and their prefixes of the configured length. Footer
+// contains offsets and lengths of index and filter, the prefix length, CRC32
+// checksum of index and filter and a magic number.
+
+const (
+	// lsmEntryValue marks an entry containing a value.
+	lsmEntryValue byte = 0
+	// lsmEntryTombstone marks an entry denoting key deletion.
+	lsmEntryTombstone byte = 1
+
+	lsmTableMagic      uint32 = 0x4c534d31 // "LSM1"
+	lsmFooterSize             = 8*4 + 4*3
+	lsmBloomBitsPerKey        = 10
+	// lsmBlockCacheSize is the number of decoded data blocks cached by the
+	// store.
+	lsmBlockCacheSize = 512
+)
+
+var lsmBloom = filter.NewBloomFilter(lsmBloomBitsPerKey)
+
+// lsmEntry is a single key-value pair of LSM table or memtable.
+type lsmEntry struct {
+	flag  byte
+	key   []byte
+	value []byte
+}
+
+// lsmBlockHandle describes table data block.
+type lsmBlockHandle struct {
+	first  []byte
+	offset uint64
+	length uint32
+}
+
+// lsmBlockKey identifies data block in the block cache.
+type lsmBlockKey struct {
+	table uint64
+	block int
+}
+
+// lsmTable is an opened LSM table.
+type lsmTable struct {
+	num       uint64
+	path      string
+	f         *os.File
+	size      int64
+	prefixLen int
+	blocks    []lsmBlockHandle
+	last      []byte
+	filter    []byte
+	cache     *lru.Cache[lsmBlockKey, []lsmEntry]
+
+	// refs is the number of table users including the store itself, the file
+	// is closed once it drops to zero.
+	refs atomic.Int32
+	// obsolete denotes that the table was replaced by compaction and its file
+	// should be removed once it's closed.
+	obsolete atomic.Bool
+}
+
+// lsmTableWriter creates LSM table file.
+type lsmTableWriter struct {
+	f          *os.File
+	w          *bufio.Writer
+	blockSize  int
+	prefixLen  int
+	offset     uint64
+	block      []byte
+	blockFirst []byte
+	index      []byte
+	nBlocks    int
+	last       []byte
+	lastPrefix []byte
+	filter     filter.FilterGenerator
+	count      int
+}
+
+func lsmTableName(num uint64) string {
+	return fmt.Sprintf("%06d.sst", num)
+}
+
+func newLSMTableWriter(path string, blockSize, prefixLen int) (*lsmTableWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &lsmTableWriter{
+		f:         f,
+		w:         bufio.NewWriterSize(f, 1<<16),
+		blockSize: blockSize,
+		prefixLen: prefixLen,
+		filter:    lsmBloom.NewGenerator(),
+	}, nil
+}
+
+// add appends an entry to the table, keys must be added in ascending order.
+func (w *lsmTableWriter) add(flag byte, key, value []byte) error {
+	if len(w.block) == 0 {
+		w.blockFirst = append(w.blockFirst[:0], key...)
+	}
+	w.block = append(w.block, flag)
+	w.block = binary.AppendUvarint(w.block, uint64(len(key)))
+	w.block = binary.AppendUvarint(w.block, uint64(len(value)))
+	w.block = append(w.block, key...)
+	w.block = append(w.block, value...)
+	w.last = append(w.last[:0], key...)
+	w.filter.Add(key)
+	if w.prefixLen > 0 && len(key) >= w.prefixLen && !bytes.Equal(key[:w.prefixLen], w.lastPrefix) {
+		w.lastPrefix = append(w.lastPrefix[:0], key[:w.prefixLen]...)
+		w.filter.Add(w.lastPrefix)
+	}
+	w.count++
+	if len(w.block) >= w.blockSize {
+		return w.flushBlock()
+	}
+	return nil
+}
+
+func (w *lsmTableWriter) flushBlock() error {
+	if len(w.block) == 0 {
+		return nil
+	}
+	if _, err := w.w.Write(w.block); err != nil {
+		return err
+	}
+	w.index = binary.AppendUvarint(w.index, uint64(len(w.blockFirst)))
+	w.index = append(w.index, w.blockFirst...)
+	w.index = binary.LittleEndian.AppendUint64(w.index, w.offset)
+	w.index = binary.LittleEndian.AppendUint32(w.index, uint32(len(w.block)))
+	w.offset += uint64(len(w.block))
+	w.nBlocks++
+	w.block = w.block[:0]
+	return nil
+}
+
+// finish writes index, filter and footer, syncs and closes the file.
+func (w *lsmTableWriter) finish() error {
+	err := w.flushBlock()
+	if err != nil {
+		w.f.Close()
+		return err
+	}
+	index := binary.AppendUvarint(nil, uint64(w.nBlocks))
+	index = append(index, w.index...)
+	index = binary.AppendUvarint(index, uint64(len(w.last)))
+	index = append(index, w.last...)
+	flt := new(util.Buffer)
+	w.filter.Generate(flt)
+
+	footer := make([]byte, 0, lsmFooterSize)
+	footer = binary.LittleEndian.AppendUint64(footer, w.offset)
+	footer = binary.LittleEndian.AppendUint64(footer, uint64(len(index)))
+	footer = binary.LittleEndian.AppendUint64(footer, w.offset+uint64(len(index)))
+	footer = binary.LittleEndian.AppendUint64(footer, uint64(flt.Len()))
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(w.prefixLen))
+	crc := crc32.ChecksumIEEE(index)
+	crc = crc32.Update(crc, crc32.IEEETable, flt.Bytes())
+	footer = binary.LittleEndian.AppendUint32(footer, crc)
+	footer = binary.LittleEndian.AppendUint32(footer, lsmTableMagic)
+	for _, b := range [][]byte{index, flt.Bytes(), footer} {
+		if _, err = w.w.Write(b); err != nil {
+			w.f.Close()
+			return err
+		}
+	}
+	if err = w.w.Flush(); err == nil {
+		err = w.f.Sync()
+	}
+	if err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+// abort closes and removes unfinished table file.
+func (w *lsmTableWriter) abort() {
+	w.f.Close()
+	os.Remove(w.f.Name())
+}
+
+// openLSMTable opens LSM table file and reads its index and filter.
+func openLSMTable(path string, num uint64, cache *lru.Cache[lsmBlockKey, []lsmEntry]) (*lsmTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := readLSMTable(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("table %s: %w", path, err)
+	}
+	t.num = num
+	t.path = path
+	t.cache = cache
+	t.refs.Store(1)
+	return t, nil
+}
+
+func readLSMTable(f *os.File) (*lsmTable, error) {
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if st.Size() < lsmFooterSize {
+		return nil, errors.New("file is too short")
+	}
+	footer := make([]byte, lsmFooterSize)
+	if _, err = f.ReadAt(footer, st.Size()-lsmFooterSize); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(footer[40:]) != lsmTableMagic {
+		return nil, errors.New("bad magic")
+	}
+	var (
+		indexOff  = binary.LittleEndian.Uint64(footer)
+		indexLen  = binary.LittleEndian.Uint64(footer[8:])
+		filterOff = binary.LittleEndian.Uint64(footer[16:])
+		filterLen = binary.LittleEndian.Uint64(footer[24:])
+	)
+	if indexOff+indexLen != filterOff || filterOff+filterLen != uint64(st.Size()-lsmFooterSize) {
+		return nil, errors.New("bad footer")
+	}
+	meta := make([]byte, indexLen+filterLen)
+	if _, err = f.ReadAt(meta, int64(indexOff)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(meta) != binary.LittleEndian.Uint32(footer[36:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	t := &lsmTable{
+		f:         f,
+		size:      st.Size(),
+		prefixLen: int(binary.LittleEndian.Uint32(footer[32:])),
+		filter:    meta[indexLen:],
+	}
+	index := meta[:indexLen]
+	n, index, err := lsmReadUvarint(index)
+	if err != nil {
+		return nil, err
+	}
+	t.blocks = make([]lsmBlockHandle, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var h lsmBlockHandle
+		h.first, index, err = lsmReadBytes(index)
+		if err != nil {
+			return nil, err
+		}
+		if len(index) < 12 {
+			return nil, errors.New("truncated index")
+		}
+		h.offset = binary.LittleEndian.Uint64(index)
+		h.length = binary.LittleEndian.Uint32(index[8:])
+		index = index[12:]
+		t.blocks = append(t.blocks, h)
+	}
+	t.last, _, err = lsmReadBytes(index)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func lsmReadUvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, errors.New("bad varint")
+	}
+	return v, b[n:], nil
+}
+
+func lsmReadBytes(b []byte) ([]byte, []byte, error) {
+	l, b, err := lsmReadUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(b)) < l {
+		return nil, nil, errors.New("truncated data")
+	}
+	return b[:l], b[l:], nil
+}
+
+// decodeLSMEntries parses a sequence of entries.
+func decodeLSMEntries(b []byte) ([]lsmEntry, error) {
+	var res []lsmEntry
+	for len(b) > 0 {
+		var (
+			e    = lsmEntry{flag: b[0]}
+			klen uint64
+			vlen uint64
+			err  error
+		)
+		klen, b, err = lsmReadUvarint(b[1:])
+		if err == nil {
+			vlen, b, err = lsmReadUvarint(b)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(b)) < klen+vlen {
+			return nil, errors.New("truncated entry")
+		}
+		e.key = b[:klen:klen]
+		e.value = b[klen : klen+vlen : klen+vlen]
+		b = b[klen+vlen:]
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func (t *lsmTable) ref() { t.refs.Add(1) }
+
+func (t *lsmTable) unref() {
+	if t.refs.Add(-1) == 0 {
+		t.f.Close()
+		if t.obsolete.Load() {
+			os.Remove(t.path)
+		}
+	}
+}
+
+// readBlock returns decoded entries of the i-th data block.
+func (t *lsmTable) readBlock(i int) ([]lsmEntry, error) {
+	key := lsmBlockKey{table: t.num, block: i}
+	if es, ok := t.cache.Get(key); ok {
+		return es, nil
+	}
+	h := t.blocks[i]
+	buf := make([]byte, h.length)
+	if _, err := t.f.ReadAt(buf, int64(h.offset)); err != nil {
+		return nil, fmt.Errorf("failed to read block %d of %s: %w", i, t.path, err)
+	}
+	es, err := decodeLSMEntries(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block %d of %s: %w", i, t.path, err)
+	}
+	t.cache.Add(key, es)
+	return es, nil
+}
+
+// findBlock returns the index of the last block with the first key less than
+// or equal to the given one or -1 if there is no such block.
+func (t *lsmTable) findBlock(key []byte) int {
+	return sort.Search(len(t.blocks), func(i int) bool {
+		return bytes.Compare(t.blocks[i].first, key) > 0
+	}) - 1
+}
+
+// get returns an entry for the given key, nil is returned if there is no
+// such key in the table.
+func (t *lsmTable) get(key []byte) (*lsmEntry, error) {
+	if len(t.blocks) == 0 || !lsmBloom.Contains(t.filter, key) {
+		return nil, nil
+	}
+	b := t.findBlock(key)
+	if b < 0 {
+		return nil, nil
+	}
+	es, err := t.readBlock(b)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(es), func(i int) bool { return bytes.Compare(es[i].key, key) >= 0 })
+	if i < len(es) && bytes.Equal(es[i].key, key) {
+		return &es[i], nil
+	}
+	return nil, nil
+}
+
+// mayContain checks whether the table can contain keys from the given range
+// with the given prefix.
+func (t *lsmTable) mayContain(rng *util.Range, prefix []byte) bool {
+	if len(t.blocks) == 0 {
+		return false
+	}
+	if rng.Start != nil && bytes.Compare(t.last, rng.Start) < 0 ||
+		rng.Limit != nil && bytes.Compare(t.blocks[0].first, rng.Limit) >= 0 {
+		return false
+	}
+	if t.prefixLen > 0 && len(prefix) >= t.prefixLen {
+		return lsmBloom.Contains(t.filter, prefix[:t.prefixLen])
+	}
+	return true
+}
+
+// lsmIterator iterates over entries in a fixed direction.
+type lsmIterator interface {
+	// next moves to the next entry, false is returned when there are no
+	// more entries.
+	next() bool
+	// entry returns the current entry.
+	entry() *lsmEntry
+	// error returns an error occurred during iteration if any.
+	error() error
+}
+
+// lsmSliceIter iterates over the slice of entries sorted in the iteration
+// direction.
+type lsmSliceIter struct {
+	es  []lsmEntry
+	pos int
+}
+
+func newLSMSliceIter(es []lsmEntry) *lsmSliceIter {
+	return &lsmSliceIter{es: es, pos: -1}
+}
+
+func (it *lsmSliceIter) next() bool {
+	if it.pos < len(it.es) {
+		it.pos++
+	}
+	return it.pos < len(it.es)
+}
+
+func (it *lsmSliceIter) entry() *lsmEntry { return &it.es[it.pos] }
+func (it *lsmSliceIter) error() error     { return nil }
+
+// lsmTableIter iterates over table entries within the given range.
+type lsmTableIter struct {
+	t         *lsmTable
+	rng       *util.Range
+	backwards bool
+	block     int
+	es        []lsmEntry
+	pos       int
+	started   bool
+	err       error
+}
+
+func newLSMTableIter(t *lsmTable, rng *util.Range, backwards bool) *lsmTableIter {
+	return &lsmTableIter{t: t, rng: rng, backwards: backwards}
+}
+
+func (it *lsmTableIter) load(block int) bool {
+	if block < 0 || block >= len(it.t.blocks) {
+		return false
+	}
+	it.block = block
+	it.es, it.err = it.t.readBlock(block)
+	return it.err == nil
+}
+
+func (it *lsmTableIter) start() bool {
+	it.started = true
+	if !it.backwards {
+		b := 0
+		if it.rng.Start != nil {
+			if b = it.t.findBlock(it.rng.Start); b < 0 {
+				b = 0
+			}
+		}
+		if !it.load(b) {
+			return false
+		}
+		it.pos = 0
+		if it.rng.Start != nil {
+			it.pos = sort.Search(len(it.es), func(i int) bool { return bytes.Compare(it.es[i].key, it.rng.Start) >= 0 })
+		}
+		return it.settle()
+	}
+	b := len(it.t.blocks) - 1
+	if it.rng.Limit != nil {
+		b = it.t.findBlock(it.rng.Limit)
+	}
+	if !it.load(b) {
+		return false
+	}
+	it.pos = len(it.es) - 1
+	if it.rng.Limit != nil {
+		it.pos = sort.Search(len(it.es), func(i int) bool { return bytes.Compare(it.es[i].key, it.rng.Limit) >= 0 }) - 1
+	}
+	return it.settle()
+}
+
+// settle moves to the neighbouring block if the position is out of the
+// current one and checks range bounds.
+func (it *lsmTableIter) settle() bool {
+	for it.pos < 0 || it.pos >= len(it.es) {
+		var b = it.block + 1
+		if it.backwards {
+			b = it.block - 1
+		}
+		if !it.load(b) {
+			return false
+		}
+		it.pos = 0
+		if it.backwards {
+			it.pos = len(it.es) - 1
+		}
+	}
+	k := it.es[it.pos].key
+	if !it.backwards {
+		return it.rng.Limit == nil || bytes.Compare(k, it.rng.Limit) < 0
+	}
+	return it.rng.Start == nil || bytes.Compare(k, it.rng.Start) >= 0
+}
+
+func (it *lsmTableIter) next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		return it.start()
+	}
+	if it.backwards {
+		it.pos--
+	} else {
+		it.pos++
+	}
+	return it.settle()
+}
+
+func (it *lsmTableIter) entry() *lsmEntry { return &it.es[it.pos] }
+func (it *lsmTableIter) error() error     { return it.err }
+
+// mergeLSMIterators merges entries of the given iterators passing them to f in
+// the iteration order until f returns false. Iterators are ordered by
+// priority, if several of them contain the same key, the entry of the first
+// one is used. An error of any iterator stops merging and is returned.
+func mergeLSMIterators(iters []lsmIterator, backwards bool, f func(e *lsmEntry) bool) error {
+	valid := make([]bool, len(iters))
+	for i := range iters {
+		valid[i] = iters[i].next()
+	}
+	for {
+		best := -1
+		for i := range iters {
+			if !valid[i] {
+				continue
+			}
+			if best < 0 {
+				best = i
+				continue
+			}
+			c := bytes.Compare(iters[i].entry().key, iters[best].entry().key)
+			if backwards && c > 0 || !backwards && c < 0 {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		e := iters[best].entry()
+		for i := range iters {
+			if i != best && valid[i] && bytes.Equal(iters[i].entry().key, e.key) {
+				valid[i] = iters[i].next()
+			}
+		}
+		if !f(e) {
+			break
+		}
+		valid[best] = iters[best].next()
+	}
+	for i := range iters {
+		if err := iters[i].error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// Compactor is an optional interface implemented by stores that are able to
+// reclaim the space occupied by deleted and overwritten data. Compact can be
+// called while the store is in use.
+type Compactor interface {
+	Compact() error
+}
+
+// PrefixStats contains the number of keys and their total size for some set
+// of DB entries.
+type PrefixStats struct {
+	Keys       uint64
+	KeyBytes   uint64
+	ValueBytes uint64
+}
+
+// Stats contains storage statistics collected by GetStats.
+type Stats struct {
+	// Prefixes contains statistics for every KeyPrefix present in the DB.
+	Prefixes map[KeyPrefix]PrefixStats
+	// Contracts contains contract storage statistics by contract ID,
+	// both STStorage and STTempStorage items are counted.
+	Contracts map[int32]PrefixStats
+}
+
+// statsCheckInterval is the number of entries after which GetStats checks the
+// context.
+const statsCheckInterval = 4096
+
+// GetStats iterates over the whole store and returns the number of keys and
+// their sizes per KeyPrefix and per contract. Collection is stopped and the
+// context error is returned if the context is canceled.
+func GetStats(ctx context.Context, s Store) (*Stats, error) {
+	var (
+		res = &Stats{
+			Prefixes:  make(map[KeyPrefix]PrefixStats),
+			Contracts: make(map[int32]PrefixStats),
+		}
+		n   int
+		err error
+	)
+	for i := 0; i < 256 && err == nil; i++ {
+		var (
+			p         = KeyPrefix(i)
+			ps        PrefixStats
+			isStorage = p == STStorage || p == STTempStorage
+		)
+		s.Seek(SeekRange{Prefix: []byte{byte(p)}}, func(k, v []byte) bool {
+			if n++; n%statsCheckInterval == 0 {
+				if err = ctx.Err(); err != nil {
+					return false
+				}
+			}
+			ps.add(k, v)
+			if isStorage && len(k) >= 5 {
+				id := int32(binary.LittleEndian.Uint32(k[1:5]))
+				cs := res.Contracts[id]
+				cs.add(k, v)
+				res.Contracts[id] = cs
+			}
+			return true
+		})
+		if ps.Keys != 0 {
+			res.Prefixes[p] = ps
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *PrefixStats) add(k, v []byte) {
+	s.Keys++
+	s.KeyBytes += uint64(len(k))
+	s.ValueBytes += uint64(len(v))
+}
+
+// Total returns the total size of keys and values.
+func (s PrefixStats) Total() uint64 {
+	return s.KeyBytes + s.ValueBytes
+}
+
+var prefixNames = map[KeyPrefix]string{
+	DataExecutable:                 "DataExecutable",
+	DataMPT:                        "DataMPT",
+	DataMPTAux:                     "DataMPTAux",
+	STStorage:                      "STStorage",
+	STTempStorage:                  "STTempStorage",
+	STNEP11Transfers:               "STNEP11Transfers",
+	STNEP17Transfers:               "STNEP17Transfers",
+	STTokenTransferInfo:            "STTokenTransferInfo",
+	IXHeaderHashList:               "IXHeaderHashList",
+	SYSCurrentBlock:                "SYSCurrentBlock",
+	SYSCurrentHeader:               "SYSCurrentHeader",
+	SYSStateSyncCurrentBlockHeight: "SYSStateSyncCurrentBlockHeight",
+	SYSStateSyncPoint:              "SYSStateSyncPoint",
+	SYSStateChangeStage:            "SYSStateChangeStage",
+	SYSVersion:                     "SYSVersion",
+}
+
+// String implements the fmt.Stringer interface, unknown prefixes are
+// formatted as hex numbers.
+func (p KeyPrefix) String() string {
+	if name, ok := prefixNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(p))
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStats(t *testing.T) {
+	s := NewMemoryStore()
+	storageKey := func(prefix KeyPrefix, id int32, k string) string {
+		key := make([]byte, 5, 5+len(k))
+		key[0] = byte(prefix)
+		binary.LittleEndian.PutUint32(key[1:], uint32(id))
+		return string(append(key, k...))
+	}
+	require.NoError(t, s.PutChangeSet(map[string][]byte{
+		string([]byte{byte(DataExecutable), 1, 2, 3}): {1, 2, 3, 4},
+		string([]byte{byte(DataExecutable), 4, 5}):    {5},
+		string([]byte{byte(SYSVersion)}):              {1, 2},
+		string([]byte{0xee, 1}):                       {},
+	}, map[string][]byte{
+		storageKey(STStorage, -1, "a"):    {1},
+		storageKey(STStorage, -1, "bb"):   {1, 2},
+		storageKey(STStorage, 5, "c"):     {1, 2, 3},
+		storageKey(STTempStorage, 5, "d"): {4},
+	}))
+
+	stats, err := GetStats(context.Background(), s)
+	require.NoError(t, err)
+	require.Equal(t, map[KeyPrefix]PrefixStats{
+		DataExecutable: {Keys: 2, KeyBytes: 7, ValueBytes: 5},
+		SYSVersion:     {Keys: 1, KeyBytes: 1, ValueBytes: 2},
+		0xee:           {Keys: 1, KeyBytes: 2},
+		STStorage:      {Keys: 3, KeyBytes: 19, ValueBytes: 6},
+		STTempStorage:  {Keys: 1, KeyBytes: 6, ValueBytes: 1},
+	}, stats.Prefixes)
+	require.Equal(t, map[int32]PrefixStats{
+		-1: {Keys: 2, KeyBytes: 13, ValueBytes: 3},
+		5:  {Keys: 2, KeyBytes: 12, ValueBytes: 4},
+	}, stats.Contracts)
+	require.Equal(t, uint64(25), stats.Prefixes[STStorage].Total())
+
+	require.Equal(t, "STStorage", STStorage.String())
+	require.Equal(t, "0xee", KeyPrefix(0xee).String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	puts := make(map[string][]byte, statsCheckInterval)
+	for i := 0; i < statsCheckInterval; i++ {
+		puts[storageKey(STStorage, 1, string(binary.BigEndian.AppendUint32(nil, uint32(i))))] = []byte{1}
+	}
+	require.NoError(t, s.PutChangeSet(nil, puts))
+	_, err = GetStats(ctx, s)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCompact(t *testing.T) {
+	const n = 4096
+	var (
+		value = make([]byte, 512)
+		puts  = make(map[string][]byte, n)
+		dels  = make(map[string][]byte, n/2)
+	)
+	for i := 0; i < n; i++ {
+		k := string(binary.BigEndian.AppendUint32([]byte{byte(STStorage)}, uint32(i)))
+		puts[k] = value
+		if i%2 == 0 {
+			dels[k] = nil
+		}
+	}
+	check := func(t *testing.T, s Store) {
+		for i := 0; i < n; i++ {
+			k := binary.BigEndian.AppendUint32([]byte{byte(STStorage)}, uint32(i))
+			v, err := s.Get(k)
+			if i%2 == 0 {
+				require.ErrorIs(t, err, ErrKeyNotFound)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, value, v)
+			}
+		}
+	}
+	var stores = []struct {
+		name   string
+		create func(t testing.TB) Store
+	}{
+		{"BoltDB", newBoltStoreForTesting},
+		{"LevelDB", newLevelDBForTesting},
+		{"LSM", newLSMStoreForTesting},
+	}
+	for _, st := range stores {
+		t.Run(st.name, func(t *testing.T) {
+			s := st.create(t)
+			t.Cleanup(func() { require.NoError(t, s.Close()) })
+			require.NoError(t, s.PutChangeSet(puts, nil))
+			require.NoError(t, s.PutChangeSet(dels, nil))
+
+			c, ok := s.(Compactor)
+			require.True(t, ok)
+			require.NoError(t, c.Compact())
+			check(t, s)
+
+			// The store remains usable after compaction.
+			require.NoError(t, s.PutChangeSet(map[string][]byte{"\x01key": {1}}, nil))
+			v, err := s.Get([]byte("\x01key"))
+			require.NoError(t, err)
+			require.Equal(t, []byte{1}, v)
+			require.NoError(t, c.Compact())
+			check(t, s)
+		})
+	}
+
+	t.Run("BoltDB file size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bolt")
+		s, err := NewBoltDBStore(dbconfig.BoltDBOptions{FilePath: path})
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, s.Close()) })
+		require.NoError(t, s.PutChangeSet(puts, nil))
+		require.NoError(t, s.PutChangeSet(dels, nil))
+		before, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, s.Compact())
+		after, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Less(t, after.Size(), before.Size())
+		check(t, s)
+	})
+}
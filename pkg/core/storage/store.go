@@ -3,6 +3,8 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dboper"
@@ -120,21 +122,69 @@ func seekRangeToPrefixes(sr SeekRange) *util.Range {
 	return rang
 }
 
+// StoreConstructor creates a new Store using the given configuration.
+type StoreConstructor func(cfg dbconfig.DBConfiguration) (Store, error)
+
+var (
+	constructorsLock sync.RWMutex
+	constructors     = make(map[string]StoreConstructor)
+)
+
+func init() {
+	RegisterStore(dbconfig.LevelDB, func(cfg dbconfig.DBConfiguration) (Store, error) {
+		return NewLevelDBStore(cfg.LevelDBOptions)
+	})
+	RegisterStore(dbconfig.InMemoryDB, func(dbconfig.DBConfiguration) (Store, error) {
+		return NewMemoryStore(), nil
+	})
+	RegisterStore(dbconfig.BoltDB, func(cfg dbconfig.DBConfiguration) (Store, error) {
+		return NewBoltDBStore(cfg.BoltDBOptions)
+	})
+	RegisterStore(dbconfig.LSMDB, func(cfg dbconfig.DBConfiguration) (Store, error) {
+		return NewLSMStore(cfg.LSMOptions)
+	})
+}
+
+// RegisterStore makes Store implementation available for [NewStore] by the
+// given type name. It's intended to be called from init functions of packages
+// providing additional storage backends. It panics if the name is empty, the
+// constructor is nil or the name is already registered.
+func RegisterStore(typ string, c StoreConstructor) {
+	if typ == "" {
+		panic("storage: empty store type")
+	}
+	if c == nil {
+		panic("storage: nil constructor for " + typ)
+	}
+	constructorsLock.Lock()
+	defer constructorsLock.Unlock()
+	if _, ok := constructors[typ]; ok {
+		panic("storage: store type " + typ + " is already registered")
+	}
+	constructors[typ] = c
+}
+
+// StoreTypes returns a sorted list of registered store types.
+func StoreTypes() []string {
+	constructorsLock.RLock()
+	defer constructorsLock.RUnlock()
+	res := make([]string, 0, len(constructors))
+	for typ := range constructors {
+		res = append(res, typ)
+	}
+	sort.Strings(res)
+	return res
+}
+
 // NewStore creates storage with preselected in configuration database type.
 func NewStore(cfg dbconfig.DBConfiguration) (Store, error) {
-	var store Store
-	var err error
-	switch cfg.Type {
-	case dbconfig.LevelDB:
-		store, err = NewLevelDBStore(cfg.LevelDBOptions)
-	case dbconfig.InMemoryDB:
-		store = NewMemoryStore()
-	case dbconfig.BoltDB:
-		store, err = NewBoltDBStore(cfg.BoltDBOptions)
-	default:
+	constructorsLock.RLock()
+	c, ok := constructors[cfg.Type]
+	constructorsLock.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
 	}
-	return store, err
+	return c(cfg)
 }
 
 // BatchToOperations converts a batch of changes into array of dboper.Operation.
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/internal/random"
+	"github.com/stretchr/testify/require"
+)
+
+// benchWorkload describes a set of keys typical for some part of the chain
+// state.
+type benchWorkload struct {
+	name      string
+	prefix    KeyPrefix
+	groups    int // Number of distinct key sub-prefixes (like contract IDs).
+	keyLen    int
+	valueLen  int
+	batchSize int
+}
+
+var benchWorkloads = []benchWorkload{
+	{name: "STStorage", prefix: STStorage, groups: 64, keyLen: 20, valueLen: 512, batchSize: 1000},
+	{name: "DataMPT", prefix: DataMPT, groups: 256, keyLen: 32, valueLen: 128, batchSize: 1000},
+}
+
+var benchStores = []dbSetup{
+	{"BoltDB", newBoltStoreForTesting},
+	{"LevelDB", newLevelDBForTesting},
+	{"LSM", newLSMStoreForTesting},
+}
+
+func (w benchWorkload) key(group uint32) []byte {
+	k := make([]byte, 5, 5+w.keyLen)
+	k[0] = byte(w.prefix)
+	binary.BigEndian.PutUint32(k[1:], group)
+	return append(k, random.Bytes(w.keyLen)...)
+}
+
+func (w benchWorkload) batch() map[string][]byte {
+	m := make(map[string][]byte, w.batchSize)
+	for i := 0; i < w.batchSize; i++ {
+		m[string(w.key(uint32(random.Int(0, w.groups))))] = random.Bytes(w.valueLen)
+	}
+	return m
+}
+
+func (w benchWorkload) put(s Store, m map[string][]byte) error {
+	if w.prefix == STStorage {
+		return s.PutChangeSet(nil, m)
+	}
+	return s.PutChangeSet(m, nil)
+}
+
+func BenchmarkStorePutChangeSet(b *testing.B) {
+	for _, w := range benchWorkloads {
+		for _, db := range benchStores {
+			b.Run(fmt.Sprintf("%s/%s", w.name, db.name), func(b *testing.B) {
+				s := db.create(b)
+				b.Cleanup(func() { require.NoError(b, s.Close()) })
+				batches := make([]map[string][]byte, 16)
+				for i := range batches {
+					batches[i] = w.batch()
+				}
+				b.SetBytes(int64(w.batchSize * (w.keyLen + w.valueLen)))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					require.NoError(b, w.put(s, batches[i%len(batches)]))
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkStoreSeekPrefix(b *testing.B) {
+	for _, w := range benchWorkloads {
+		for _, db := range benchStores {
+			b.Run(fmt.Sprintf("%s/%s", w.name, db.name), func(b *testing.B) {
+				s := db.create(b)
+				b.Cleanup(func() { require.NoError(b, s.Close()) })
+				for i := 0; i < 20; i++ {
+					require.NoError(b, w.put(s, w.batch()))
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					prefix := w.key(uint32(i % w.groups))[:5]
+					s.Seek(SeekRange{Prefix: prefix}, func(k, v []byte) bool { return true })
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkStoreGet(b *testing.B) {
+	for _, w := range benchWorkloads {
+		for _, db := range benchStores {
+			b.Run(fmt.Sprintf("%s/%s", w.name, db.name), func(b *testing.B) {
+				s := db.create(b)
+				b.Cleanup(func() { require.NoError(b, s.Close()) })
+				var keys [][]byte
+				for i := 0; i < 20; i++ {
+					m := w.batch()
+					for k := range m {
+						keys = append(keys, []byte(k))
+					}
+					require.NoError(b, w.put(s, m))
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, err := s.Get(keys[i%len(keys)])
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/epicchainlabs/epicchain-go/internal/random"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dbconfig"
 	"github.com/stretchr/testify/require"
 )
@@ -17,8 +18,11 @@ func TestStorageNames(t *testing.T) {
 		BoltDBOptions: dbconfig.BoltDBOptions{
 			FilePath: filepath.Join(tmp, "bolt"),
 		},
+		LSMOptions: dbconfig.LSMOptions{
+			DataDirectoryPath: filepath.Join(tmp, "lsm"),
+		},
 	}
-	for _, name := range []string{dbconfig.BoltDB, dbconfig.LevelDB, dbconfig.InMemoryDB} {
+	for _, name := range []string{dbconfig.BoltDB, dbconfig.LevelDB, dbconfig.InMemoryDB, dbconfig.LSMDB} {
 		t.Run(name, func(t *testing.T) {
 			cfg.Type = name
 			s, err := NewStore(cfg)
@@ -27,3 +31,31 @@ func TestStorageNames(t *testing.T) {
 		})
 	}
 }
+
+type testStore struct {
+	*MemoryStore
+	opts map[string]any
+}
+
+func TestRegisterStore(t *testing.T) {
+	name := "test" + random.String(8)
+
+	_, err := NewStore(dbconfig.DBConfiguration{Type: name})
+	require.Error(t, err)
+	require.NotContains(t, StoreTypes(), name)
+
+	RegisterStore(name, func(cfg dbconfig.DBConfiguration) (Store, error) {
+		return &testStore{MemoryStore: NewMemoryStore(), opts: cfg.Options}, nil
+	})
+	require.Contains(t, StoreTypes(), name)
+	require.Subset(t, StoreTypes(), []string{dbconfig.BoltDB, dbconfig.LevelDB, dbconfig.InMemoryDB, dbconfig.LSMDB})
+
+	s, err := NewStore(dbconfig.DBConfiguration{Type: name, Options: map[string]any{"key": "value"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"key": "value"}, s.(*testStore).opts)
+	require.NoError(t, s.Close())
+
+	require.Panics(t, func() { RegisterStore(name, func(dbconfig.DBConfiguration) (Store, error) { return nil, nil }) })
+	require.Panics(t, func() { RegisterStore("", func(dbconfig.DBConfiguration) (Store, error) { return nil, nil }) })
+	require.Panics(t, func() { RegisterStore("another", nil) })
+}
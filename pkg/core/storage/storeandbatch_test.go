@@ -232,6 +232,8 @@ func TestAllDBs(t *testing.T) {
 	var DBs = []dbSetup{
 		{"BoltDB", newBoltStoreForTesting},
 		{"LevelDB", newLevelDBForTesting},
+		{"LSM", newLSMStoreForTesting},
+		{"LSMFlushed", newFlushingLSMStoreForTesting},
 		{"MemCached", newMemCachedStoreForTesting},
 		{"Memory", newMemoryStoreForTesting},
 	}
@@ -1,21 +1,42 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/config/limits"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/fee"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/nef"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
 )
 
 // CreateGenesisBlock creates a genesis block based on the given configuration.
+// Files of contracts deployed in the genesis block (if any) are read to be
+// included into the block, see config.Genesis.
 func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error) {
+	contracts, err := loadGenesisContracts(cfg.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load genesis contracts: %w", err)
+	}
+	return createGenesisBlock(cfg, contracts)
+}
+
+// createGenesisBlock creates a genesis block based on the given configuration
+// and the set of contracts deployed in it.
+func createGenesisBlock(cfg config.ProtocolConfiguration, contracts []native.GenesisContract) (*block.Block, error) {
 	validators, committee, err := validatorsFromConfig(cfg)
 	if err != nil {
 		return nil, err
@@ -27,12 +48,11 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 	}
 
 	txs := []*transaction.Transaction{}
-	if cfg.Genesis.Transaction != nil {
+	if cfg.Genesis.Transaction != nil || len(contracts) != 0 {
 		committeeH, err := getCommitteeAddress(committee)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate committee address: %w", err)
 		}
-		tx := cfg.Genesis.Transaction
 		signers := []transaction.Signer{
 			{
 				Account: nextConsensus,
@@ -60,13 +80,31 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 			}...)
 		}
 
-		txs = append(txs, &transaction.Transaction{
-			SystemFee:       tx.SystemFee,
-			ValidUntilBlock: 1,
-			Script:          tx.Script,
-			Signers:         signers,
-			Scripts:         scripts,
-		})
+		if tx := cfg.Genesis.Transaction; tx != nil {
+			txs = append(txs, &transaction.Transaction{
+				SystemFee:       tx.SystemFee,
+				ValidUntilBlock: 1,
+				Script:          tx.Script,
+				Signers:         signers,
+				Scripts:         scripts,
+			})
+		}
+		if len(contracts) != 0 {
+			// Contracts are deployed by Management, but the set of them
+			// must affect the genesis block hash, so a transaction
+			// returning the hash of this set is added.
+			h, err := genesisContractsHash(contracts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate genesis contracts hash: %w", err)
+			}
+			txs = append(txs, &transaction.Transaction{
+				SystemFee:       fee.Opcode(interop.DefaultBaseExecFee, opcode.PUSHDATA1),
+				ValidUntilBlock: 1,
+				Script:          append([]byte{byte(opcode.PUSHDATA1), util.Uint256Size}, h.BytesBE()...),
+				Signers:         signers,
+				Scripts:         scripts,
+			})
+		}
 	}
 
 	base := block.Header{
@@ -92,6 +130,85 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 	return b, nil
 }
 
+// loadGenesisContracts reads NEF and manifest files of contracts that should be
+// deployed in the genesis block and checks their storage items.
+func loadGenesisContracts(cfg config.Genesis) ([]native.GenesisContract, error) {
+	var res = make([]native.GenesisContract, 0, len(cfg.Contracts))
+	for i, c := range cfg.Contracts {
+		var gc native.GenesisContract
+
+		b, err := os.ReadFile(c.NEF)
+		if err != nil {
+			return nil, fmt.Errorf("genesis contract #%d: can't read NEF file: %w", i, err)
+		}
+		gc.NEF, err = nef.FileFromBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("genesis contract #%d: can't parse NEF file: %w", i, err)
+		}
+		b, err = os.ReadFile(c.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("genesis contract #%d: can't read manifest file: %w", i, err)
+		}
+		if err = json.Unmarshal(b, &gc.Manifest); err != nil {
+			return nil, fmt.Errorf("genesis contract #%d: can't parse manifest file: %w", i, err)
+		}
+		if c.Data != nil {
+			gc.Data, err = c.Data.ToStackItem()
+			if err != nil {
+				return nil, fmt.Errorf("genesis contract #%d: invalid data: %w", i, err)
+			}
+		}
+		for j, si := range c.Storage {
+			if len(si.Key) == 0 || len(si.Key) > limits.MaxStorageKeyLen {
+				return nil, fmt.Errorf("genesis contract #%d: storage item #%d has invalid key length %d", i, j, len(si.Key))
+			}
+			if len(si.Value) > limits.MaxStorageValueLen {
+				return nil, fmt.Errorf("genesis contract #%d: storage item #%d has too big value", i, j)
+			}
+		}
+		gc.Storage = c.Storage
+		res = append(res, gc)
+	}
+	return res, nil
+}
+
+// genesisContractsHash returns the hash of NEF files, manifests, _deploy data
+// and storage items of the given genesis contracts.
+func genesisContractsHash(contracts []native.GenesisContract) (util.Uint256, error) {
+	w := io.NewBufBinWriter()
+	w.WriteVarUint(uint64(len(contracts)))
+	for i := range contracts {
+		gc := &contracts[i]
+		nefBytes, err := gc.NEF.Bytes()
+		if err != nil {
+			return util.Uint256{}, fmt.Errorf("genesis contract #%d: %w", i, err)
+		}
+		manifestBytes, err := json.Marshal(&gc.Manifest)
+		if err != nil {
+			return util.Uint256{}, fmt.Errorf("genesis contract #%d: %w", i, err)
+		}
+		var data []byte
+		if gc.Data != nil {
+			data, err = stackitem.Serialize(gc.Data)
+			if err != nil {
+				return util.Uint256{}, fmt.Errorf("genesis contract #%d: %w", i, err)
+			}
+		}
+		w.WriteVarBytes(nefBytes)
+		w.WriteVarBytes(manifestBytes)
+		w.WriteVarBytes(data)
+		w.WriteVarUint(uint64(len(gc.Storage)))
+		for _, si := range gc.Storage {
+			w.WriteVarBytes(si.Key)
+			w.WriteVarBytes(si.Value)
+		}
+	}
+	if w.Err != nil {
+		return util.Uint256{}, w.Err
+	}
+	return hash.Sha256(w.Bytes()), nil
+}
+
 func validatorsFromConfig(cfg config.ProtocolConfiguration) ([]*keys.PublicKey, []*keys.PublicKey, error) {
 	vs, err := keys.NewPublicKeysFromStrings(cfg.StandbyCommittee)
 	if err != nil {
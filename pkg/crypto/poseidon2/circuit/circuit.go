@@ -0,0 +1,146 @@
+/*
+Package circuit implements Poseidon2 hash function over BLS12-381 scalar field
+as gnark circuit gadget. It produces the same results as poseidon2 package
+(and CryptoLib's poseidon2Bls12381 method), so hashes computed by contracts
+can be checked in circuits and vice versa. The gadget works with gnark
+versions that lack Poseidon2 support (like v0.9.1 used here) and is
+equivalent to the Merkle–Damgård hasher over gnark v0.13.0+
+std/permutation/poseidon2 with the default gnark-crypto BLS12-381 parameters.
+Gadgets can only be used in circuits compiled for BLS12-381 scalar field.
+*/
+package circuit
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/poseidon2"
+)
+
+// Hasher is a Merkle–Damgård Poseidon2 hasher implementing gnark's
+// hash.FieldHasher interface, so it can be used with gnark's standard gadgets
+// like Merkle proof verification.
+type Hasher struct {
+	api   frontend.API
+	state frontend.Variable
+	data  []frontend.Variable
+}
+
+var _ hash.FieldHasher = (*Hasher)(nil)
+
+var (
+	roundKeysOnce sync.Once
+	roundKeys     [][]*big.Int
+)
+
+func getRoundKeys() [][]*big.Int {
+	roundKeysOnce.Do(func() {
+		rk := poseidon2.RoundKeys()
+		roundKeys = make([][]*big.Int, len(rk))
+		for i := range rk {
+			roundKeys[i] = make([]*big.Int, len(rk[i]))
+			for j := range rk[i] {
+				roundKeys[i][j] = rk[i][j].BigInt(new(big.Int))
+			}
+		}
+	})
+	return roundKeys
+}
+
+// NewHasher returns a new Hasher for the given API. It panics if the circuit
+// is not compiled for BLS12-381 scalar field.
+func NewHasher(api frontend.API) *Hasher {
+	checkField(api)
+	return &Hasher{api: api, state: 0}
+}
+
+func checkField(api frontend.API) {
+	if api.Compiler().Field().Cmp(fr.Modulus()) != 0 {
+		panic("poseidon2 gadget requires BLS12-381 scalar field")
+	}
+}
+
+// Write implements hash.FieldHasher interface.
+func (h *Hasher) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset implements hash.FieldHasher interface.
+func (h *Hasher) Reset() {
+	h.data = nil
+	h.state = 0
+}
+
+// Sum implements hash.FieldHasher interface. It returns the hash of all
+// elements written so far.
+func (h *Hasher) Sum() frontend.Variable {
+	for _, e := range h.data {
+		h.state = compress(h.api, h.state, e)
+	}
+	h.data = nil
+	return h.state
+}
+
+// Permute applies Poseidon2 permutation to the given state.
+func Permute(api frontend.API, x *[poseidon2.Width]frontend.Variable) {
+	checkField(api)
+	permute(api, x)
+}
+
+// Compress is the compression function of the hash, see poseidon2.Compress.
+func Compress(api frontend.API, left, right frontend.Variable) frontend.Variable {
+	checkField(api)
+	return compress(api, left, right)
+}
+
+func compress(api frontend.API, left, right frontend.Variable) frontend.Variable {
+	var x = [poseidon2.Width]frontend.Variable{left, right}
+	permute(api, &x)
+	return api.Add(x[1], right)
+}
+
+func permute(api frontend.API, x *[poseidon2.Width]frontend.Variable) {
+	var rk = getRoundKeys()
+
+	matMulExternal(api, x)
+	for i := 0; i < poseidon2.FullRounds/2; i++ {
+		fullRound(api, x, rk[i])
+	}
+	for i := poseidon2.FullRounds / 2; i < poseidon2.FullRounds/2+poseidon2.PartialRounds; i++ {
+		x[0] = sBox(api, api.Add(x[0], rk[i][0]))
+		matMulInternal(api, x)
+	}
+	for i := poseidon2.FullRounds/2 + poseidon2.PartialRounds; i < poseidon2.FullRounds+poseidon2.PartialRounds; i++ {
+		fullRound(api, x, rk[i])
+	}
+}
+
+func fullRound(api frontend.API, x *[poseidon2.Width]frontend.Variable, rk []*big.Int) {
+	for j := range x {
+		x[j] = sBox(api, api.Add(x[j], rk[j]))
+	}
+	matMulExternal(api, x)
+}
+
+func sBox(api frontend.API, x frontend.Variable) frontend.Variable {
+	x2 := api.Mul(x, x)
+	x4 := api.Mul(x2, x2)
+	return api.Mul(x4, x)
+}
+
+// matMulExternal multiplies the state by circ(2, 1) matrix.
+func matMulExternal(api frontend.API, x *[poseidon2.Width]frontend.Variable) {
+	sum := api.Add(x[0], x[1])
+	x[0] = api.Add(x[0], sum)
+	x[1] = api.Add(x[1], sum)
+}
+
+// matMulInternal multiplies the state by [[2, 1], [1, 3]] matrix.
+func matMulInternal(api frontend.API, x *[poseidon2.Width]frontend.Variable) {
+	sum := api.Add(x[0], x[1])
+	x[0] = api.Add(x[0], sum)
+	x[1] = api.Add(api.Mul(x[1], 2), sum)
+}
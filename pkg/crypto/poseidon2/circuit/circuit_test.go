@@ -0,0 +1,88 @@
+package circuit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/poseidon2"
+	"github.com/stretchr/testify/require"
+)
+
+type hashCircuit struct {
+	Data []frontend.Variable
+	Hash frontend.Variable `gnark:",public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	h := NewHasher(api)
+	h.Write(c.Data...)
+	api.AssertIsEqual(h.Sum(), c.Hash)
+
+	// Reset must bring the hasher to its initial state.
+	h.Reset()
+	h.Write(c.Data...)
+	api.AssertIsEqual(h.Sum(), c.Hash)
+	return nil
+}
+
+type compressCircuit struct {
+	Left, Right frontend.Variable
+	Result      frontend.Variable `gnark:",public"`
+}
+
+func (c *compressCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Compress(api, c.Left, c.Right), c.Result)
+	return nil
+}
+
+func TestHasher(t *testing.T) {
+	for _, n := range []int{1, 2, 5} {
+		var (
+			data    = make([]byte, 0, n*poseidon2.ElementSize)
+			witness = &hashCircuit{Data: make([]frontend.Variable, n)}
+			e       fr.Element
+		)
+		for i := 0; i < n; i++ {
+			e.SetRandom()
+			b := e.Bytes()
+			data = append(data, b[:]...)
+			witness.Data[i] = e.BigInt(new(big.Int))
+		}
+		h, err := poseidon2.Hash(data)
+		require.NoError(t, err)
+		witness.Hash = new(big.Int).SetBytes(h)
+
+		circuit := &hashCircuit{Data: make([]frontend.Variable, n)}
+		require.NoError(t, test.IsSolved(circuit, witness, ecc.BLS12_381.ScalarField()))
+
+		witness.Hash = new(big.Int).Add(witness.Hash.(*big.Int), big.NewInt(1))
+		require.Error(t, test.IsSolved(circuit, witness, ecc.BLS12_381.ScalarField()))
+	}
+}
+
+func TestCompress(t *testing.T) {
+	var l, r fr.Element
+	l.SetRandom()
+	r.SetRandom()
+	res := poseidon2.Compress(&l, &r)
+
+	witness := &compressCircuit{
+		Left:   l.BigInt(new(big.Int)),
+		Right:  r.BigInt(new(big.Int)),
+		Result: res.BigInt(new(big.Int)),
+	}
+	require.NoError(t, test.IsSolved(&compressCircuit{}, witness, ecc.BLS12_381.ScalarField()))
+	require.ErrorContains(t, test.IsSolved(&compressCircuit{}, witness, ecc.BN254.ScalarField()), "BLS12-381 scalar field")
+}
+
+func TestHasherKnownAnswer(t *testing.T) {
+	// See poseidon2 package tests for the known answer source.
+	h, _ := new(big.Int).SetString("10b13d671a35ac6c0bca4df6f5857ee8754b27b5801cf5e38f90787505af4835", 16)
+	witness := &hashCircuit{Data: []frontend.Variable{1, 2, 3}, Hash: h}
+	circuit := &hashCircuit{Data: make([]frontend.Variable, 3)}
+	require.NoError(t, test.IsSolved(circuit, witness, ecc.BLS12_381.ScalarField()))
+}
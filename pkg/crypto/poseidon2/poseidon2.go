@@ -0,0 +1,153 @@
+/*
+Package poseidon2 implements Poseidon2 hash function over BLS12-381 scalar
+field compatible with gnark-crypto and gnark.
+
+Parameters are the default BLS12-381 ones of gnark-crypto (see
+ecc/bls12-381/fr/poseidon2.GetDefaultParameters in gnark-crypto v0.18.0): the
+permutation has width 2 with 6 full and 50 partial rounds and x^5 S-box, round
+constants are derived from "Poseidon2-BLS12_381[t=2,rF=6,rP=50,d=5]" seed by
+iterating Keccak256. The hash is the Merkle–Damgård construction of
+gnark-crypto (poseidon2.NewMerkleDamgardHasher) over 32-byte big-endian
+canonical field elements with zero initial state and the compression function
+f(l, r) = P(l, r)[1] + r where P is the permutation. Unlike gnark-crypto
+hasher, the data must be a concatenation of complete field elements, there is
+no padding for the trailing partial element.
+
+The same hash is computed in gnark (v0.13.0+) circuits by
+std/permutation/poseidon2.NewPoseidon2FromParameters(api, 2, 6, 50) used with
+std/hash.NewMerkleDamgardHasher(api, p, 0). See circuit subpackage for the
+gadget compatible with gnark versions that lack it.
+*/
+package poseidon2
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Width is the number of field elements the permutation operates on.
+	Width = 2
+	// FullRounds is the number of full rounds of the permutation.
+	FullRounds = 6
+	// PartialRounds is the number of partial rounds of the permutation.
+	PartialRounds = 50
+	// SBoxDegree is the degree of the S-box.
+	SBoxDegree = 5
+	// ElementSize is the size of the serialized field element.
+	ElementSize = fr.Bytes
+)
+
+var (
+	roundKeysOnce sync.Once
+	roundKeys     [][]fr.Element
+)
+
+// RoundKeys returns round constants of the permutation, full rounds have
+// Width constants and partial rounds have one. The result must not be
+// modified.
+func RoundKeys() [][]fr.Element {
+	roundKeysOnce.Do(func() {
+		seed := fmt.Sprintf("Poseidon2-BLS12_381[t=%d,rF=%d,rP=%d,d=%d]", Width, FullRounds, PartialRounds, SBoxDegree)
+		h := sha3.NewLegacyKeccak256()
+		_, _ = h.Write([]byte(seed))
+		rnd := h.Sum(nil)
+		next := func(e *fr.Element) {
+			h.Reset()
+			_, _ = h.Write(rnd)
+			rnd = h.Sum(nil)
+			e.SetBytes(rnd)
+		}
+		roundKeys = make([][]fr.Element, FullRounds+PartialRounds)
+		for i := range roundKeys {
+			n := Width
+			if i >= FullRounds/2 && i < FullRounds/2+PartialRounds {
+				n = 1
+			}
+			roundKeys[i] = make([]fr.Element, n)
+			for j := range roundKeys[i] {
+				next(&roundKeys[i][j])
+			}
+		}
+	})
+	return roundKeys
+}
+
+// Permute applies Poseidon2 permutation to the given state.
+func Permute(x *[Width]fr.Element) {
+	var rk = RoundKeys()
+
+	matMulExternal(x)
+	for i := 0; i < FullRounds/2; i++ {
+		fullRound(x, rk[i])
+	}
+	for i := FullRounds / 2; i < FullRounds/2+PartialRounds; i++ {
+		x[0].Add(&x[0], &rk[i][0])
+		sBox(&x[0])
+		matMulInternal(x)
+	}
+	for i := FullRounds/2 + PartialRounds; i < FullRounds+PartialRounds; i++ {
+		fullRound(x, rk[i])
+	}
+}
+
+func fullRound(x *[Width]fr.Element, rk []fr.Element) {
+	for j := range x {
+		x[j].Add(&x[j], &rk[j])
+		sBox(&x[j])
+	}
+	matMulExternal(x)
+}
+
+func sBox(x *fr.Element) {
+	var x2 fr.Element
+	x2.Square(x)
+	x2.Square(&x2)
+	x.Mul(x, &x2)
+}
+
+// matMulExternal multiplies the state by circ(2, 1) matrix.
+func matMulExternal(x *[Width]fr.Element) {
+	var sum fr.Element
+	sum.Add(&x[0], &x[1])
+	x[0].Add(&x[0], &sum)
+	x[1].Add(&x[1], &sum)
+}
+
+// matMulInternal multiplies the state by [[2, 1], [1, 3]] matrix.
+func matMulInternal(x *[Width]fr.Element) {
+	var sum fr.Element
+	sum.Add(&x[0], &x[1])
+	x[0].Add(&x[0], &sum)
+	x[1].Double(&x[1])
+	x[1].Add(&x[1], &sum)
+}
+
+// Compress is the compression function of the hash, it returns the second
+// element of the permuted (left, right) state plus right.
+func Compress(left, right *fr.Element) fr.Element {
+	var x = [Width]fr.Element{*left, *right}
+	Permute(&x)
+	x[1].Add(&x[1], right)
+	return x[1]
+}
+
+// Hash returns Poseidon2 hash of data that is a concatenation of 32-byte
+// big-endian canonical field elements.
+func Hash(data []byte) ([]byte, error) {
+	if len(data)%ElementSize != 0 {
+		return nil, fmt.Errorf("invalid data length: must be a multiple of %d", ElementSize)
+	}
+	var state, e fr.Element
+	for ; len(data) != 0; data = data[ElementSize:] {
+		if err := e.SetBytesCanonical(data[:ElementSize]); err != nil {
+			return nil, fmt.Errorf("invalid field element: %w", err)
+		}
+		state = Compress(&state, &e)
+	}
+	res := state.Bytes()
+	return res[:], nil
+}
@@ -0,0 +1,102 @@
+package poseidon2
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundKeys(t *testing.T) {
+	rk := RoundKeys()
+	require.Equal(t, FullRounds+PartialRounds, len(rk))
+	for i := range rk {
+		if i < FullRounds/2 || i >= FullRounds/2+PartialRounds {
+			require.Equal(t, Width, len(rk[i]))
+		} else {
+			require.Equal(t, 1, len(rk[i]))
+		}
+	}
+	require.NotEqual(t, rk[0][0], rk[0][1])
+}
+
+func TestHash(t *testing.T) {
+	var l, r fr.Element
+	l.SetUint64(1)
+	r.SetUint64(2)
+	lb, rb := l.Bytes(), r.Bytes()
+
+	t.Run("empty", func(t *testing.T) {
+		h, err := Hash(nil)
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, ElementSize), h)
+	})
+	t.Run("compress chain", func(t *testing.T) {
+		h, err := Hash(append(lb[:], rb[:]...))
+		require.NoError(t, err)
+
+		var zero fr.Element
+		s := Compress(&zero, &l)
+		s = Compress(&s, &r)
+		expected := s.Bytes()
+		require.Equal(t, expected[:], h)
+
+		h2, err := Hash(append(rb[:], lb[:]...))
+		require.NoError(t, err)
+		require.NotEqual(t, h, h2)
+	})
+	t.Run("bad length", func(t *testing.T) {
+		_, err := Hash(make([]byte, ElementSize+1))
+		require.Error(t, err)
+	})
+	t.Run("non-canonical", func(t *testing.T) {
+		m := fr.Modulus().FillBytes(make([]byte, ElementSize))
+		_, err := Hash(m)
+		require.Error(t, err)
+	})
+}
+
+// elements returns serialized field elements, negative values are taken
+// modulo field order.
+func elements(vals ...int64) []byte {
+	var res []byte
+	for _, v := range vals {
+		var e fr.Element
+		e.SetInt64(v)
+		b := e.Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+// Known answers are produced by gnark-crypto v0.18.0 ecc/bls12-381/fr/poseidon2
+// package (NewPermutation(2, 6, 50) and NewMerkleDamgardHasher), hashes are
+// also reproduced by gnark v0.13.0 std/hash Merkle–Damgård hasher over
+// std/permutation/poseidon2.NewPoseidon2FromParameters(api, 2, 6, 50).
+func TestPermuteKnownAnswer(t *testing.T) {
+	var x [Width]fr.Element
+	x[1].SetOne()
+	Permute(&x)
+	b0, b1 := x[0].Bytes(), x[1].Bytes()
+	require.Equal(t, "1cdd6c164add05a319507930b1156434264008ba8b5f523f1e411a63038c7ef3", hex.EncodeToString(b0[:]))
+	require.Equal(t, "5dba32f849dc767eeb4429b440b32c775d970a269ba70e821ff6e17b9254c9e3", hex.EncodeToString(b1[:]))
+}
+
+func TestHashKnownAnswers(t *testing.T) {
+	for _, tc := range []struct {
+		data     []byte
+		expected string
+	}{
+		{elements(), "0000000000000000000000000000000000000000000000000000000000000000"},
+		{elements(0), "0440d4ee8370b0cd0098bbc48e4b57cce27b1dbdc977f6c1b45c00064e29e374"},
+		{elements(1), "5dba32f849dc767eeb4429b440b32c775d970a269ba70e821ff6e17b9254c9e4"},
+		{elements(1, 2), "0ee043427202d0231469ed27ea4e3c7f0a31530c6e90c8fe971a1a25da9db4d9"},
+		{elements(1, 2, 3), "10b13d671a35ac6c0bca4df6f5857ee8754b27b5801cf5e38f90787505af4835"},
+		{elements(0, -1, -2, -3), "29642c4a5fdceb9dfe63434021b8cc56f64c09b53fce120f93f32bb895293c37"},
+	} {
+		h, err := Hash(tc.data)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, hex.EncodeToString(h))
+	}
+}
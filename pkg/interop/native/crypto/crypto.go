@@ -100,3 +100,84 @@ func Bls12381Pairing(g1, g2 Bls12381Point) Bls12381Point {
 func Keccak256(b []byte) interop.Hash256 {
 	return neogointernal.CallWithToken(Hash, "keccak256", int(contract.NoneFlag), b).(interop.Hash256)
 }
+
+// RecoverSecp256K1 calls `recoverSecp256K1` method of native CryptoLib contract
+// and recovers compressed secp256k1 public key from the given 32-byte message
+// hash and 65-byte (r || s || v) or 64-byte EIP-2098 compact signature. It
+// returns nil if the key can't be recovered.
+func RecoverSecp256K1(msgHash []byte, sig []byte) interop.PublicKey {
+	return neogointernal.CallWithToken(Hash, "recoverSecp256K1", int(contract.NoneFlag), msgHash, sig).(interop.PublicKey)
+}
+
+// VerifyWithEd25519 calls `verifyWithEd25519` method of native CryptoLib
+// contract and checks that sig is a correct Ed25519 signature of msg for the
+// given 32-byte public key.
+func VerifyWithEd25519(msg []byte, pub []byte, sig []byte) bool {
+	return neogointernal.CallWithToken(Hash, "verifyWithEd25519", int(contract.NoneFlag), msg, pub, sig).(bool)
+}
+
+// Bn254Point represents BN254 (alt_bn128) curve point (G1 or G2 in the Affine
+// or Jacobian form or GT). It's an opaque type similar to Bls12381Point that
+// can only be created properly by Bn254Deserialize, Bn254Add, Bn254Mul or
+// Bn254Pairing and can be exposed to the outside world via Bn254Serialize.
+type Bn254Point struct{}
+
+// Bn254Serialize calls `bn254Serialize` method of native CryptoLib contract
+// and serializes given BN254 point into byte array (compressed form).
+func Bn254Serialize(g Bn254Point) []byte {
+	return neogointernal.CallWithToken(Hash, "bn254Serialize", int(contract.NoneFlag), g).([]byte)
+}
+
+// Bn254Deserialize calls `bn254Deserialize` method of native CryptoLib
+// contract and deserializes given BN254 point from byte array. 32-byte
+// compressed G1, 64-byte compressed G2 and 384-byte GT points are accepted.
+func Bn254Deserialize(data []byte) Bn254Point {
+	return neogointernal.CallWithToken(Hash, "bn254Deserialize", int(contract.NoneFlag), data).(Bn254Point)
+}
+
+// Bn254Equal calls `bn254Equal` method of native CryptoLib contract and
+// checks whether two BN254 points are equal.
+func Bn254Equal(x, y Bn254Point) bool {
+	return neogointernal.CallWithToken(Hash, "bn254Equal", int(contract.NoneFlag), x, y).(bool)
+}
+
+// Bn254Add calls `bn254Add` method of native CryptoLib contract and
+// performs addition operation over two BN254 points.
+func Bn254Add(x, y Bn254Point) Bn254Point {
+	return neogointernal.CallWithToken(Hash, "bn254Add", int(contract.NoneFlag), x, y).(Bn254Point)
+}
+
+// Bn254Mul calls `bn254Mul` method of native CryptoLib contract and performs
+// multiplication operation over BN254 point and the given scalar multiplicator.
+// The multiplicator is the serialized 32-bytes LE representation of the BN254
+// scalar field element. The last argument denotes whether the multiplicator
+// should be negative.
+func Bn254Mul(x Bn254Point, mul []byte, neg bool) Bn254Point {
+	return neogointernal.CallWithToken(Hash, "bn254Mul", int(contract.NoneFlag), x, mul, neg).(Bn254Point)
+}
+
+// Bn254Pairing calls `bn254Pairing` method of native CryptoLib contract and
+// performs pairing operation over two BN254 points which must be G1 and G2 either
+// in Affine or Jacobian forms. The result of this operation is GT point.
+func Bn254Pairing(g1, g2 Bn254Point) Bn254Point {
+	return neogointernal.CallWithToken(Hash, "bn254Pairing", int(contract.NoneFlag), g1, g2).(Bn254Point)
+}
+
+// MimcBls12381 calls `mimcBls12381` method of native CryptoLib contract and
+// computes MiMC hash of the given data over BLS12-381 scalar field the same way
+// gnark does it (both in circuits and natively). The data must be a
+// concatenation of 32-byte big-endian canonical field elements, the result is
+// a 32-byte big-endian field element.
+func MimcBls12381(data []byte) []byte {
+	return neogointernal.CallWithToken(Hash, "mimcBls12381", int(contract.NoneFlag), data).([]byte)
+}
+
+// Poseidon2Bls12381 calls `poseidon2Bls12381` method of native CryptoLib
+// contract and computes Poseidon2 hash of the given data over BLS12-381 scalar
+// field the same way gnark-crypto and gnark do it (with default BLS12-381
+// parameters and Merkle–Damgård construction). The data must be a
+// concatenation of 32-byte big-endian canonical field elements, the result is
+// a 32-byte big-endian field element.
+func Poseidon2Bls12381(data []byte) []byte {
+	return neogointernal.CallWithToken(Hash, "poseidon2Bls12381", int(contract.NoneFlag), data).([]byte)
+}
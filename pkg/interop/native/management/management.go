@@ -84,3 +84,24 @@ func UpdateWithData(script, manifest []byte, data any) {
 	neogointernal.CallWithTokenNoRet(Hash, "update",
 		int(contract.All), script, manifest, data)
 }
+
+// IsDeployer represents `isDeployer` method of Management native contract.
+// It's only available if permissioned deployment mode is enabled in the
+// network configuration.
+func IsDeployer(account interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "isDeployer", int(contract.ReadStates), account).(bool)
+}
+
+// AddDeployer represents `addDeployer` method of Management native contract.
+// It's only available if permissioned deployment mode is enabled in the
+// network configuration.
+func AddDeployer(account interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "addDeployer", int(contract.States|contract.AllowNotify), account).(bool)
+}
+
+// RemoveDeployer represents `removeDeployer` method of Management native
+// contract. It's only available if permissioned deployment mode is enabled
+// in the network configuration.
+func RemoveDeployer(account interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "removeDeployer", int(contract.States|contract.AllowNotify), account).(bool)
+}
@@ -23,6 +23,9 @@ const (
 	Oracle         Role = 8
 	NeoFSAlphabet  Role = 16
 	P2PNotary      Role = 32
+	// ContractDeployer is only valid if permissioned deployment mode is
+	// enabled in the network configuration.
+	ContractDeployer Role = 64
 )
 
 // GetDesignatedByRole represents `getDesignatedByRole` method of RoleManagement native contract.
@@ -0,0 +1,86 @@
+package result
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+)
+
+// ConsensusState is a result of the `getconsensusstate` RPC call. It describes
+// the dBFT round the node is currently following.
+type ConsensusState struct {
+	WatchOnly  bool            `json:"watchonly"`
+	Height     uint32          `json:"height"`
+	View       byte            `json:"view"`
+	Primary    uint32          `json:"primary"`
+	PrimaryKey *keys.PublicKey `json:"primarykey,omitempty"`
+	Validators keys.PublicKeys `json:"validators"`
+	// RoundStarted and ViewStarted are millisecond-precision timestamps.
+	RoundStarted      uint64            `json:"roundstarted"`
+	ViewStarted       uint64            `json:"viewstarted"`
+	ViewChanges       uint64            `json:"viewchanges"`
+	ChangeViewReasons map[string]uint64 `json:"changeviewreasons"`
+	PrimaryTimeouts   uint64            `json:"primarytimeouts"`
+	RecoveryRequests  uint64            `json:"recoveryrequests"`
+	RecoveryMessages  uint64            `json:"recoverymessages"`
+}
+
+// ConsensusStats is a result of the `getconsensusstats` RPC call. It contains
+// per-validator consensus statistics aggregated over the range of blocks.
+type ConsensusStats struct {
+	Start       uint32                    `json:"start"`
+	End         uint32                    `json:"end"`
+	Rounds      uint32                    `json:"rounds"`
+	ViewChanges uint32                    `json:"viewchanges"`
+	Validators  []ConsensusValidatorStats `json:"validators"`
+	// Records contains round records, it's only filled for verbose requests.
+	Records []ConsensusRound `json:"records,omitempty"`
+}
+
+// ConsensusValidatorStats contains consensus statistics of a single validator.
+type ConsensusValidatorStats struct {
+	PublicKey *keys.PublicKey `json:"publickey"`
+	// Rounds is the number of rounds the validator took part in.
+	Rounds uint32 `json:"rounds"`
+	// Primary is the number of views the validator was primary at.
+	Primary uint32 `json:"primary"`
+	// PrimaryFailures is the number of views the validator was primary at
+	// that ended with a view change.
+	PrimaryFailures uint32 `json:"primaryfailures"`
+	Prepared        uint32 `json:"prepared"`
+	Committed       uint32 `json:"committed"`
+	// ChangeViews is the number of ChangeView messages sent by the validator.
+	ChangeViews uint32 `json:"changeviews"`
+	// AverageCommitTime is the average time (in milliseconds, relative to the
+	// round start) of the validator's commits.
+	AverageCommitTime uint32 `json:"averagecommittime"`
+}
+
+// ConsensusRound describes a single finished consensus round. Times are in
+// milliseconds relative to the round start unless specified otherwise.
+type ConsensusRound struct {
+	Index   uint32 `json:"index"`
+	View    byte   `json:"view"`
+	Primary byte   `json:"primary"`
+	// Started is a millisecond-precision timestamp.
+	Started        uint64                    `json:"started"`
+	Duration       uint32                    `json:"duration"`
+	PrepareRequest *uint32                   `json:"preparerequest,omitempty"`
+	Prepared       *uint32                   `json:"prepared,omitempty"`
+	Committed      *uint32                   `json:"committed,omitempty"`
+	Validators     []ConsensusRoundValidator `json:"validators"`
+	ChangeViews    []ConsensusChangeView     `json:"changeviews,omitempty"`
+}
+
+// ConsensusRoundValidator describes validator activity in a consensus round.
+type ConsensusRoundValidator struct {
+	PublicKey   *keys.PublicKey `json:"publickey"`
+	PreparedAt  *uint32         `json:"preparedat,omitempty"`
+	CommittedAt *uint32         `json:"committedat,omitempty"`
+}
+
+// ConsensusChangeView describes ChangeView message seen in a consensus round.
+type ConsensusChangeView struct {
+	Validator byte   `json:"validator"`
+	NewView   byte   `json:"newview"`
+	Reason    string `json:"reason"`
+	At        uint32 `json:"at"`
+}
@@ -0,0 +1,31 @@
+package result
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// DBStats is a result of the `getdbstats` RPC call. It contains the number of
+// keys and their sizes in the node database.
+type DBStats struct {
+	Prefixes  []DBPrefixStats   `json:"prefixes"`
+	Contracts []DBContractStats `json:"contracts"`
+}
+
+// DBPrefixStats contains statistics of DB entries with the same key prefix.
+type DBPrefixStats struct {
+	Prefix     byte   `json:"prefix"`
+	Name       string `json:"name"`
+	Keys       uint64 `json:"keys"`
+	KeyBytes   uint64 `json:"keybytes"`
+	ValueBytes uint64 `json:"valuebytes"`
+}
+
+// DBContractStats contains statistics of contract storage items. Hash is
+// omitted for contracts that are destroyed or can't be resolved otherwise.
+type DBContractStats struct {
+	ID         int32         `json:"id"`
+	Hash       *util.Uint160 `json:"hash,omitempty"`
+	Keys       uint64        `json:"keys"`
+	KeyBytes   uint64        `json:"keybytes"`
+	ValueBytes uint64        `json:"valuebytes"`
+}
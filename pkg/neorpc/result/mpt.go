@@ -106,3 +106,26 @@ func (p *VerifyProof) UnmarshalJSON(data []byte) error {
 	p.Value = b
 	return nil
 }
+
+// MultiProof is a result of getmultiproof RPC. Keys are MPT keys (contract ID
+// followed by storage item key) and Proof contains every MPT node (only once)
+// needed to check presence or absence of all of these keys with
+// mpt.VerifyMultiProof.
+type MultiProof struct {
+	Keys  [][]byte `json:"keys"`
+	Proof [][]byte `json:"proof"`
+}
+
+// RangeProof is a result of getrangeproof RPC. Results contain the first
+// storage items with the given prefix following the start key (if any) with
+// keys being MPT keys (contract ID followed by storage item key). Prefix and
+// Start are MPT-level parameters of the range, so Results and Proof can be
+// checked with mpt.VerifyRangeProof using them. Truncated is set when the
+// number of items has reached the limit, so there might be more of them.
+type RangeProof struct {
+	Prefix    []byte     `json:"prefix"`
+	Start     []byte     `json:"start"`
+	Results   []KeyValue `json:"results"`
+	Proof     [][]byte   `json:"proof"`
+	Truncated bool       `json:"truncated"`
+}
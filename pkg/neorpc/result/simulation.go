@@ -0,0 +1,27 @@
+package result
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// Simulation is a result of the simulatetransactions RPC call.
+type Simulation struct {
+	// BlockIndex and Timestamp are the simulated block parameters.
+	BlockIndex uint32 `json:"blockindex"`
+	Timestamp  uint64 `json:"timestamp"`
+	// Executions contain OnPersist (for full block simulations), transactions
+	// and PostPersist (for full block simulations) execution results in order.
+	Executions []state.AppExecResult `json:"executions"`
+	// Changes is a set of contract storage changes made by the simulated
+	// block, state overrides are not included. It's sorted by contract ID and
+	// key.
+	Changes []ContractStorageChange `json:"storagechanges"`
+}
+
+// ContractStorageChange is a single storage item change of the specified
+// contract.
+type ContractStorageChange struct {
+	Contract util.Uint160 `json:"contract"`
+	StorageChange
+}
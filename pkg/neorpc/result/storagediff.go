@@ -0,0 +1,23 @@
+package result
+
+import "github.com/epicchainlabs/epicchain-go/pkg/util"
+
+// StorageDiff is a result of the getstoragediff RPC call, it contains a set
+// of contract storage changes between two states.
+type StorageDiff struct {
+	FromRoot util.Uint256    `json:"fromRoot"`
+	ToRoot   util.Uint256    `json:"toRoot"`
+	Changes  []StorageChange `json:"changes"`
+	// Truncated is set if there are more changes than returned, the next
+	// ones can be retrieved using the last returned key as a start key.
+	Truncated bool `json:"truncated"`
+}
+
+// StorageChange is a single contract storage item change.
+type StorageChange struct {
+	// State can be Added, Changed or Deleted.
+	State    string `json:"state"`
+	Key      []byte `json:"key"`
+	OldValue []byte `json:"oldValue,omitempty"`
+	NewValue []byte `json:"newValue,omitempty"`
+}
@@ -0,0 +1,148 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/vmstate"
+)
+
+// TransactionTrace is a result of the tracetransaction RPC call, it contains
+// a call-level trace of the transaction re-executed on its historic state.
+type TransactionTrace struct {
+	TxHash         util.Uint256  `json:"txid"`
+	BlockIndex     uint32        `json:"blockindex"`
+	VMState        vmstate.State `json:"vmstate"`
+	GasConsumed    int64         `json:"gasconsumed,string"`
+	FaultException string        `json:"exception,omitempty"`
+	// Call is the trace of the transaction script, it contains all contract
+	// calls made by it.
+	Call *CallTrace `json:"call"`
+}
+
+// Possible CallTrace states.
+const (
+	// CallCompleted means that the call has returned normally.
+	CallCompleted = "Completed"
+	// CallException means that the call was aborted by an exception (it
+	// could be caught by the caller).
+	CallException = "Exception"
+	// CallFault means that the VM has faulted during the call.
+	CallFault = "Fault"
+)
+
+// CallTrace is a trace of a single script execution context, it's either
+// the transaction script or a contract call.
+type CallTrace struct {
+	Hash util.Uint160
+	// Method is the called contract method, it's empty for the transaction
+	// script.
+	Method    string
+	Arguments []stackitem.Item
+	CallFlags callflag.CallFlag
+	// GasConsumed is the amount of GAS consumed by the call including the
+	// nested ones.
+	GasConsumed int64
+	// State is one of CallCompleted, CallException and CallFault.
+	State string
+	// IP is the instruction offset the exception was thrown (or the VM
+	// faulted) at, it's only meaningful for failed calls.
+	IP       int
+	Syscalls []string
+	Storage  []StorageAccess
+	Calls    []*CallTrace
+}
+
+// Possible StorageAccess operations.
+const (
+	StorageGet    = "Get"
+	StoragePut    = "Put"
+	StorageDelete = "Delete"
+)
+
+// StorageAccess is a single contract storage access made by the call.
+type StorageAccess struct {
+	// Op is one of StorageGet, StoragePut and StorageDelete.
+	Op    string `json:"op"`
+	ID    int32  `json:"id"`
+	Key   []byte `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+type callTraceAux struct {
+	Hash        util.Uint160      `json:"hash"`
+	Method      string            `json:"method,omitempty"`
+	Arguments   []json.RawMessage `json:"arguments,omitempty"`
+	CallFlags   callflag.CallFlag `json:"callflags"`
+	GasConsumed int64             `json:"gasconsumed,string"`
+	State       string            `json:"state"`
+	IP          *int              `json:"ip,omitempty"`
+	Syscalls    []string          `json:"syscalls,omitempty"`
+	Storage     []StorageAccess   `json:"storage,omitempty"`
+	Calls       []*CallTrace      `json:"calls,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c CallTrace) MarshalJSON() ([]byte, error) {
+	aux := &callTraceAux{
+		Hash:        c.Hash,
+		Method:      c.Method,
+		CallFlags:   c.CallFlags,
+		GasConsumed: c.GasConsumed,
+		State:       c.State,
+		Syscalls:    c.Syscalls,
+		Storage:     c.Storage,
+		Calls:       c.Calls,
+	}
+	if c.State != CallCompleted {
+		aux.IP = &c.IP
+	}
+	if len(c.Arguments) != 0 {
+		aux.Arguments = make([]json.RawMessage, len(c.Arguments))
+		for i := range c.Arguments {
+			data, err := stackitem.ToJSONWithTypes(c.Arguments[i])
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i, err)
+			}
+			aux.Arguments[i] = data
+		}
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *CallTrace) UnmarshalJSON(data []byte) error {
+	aux := new(callTraceAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	var args []stackitem.Item
+	if len(aux.Arguments) != 0 {
+		args = make([]stackitem.Item, len(aux.Arguments))
+		for i := range aux.Arguments {
+			item, err := stackitem.FromJSONWithTypes(aux.Arguments[i])
+			if err != nil {
+				return fmt.Errorf("argument %d: %w", i, err)
+			}
+			args[i] = item
+		}
+	}
+	*c = CallTrace{
+		Hash:        aux.Hash,
+		Method:      aux.Method,
+		Arguments:   args,
+		CallFlags:   aux.CallFlags,
+		GasConsumed: aux.GasConsumed,
+		State:       aux.State,
+		Syscalls:    aux.Syscalls,
+		Storage:     aux.Storage,
+		Calls:       aux.Calls,
+	}
+	if aux.IP != nil {
+		c.IP = *aux.IP
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package neorpc
+
+import (
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+type (
+	// SimulationOptions is a set of optional simulatetransactions call
+	// parameters that change the block transactions are executed in and the
+	// state they're executed with. Overrides are applied in order: contracts,
+	// storage items and then GAS balances.
+	SimulationOptions struct {
+		// FullBlock enables OnPersist and PostPersist scripts execution around
+		// the transactions, so network and system fees are paid by senders
+		// like it happens for real blocks.
+		FullBlock bool `json:"fullblock,omitempty"`
+		// Timestamp is the simulated block timestamp (in milliseconds), the
+		// expected next block timestamp is used if it's zero.
+		Timestamp   uint64             `json:"timestamp,omitempty"`
+		Contracts   []ContractOverride `json:"contracts,omitempty"`
+		Storage     []StorageOverride  `json:"storage,omitempty"`
+		GASBalances []BalanceOverride  `json:"gasbalances,omitempty"`
+	}
+	// ContractOverride replaces the NEF file and/or manifest of the deployed
+	// contract, nil values are left unchanged.
+	ContractOverride struct {
+		Hash util.Uint160 `json:"hash"`
+		// NEF is a serialized NEF file.
+		NEF      []byte             `json:"nef,omitempty"`
+		Manifest *manifest.Manifest `json:"manifest,omitempty"`
+	}
+	// StorageOverride puts the storage item into the contract storage (or
+	// deletes it if the value is nil).
+	StorageOverride struct {
+		Hash  util.Uint160 `json:"hash"`
+		Key   []byte       `json:"key"`
+		Value []byte       `json:"value,omitempty"`
+	}
+	// BalanceOverride sets the account balance.
+	BalanceOverride struct {
+		Account util.Uint160 `json:"account"`
+		Amount  int64        `json:"amount,string"`
+	}
+)
@@ -2,6 +2,7 @@ package chain
 
 import (
 	"encoding/hex"
+	"os"
 	"sort"
 	"testing"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/contractdump"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
 	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
@@ -289,3 +292,19 @@ func NewMultiWithOptionsNoCheck(t testing.TB, options *Options) (*core.Blockchai
 	}
 	return bc, neotest.NewMultiSigner(multiValidatorAcc...), neotest.NewMultiSigner(multiCommitteeAcc...), err
 }
+
+// ImportContractSnapshot loads the contract snapshot (see contractdump.Snapshot
+// and `contract storage export` CLI command) from the given file into the given
+// chain. It must be done before any block is added to the chain. The contract
+// keeps its hash, ID and storage, so it can be used via neotest.Executor like any
+// other deployed contract.
+func ImportContractSnapshot(t testing.TB, bc *core.Blockchain, path string) *state.Contract {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	snap, err := contractdump.ReadSnapshot(f)
+	require.NoError(t, err)
+	require.NoError(t, contractdump.Import(bc, snap))
+	return &snap.Contract
+}
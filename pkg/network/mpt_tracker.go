@@ -0,0 +1,181 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/network/payload"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// mptRequestTimeout is the time given to the peer to reply to the MPT nodes
+// request, requested nodes are retried with other peers after it.
+const mptRequestTimeout = 10 * time.Second
+
+// mptTracker keeps track of MPT nodes requested during state synchronisation.
+// Every peer has at most one outstanding request and every node is requested
+// from a single peer at a time, so requests are spread across all peers.
+// Nodes not received in time are retried with other peers and the peer failing
+// requests gets smaller batches until it replies again.
+type mptTracker struct {
+	lock    sync.Mutex
+	timeout time.Duration
+	// requests maps requested node hashes to the peers they're requested from.
+	requests map[util.Uint256]Peer
+	// failed maps node hashes to the last peer that failed to return them.
+	failed map[util.Uint256]Peer
+	peers  map[Peer]*mptPeerState
+}
+
+// mptPeerState contains MPT requests statistics of a single peer.
+type mptPeerState struct {
+	// requested contains hashes of the outstanding request.
+	requested []util.Uint256
+	// sent is the time the outstanding request was sent at.
+	sent time.Time
+	// failures is the number of consecutive failed requests.
+	failures int
+	// received is the number of MPT nodes received from the peer.
+	received uint64
+}
+
+func newMPTTracker(timeout time.Duration) *mptTracker {
+	return &mptTracker{
+		timeout:  timeout,
+		requests: make(map[util.Uint256]Peer),
+		failed:   make(map[util.Uint256]Peer),
+		peers:    make(map[Peer]*mptPeerState),
+	}
+}
+
+// assign returns a batch of MPT nodes to be requested from the peer and marks
+// them as requested. getBatch is used to get unknown nodes, an empty batch is
+// returned if the peer has an outstanding request.
+func (t *mptTracker) assign(p Peer, now time.Time, getBatch func(limit int) []util.Uint256) []util.Uint256 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.expire(now)
+	st := t.peers[p]
+	if st == nil {
+		st = new(mptPeerState)
+		t.peers[p] = st
+	}
+	if len(st.requested) != 0 {
+		return nil
+	}
+	limit := payload.MaxMPTHashesCount >> st.failures
+	if limit == 0 {
+		limit = 1
+	}
+	var (
+		// Requested and failed nodes can be returned by getBatch, so
+		// request more to get enough of the others.
+		unknown = getBatch(limit + len(t.requests) + len(t.failed))
+		res     = make([]util.Uint256, 0, limit)
+		retry   []util.Uint256
+	)
+	for _, h := range unknown {
+		if len(res) == limit {
+			break
+		}
+		if _, ok := t.requests[h]; ok {
+			continue
+		}
+		if t.failed[h] == p {
+			retry = append(retry, h)
+			continue
+		}
+		res = append(res, h)
+	}
+	if len(res) == 0 {
+		// Nodes failed by this peer are only retried with it if there is
+		// nothing else to request.
+		res = retry
+		if len(res) > limit {
+			res = res[:limit]
+		}
+	}
+	for _, h := range res {
+		t.requests[h] = p
+	}
+	st.requested = res
+	st.sent = now
+	return res
+}
+
+// expire releases nodes of the requests that haven't been replied in time. It
+// must be called with the lock held.
+func (t *mptTracker) expire(now time.Time) {
+	for p, st := range t.peers {
+		if len(st.requested) == 0 || now.Sub(st.sent) < t.timeout {
+			continue
+		}
+		for _, h := range st.requested {
+			if t.requests[h] == p {
+				delete(t.requests, h)
+				t.failed[h] = p
+			}
+		}
+		st.requested = nil
+		st.failures++
+	}
+}
+
+// received marks the specified nodes as received from the peer and completes
+// the outstanding peer's request. Nodes requested, but not received, are
+// released to be requested again, the request is considered failed if none of
+// the requested nodes are received.
+func (t *mptTracker) received(p Peer, hashes map[util.Uint256]struct{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for h := range hashes {
+		delete(t.requests, h)
+		delete(t.failed, h)
+	}
+	st := t.peers[p]
+	if st == nil {
+		return
+	}
+	st.received += uint64(len(hashes))
+	if len(st.requested) == 0 {
+		return
+	}
+	var got bool
+	for _, h := range st.requested {
+		if _, ok := hashes[h]; ok {
+			got = true
+		} else if t.requests[h] == p {
+			delete(t.requests, h)
+		}
+	}
+	if got {
+		st.failures = 0
+	} else {
+		st.failures++
+	}
+	st.requested = nil
+}
+
+// removePeer releases nodes requested from the disconnected peer.
+func (t *mptTracker) removePeer(p Peer) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	st := t.peers[p]
+	if st == nil {
+		return
+	}
+	for _, h := range st.requested {
+		if t.requests[h] == p {
+			delete(t.requests, h)
+		}
+	}
+	for h, fp := range t.failed {
+		if fp == p {
+			delete(t.failed, h)
+		}
+	}
+	delete(t.peers, p)
+}
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/epicchainlabs/epicchain-go/internal/random"
+	"github.com/epicchainlabs/epicchain-go/pkg/network/payload"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPTTracker(t *testing.T) {
+	unknown := make([]util.Uint256, 3*payload.MaxMPTHashesCount)
+	for i := range unknown {
+		unknown[i] = random.Uint256()
+	}
+	getBatch := func(limit int) []util.Uint256 {
+		if limit > len(unknown) {
+			limit = len(unknown)
+		}
+		return unknown[:limit]
+	}
+	toSet := func(hs []util.Uint256) map[util.Uint256]struct{} {
+		res := make(map[util.Uint256]struct{}, len(hs))
+		for _, h := range hs {
+			res[h] = struct{}{}
+		}
+		return res
+	}
+
+	s := newTestServer(t, ServerConfig{})
+	p1, p2, p3 := newLocalPeer(t, s), newLocalPeer(t, s), newLocalPeer(t, s)
+	tr := newMPTTracker(time.Second)
+	now := time.Now()
+
+	// Different peers get different nodes.
+	b1 := tr.assign(p1, now, getBatch)
+	require.Equal(t, unknown[:payload.MaxMPTHashesCount], b1)
+	b2 := tr.assign(p2, now, getBatch)
+	require.Equal(t, unknown[payload.MaxMPTHashesCount:2*payload.MaxMPTHashesCount], b2)
+
+	// Single outstanding request per peer.
+	require.Empty(t, tr.assign(p1, now, getBatch))
+
+	// Partial reply completes the request, missing nodes are released.
+	tr.received(p1, toSet(b1[:1]))
+	unknown = unknown[1:]
+	b1 = tr.assign(p1, now, getBatch)
+	require.Equal(t, payload.MaxMPTHashesCount, len(b1))
+	require.Equal(t, unknown[0], b1[0])
+	require.NotContains(t, b1, b2[0])
+
+	// Timed out nodes go to other peers and the peer gets smaller batches.
+	now = now.Add(2 * time.Second)
+	b3 := tr.assign(p3, now, getBatch)
+	require.Equal(t, unknown[:payload.MaxMPTHashesCount], b3)
+	require.Equal(t, 1, tr.peers[p1].failures)
+	require.Equal(t, 1, tr.peers[p2].failures)
+	b2 = tr.assign(p2, now, getBatch)
+	require.Equal(t, payload.MaxMPTHashesCount/2, len(b2))
+	for _, h := range b2 {
+		require.NotContains(t, b3, h)
+		require.True(t, tr.failed[h] != p2)
+	}
+
+	// Reply resets failures.
+	tr.received(p2, toSet(b2))
+	require.Equal(t, 0, tr.peers[p2].failures)
+	require.Equal(t, uint64(len(b2)), tr.peers[p2].received)
+	for _, h := range b2 {
+		require.NotContains(t, tr.failed, h)
+	}
+
+	// Empty reply is a failure.
+	b2 = tr.assign(p2, now, getBatch)
+	require.NotEmpty(t, b2)
+	tr.received(p2, nil)
+	require.Equal(t, 1, tr.peers[p2].failures)
+	require.Empty(t, tr.requests[b2[0]])
+
+	// Nodes failed by the peer are retried with it if there is nothing else.
+	unknown = unknown[:1]
+	tr.failed[unknown[0]] = p1
+	delete(tr.requests, unknown[0])
+	tr.peers[p1].requested = nil
+	require.Equal(t, unknown, tr.assign(p1, now, getBatch))
+
+	// Disconnection releases nodes.
+	tr.removePeer(p1)
+	_, ok := tr.peers[p1]
+	require.False(t, ok)
+	require.Empty(t, tr.requests[unknown[0]])
+	require.Equal(t, unknown, tr.assign(p2, now, getBatch))
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/epicchainlabs/epicchain-go/pkg/core/statesync"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -68,6 +69,28 @@ var (
 	)
 	p2pCmds = make(map[CommandType]prometheus.Histogram)
 
+	stateSyncMPTFetched = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of MPT nodes fetched during state sync",
+			Name:      "statesync_mpt_fetched",
+			Namespace: "neogo",
+		},
+	)
+	stateSyncMPTPending = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of known missing MPT nodes during state sync",
+			Name:      "statesync_mpt_pending",
+			Namespace: "neogo",
+		},
+	)
+	stateSyncMPTRemaining = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Estimated number of MPT nodes left to fetch during state sync",
+			Name:      "statesync_mpt_remaining",
+			Namespace: "neogo",
+		},
+	)
+
 	// notarypoolUnsortedTx prometheus metric.
 	notarypoolUnsortedTx = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -88,6 +111,9 @@ func init() {
 		poolCount,
 		blockQueueLength,
 		notarypoolUnsortedTx,
+		stateSyncMPTFetched,
+		stateSyncMPTPending,
+		stateSyncMPTRemaining,
 	)
 	for _, cmd := range []CommandType{CMDVersion, CMDVerack, CMDGetAddr,
 		CMDAddr, CMDPing, CMDPong, CMDGetHeaders, CMDHeaders, CMDGetBlocks,
@@ -149,3 +175,10 @@ func addCmdTimeMetric(cmd CommandType, t time.Duration) {
 func updateNotarypoolMetrics(unsortedTxnLen int) {
 	notarypoolUnsortedTx.Set(float64(unsortedTxnLen))
 }
+
+// updateStateSyncMPTMetrics updates MPT state synchronisation progress metrics.
+func updateStateSyncMPTMetrics(p statesync.MPTProgress) {
+	stateSyncMPTFetched.Set(float64(p.Fetched))
+	stateSyncMPTPending.Set(float64(p.Pending))
+	stateSyncMPTRemaining.Set(float64(p.Remaining))
+}
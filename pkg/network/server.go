@@ -22,6 +22,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mempoolevent"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/address"
 	"github.com/epicchainlabs/epicchain-go/pkg/io"
 	"github.com/epicchainlabs/epicchain-go/pkg/network/bqueue"
@@ -138,6 +139,8 @@ type (
 		syncReached atomic.Bool
 
 		stateSync StateSync
+		// mptTracker spreads MPT nodes requests across peers during state sync.
+		mptTracker *mptTracker
 
 		log *zap.Logger
 
@@ -197,6 +200,7 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		peers:          make(map[Peer]bool),
 		mempool:        chain.GetMemPool(),
 		extensiblePool: extpool.New(chain, config.ExtensiblePoolSize),
+		mptTracker:     newMPTTracker(mptRequestTimeout),
 		log:            log,
 		txin:           make(chan *transaction.Transaction, 64),
 		transactions:   make(chan *transaction.Transaction, 64),
@@ -512,6 +516,7 @@ func (s *Server) run() {
 			if s.peers[drop.peer] {
 				delete(s.peers, drop.peer)
 				s.lock.Unlock()
+				s.mptTracker.removePeer(drop.peer)
 				s.log.Warn("peer disconnected",
 					zap.Stringer("addr", drop.peer.RemoteAddr()),
 					zap.Error(drop.reason),
@@ -796,7 +801,7 @@ func (s *Server) requestBlocksOrHeaders(p Peer) error {
 		return err
 	}
 	if requestMPTNodes {
-		return s.requestMPTNodes(p, s.stateSync.GetUnknownMPTNodesBatch(payload.MaxMPTHashesCount))
+		return s.requestUnknownMPTNodes(p)
 	}
 	return nil
 }
@@ -990,7 +995,28 @@ func (s *Server) handleMPTDataCmd(p Peer, data *payload.MPTData) error {
 	if !s.config.P2PStateExchangeExtensions {
 		return errors.New("MPTDataCMD was received, but P2PStateExchangeExtensions are disabled")
 	}
-	return s.stateSync.AddMPTNodes(data.Nodes)
+	err := s.stateSync.AddMPTNodes(data.Nodes)
+	if err != nil {
+		return err
+	}
+	hashes := make(map[util.Uint256]struct{}, len(data.Nodes))
+	for _, n := range data.Nodes {
+		hashes[hash.DoubleSha256(n)] = struct{}{}
+	}
+	s.mptTracker.received(p, hashes)
+	updateStateSyncMPTMetrics(s.stateSync.GetMPTProgress())
+	if s.stateSync.NeedMPTNodes() {
+		// Don't wait for ping/pong to request more from the responsive peer.
+		return s.requestUnknownMPTNodes(p)
+	}
+	return nil
+}
+
+// requestUnknownMPTNodes requests the next batch of unknown MPT nodes from the
+// peer unless it has an outstanding MPT request. Nodes requested from other
+// peers are not included into the batch.
+func (s *Server) requestUnknownMPTNodes(p Peer) error {
+	return s.requestMPTNodes(p, s.mptTracker.assign(p, time.Now(), s.stateSync.GetUnknownMPTNodesBatch))
 }
 
 // requestMPTNodes requests the specified MPT nodes from the peer or broadcasts
@@ -53,6 +53,10 @@ func (f *fakeConsensus) OnTransaction(tx *transaction.Transaction) {
 	f.txs = append(f.txs, tx)
 }
 func (f *fakeConsensus) GetPayload(h util.Uint256) *payload.Extensible { panic("implement me") }
+func (f *fakeConsensus) GetState() consensus.State                     { return consensus.State{} }
+func (f *fakeConsensus) ForEachRoundRecord(_, _ uint32, _ func(*consensus.RoundRecord) bool) error {
+	return consensus.ErrRoundsNotPersisted
+}
 
 func TestNewServer(t *testing.T) {
 	bc := &fakechain.FakeChain{Blockchain: config.Blockchain{
@@ -872,6 +876,49 @@ func TestHandleMPTData(t *testing.T) {
 		})
 		require.NoError(t, s.handleMessage(p, msg))
 	})
+
+	t.Run("request more", func(t *testing.T) {
+		unknown := []util.Uint256{random.Uint256(), random.Uint256()}
+		s := newTestServer(t, ServerConfig{UserAgent: "/test/"})
+		s.config.P2PStateExchangeExtensions = true
+		ss := &fakechain.FakeStateSync{
+			AddMPTNodesFunc: func(nodes [][]byte) error { return nil },
+			GetUnknownMPTNodesBatchFunc: func(limit int) []util.Uint256 {
+				return unknown
+			},
+		}
+		ss.RequestMPTNodes.Store(true)
+		s.stateSync = ss
+		startWithCleanup(t, s)
+
+		var requested []util.Uint256
+		handler := func(t *testing.T, msg *Message) {
+			if msg.Command == CMDGetMPTData {
+				requested = append(requested, msg.Payload.(*payload.MPTInventory).Hashes...)
+			}
+		}
+		p := newLocalPeer(t, s)
+		p.handshaked = 1
+		p.messageHandler = handler
+		msg := NewMessage(CMDMPTData, &payload.MPTData{
+			Nodes: [][]byte{{1, 2, 3}},
+		})
+		require.NoError(t, s.handleMessage(p, msg))
+		require.Equal(t, unknown, requested)
+
+		// Outstanding request, nothing is requested from the other peer.
+		requested = nil
+		p2 := newLocalPeer(t, s)
+		p2.handshaked = 1
+		p2.messageHandler = handler
+		require.NoError(t, s.requestUnknownMPTNodes(p2))
+		require.Nil(t, requested)
+
+		// The reply allows to request the same nodes again.
+		s.mptTracker.received(p, nil)
+		require.NoError(t, s.handleMessage(p, msg))
+		require.Equal(t, unknown, requested)
+	})
 }
 
 func TestRequestMPTNodes(t *testing.T) {
@@ -2,6 +2,7 @@ package network
 
 import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/statesync"
 	"github.com/epicchainlabs/epicchain-go/pkg/network/bqueue"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
 )
@@ -14,6 +15,7 @@ type StateSync interface {
 	IsActive() bool
 	IsInitialized() bool
 	GetUnknownMPTNodesBatch(limit int) []util.Uint256
+	GetMPTProgress() statesync.MPTProgress
 	NeedHeaders() bool
 	NeedMPTNodes() bool
 	Traverse(root util.Uint256, process func(node mpt.Node, nodeBytes []byte) bool) error
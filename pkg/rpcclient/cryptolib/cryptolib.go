@@ -0,0 +1,82 @@
+/*
+Package cryptolib allows to work with the native CryptoLib contract via RPC.
+
+CryptoLib has no state and all of its methods are safe, so only ContractReader
+is provided. It can be used to perform cryptographic operations with the same
+rules the network uses when executing contracts.
+*/
+package cryptolib
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativehashes"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/rpcclient/unwrap"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+)
+
+// Invoker is used by ContractReader to call various methods.
+type Invoker interface {
+	Call(contract util.Uint160, operation string, params ...any) (*result.Invoke, error)
+}
+
+// Hash stores the hash of the native CryptoLib contract.
+var Hash = nativehashes.CryptoLib
+
+// ContractReader provides an interface to call read-only CryptoLib contract's
+// methods.
+type ContractReader struct {
+	invoker Invoker
+}
+
+// NewReader creates an instance of ContractReader that can be used to read
+// data from the contract.
+func NewReader(invoker Invoker) *ContractReader {
+	return &ContractReader{invoker}
+}
+
+// RecoverSecp256K1 recovers secp256k1 public key from the given 32-byte message
+// hash and 65-byte (r || s || v) or 64-byte EIP-2098 compact signature. It
+// returns nil key and no error if the key can't be recovered from the given
+// data.
+func (c *ContractReader) RecoverSecp256K1(msgHash []byte, sig []byte) (*keys.PublicKey, error) {
+	itm, err := unwrap.Item(c.invoker.Call(Hash, "recoverSecp256K1", msgHash, sig))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := itm.(stackitem.Null); ok {
+		return nil, nil
+	}
+	b, err := itm.TryBytes()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := keys.NewPublicKeyFromBytes(b, secp256k1.S256())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	return pub, nil
+}
+
+// VerifyWithEd25519 checks that sig is a correct Ed25519 signature of msg for
+// the given 32-byte public key.
+func (c *ContractReader) VerifyWithEd25519(msg []byte, pub []byte, sig []byte) (bool, error) {
+	return unwrap.Bool(c.invoker.Call(Hash, "verifyWithEd25519", msg, pub, sig))
+}
+
+// MimcBls12381 computes MiMC hash of the given data (a set of 32-byte
+// big-endian BLS12-381 scalar field elements) the same way gnark does it.
+func (c *ContractReader) MimcBls12381(data []byte) ([]byte, error) {
+	return unwrap.Bytes(c.invoker.Call(Hash, "mimcBls12381", data))
+}
+
+// Poseidon2Bls12381 computes Poseidon2 hash of the given data (a set of 32-byte
+// big-endian BLS12-381 scalar field elements) the same way gnark-crypto does
+// it.
+func (c *ContractReader) Poseidon2Bls12381(data []byte) ([]byte, error) {
+	return unwrap.Bytes(c.invoker.Call(Hash, "poseidon2Bls12381", data))
+}
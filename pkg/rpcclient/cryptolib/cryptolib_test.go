@@ -0,0 +1,91 @@
+package cryptolib
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+type testInv struct {
+	err error
+	res *result.Invoke
+}
+
+func (t *testInv) Call(contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
+	return t.res, t.err
+}
+
+func TestReader(t *testing.T) {
+	ti := new(testInv)
+	cl := NewReader(ti)
+
+	ti.err = errors.New("")
+	_, err := cl.RecoverSecp256K1([]byte{1}, []byte{2})
+	require.Error(t, err)
+	_, err = cl.VerifyWithEd25519([]byte{1}, []byte{2}, []byte{3})
+	require.Error(t, err)
+	_, err = cl.MimcBls12381([]byte{1})
+	require.Error(t, err)
+	_, err = cl.Poseidon2Bls12381([]byte{1})
+	require.Error(t, err)
+
+	ti.err = nil
+	ti.res = &result.Invoke{
+		State: "HALT",
+		Stack: []stackitem.Item{
+			stackitem.Make(true),
+		},
+	}
+	ok, err := cl.VerifyWithEd25519([]byte{1}, []byte{2}, []byte{3})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ti.res = &result.Invoke{
+		State: "HALT",
+		Stack: []stackitem.Item{
+			stackitem.Null{},
+		},
+	}
+	pub, err := cl.RecoverSecp256K1([]byte{1}, []byte{2})
+	require.NoError(t, err)
+	require.Nil(t, pub)
+
+	ti.res = &result.Invoke{
+		State: "HALT",
+		Stack: []stackitem.Item{
+			stackitem.Make([]byte{1, 2, 3}),
+		},
+	}
+	_, err = cl.RecoverSecp256K1([]byte{1}, []byte{2})
+	require.Error(t, err)
+
+	pk, err := keys.NewSecp256k1PrivateKey()
+	require.NoError(t, err)
+	ti.res = &result.Invoke{
+		State: "HALT",
+		Stack: []stackitem.Item{
+			stackitem.Make(pk.PublicKey().Bytes()),
+		},
+	}
+	pub, err = cl.RecoverSecp256K1([]byte{1}, []byte{2})
+	require.NoError(t, err)
+	require.Equal(t, pk.PublicKey(), pub)
+
+	ti.res = &result.Invoke{
+		State: "HALT",
+		Stack: []stackitem.Item{
+			stackitem.Make([]byte{1, 2, 3}),
+		},
+	}
+	h, err := cl.MimcBls12381([]byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, h)
+	h, err = cl.Poseidon2Bls12381([]byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, h)
+}
@@ -42,6 +42,10 @@ These layers are:
     and a notary-specific actor implementation that allows to easily wrap any
     transaction into a notary request.
 
+  - Light client implemented in lightclient package that verifies headers,
+    state roots and MPT proofs for everything it gets from the RPC node, so
+    contract states and storage items can be used without trusting the node.
+
   - Non-native contract-specific wrappers, currently partially provided only for
     NNS contract (it's still in development), at the moment that's mostly an
     example of how contract-specific wrappers can be built for other dApps
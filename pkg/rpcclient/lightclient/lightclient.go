@@ -0,0 +1,212 @@
+/*
+Package lightclient provides a client that verifies data received from an
+untrusted RPC node.
+
+Client starts with some trusted block header (a checkpoint obtained out of
+band) and then follows the chain verifying every subsequent header: its
+hash, index, link to the previous header and the witness that must match
+NextConsensus field of the previous header (so that consensus node and
+committee changes are tracked automatically). State roots are checked either
+against verified headers (if StateRootInHeader extension is enabled) or
+using state validators' witnesses. State validators designated via
+RoleManagement contract are tracked starting from the set provided in the
+configuration, every change is verified using the previous set.
+
+Contract states and storage items are only returned after verification of
+MPT proofs against the state root for the specified height, so they can be
+safely used as an input for local invocations or any other purpose without
+trusting the RPC node.
+*/
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+)
+
+// RPC is a set of RPC methods used by Client. It's implemented by
+// rpcclient.Client (and rpcclient.WSClient), the client must be initialized
+// (see rpcclient.Client.Init) before it's used by Client.
+type RPC interface {
+	GetBlockCount() (uint32, error)
+	GetBlockHash(index uint32) (util.Uint256, error)
+	GetBlockHeader(hash util.Uint256) (*block.Header, error)
+	GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+	GetMultiProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKeys [][]byte) (*result.MultiProof, error)
+	GetRangeProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte, start []byte, maxCount *int) (*result.RangeProof, error)
+}
+
+// Config contains network parameters that can't be obtained from the RPC
+// node since they need to be trusted.
+type Config struct {
+	// Magic is the network magic used to check witnesses.
+	Magic netmode.Magic
+	// StateRootInHeader is the StateRootInHeader protocol setting of the
+	// network.
+	StateRootInHeader bool
+	// StateValidators is the set of state validators effective for the
+	// trusted header height. It's mandatory if StateRootInHeader is false.
+	StateValidators keys.PublicKeys
+}
+
+// Client is a light client following the chain and verifying all data
+// received from the RPC node. It's safe for concurrent use.
+type Client struct {
+	rpc RPC
+	cfg Config
+
+	lock sync.Mutex
+	// start is the index of the trusted header.
+	start uint32
+	// headers contains verified headers data starting from the trusted one.
+	headers []headerInfo
+	last    *block.Header
+	// validators contains known state validators sets ordered by index,
+	// the first one is the configured set.
+	validators []designation
+	// roots contains verified state roots.
+	roots map[uint32]*state.MPTRoot
+}
+
+// headerInfo is a verified header data.
+type headerInfo struct {
+	hash          util.Uint256
+	prevStateRoot util.Uint256
+}
+
+// ErrUnknownHeight is returned when the data for some height that is not
+// verified yet (or that precedes the trusted header) is requested.
+var ErrUnknownHeight = errors.New("height is out of verified range")
+
+// New creates a light client using the given RPC node, configuration and
+// trusted header. The header is not verified in any way, so it must be
+// obtained from some trusted source.
+func New(rpc RPC, cfg Config, trusted *block.Header) (*Client, error) {
+	if trusted.StateRootEnabled != cfg.StateRootInHeader {
+		return nil, errors.New("trusted header doesn't match StateRootInHeader setting")
+	}
+	c := &Client{
+		rpc:   rpc,
+		cfg:   cfg,
+		start: trusted.Index,
+		headers: []headerInfo{{
+			hash:          trusted.Hash(),
+			prevStateRoot: trusted.PrevStateRoot,
+		}},
+		last:  trusted,
+		roots: make(map[uint32]*state.MPTRoot),
+	}
+	if !cfg.StateRootInHeader {
+		if len(cfg.StateValidators) == 0 {
+			return nil, errors.New("no state validators")
+		}
+		d, err := newDesignation(trusted.Index, cfg.StateValidators)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state validators: %w", err)
+		}
+		c.validators = []designation{d}
+	}
+	return c, nil
+}
+
+// Height returns the index of the latest verified header.
+func (c *Client) Height() uint32 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.last.Index
+}
+
+// Header returns the latest verified header.
+func (c *Client) Header() *block.Header {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.last
+}
+
+// GetHeaderHash returns the hash of the verified header with the specified
+// index.
+func (c *Client) GetHeaderHash(index uint32) (util.Uint256, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.isVerified(index) {
+		return util.Uint256{}, ErrUnknownHeight
+	}
+	return c.headers[index-c.start].hash, nil
+}
+
+// Sync fetches and verifies all headers up to the current RPC node's height.
+// It returns the index of the latest verified header.
+func (c *Client) Sync() (uint32, error) {
+	count, err := c.rpc.GetBlockCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: %w", err)
+	}
+	if count == 0 {
+		return 0, errors.New("empty chain")
+	}
+	err = c.SyncTo(count - 1)
+	return c.Height(), err
+}
+
+// SyncTo fetches and verifies all headers up to the specified index (if
+// they're not verified yet). Headers verified before an error is encountered
+// are kept.
+func (c *Client) SyncTo(index uint32) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.last.Index < index {
+		i := c.last.Index + 1
+		h, err := c.rpc.GetBlockHash(i)
+		if err != nil {
+			return fmt.Errorf("failed to get header hash %d: %w", i, err)
+		}
+		hdr, err := c.rpc.GetBlockHeader(h)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		err = c.verifyHeader(hdr, h)
+		if err != nil {
+			return fmt.Errorf("invalid header %d: %w", i, err)
+		}
+		c.headers = append(c.headers, headerInfo{
+			hash:          h,
+			prevStateRoot: hdr.PrevStateRoot,
+		})
+		c.last = hdr
+	}
+	return nil
+}
+
+// verifyHeader checks the next header with the given hash against the
+// latest verified one.
+func (c *Client) verifyHeader(hdr *block.Header, h util.Uint256) error {
+	if hdr.Hash() != h {
+		return errors.New("hash mismatch")
+	}
+	if hdr.StateRootEnabled != c.cfg.StateRootInHeader {
+		return errors.New("state root setting mismatch")
+	}
+	if hdr.Index != c.last.Index+1 {
+		return errors.New("index mismatch")
+	}
+	if hdr.PrevHash != c.last.Hash() {
+		return errors.New("previous hash mismatch")
+	}
+	if hdr.Timestamp <= c.last.Timestamp {
+		return errors.New("invalid timestamp")
+	}
+	return verifyWitness(c.cfg.Magic, c.last.NextConsensus, hdr, &hdr.Script)
+}
+
+// isVerified returns true if the header with the specified index is verified.
+func (c *Client) isVerified(index uint32) bool {
+	return index >= c.start && index <= c.last.Index
+}
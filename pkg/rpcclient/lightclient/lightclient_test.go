@@ -0,0 +1,381 @@
+package lightclient_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/compiler"
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/epicchainlabs/epicchain-go/pkg/rpcclient"
+	"github.com/epicchainlabs/epicchain-go/pkg/rpcclient/lightclient"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/emit"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+const storageContractSrc = `package storagectr
+import "github.com/epicchainlabs/epicchain-go/pkg/interop/storage"
+func Put(k, v []byte) {
+	storage.Put(storage.GetContext(), k, v)
+}`
+
+var _ lightclient.RPC = (*rpcclient.Client)(nil)
+
+// stateValidators is a set of state validators keys effective starting from
+// the given height.
+type stateValidators struct {
+	index uint32
+	privs []*keys.PrivateKey
+}
+
+// chainRPC implements lightclient.RPC using the local chain, it signs state
+// roots with state validators keys. Hooks allow to tamper the data returned.
+type chainRPC struct {
+	t          *testing.T
+	bc         *core.Blockchain
+	validators []stateValidators
+	header     func(*block.Header)
+	root       func(*state.MPTRoot)
+	proof      func([][]byte) [][]byte
+}
+
+func (c *chainRPC) GetBlockCount() (uint32, error) {
+	return c.bc.BlockHeight() + 1, nil
+}
+
+func (c *chainRPC) GetBlockHash(index uint32) (util.Uint256, error) {
+	return c.bc.GetHeaderHash(index), nil
+}
+
+func (c *chainRPC) GetBlockHeader(h util.Uint256) (*block.Header, error) {
+	hdr, err := c.bc.GetHeader(h)
+	if err != nil {
+		return nil, err
+	}
+	if c.header != nil {
+		c.header(hdr)
+	}
+	// Decode it the way RPC client does to get a fresh hash.
+	w := io.NewBufBinWriter()
+	hdr.EncodeBinary(w.BinWriter)
+	require.NoError(c.t, w.Err)
+	res := &block.Header{StateRootEnabled: hdr.StateRootEnabled}
+	r := io.NewBinReaderFromBuf(w.Bytes())
+	res.DecodeBinary(r)
+	return res, r.Err
+}
+
+func (c *chainRPC) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	r, err := c.bc.GetStateModule().GetStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	var privs []*keys.PrivateKey
+	for _, v := range c.validators {
+		if v.index <= height {
+			privs = v.privs
+		}
+	}
+	if privs != nil {
+		r.Witness = []transaction.Witness{signMultisig(c.t, uint32(c.bc.GetConfig().Magic), r, privs)}
+	}
+	if c.root != nil {
+		c.root(r)
+	}
+	return r, nil
+}
+
+func (c *chainRPC) GetMultiProof(root util.Uint256, h util.Uint160, keys [][]byte) (*result.MultiProof, error) {
+	cs := c.bc.GetContractState(h)
+	if cs == nil {
+		return nil, errors.New("unknown contract")
+	}
+	res := &result.MultiProof{Keys: make([][]byte, len(keys))}
+	for i := range keys {
+		res.Keys[i] = makeStorageKey(cs.ID, keys[i])
+	}
+	var err error
+	res.Proof, err = c.bc.GetStateModule().GetStateMultiProof(root, res.Keys)
+	if err != nil {
+		return nil, err
+	}
+	if c.proof != nil {
+		res.Proof = c.proof(res.Proof)
+	}
+	return res, nil
+}
+
+func (c *chainRPC) GetRangeProof(root util.Uint256, h util.Uint160, prefix []byte, start []byte, maxCount *int) (*result.RangeProof, error) {
+	cs := c.bc.GetContractState(h)
+	if cs == nil {
+		return nil, errors.New("unknown contract")
+	}
+	res := &result.RangeProof{Prefix: makeStorageKey(cs.ID, prefix)}
+	if start != nil {
+		res.Start = start[len(prefix):]
+	}
+	count := 2 // Small pages to check continuation.
+	if maxCount != nil {
+		count = *maxCount
+	}
+	kvs, proof, err := c.bc.GetStateModule().GetStateRangeProof(root, res.Prefix, res.Start, count)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range kvs {
+		res.Results = append(res.Results, result.KeyValue{Key: kv.Key, Value: kv.Value})
+	}
+	res.Proof = proof
+	res.Truncated = len(kvs) == count
+	if c.proof != nil {
+		res.Proof = c.proof(res.Proof)
+	}
+	return res, nil
+}
+
+func makeStorageKey(id int32, key []byte) []byte {
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(id))
+	copy(skey[4:], key)
+	return skey
+}
+
+func newKeys(t *testing.T, n int) ([]*keys.PrivateKey, []any) {
+	privs := make([]*keys.PrivateKey, n)
+	for i := range privs {
+		var err error
+		privs[i], err = keys.NewPrivateKey()
+		require.NoError(t, err)
+	}
+	sort.Slice(privs, func(i, j int) bool {
+		return privs[i].PublicKey().Cmp(privs[j].PublicKey()) < 0
+	})
+	pubs := make([]any, n)
+	for i := range privs {
+		pubs[i] = privs[i].PublicKey().Bytes()
+	}
+	return privs, pubs
+}
+
+func signMultisig(t *testing.T, magic uint32, hh interface{ Hash() util.Uint256 }, privs []*keys.PrivateKey) transaction.Witness {
+	pubs := make(keys.PublicKeys, len(privs))
+	for i := range privs {
+		pubs[i] = privs[i].PublicKey()
+	}
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(pubs)
+	require.NoError(t, err)
+	w := io.NewBufBinWriter()
+	for i := 0; i < smartcontract.GetDefaultHonestNodeCount(len(privs)); i++ {
+		emit.Bytes(w.BinWriter, privs[i].SignHashable(magic, hh))
+	}
+	require.NoError(t, w.Err)
+	return transaction.Witness{InvocationScript: w.Bytes(), VerificationScript: script}
+}
+
+func TestClient(t *testing.T) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	designation := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validator, committee)
+	rpc := &chainRPC{t: t, bc: bc}
+
+	privsA, pubsA := newKeys(t, 4)
+	designation.Invoke(t, stackitem.Null{}, "designateAsRole", int64(noderoles.StateValidator), pubsA)
+	rpc.validators = append(rpc.validators, stateValidators{index: bc.BlockHeight() + 1, privs: privsA})
+	e.AddNewBlock(t)
+	trusted, err := bc.GetHeader(bc.GetHeaderHash(bc.BlockHeight()))
+	require.NoError(t, err)
+
+	ctr := neotest.CompileSource(t, e.Validator.ScriptHash(), strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage"})
+	e.DeployContract(t, ctr, nil)
+	deployed := bc.BlockHeight()
+	c := e.ValidatorInvoker(ctr.Hash)
+	expected := []result.KeyValue{
+		{Key: []byte("aa"), Value: []byte{1}},
+		{Key: []byte("aa1"), Value: []byte{2}},
+		{Key: []byte("aa2"), Value: []byte{3}},
+		{Key: []byte("aa3"), Value: []byte{4}},
+		{Key: []byte("aa4"), Value: []byte{5}},
+	}
+	for _, kv := range expected {
+		c.Invoke(t, stackitem.Null{}, "put", kv.Key, kv.Value)
+	}
+	c.Invoke(t, stackitem.Null{}, "put", []byte("bb"), []byte{6})
+
+	privsB, pubsB := newKeys(t, 7)
+	designation.Invoke(t, stackitem.Null{}, "designateAsRole", int64(noderoles.StateValidator), pubsB)
+	rpc.validators = append(rpc.validators, stateValidators{index: bc.BlockHeight() + 1, privs: privsB})
+	for i := 0; i < 3; i++ {
+		e.AddNewBlock(t)
+	}
+	height := bc.BlockHeight()
+
+	cfg := lightclient.Config{
+		Magic:           bc.GetConfig().Magic,
+		StateValidators: keys.PublicKeys{privsA[0].PublicKey(), privsA[1].PublicKey(), privsA[2].PublicKey(), privsA[3].PublicKey()},
+	}
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := lightclient.New(rpc, lightclient.Config{Magic: cfg.Magic}, trusted)
+		require.Error(t, err)
+		_, err = lightclient.New(rpc, lightclient.Config{Magic: cfg.Magic, StateRootInHeader: true}, trusted)
+		require.Error(t, err)
+	})
+
+	lc, err := lightclient.New(rpc, cfg, trusted)
+	require.NoError(t, err)
+	require.Equal(t, trusted.Index, lc.Height())
+	_, err = lc.GetStateRoot(height)
+	require.ErrorIs(t, err, lightclient.ErrUnknownHeight)
+
+	actual, err := lc.Sync()
+	require.NoError(t, err)
+	require.Equal(t, height, actual)
+	require.Equal(t, bc.GetHeaderHash(height), lc.Header().Hash())
+	h, err := lc.GetHeaderHash(deployed)
+	require.NoError(t, err)
+	require.Equal(t, bc.GetHeaderHash(deployed), h)
+	_, err = lc.GetHeaderHash(trusted.Index - 1)
+	require.ErrorIs(t, err, lightclient.ErrUnknownHeight)
+
+	// The latest root is signed by the new state validators.
+	r, err := lc.GetStateRoot(height)
+	require.NoError(t, err)
+	localRoot, err := bc.GetStateModule().GetStateRoot(height)
+	require.NoError(t, err)
+	require.Equal(t, localRoot.Root, r.Root)
+
+	cs, err := lc.GetContractState(height, ctr.Hash)
+	require.NoError(t, err)
+	require.Equal(t, bc.GetContractState(ctr.Hash).ID, cs.ID)
+	require.Equal(t, ctr.NEF.Checksum, cs.NEF.Checksum)
+	_, err = lc.GetContractState(deployed-1, ctr.Hash)
+	require.ErrorIs(t, err, lightclient.ErrContractNotFound)
+
+	v, err := lc.GetStorage(height, ctr.Hash, []byte("aa2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, v)
+	v, err = lc.GetStorage(height, ctr.Hash, []byte("missing"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+	v, err = lc.GetStorage(deployed, ctr.Hash, []byte("aa2"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	kvs, err := lc.FindStorage(height, ctr.Hash, []byte("aa"))
+	require.NoError(t, err)
+	require.Equal(t, expected, kvs)
+	kvs, err = lc.FindStorage(height, ctr.Hash, nil)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(kvs))
+
+	t.Run("invalid header", func(t *testing.T) {
+		rpc := *rpc
+		rpc.header = func(h *block.Header) { h.Script.InvocationScript[10] ^= 0xff }
+		lc, err := lightclient.New(&rpc, cfg, trusted)
+		require.NoError(t, err)
+		_, err = lc.Sync()
+		require.Error(t, err)
+		require.Equal(t, trusted.Index, lc.Height())
+
+		rpc.header = func(h *block.Header) { h.Timestamp++ }
+		require.Error(t, lc.SyncTo(height))
+	})
+	t.Run("invalid root", func(t *testing.T) {
+		rpc := *rpc
+		rpc.root = func(r *state.MPTRoot) { r.Root = util.Uint256{1, 2, 3} }
+		lc, err := lightclient.New(&rpc, cfg, trusted)
+		require.NoError(t, err)
+		require.NoError(t, lc.SyncTo(height))
+		_, err = lc.GetStateRoot(height)
+		require.Error(t, err)
+	})
+	t.Run("old validators", func(t *testing.T) {
+		rpc := *rpc
+		rpc.validators = rpc.validators[:1]
+		lc, err := lightclient.New(&rpc, cfg, trusted)
+		require.NoError(t, err)
+		require.NoError(t, lc.SyncTo(height))
+		_, err = lc.GetStateRoot(height)
+		require.Error(t, err)
+		// Roots preceding the change are still fine.
+		_, err = lc.GetStateRoot(deployed)
+		require.NoError(t, err)
+	})
+	t.Run("wrong configured validators", func(t *testing.T) {
+		cfg := cfg
+		cfg.StateValidators = keys.PublicKeys{privsB[0].PublicKey()}
+		lc, err := lightclient.New(rpc, cfg, trusted)
+		require.NoError(t, err)
+		require.NoError(t, lc.SyncTo(height))
+		_, err = lc.GetStateRoot(height)
+		require.Error(t, err)
+	})
+	t.Run("invalid proof", func(t *testing.T) {
+		rpc := *rpc
+		lc, err := lightclient.New(&rpc, cfg, trusted)
+		require.NoError(t, err)
+		require.NoError(t, lc.SyncTo(height))
+		_, err = lc.GetStateRoot(height)
+		require.NoError(t, err)
+
+		rpc.proof = func(p [][]byte) [][]byte { return p[1:] }
+		_, err = lc.GetStorage(height, ctr.Hash, []byte("aa2"))
+		require.Error(t, err)
+		_, err = lc.FindStorage(height, ctr.Hash, []byte("aa"))
+		require.Error(t, err)
+	})
+}
+
+func TestClient_StateRootInHeader(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.StateRootInHeader = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	rpc := &chainRPC{t: t, bc: bc}
+	trusted, err := bc.GetHeader(bc.GetHeaderHash(0))
+	require.NoError(t, err)
+
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage"})
+	e.DeployContract(t, ctr, nil)
+	e.CommitteeInvoker(ctr.Hash).Invoke(t, stackitem.Null{}, "put", []byte{1}, []byte{2})
+
+	_, err = lightclient.New(rpc, lightclient.Config{Magic: bc.GetConfig().Magic}, trusted)
+	require.Error(t, err)
+	lc, err := lightclient.New(rpc, lightclient.Config{Magic: bc.GetConfig().Magic, StateRootInHeader: true}, trusted)
+	require.NoError(t, err)
+	height, err := lc.Sync()
+	require.NoError(t, err)
+	require.Equal(t, bc.BlockHeight(), height)
+
+	// The latest root is not known until the next header is available.
+	_, err = lc.GetStateRoot(height)
+	require.ErrorIs(t, err, lightclient.ErrUnknownHeight)
+	e.AddNewBlock(t)
+	require.NoError(t, lc.SyncTo(height+1))
+	v, err := lc.GetStorage(height, ctr.Hash, []byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, v)
+
+	t.Run("invalid root", func(t *testing.T) {
+		rpc := *rpc
+		rpc.header = func(h *block.Header) { h.PrevStateRoot = util.Uint256{1, 2, 3} }
+		lc, err := lightclient.New(&rpc, lightclient.Config{Magic: bc.GetConfig().Magic, StateRootInHeader: true}, trusted)
+		require.NoError(t, err)
+		require.Error(t, lc.SyncTo(1)) // Header hash covers state root.
+	})
+}
@@ -0,0 +1,323 @@
+package lightclient
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativehashes"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/noderoles"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+)
+
+// Native contract storage layout details used to read contract states and
+// state validators.
+const (
+	managementContractID     = -1
+	managementPrefixContract = 8
+	roleManagementContractID = -8
+)
+
+// ErrContractNotFound is returned when contract is proven to be missing.
+var ErrContractNotFound = errors.New("contract not found")
+
+// designation is a state validators set effective starting from the given
+// height.
+type designation struct {
+	index uint32
+	// hash is the script hash of the state validators multisignature
+	// account.
+	hash util.Uint160
+}
+
+func newDesignation(index uint32, pubs keys.PublicKeys) (designation, error) {
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(pubs.Copy())
+	if err != nil {
+		return designation{}, err
+	}
+	return designation{index: index, hash: hash.Hash160(script)}, nil
+}
+
+// GetStateRoot returns the verified state root for the specified height. The
+// header with the given index (or the next one if StateRootInHeader is
+// enabled) must be verified already.
+func (c *Client) GetStateRoot(height uint32) (*state.MPTRoot, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.getStateRoot(height)
+}
+
+// GetContractState returns the verified state of the contract with the given
+// hash for the specified height. ErrContractNotFound is returned if the
+// contract is proven to be missing.
+func (c *Client) GetContractState(height uint32, h util.Uint160) (*state.Contract, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r, err := c.getStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	return c.getContractState(r.Root, h)
+}
+
+// GetStorage returns the verified value of the contract storage item with the
+// given key for the specified height. nil is returned if the item is proven to
+// be missing.
+func (c *Client) GetStorage(height uint32, h util.Uint160, key []byte) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r, err := c.getStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := c.getContractState(r.Root, h)
+	if err != nil {
+		return nil, err
+	}
+	return c.getState(r.Root, h, cs.ID, key)
+}
+
+// FindStorage returns all verified contract storage items with the given
+// prefix for the specified height in ascending key order. Keys returned
+// include prefix. The proof checked for every batch of items ensures that
+// there are no other items with this prefix.
+func (c *Client) FindStorage(height uint32, h util.Uint160, prefix []byte) ([]result.KeyValue, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r, err := c.getStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := c.getContractState(r.Root, h)
+	if err != nil {
+		return nil, err
+	}
+	return c.findStates(r.Root, h, cs.ID, prefix)
+}
+
+func (c *Client) getStateRoot(height uint32) (*state.MPTRoot, error) {
+	if r, ok := c.roots[height]; ok {
+		return r, nil
+	}
+	if c.cfg.StateRootInHeader {
+		// Block state root is stored in the next header.
+		if height == math.MaxUint32 || !c.isVerified(height+1) {
+			return nil, ErrUnknownHeight
+		}
+		r := &state.MPTRoot{
+			Index: height,
+			Root:  c.headers[height+1-c.start].prevStateRoot,
+		}
+		c.roots[height] = r
+		return r, nil
+	}
+	if !c.isVerified(height) {
+		return nil, ErrUnknownHeight
+	}
+	r, err := c.rpc.GetStateRootByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state root %d: %w", height, err)
+	}
+	if r.Index != height {
+		return nil, fmt.Errorf("state root %d has wrong index %d", height, r.Index)
+	}
+	if len(r.Witness) != 1 {
+		return nil, fmt.Errorf("state root %d is not signed", height)
+	}
+	// State validators designation data is taken from the root being
+	// verified, it's only trusted if it matches the known set used to check
+	// the witness.
+	ds, err := c.getDesignations(r.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state validators for %d: %w", height, err)
+	}
+	var rec *designation
+	for i := range ds {
+		if ds[i].index <= height {
+			rec = &ds[i]
+		}
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("no state validators for %d", height)
+	}
+	known := c.getValidators(height)
+	if rec.index > known.index {
+		// The root containing the change is signed by previous validators.
+		if _, err := c.getStateRoot(rec.index - 1); err != nil {
+			return nil, fmt.Errorf("failed to verify state validators change at %d: %w", rec.index, err)
+		}
+		known = c.getValidators(height)
+	}
+	recIndex := rec.index
+	if recIndex < c.start {
+		recIndex = c.start // Configured validators.
+	}
+	if recIndex != known.index || rec.hash != known.hash {
+		return nil, fmt.Errorf("state validators mismatch for %d", height)
+	}
+	err = verifyWitness(c.cfg.Magic, known.hash, r, &r.Witness[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid state root %d witness: %w", height, err)
+	}
+	for _, d := range ds {
+		if d.index > c.start {
+			c.addValidators(d)
+		}
+	}
+	c.roots[height] = r
+	return r, nil
+}
+
+// getValidators returns the latest known state validators set effective for
+// the specified height.
+func (c *Client) getValidators(height uint32) designation {
+	i := sort.Search(len(c.validators), func(i int) bool {
+		return c.validators[i].index > height
+	})
+	return c.validators[i-1]
+}
+
+// addValidators adds verified state validators set if it's not known yet.
+func (c *Client) addValidators(d designation) {
+	i := sort.Search(len(c.validators), func(i int) bool {
+		return c.validators[i].index >= d.index
+	})
+	if i < len(c.validators) && c.validators[i].index == d.index {
+		return
+	}
+	c.validators = append(c.validators, designation{})
+	copy(c.validators[i+1:], c.validators[i:])
+	c.validators[i] = d
+}
+
+// getDesignations returns all state validators designations stored in the
+// MPT with the given root.
+func (c *Client) getDesignations(root util.Uint256) ([]designation, error) {
+	kvs, err := c.findStates(root, nativehashes.RoleManagement, roleManagementContractID, []byte{byte(noderoles.StateValidator)})
+	if err != nil {
+		return nil, err
+	}
+	ds := make([]designation, 0, len(kvs))
+	for _, kv := range kvs {
+		if len(kv.Key) != 5 {
+			return nil, fmt.Errorf("invalid designation key %x", kv.Key)
+		}
+		item, err := stackitem.Deserialize(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := item.Value().([]stackitem.Item)
+		if !ok {
+			return nil, errors.New("invalid designation value")
+		}
+		pubs := make(keys.PublicKeys, len(arr))
+		for i := range arr {
+			b, err := arr[i].TryBytes()
+			if err != nil {
+				return nil, err
+			}
+			pubs[i], err = keys.NewPublicKeyFromBytes(b, elliptic.P256())
+			if err != nil {
+				return nil, err
+			}
+		}
+		d, err := newDesignation(binary.BigEndian.Uint32(kv.Key[1:]), pubs)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// getContractState returns the contract state from the MPT with the given
+// root.
+func (c *Client) getContractState(root util.Uint256, h util.Uint160) (*state.Contract, error) {
+	key := append([]byte{managementPrefixContract}, h.BytesBE()...)
+	v, err := c.getState(root, nativehashes.ContractManagement, managementContractID, key)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrContractNotFound
+	}
+	cs := new(state.Contract)
+	err = stackitem.DeserializeConvertible(v, cs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract state: %w", err)
+	}
+	if cs.Hash != h {
+		return nil, errors.New("contract hash mismatch")
+	}
+	return cs, nil
+}
+
+// getState returns the verified value of the contract storage item (nil if
+// it's missing).
+func (c *Client) getState(root util.Uint256, h util.Uint160, id int32, key []byte) ([]byte, error) {
+	p, err := c.rpc.GetMultiProof(root, h, [][]byte{key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proof: %w", err)
+	}
+	vals, ok := mpt.VerifyMultiProof(root, [][]byte{makeStorageKey(id, key)}, p.Proof)
+	if !ok {
+		return nil, errors.New("invalid proof")
+	}
+	return vals[0], nil
+}
+
+// findStates returns all verified contract storage items with the given
+// prefix, keys are returned without contract ID.
+func (c *Client) findStates(root util.Uint256, h util.Uint160, id int32, prefix []byte) ([]result.KeyValue, error) {
+	var (
+		res       []result.KeyValue
+		start     []byte
+		mptPrefix = makeStorageKey(id, prefix)
+	)
+	for {
+		p, err := c.rpc.GetRangeProof(root, h, prefix, start, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get range proof: %w", err)
+		}
+		var from []byte
+		if start != nil {
+			from = start[len(prefix):]
+		}
+		kvs := make([]storage.KeyValue, len(p.Results))
+		for i := range p.Results {
+			kvs[i] = storage.KeyValue{Key: p.Results[i].Key, Value: p.Results[i].Value}
+		}
+		complete, ok := mpt.VerifyRangeProof(root, mptPrefix, from, kvs, p.Proof)
+		if !ok {
+			return nil, errors.New("invalid range proof")
+		}
+		for _, kv := range kvs {
+			res = append(res, result.KeyValue{Key: kv.Key[4:], Value: kv.Value})
+		}
+		if complete {
+			return res, nil
+		}
+		if len(kvs) == 0 {
+			return nil, errors.New("empty incomplete range")
+		}
+		start = res[len(res)-1].Key
+	}
+}
+
+func makeStorageKey(id int32, key []byte) []byte {
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(id))
+	copy(skey[4:], key)
+	return skey
+}
@@ -0,0 +1,74 @@
+package lightclient
+
+import (
+	"crypto/elliptic"
+	"errors"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
+	"github.com/epicchainlabs/epicchain-go/pkg/crypto/keys"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
+)
+
+// verifyWitness checks that the witness belongs to the expected script hash
+// and contains correct signatures of the given hashable item. Only standard
+// signature and multisignature verification scripts are supported, that's
+// what headers and state roots use.
+func verifyWitness(magic netmode.Magic, expected util.Uint160, hh hash.Hashable, w *transaction.Witness) error {
+	if w.ScriptHash() != expected {
+		return errors.New("unexpected witness script hash")
+	}
+	var (
+		pkeys [][]byte
+		nsigs = 1
+	)
+	if pub, ok := vm.ParseSignatureContract(w.VerificationScript); ok {
+		pkeys = [][]byte{pub}
+	} else if m, pubs, ok := vm.ParseMultiSigContract(w.VerificationScript); ok {
+		nsigs = m
+		pkeys = pubs
+	} else {
+		return errors.New("non-standard verification script")
+	}
+	sigs, ok := parseSignatures(w.InvocationScript, nsigs)
+	if !ok {
+		return errors.New("non-standard invocation script")
+	}
+	msg := hash.NetSha256(uint32(magic), hh).BytesBE()
+	// Signatures are ordered the same way keys are, some keys can be skipped.
+	var j int
+	for i := range sigs {
+		for ; j < len(pkeys); j++ {
+			pub, err := keys.NewPublicKeyFromBytes(pkeys[j], elliptic.P256())
+			if err == nil && pub.Verify(sigs[i], msg) {
+				break
+			}
+		}
+		if j == len(pkeys) {
+			return errors.New("invalid signature")
+		}
+		j++
+	}
+	return nil
+}
+
+// parseSignatures returns signatures pushed by the standard invocation script
+// if it contains exactly n of them.
+func parseSignatures(script []byte, n int) ([][]byte, bool) {
+	const pushLen = 2 + keys.SignatureLen
+	if len(script) != n*pushLen {
+		return nil, false
+	}
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		push := script[i*pushLen : (i+1)*pushLen]
+		if push[0] != byte(opcode.PUSHDATA1) || push[1] != keys.SignatureLen {
+			return nil, false
+		}
+		sigs[i] = push[2:]
+	}
+	return sigs, true
+}
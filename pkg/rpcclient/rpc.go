@@ -223,6 +223,33 @@ func (c *Client) GetConnectionCount() (int, error) {
 	return resp, nil
 }
 
+// GetConsensusState returns the state of the dBFT round followed by the node.
+// This method is a NeoGo extension and requires consensus service (maybe in
+// watch-only mode) to be running on the node.
+func (c *Client) GetConsensusState() (*result.ConsensusState, error) {
+	var resp = new(result.ConsensusState)
+
+	if err := c.performRequest("getconsensusstate", nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetConsensusStats returns per-validator consensus statistics for the
+// [start, end] range of blocks. Verbose requests include records of every
+// round into the result. This method is a NeoGo extension and requires
+// consensus service with round persistence enabled to be running on the node.
+func (c *Client) GetConsensusStats(start, end uint32, verbose bool) (*result.ConsensusStats, error) {
+	var (
+		params = []any{start, end, verbose}
+		resp   = new(result.ConsensusStats)
+	)
+	if err := c.performRequest("getconsensusstats", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetCommittee returns the current public keys of NEO nodes in the committee.
 func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 	var resp = new(keys.PublicKeys)
@@ -233,6 +260,18 @@ func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 	return *resp, nil
 }
 
+// GetDBStats returns the number of keys and their sizes per key prefix and
+// per contract ID stored in the node database. This method is a NeoGo
+// extension, it iterates over the whole node database and can take some time.
+func (c *Client) GetDBStats() (*result.DBStats, error) {
+	var resp = new(result.DBStats)
+
+	if err := c.performRequest("getdbstats", nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetContractStateByHash queries contract information according to the contract script hash.
 func (c *Client) GetContractStateByHash(hash util.Uint160) (*state.Contract, error) {
 	return c.getContractState(hash.StringLE())
@@ -464,6 +503,49 @@ func (c *Client) VerifyProof(stateroot util.Uint256, proof *result.ProofWithKey)
 	return resp, nil
 }
 
+// GetMultiProof returns a proof of presence or absence of all of the given
+// historical contract storage keys for the specified stateroot. It can be
+// verified locally with mpt.VerifyMultiProof using MPT keys from the result.
+func (c *Client) GetMultiProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKeys [][]byte) (*result.MultiProof, error) {
+	var (
+		params = []any{stateroot.StringLE(), historicalContractHash.StringLE(), historicalKeys}
+		resp   = new(result.MultiProof)
+	)
+	if err := c.performRequest("getmultiproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetRangeProof returns historical contract storage items with the given
+// prefix for the specified stateroot along with the proof that there are no
+// other items in the range. It can be verified locally with
+// mpt.VerifyRangeProof using MPT-level parameters from the result. `start`
+// and `maxCount` parameters have the same meaning as for FindStates.
+func (c *Client) GetRangeProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte,
+	start []byte, maxCount *int) (*result.RangeProof, error) {
+	if historicalPrefix == nil {
+		historicalPrefix = []byte{}
+	}
+	var (
+		params = []any{stateroot.StringLE(), historicalContractHash.StringLE(), historicalPrefix}
+		resp   = new(result.RangeProof)
+	)
+	if start == nil && maxCount != nil {
+		start = []byte{}
+	}
+	if start != nil {
+		params = append(params, start)
+	}
+	if maxCount != nil {
+		params = append(params, *maxCount)
+	}
+	if err := c.performRequest("getrangeproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetState returns historical contract storage item state by the given stateroot,
 // historical contract hash and historical item key.
 func (c *Client) GetState(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) ([]byte, error) {
@@ -571,6 +653,36 @@ func (c *Client) getStorageHistoric(params []any) ([]byte, error) {
 	return resp, nil
 }
 
+// GetStorageDiff returns contract storage changes between states at the
+// specified heights. Only keys with the given prefix are compared. If `start`
+// is specified, changes are returned for keys following it (`start` must
+// include the prefix), it's usually the last key from the previous truncated
+// response. `maxCount` limits the number of changes returned, it's limited by
+// the server's configuration as well.
+func (c *Client) GetStorageDiff(contractHash util.Uint160, fromHeight, toHeight uint32, prefix, start []byte,
+	maxCount *int) (*result.StorageDiff, error) {
+	if prefix == nil {
+		prefix = []byte{}
+	}
+	if start == nil && maxCount != nil {
+		start = []byte{}
+	}
+	var (
+		params = []any{contractHash.StringLE(), fromHeight, toHeight, prefix}
+		resp   = new(result.StorageDiff)
+	)
+	if start != nil {
+		params = append(params, start)
+	}
+	if maxCount != nil {
+		params = append(params, *maxCount)
+	}
+	if err := c.performRequest("getstoragediff", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // FindStorageByHash returns contract storage items by the given contract hash and prefix.
 // If `start` index is specified, items starting from `start` index are being returned
 // (including item located at the start index).
@@ -930,6 +1042,45 @@ func (c *Client) TerminateSession(sessionID uuid.UUID) (bool, error) {
 	return resp, nil
 }
 
+// TraceTransaction re-executes the persisted transaction with the given hash
+// on its historic state and returns the call-level trace of this execution.
+// It's a NeoGo extension that requires historic states to be available on the
+// server.
+func (c *Client) TraceTransaction(hash util.Uint256) (*result.TransactionTrace, error) {
+	var (
+		params = []any{hash.StringLE()}
+		resp   = new(result.TransactionTrace)
+	)
+	if err := c.performRequest("tracetransaction", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SimulateTransactions executes the given transactions in a simulated block
+// following the block at the given height with optional state overrides and
+// returns execution results along with contract storage changes. Transactions
+// don't need to be signed, their system fee is used as a GAS limit. It's a
+// NeoGo extension that requires historic states to be available on the server.
+func (c *Client) SimulateTransactions(height uint32, txs []*transaction.Transaction, opts *neorpc.SimulationOptions) (*result.Simulation, error) {
+	var (
+		rawTxs = make([][]byte, len(txs))
+		params = []any{height}
+		resp   = new(result.Simulation)
+	)
+	for i := range txs {
+		rawTxs[i] = txs[i].Bytes()
+	}
+	params = append(params, rawTxs)
+	if opts != nil {
+		params = append(params, opts)
+	}
+	if err := c.performRequest("simulatetransactions", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetRawNotaryTransaction  returns main or fallback transaction from the
 // RPC node's notary request pool.
 func (c *Client) GetRawNotaryTransaction(hash util.Uint256) (*transaction.Transaction, error) {
@@ -26,9 +26,11 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/bigint"
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/fixedn"
 	"github.com/epicchainlabs/epicchain-go/pkg/io"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc"
 	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
 	"github.com/epicchainlabs/epicchain-go/pkg/services/rpcsrv/params"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/nef"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
@@ -327,6 +329,90 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getconsensusstate": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetConsensusState()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"watchonly":true,"height":10,"view":1,"primary":2,"validators":[],"roundstarted":1700000000000,"viewstarted":1700000015000,"viewchanges":3,"changeviewreasons":{"Timeout":4},"primarytimeouts":1,"recoveryrequests":2,"recoverymessages":5}}`,
+			result: func(c *Client) any {
+				return &result.ConsensusState{
+					WatchOnly:         true,
+					Height:            10,
+					View:              1,
+					Primary:           2,
+					Validators:        keys.PublicKeys{},
+					RoundStarted:      1700000000000,
+					ViewStarted:       1700000015000,
+					ViewChanges:       3,
+					ChangeViewReasons: map[string]uint64{"Timeout": 4},
+					PrimaryTimeouts:   1,
+					RecoveryRequests:  2,
+					RecoveryMessages:  5,
+				}
+			},
+		},
+	},
+	"getconsensusstats": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetConsensusStats(5, 6, false)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"start":5,"end":6,"rounds":2,"viewchanges":1,"validators":[{"publickey":"02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62","rounds":2,"primary":1,"primaryfailures":1,"prepared":1,"committed":2,"changeviews":1,"averagecommittime":150}]}}`,
+			result: func(c *Client) any {
+				pub, err := keys.NewPublicKeyFromString("02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62")
+				if err != nil {
+					panic(err)
+				}
+				return &result.ConsensusStats{
+					Start:       5,
+					End:         6,
+					Rounds:      2,
+					ViewChanges: 1,
+					Validators: []result.ConsensusValidatorStats{{
+						PublicKey:         pub,
+						Rounds:            2,
+						Primary:           1,
+						PrimaryFailures:   1,
+						Prepared:          1,
+						Committed:         2,
+						ChangeViews:       1,
+						AverageCommitTime: 150,
+					}},
+				}
+			},
+		},
+	},
+	"getdbstats": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetDBStats()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"prefixes":[{"prefix":112,"name":"STStorage","keys":3,"keybytes":30,"valuebytes":100}],"contracts":[{"id":-1,"hash":"0xfffdc93764dbaddd97c48f252a53ea4643faa3fd","keys":2,"keybytes":20,"valuebytes":90},{"id":5,"keys":1,"keybytes":10,"valuebytes":10}]}}`,
+			result: func(c *Client) any {
+				h, err := util.Uint160DecodeStringLE("fffdc93764dbaddd97c48f252a53ea4643faa3fd")
+				if err != nil {
+					panic(err)
+				}
+				return &result.DBStats{
+					Prefixes: []result.DBPrefixStats{{
+						Prefix:     0x70,
+						Name:       "STStorage",
+						Keys:       3,
+						KeyBytes:   30,
+						ValueBytes: 100,
+					}},
+					Contracts: []result.DBContractStats{
+						{ID: -1, Hash: &h, Keys: 2, KeyBytes: 20, ValueBytes: 90},
+						{ID: 5, Keys: 1, KeyBytes: 10, ValueBytes: 10},
+					},
+				}
+			},
+		},
+	},
 	"getcontractstate": {
 		{
 			name: "positive, by hash",
@@ -784,6 +870,146 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getstoragediff": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				cHash, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				count := 2
+				return c.GetStorageDiff(cHash, 1, 5, []byte("aa"), []byte("aa00"), &count)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"fromRoot":"0x252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170","toRoot":"0x5d5d6c40b1e4a2fb4c4b7e1e6fa64fc53aeb81c53e7d4a9b1b7d2a7d5f33a1b2","changes":[{"state":"Added","key":"YWExMA==","newValue":"djI="},{"state":"Changed","key":"YWExMQ==","oldValue":"djE=","newValue":"djI="}],"truncated":true}}`,
+			result: func(c *Client) any {
+				from, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				to, _ := util.Uint256DecodeStringLE("5d5d6c40b1e4a2fb4c4b7e1e6fa64fc53aeb81c53e7d4a9b1b7d2a7d5f33a1b2")
+				return &result.StorageDiff{
+					FromRoot: from,
+					ToRoot:   to,
+					Changes: []result.StorageChange{
+						{State: "Added", Key: []byte("aa10"), NewValue: []byte("v2")},
+						{State: "Changed", Key: []byte("aa11"), OldValue: []byte("v1"), NewValue: []byte("v2")},
+					},
+					Truncated: true,
+				}
+			},
+		},
+	},
+	"tracetransaction": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.TraceTransaction(util.Uint256{})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"txid":"0x17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521","blockindex":5,"vmstate":"FAULT","gasconsumed":"300","exception":"at instruction 3 (THROW): unhandled exception: \"oops\"","call":{"hash":"0x5c9e40a12055c6b9e3f72271c9779958c842135d","callflags":"All","gasconsumed":"300","state":"Fault","ip":7,"syscalls":["System.Contract.Call"],"calls":[{"hash":"0xd2a4cff31913016155e38e474a2c06d08be276cf","method":"transfer","arguments":[{"type":"Integer","value":"1"}],"callflags":"ReadStates","gasconsumed":"100","state":"Exception","ip":3,"storage":[{"op":"Get","id":-6,"key":"AQ==","value":"Ag=="},{"op":"Delete","id":-6,"key":"Aw=="}]}]}}}`,
+			result: func(c *Client) any {
+				txHash, _ := util.Uint256DecodeStringLE("17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521")
+				entry, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				gas, _ := util.Uint160DecodeStringLE("d2a4cff31913016155e38e474a2c06d08be276cf")
+				return &result.TransactionTrace{
+					TxHash:         txHash,
+					BlockIndex:     5,
+					VMState:        vmstate.Fault,
+					GasConsumed:    300,
+					FaultException: `at instruction 3 (THROW): unhandled exception: "oops"`,
+					Call: &result.CallTrace{
+						Hash:        entry,
+						CallFlags:   callflag.All,
+						GasConsumed: 300,
+						State:       result.CallFault,
+						IP:          7,
+						Syscalls:    []string{"System.Contract.Call"},
+						Calls: []*result.CallTrace{{
+							Hash:        gas,
+							Method:      "transfer",
+							Arguments:   []stackitem.Item{stackitem.NewBigInteger(big.NewInt(1))},
+							CallFlags:   callflag.ReadStates,
+							GasConsumed: 100,
+							State:       result.CallException,
+							IP:          3,
+							Storage: []result.StorageAccess{
+								{Op: result.StorageGet, ID: -6, Key: []byte{1}, Value: []byte{2}},
+								{Op: result.StorageDelete, ID: -6, Key: []byte{3}},
+							},
+						}},
+					},
+				}
+			},
+		},
+	},
+	"simulatetransactions": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.SimulateTransactions(5, []*transaction.Transaction{transaction.New([]byte{byte(opcode.PUSH1)}, 0)}, &neorpc.SimulationOptions{Timestamp: 123})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"blockindex":6,"timestamp":123,"executions":[{"container":"0x17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521","trigger":"Application","vmstate":"HALT","gasconsumed":"1","stack":[{"type":"Integer","value":"1"}],"notifications":[]}],"storagechanges":[{"contract":"0xd2a4cff31913016155e38e474a2c06d08be276cf","state":"Changed","key":"FA==","oldValue":"AQ==","newValue":"Ag=="}]}}`,
+			result: func(c *Client) any {
+				txHash, _ := util.Uint256DecodeStringLE("17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521")
+				gas, _ := util.Uint160DecodeStringLE("d2a4cff31913016155e38e474a2c06d08be276cf")
+				return &result.Simulation{
+					BlockIndex: 6,
+					Timestamp:  123,
+					Executions: []state.AppExecResult{{
+						Container: txHash,
+						Execution: state.Execution{
+							Trigger:     trigger.Application,
+							VMState:     vmstate.Halt,
+							GasConsumed: 1,
+							Stack:       []stackitem.Item{stackitem.NewBigInteger(big.NewInt(1))},
+							Events:      []state.NotificationEvent{},
+						},
+					}},
+					Changes: []result.ContractStorageChange{{
+						Contract: gas,
+						StorageChange: result.StorageChange{
+							State:    "Changed",
+							Key:      []byte{0x14},
+							OldValue: []byte{1},
+							NewValue: []byte{2},
+						},
+					}},
+				}
+			},
+		},
+	},
+	"getmultiproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				cHash, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				return c.GetMultiProof(root, cHash, [][]byte{[]byte("aa10"), []byte("aa11")})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"keys":["AQAAAGFhMTA=","AQAAAGFhMTE="],"proof":["AQID","BAU="]}}`,
+			result: func(c *Client) any {
+				return &result.MultiProof{
+					Keys:  [][]byte{append([]byte{1, 0, 0, 0}, "aa10"...), append([]byte{1, 0, 0, 0}, "aa11"...)},
+					Proof: [][]byte{{1, 2, 3}, {4, 5}},
+				}
+			},
+		},
+	},
+	"getrangeproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				cHash, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				count := 1
+				return c.GetRangeProof(root, cHash, []byte("aa"), []byte("aa00"), &count)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"prefix":"AQAAAGFh","start":"MDA=","results":[{"key":"AQAAAGFhMTA=","value":"djI="}],"proof":["AQID"],"truncated":true}}`,
+			result: func(c *Client) any {
+				return &result.RangeProof{
+					Prefix:    append([]byte{1, 0, 0, 0}, "aa"...),
+					Start:     []byte("00"),
+					Results:   []result.KeyValue{{Key: append([]byte{1, 0, 0, 0}, "aa10"...), Value: []byte("v2")}},
+					Proof:     [][]byte{{1, 2, 3}},
+					Truncated: true,
+				}
+			},
+		},
+	},
 	"findstorage": {
 		{
 			name: "positive by hash",
@@ -57,6 +57,16 @@ import (
 // will also be closed on disconnection from server or on situation when it's
 // impossible to send a subsequent notification to the subscriber's channel and
 // CloseNotificationChannelIfFull option is on.
+//
+// Receive*From methods additionally request the server to send past events
+// starting from the given block index before switching to the new ones. Server
+// notifications don't carry subscription IDs, so these past events are routed
+// using subscription filters the same way live ones are, which means that they
+// can be delivered to any other subscription of the same type matching them.
+// Use a separate WSClient for such subscriptions if that's not desirable. Past
+// events can be received before Receive*From returns, so the receiver channel
+// must be read from concurrently (or be sufficiently buffered) to avoid
+// blocking the client.
 type WSClient struct {
 	Client
 
@@ -771,6 +781,12 @@ func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver)
 			return "", err
 		}
 	}
+	// Past events are sent by the server right after the response, so
+	// receivers of replaying subscriptions are registered in advance with a
+	// temporary ID to be replaced with the real one.
+	if len(params) == 3 {
+		return c.performReplayingSubscription(params, rcvr)
+	}
 	if err := c.performRequest("subscribe", params, &resp); err != nil {
 		return "", err
 	}
@@ -784,10 +800,62 @@ func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver)
 	return resp, nil
 }
 
+func (c *WSClient) performReplayingSubscription(params []any, rcvr notificationReceiver) (string, error) {
+	var (
+		resp  string
+		tmpID = fmt.Sprintf("pending-%p", rcvr)
+		ch    = rcvr.Receiver()
+	)
+	c.subscriptionsLock.Lock()
+	c.subscriptions[tmpID] = rcvr
+	c.receivers[ch] = append(c.receivers[ch], tmpID)
+	c.subscriptionsLock.Unlock()
+
+	err := c.performRequest("subscribe", params, &resp)
+
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	delete(c.subscriptions, tmpID)
+	if err == nil {
+		c.subscriptions[resp] = rcvr
+	}
+	// The channel could've been dropped already, then there is nothing to update.
+	ids := c.receivers[ch]
+	for i, id := range ids {
+		if id != tmpID {
+			continue
+		}
+		if err == nil {
+			ids[i] = resp
+		} else if len(ids) == 1 {
+			delete(c.receivers, ch)
+		} else {
+			c.receivers[ch] = append(ids[:i], ids[i+1:]...)
+		}
+		break
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp, nil
+}
+
 // ReceiveBlocks registers provided channel as a receiver for the new block events.
 // Events can be filtered by the given BlockFilter, nil value doesn't add any filter.
 // See WSClient comments for generic Receive* behaviour details.
 func (c *WSClient) ReceiveBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Block) (string, error) {
+	return c.receiveBlocks(flt, rcvr, nil)
+}
+
+// ReceiveBlocksFrom is similar to ReceiveBlocks, but the server sends past block events
+// starting from the specified block index before the new ones.
+// See WSClient comments for details.
+func (c *WSClient) ReceiveBlocksFrom(start uint32, flt *neorpc.BlockFilter, rcvr chan<- *block.Block) (string, error) {
+	return c.receiveBlocks(flt, rcvr, &start)
+}
+
+func (c *WSClient) receiveBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Block, start *uint32) (string, error) {
 	if rcvr == nil {
 		return "", ErrNilNotificationReceiver
 	}
@@ -799,6 +867,9 @@ func (c *WSClient) ReceiveBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Blo
 		flt = flt.Copy()
 		params = append(params, *flt)
 	}
+	if start != nil {
+		params = withStartIndex(params, *start)
+	}
 	r := &blockReceiver{
 		filter: flt,
 		ch:     rcvr,
@@ -811,6 +882,17 @@ func (c *WSClient) ReceiveBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Blo
 // nil value doesn't add any filter. See WSClient comments for generic
 // Receive* behaviour details.
 func (c *WSClient) ReceiveHeadersOfAddedBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Header) (string, error) {
+	return c.receiveHeadersOfAddedBlocks(flt, rcvr, nil)
+}
+
+// ReceiveHeadersOfAddedBlocksFrom is similar to ReceiveHeadersOfAddedBlocks, but the server sends past block header events
+// starting from the specified block index before the new ones.
+// See WSClient comments for details.
+func (c *WSClient) ReceiveHeadersOfAddedBlocksFrom(start uint32, flt *neorpc.BlockFilter, rcvr chan<- *block.Header) (string, error) {
+	return c.receiveHeadersOfAddedBlocks(flt, rcvr, &start)
+}
+
+func (c *WSClient) receiveHeadersOfAddedBlocks(flt *neorpc.BlockFilter, rcvr chan<- *block.Header, start *uint32) (string, error) {
 	if rcvr == nil {
 		return "", ErrNilNotificationReceiver
 	}
@@ -822,6 +904,9 @@ func (c *WSClient) ReceiveHeadersOfAddedBlocks(flt *neorpc.BlockFilter, rcvr cha
 		flt = flt.Copy()
 		params = append(params, *flt)
 	}
+	if start != nil {
+		params = withStartIndex(params, *start)
+	}
 	r := &headerOfAddedBlockReceiver{
 		filter: flt,
 		ch:     rcvr,
@@ -833,6 +918,17 @@ func (c *WSClient) ReceiveHeadersOfAddedBlocks(flt *neorpc.BlockFilter, rcvr cha
 // events. Events can be filtered by the given TxFilter, nil value doesn't add any
 // filter. See WSClient comments for generic Receive* behaviour details.
 func (c *WSClient) ReceiveTransactions(flt *neorpc.TxFilter, rcvr chan<- *transaction.Transaction) (string, error) {
+	return c.receiveTransactions(flt, rcvr, nil)
+}
+
+// ReceiveTransactionsFrom is similar to ReceiveTransactions, but the server sends past transaction events
+// starting from the specified block index before the new ones.
+// See WSClient comments for details.
+func (c *WSClient) ReceiveTransactionsFrom(start uint32, flt *neorpc.TxFilter, rcvr chan<- *transaction.Transaction) (string, error) {
+	return c.receiveTransactions(flt, rcvr, &start)
+}
+
+func (c *WSClient) receiveTransactions(flt *neorpc.TxFilter, rcvr chan<- *transaction.Transaction, start *uint32) (string, error) {
 	if rcvr == nil {
 		return "", ErrNilNotificationReceiver
 	}
@@ -841,6 +937,9 @@ func (c *WSClient) ReceiveTransactions(flt *neorpc.TxFilter, rcvr chan<- *transa
 		flt = flt.Copy()
 		params = append(params, *flt)
 	}
+	if start != nil {
+		params = withStartIndex(params, *start)
+	}
 	r := &txReceiver{
 		filter: flt,
 		ch:     rcvr,
@@ -852,6 +951,17 @@ func (c *WSClient) ReceiveTransactions(flt *neorpc.TxFilter, rcvr chan<- *transa
 // events. Events can be filtered by the given NotificationFilter, nil value doesn't add
 // any filter. See WSClient comments for generic Receive* behaviour details.
 func (c *WSClient) ReceiveExecutionNotifications(flt *neorpc.NotificationFilter, rcvr chan<- *state.ContainedNotificationEvent) (string, error) {
+	return c.receiveExecutionNotifications(flt, rcvr, nil)
+}
+
+// ReceiveExecutionNotificationsFrom is similar to ReceiveExecutionNotifications, but the server sends past execution notification events
+// starting from the specified block index before the new ones.
+// See WSClient comments for details.
+func (c *WSClient) ReceiveExecutionNotificationsFrom(start uint32, flt *neorpc.NotificationFilter, rcvr chan<- *state.ContainedNotificationEvent) (string, error) {
+	return c.receiveExecutionNotifications(flt, rcvr, &start)
+}
+
+func (c *WSClient) receiveExecutionNotifications(flt *neorpc.NotificationFilter, rcvr chan<- *state.ContainedNotificationEvent, start *uint32) (string, error) {
 	if rcvr == nil {
 		return "", ErrNilNotificationReceiver
 	}
@@ -860,6 +970,9 @@ func (c *WSClient) ReceiveExecutionNotifications(flt *neorpc.NotificationFilter,
 		flt = flt.Copy()
 		params = append(params, *flt)
 	}
+	if start != nil {
+		params = withStartIndex(params, *start)
+	}
 	r := &executionNotificationReceiver{
 		filter: flt,
 		ch:     rcvr,
@@ -872,6 +985,17 @@ func (c *WSClient) ReceiveExecutionNotifications(flt *neorpc.NotificationFilter,
 // Events can be filtered by the given ExecutionFilter, nil value doesn't add any filter.
 // See WSClient comments for generic Receive* behaviour details.
 func (c *WSClient) ReceiveExecutions(flt *neorpc.ExecutionFilter, rcvr chan<- *state.AppExecResult) (string, error) {
+	return c.receiveExecutions(flt, rcvr, nil)
+}
+
+// ReceiveExecutionsFrom is similar to ReceiveExecutions, but the server sends past execution result events
+// starting from the specified block index before the new ones.
+// See WSClient comments for details.
+func (c *WSClient) ReceiveExecutionsFrom(start uint32, flt *neorpc.ExecutionFilter, rcvr chan<- *state.AppExecResult) (string, error) {
+	return c.receiveExecutions(flt, rcvr, &start)
+}
+
+func (c *WSClient) receiveExecutions(flt *neorpc.ExecutionFilter, rcvr chan<- *state.AppExecResult, start *uint32) (string, error) {
 	if rcvr == nil {
 		return "", ErrNilNotificationReceiver
 	}
@@ -880,6 +1004,9 @@ func (c *WSClient) ReceiveExecutions(flt *neorpc.ExecutionFilter, rcvr chan<- *s
 		flt = flt.Copy()
 		params = append(params, *flt)
 	}
+	if start != nil {
+		params = withStartIndex(params, *start)
+	}
 	r := &executionReceiver{
 		filter: flt,
 		ch:     rcvr,
@@ -910,6 +1037,15 @@ func (c *WSClient) ReceiveNotaryRequests(flt *neorpc.NotaryRequestFilter, rcvr c
 	return c.performSubscription(params, r)
 }
 
+// withStartIndex adds the start index to the subscription parameters, filter
+// parameter is null if not specified.
+func withStartIndex(params []any, start uint32) []any {
+	if len(params) == 1 {
+		params = append(params, nil)
+	}
+	return append(params, start)
+}
+
 // Unsubscribe removes subscription for the given event stream. It will return an
 // error in case if there's no subscription with the provided ID. Call to Unsubscribe
 // doesn't block notifications receive process for given subscriber, thus, ensure
@@ -24,6 +24,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/fee"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop/interopnames"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativehashes"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
@@ -1983,6 +1984,66 @@ func testSubClientWait(t *testing.T, local bool) {
 	require.True(t, faultedChecked, "FAULTed transaction wasn't checked")
 }
 
+func TestSubClientReplay(t *testing.T) {
+	runWSAndLocal(t, testSubClientReplay)
+}
+
+func testSubClientReplay(t *testing.T, local bool) {
+	chain, rpcSrv, httpSrv := initClearServerWithServices(t, false, false, true)
+
+	c := mkSubsClient(t, rpcSrv, httpSrv, local)
+	blocks := getTestBlocks(t)
+	last := blocks[len(blocks)-1]
+	for _, b := range blocks[:len(blocks)-1] {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	_, err := c.ReceiveBlocksFrom(chain.BlockHeight()+2, nil, make(chan *block.Block))
+	require.Error(t, err)
+
+	// Past events are received before ReceiveBlocksFrom returns.
+	blockCh := make(chan *block.Block, len(blocks))
+	bID, err := c.ReceiveBlocksFrom(1, nil, blockCh)
+	require.NoError(t, err)
+	for i := uint32(1); i <= chain.BlockHeight(); i++ {
+		b := <-blockCh
+		require.Equal(t, chain.GetHeaderHash(i), b.Hash())
+	}
+
+	primary := last.PrimaryIndex
+	hdrCh := make(chan *block.Header, 1)
+	hID, err := c.ReceiveHeadersOfAddedBlocksFrom(chain.BlockHeight()+1, &neorpc.BlockFilter{Primary: &primary}, hdrCh)
+	require.NoError(t, err)
+
+	require.NoError(t, chain.AddBlock(last))
+	require.Equal(t, last.Hash(), (<-blockCh).Hash())
+	require.Equal(t, last.Hash(), (<-hdrCh).Hash())
+
+	require.NoError(t, c.Unsubscribe(bID))
+	require.NoError(t, c.Unsubscribe(hID))
+}
+
+func TestSubClientReplayLimit(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.RPC.MaxSubscriptionReplayBlocks = 3
+	})
+	c := mkSubsClient(t, rpcSrv, httpSrv, false)
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	_, err := c.ReceiveBlocksFrom(chain.BlockHeight()-3, nil, make(chan *block.Block))
+	require.Error(t, err)
+
+	blockCh := make(chan *block.Block, 3)
+	id, err := c.ReceiveBlocksFrom(chain.BlockHeight()-2, nil, blockCh)
+	require.NoError(t, err)
+	for i := chain.BlockHeight() - 2; i <= chain.BlockHeight(); i++ {
+		require.Equal(t, chain.GetHeaderHash(i), (<-blockCh).Hash())
+	}
+	require.NoError(t, c.Unsubscribe(id))
+}
+
 func TestSubClientWaitWithLateSubscription(t *testing.T) {
 	runWSAndLocal(t, testSubClientWaitWithLateSubscription)
 }
@@ -2450,3 +2511,146 @@ func TestClient_GetVersion_Hardforks(t *testing.T) {
 	}
 	require.InDeltaMapValues(t, expected, v.Protocol.Hardforks, 0)
 }
+
+func TestClient_TraceTransaction(t *testing.T) {
+	chain, _, httpSrv := initServerWithInMemoryChain(t)
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	rubles, err := util.Uint160DecodeStringLE(testContractHash)
+	require.NoError(t, err)
+	var findCall func(c *result.CallTrace, h util.Uint160, method string) *result.CallTrace
+	findCall = func(c *result.CallTrace, h util.Uint160, method string) *result.CallTrace {
+		if c.Hash == h && c.Method == method {
+			return c
+		}
+		for _, sub := range c.Calls {
+			if res := findCall(sub, h, method); res != nil {
+				return res
+			}
+		}
+		return nil
+	}
+
+	// Find Rubles transfer.
+	var (
+		transferTx util.Uint256
+		tr         *result.TransactionTrace
+		call       *result.CallTrace
+	)
+	for i := uint32(1); i <= chain.BlockHeight() && call == nil; i++ {
+		b, err := chain.GetBlock(chain.GetHeaderHash(i))
+		require.NoError(t, err)
+		for _, tx := range b.Transactions {
+			aers, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+			require.NoError(t, err)
+			if aers[0].VMState != vmstate.Halt || len(aers[0].Events) != 1 ||
+				aers[0].Events[0].ScriptHash != rubles || aers[0].Events[0].Name != "Transfer" {
+				continue
+			}
+			tr, err = c.TraceTransaction(tx.Hash())
+			require.NoError(t, err)
+			if call = findCall(tr.Call, rubles, "transfer"); call != nil {
+				transferTx = tx.Hash()
+				break
+			}
+		}
+	}
+	require.NotNil(t, call)
+
+	aers, err := chain.GetAppExecResults(transferTx, trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, transferTx, tr.TxHash)
+	require.Equal(t, vmstate.Halt, tr.VMState)
+	require.Equal(t, aers[0].GasConsumed, tr.GasConsumed)
+	require.Empty(t, tr.FaultException)
+	require.Equal(t, result.CallCompleted, tr.Call.State)
+	require.Equal(t, aers[0].GasConsumed, tr.Call.GasConsumed)
+
+	require.Equal(t, result.CallCompleted, call.State)
+	require.Equal(t, 4, len(call.Arguments))
+	require.Less(t, call.GasConsumed, tr.Call.GasConsumed)
+	require.Contains(t, call.Syscalls, interopnames.SystemStoragePut)
+	var gets, puts int
+	for _, s := range call.Storage {
+		switch s.Op {
+		case result.StorageGet:
+			gets++
+		case result.StoragePut:
+			puts++
+		}
+	}
+	require.Equal(t, 2, gets)
+	require.Equal(t, 2, puts)
+
+	// Faulted transaction.
+	faulted, err := util.Uint256DecodeStringLE(faultedTxHashLE)
+	require.NoError(t, err)
+	tr, err = c.TraceTransaction(faulted)
+	require.NoError(t, err)
+	require.Equal(t, vmstate.Fault, tr.VMState)
+	require.NotEmpty(t, tr.FaultException)
+	require.Equal(t, result.CallFault, tr.Call.State)
+
+	// Unknown transaction.
+	_, err = c.TraceTransaction(util.Uint256{1, 2, 3})
+	require.ErrorIs(t, err, neorpc.ErrUnknownTransaction)
+}
+
+func TestClient_SimulateTransactions(t *testing.T) {
+	chain, _, httpSrv := initServerWithInMemoryChain(t)
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	acc0 := wallet.NewAccountFromPrivateKey(testchain.PrivateKeyByID(0))
+	act, err := actor.NewSimple(c, acc0)
+	require.NoError(t, err)
+	to := util.Uint160{1, 2, 3}
+	tx, err := gas.New(act).TransferUnsigned(acc0.ScriptHash(), to, big.NewInt(1000), nil)
+	require.NoError(t, err)
+	h := chain.BlockHeight()
+
+	res, err := c.SimulateTransactions(h, []*transaction.Transaction{tx}, nil)
+	require.NoError(t, err)
+	require.Equal(t, h+1, res.BlockIndex)
+	require.Equal(t, 1, len(res.Executions))
+	require.Equal(t, tx.Hash(), res.Executions[0].Container)
+	require.Equal(t, vmstate.Halt, res.Executions[0].VMState)
+	require.Equal(t, 1, len(res.Executions[0].Events))
+	require.Equal(t, 2, len(res.Changes)) // Sender and receiver balances.
+	for _, ch := range res.Changes {
+		require.Equal(t, nativehashes.GasToken, ch.Contract)
+	}
+	require.Equal(t, h, chain.BlockHeight())
+	require.Equal(t, int64(0), chain.GetUtilityTokenBalance(to).Int64())
+
+	// Not enough GAS to transfer.
+	res, err = c.SimulateTransactions(h, []*transaction.Transaction{tx}, &neorpc.SimulationOptions{
+		Timestamp:   123,
+		GASBalances: []neorpc.BalanceOverride{{Account: acc0.ScriptHash(), Amount: 500}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(123), res.Timestamp)
+	require.Equal(t, 0, len(res.Executions[0].Events))
+	require.Empty(t, res.Changes)
+
+	// Not enough GAS to pay fees.
+	_, err = c.SimulateTransactions(h, []*transaction.Transaction{tx}, &neorpc.SimulationOptions{
+		FullBlock:   true,
+		GASBalances: []neorpc.BalanceOverride{{Account: acc0.ScriptHash(), Amount: 500}},
+	})
+	require.Error(t, err)
+
+	res, err = c.SimulateTransactions(h, []*transaction.Transaction{tx}, &neorpc.SimulationOptions{FullBlock: true})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(res.Executions))
+	require.Equal(t, trigger.OnPersist, res.Executions[0].Trigger)
+	require.Equal(t, vmstate.Halt, res.Executions[1].VMState)
+	require.Equal(t, trigger.PostPersist, res.Executions[2].Trigger)
+}
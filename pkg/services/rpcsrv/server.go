@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,6 +25,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
 	"github.com/epicchainlabs/epicchain-go/pkg/config/limits"
 	"github.com/epicchainlabs/epicchain-go/pkg/config/netmode"
+	"github.com/epicchainlabs/epicchain-go/pkg/consensus"
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
@@ -50,6 +52,7 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/callflag"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/manifest/standard"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/nef"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
 	"github.com/epicchainlabs/epicchain-go/pkg/vm"
@@ -91,6 +94,7 @@ type (
 		GetNotaryContractScriptHash() util.Uint160
 		GetStateModule() core.StateRoot
 		GetStorageItem(id int32, key []byte) state.StorageItem
+		GetStorageStats(ctx context.Context) (*storage.Stats, error)
 		GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error)
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
 		GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
@@ -98,11 +102,18 @@ type (
 		HeaderHeight() uint32
 		InitVerificationContext(ic *interop.Context, hash util.Uint160, witness *transaction.Witness) error
 		P2PSigExtensionsEnabled() bool
+		ReplayTransaction(h util.Uint256, tracer core.Tracer) (*state.AppExecResult, error)
+		Simulate(sim *core.Simulation) (*core.SimulationResult, error)
 		SubscribeForBlocks(ch chan *block.Block)
 		SubscribeForHeadersOfAddedBlocks(ch chan *block.Header)
 		SubscribeForExecutions(ch chan *state.AppExecResult)
 		SubscribeForNotifications(ch chan *state.ContainedNotificationEvent)
 		SubscribeForTransactions(ch chan *transaction.Transaction)
+		SubscribeForBlocksFrom(ch chan *block.Block, start uint32) error
+		SubscribeForHeadersOfAddedBlocksFrom(ch chan *block.Header, start uint32) error
+		SubscribeForExecutionsFrom(ch chan *state.AppExecResult, start uint32) error
+		SubscribeForNotificationsFrom(ch chan *state.ContainedNotificationEvent, start uint32) error
+		SubscribeForTransactionsFrom(ch chan *transaction.Transaction, start uint32) error
 		UnsubscribeFromBlocks(ch chan *block.Block)
 		UnsubscribeFromHeadersOfAddedBlocks(ch chan *block.Header)
 		UnsubscribeFromExecutions(ch chan *state.AppExecResult)
@@ -125,6 +136,13 @@ type (
 		AddResponse(pub *keys.PublicKey, reqID uint64, txSig []byte)
 	}
 
+	// ConsensusStateProvider is the interface consensus service needs to provide
+	// for the Server.
+	ConsensusStateProvider interface {
+		GetState() consensus.State
+		ForEachRoundRecord(start, end uint32, f func(*consensus.RoundRecord) bool) error
+	}
+
 	// Server represents the JSON-RPC 2.0 server.
 	Server struct {
 		http  []*http.Server
@@ -139,6 +157,7 @@ type (
 		stateRootEnabled bool
 		coreServer       *network.Server
 		oracle           *atomic.Value
+		consensus        *atomic.Pointer[ConsensusStateProvider]
 		log              *zap.Logger
 		shutdown         chan struct{}
 		started          atomic.Bool
@@ -203,6 +222,9 @@ const (
 	// Maximum number of elements for get*transfers requests.
 	maxTransfersLimit = 1000
 
+	// Maximum number of round records for verbose getconsensusstats requests.
+	maxConsensusRecords = 1000
+
 	// defaultSessionPoolSize is the number of concurrently running iterator sessions.
 	defaultSessionPoolSize = 20
 )
@@ -223,7 +245,10 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"getcandidates":                (*Server).getCandidates,
 	"getcommittee":                 (*Server).getCommittee,
 	"getconnectioncount":           (*Server).getConnectionCount,
+	"getconsensusstate":            (*Server).getConsensusState,
+	"getconsensusstats":            (*Server).getConsensusStats,
 	"getcontractstate":             (*Server).getContractState,
+	"getdbstats":                   (*Server).getDBStats,
 	"getnativecontracts":           (*Server).getNativeContracts,
 	"getnep11balances":             (*Server).getNEP11Balances,
 	"getnep11properties":           (*Server).getNEP11Properties,
@@ -231,7 +256,9 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"getnep17balances":             (*Server).getNEP17Balances,
 	"getnep17transfers":            (*Server).getNEP17Transfers,
 	"getpeers":                     (*Server).getPeers,
+	"getmultiproof":                (*Server).getMultiProof,
 	"getproof":                     (*Server).getProof,
+	"getrangeproof":                (*Server).getRangeProof,
 	"getrawmempool":                (*Server).getRawMempool,
 	"getrawnotarypool":             (*Server).getRawNotaryPool,
 	"getrawnotarytransaction":      (*Server).getRawNotaryTransaction,
@@ -240,6 +267,7 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"getstateheight":               (*Server).getStateHeight,
 	"getstateroot":                 (*Server).getStateRoot,
 	"getstorage":                   (*Server).getStorage,
+	"getstoragediff":               (*Server).getStorageDiff,
 	"getstoragehistoric":           (*Server).getStorageHistoric,
 	"gettransactionheight":         (*Server).getTransactionHeight,
 	"getunclaimedgas":              (*Server).getUnclaimedGas,
@@ -252,10 +280,12 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"invokecontractverify":         (*Server).invokeContractVerify,
 	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
 	"sendrawtransaction":           (*Server).sendrawtransaction,
+	"simulatetransactions":         (*Server).simulateTransactions,
 	"submitblock":                  (*Server).submitBlock,
 	"submitnotaryrequest":          (*Server).submitNotaryRequest,
 	"submitoracleresponse":         (*Server).submitOracleResponse,
 	"terminatesession":             (*Server).terminateSession,
+	"tracetransaction":             (*Server).traceTransaction,
 	"traverseiterator":             (*Server).traverseIterator,
 	"validateaddress":              (*Server).validateAddress,
 	"verifyproof":                  (*Server).verifyProof,
@@ -281,6 +311,10 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 			log.Info("SessionPoolSize is not set or wrong, setting default value", zap.Int("SessionPoolSize", defaultSessionPoolSize))
 		}
 	}
+	if conf.MaxConsensusStatsBlocks <= 0 {
+		conf.MaxConsensusStatsBlocks = config.DefaultMaxConsensusStatsBlocks
+		log.Info("MaxConsensusStatsBlocks is not set or wrong, setting default value", zap.Int("MaxConsensusStatsBlocks", config.DefaultMaxConsensusStatsBlocks))
+	}
 	if conf.MaxIteratorResultItems <= 0 {
 		conf.MaxIteratorResultItems = config.DefaultMaxIteratorResultItems
 		log.Info("MaxIteratorResultItems is not set or wrong, setting default value", zap.Int("MaxIteratorResultItems", config.DefaultMaxIteratorResultItems))
@@ -305,6 +339,10 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		conf.MaxRequestHeaderBytes = config.DefaultMaxRequestHeaderBytes
 		log.Info("MaxRequestHeaderBytes is not set or wong, setting default value", zap.Int("MaxRequestHeaderBytes", config.DefaultMaxRequestHeaderBytes))
 	}
+	if conf.MaxSubscriptionReplayBlocks <= 0 {
+		conf.MaxSubscriptionReplayBlocks = config.DefaultMaxSubscriptionReplayBlocks
+		log.Info("MaxSubscriptionReplayBlocks is not set or wrong, setting default value", zap.Int("MaxSubscriptionReplayBlocks", config.DefaultMaxSubscriptionReplayBlocks))
+	}
 	if conf.MaxWebSocketClients == 0 {
 		conf.MaxWebSocketClients = defaultMaxWebSocketClients
 		log.Info("MaxWebSocketClients is not set or wrong, setting default value", zap.Int("MaxWebSocketClients", defaultMaxWebSocketClients))
@@ -352,6 +390,7 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		coreServer:       coreServer,
 		log:              log,
 		oracle:           oracleWrapped,
+		consensus:        new(atomic.Pointer[ConsensusStateProvider]),
 		shutdown:         make(chan struct{}),
 		errChan:          errChan,
 
@@ -489,6 +528,16 @@ func (s *Server) SetOracleHandler(orc OracleHandler) {
 	s.oracle.Store(orc)
 }
 
+// SetConsensusStateProvider allows to update consensus state provider used by
+// the Server. Nil disables `getconsensusstate` and `getconsensusstats` handling.
+func (s *Server) SetConsensusStateProvider(c ConsensusStateProvider) {
+	if c == nil {
+		s.consensus.Store(nil)
+		return
+	}
+	s.consensus.Store(&c)
+}
+
 func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Request) {
 	// Restrict request body before further processing.
 	httpRequest.Body = http.MaxBytesReader(w, httpRequest.Body, int64(s.config.MaxRequestBodyBytes))
@@ -561,6 +610,7 @@ func (s *Server) RegisterLocal(ctx context.Context, events chan<- neorpc.Notific
 	s.subsLock.Unlock()
 	go s.handleLocalNotifications(ctx, events, subChan, subscr)
 	return func(req *neorpc.Request) (*neorpc.Response, error) {
+		defer s.startReplays(subscr)
 		return s.handleInternal(req, subscr)
 	}
 }
@@ -748,6 +798,8 @@ requestloop:
 		case <-s.shutdown:
 			break requestloop
 		case resChan <- res:
+			// Writer handles the response before any subsequent event.
+			s.startReplays(subscr)
 		}
 	}
 	s.dropSubscriber(subscr)
@@ -761,7 +813,11 @@ func (s *Server) dropSubscriber(subscr *subscriber) {
 	s.subsLock.Unlock()
 	s.subsCounterLock.Lock()
 	for _, e := range subscr.feeds {
-		if e.event != neorpc.InvalidEventID {
+		switch {
+		case e.event == neorpc.InvalidEventID:
+		case e.stop != nil:
+			close(e.stop)
+		default:
 			s.unsubscribeFromChannel(e.event)
 		}
 	}
@@ -1566,6 +1622,9 @@ func (s *Server) getProof(ps params.Params) (any, *neorpc.Error) {
 	if err != nil {
 		return nil, neorpc.ErrInvalidParams
 	}
+	if respErr := s.checkStateRootRetention(root); respErr != nil {
+		return nil, respErr
+	}
 	cs, respErr := s.getHistoricalContractState(root, sc)
 	if respErr != nil {
 		return nil, respErr
@@ -1609,6 +1668,106 @@ func (s *Server) verifyProof(ps params.Params) (any, *neorpc.Error) {
 	return vp, nil
 }
 
+func (s *Server) getMultiProof(ps params.Params) (any, *neorpc.Error) {
+	root, respErr := s.getStateRootFromParam(ps.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	csHash, err := ps.Value(1).GetUint160FromHex()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid contract hash: %s", err))
+	}
+	keyParams, err := ps.Value(2).GetArray()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid keys: %s", err))
+	}
+	if len(keyParams) == 0 {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "no keys specified")
+	}
+	if len(keyParams) > s.config.MaxFindResultItems {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("too many keys: %d > %d", len(keyParams), s.config.MaxFindResultItems))
+	}
+	cs, respErr := s.getHistoricalContractState(root, csHash)
+	if respErr != nil {
+		return nil, respErr
+	}
+	res := &result.MultiProof{Keys: make([][]byte, len(keyParams))}
+	for i := range keyParams {
+		key, err := keyParams[i].GetBytesBase64()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid key #%d: %s", i, err))
+		}
+		res.Keys[i] = makeStorageKey(cs.ID, key)
+	}
+	res.Proof, err = s.chain.GetStateModule().GetStateMultiProof(root, res.Keys)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get proof: %s", err))
+	}
+	return res, nil
+}
+
+func (s *Server) getRangeProof(ps params.Params) (any, *neorpc.Error) {
+	root, respErr := s.getStateRootFromParam(ps.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	csHash, err := ps.Value(1).GetUint160FromHex()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid contract hash: %s", err))
+	}
+	prefix, err := ps.Value(2).GetBytesBase64()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid prefix: %s", err))
+	}
+	var (
+		start []byte
+		count = s.config.MaxFindResultItems
+	)
+	if len(ps) > 3 {
+		key, err := ps.Value(3).GetBytesBase64()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid key: %s", err))
+		}
+		if len(key) > 0 {
+			if !bytes.HasPrefix(key, prefix) {
+				return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "key doesn't match prefix")
+			}
+			start = key[len(prefix):]
+		}
+	}
+	if len(ps) > 4 {
+		count, err = ps.Value(4).GetInt()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid count: %s", err))
+		}
+		if count <= 0 {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "count must be positive")
+		}
+		if count > s.config.MaxFindResultItems {
+			count = s.config.MaxFindResultItems
+		}
+	}
+	cs, respErr := s.getHistoricalContractState(root, csHash)
+	if respErr != nil {
+		return nil, respErr
+	}
+	res := &result.RangeProof{
+		Prefix: makeStorageKey(cs.ID, prefix),
+		Start:  start,
+	}
+	kvs, proof, err := s.chain.GetStateModule().GetStateRangeProof(root, res.Prefix, start, count)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get range proof: %s", err))
+	}
+	res.Proof = proof
+	res.Truncated = len(kvs) == count
+	res.Results = make([]result.KeyValue, len(kvs))
+	for i, kv := range kvs {
+		res.Results[i] = result.KeyValue{Key: kv.Key, Value: kv.Value}
+	}
+	return res, nil
+}
+
 func (s *Server) getState(ps params.Params) (any, *neorpc.Error) {
 	root, respErr := s.getStateRootFromParam(ps.Value(0))
 	if respErr != nil {
@@ -1739,9 +1898,39 @@ func (s *Server) getStateRootFromParam(p *params.Param) (util.Uint256, *neorpc.E
 			return util.Uint256{}, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("state-based methods are not supported for old states: %s", errKeepOnlyLatestState))
 		}
 	}
+	if respErr := s.checkStateRootRetention(root); respErr != nil {
+		return util.Uint256{}, respErr
+	}
 	return root, nil
 }
 
+// checkStateRootRetention checks whether the state with the given root is
+// known, but is already outside of 'StateRetention' window. Unknown roots
+// are left to the caller to handle.
+func (s *Server) checkStateRootRetention(root util.Uint256) *neorpc.Error {
+	if s.chain.GetConfig().Ledger.StateRetention == 0 {
+		return nil
+	}
+	height, err := s.chain.GetStateModule().GetLatestStateHeight(root)
+	if err != nil {
+		return nil
+	}
+	return s.checkStateRetention(height)
+}
+
+// checkStateRetention checks whether the state for the given height is still
+// kept according to 'StateRetention' setting.
+func (s *Server) checkStateRetention(height uint32) *neorpc.Error {
+	var (
+		r    = s.chain.GetConfig().Ledger.StateRetention
+		curr = s.chain.BlockHeight()
+	)
+	if r != 0 && height <= curr && curr-height >= r {
+		return neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("state for height %d is outside of 'StateRetention' window (%d blocks, current height %d)", height, r, curr))
+	}
+	return nil
+}
+
 func (s *Server) findStorage(reqParams params.Params) (any, *neorpc.Error) {
 	id, prefix, start, take, respErr := s.getFindStorageParams(reqParams)
 	if respErr != nil {
@@ -1932,6 +2121,98 @@ func (s *Server) getStorageHistoric(ps params.Params) (any, *neorpc.Error) {
 	return v, nil
 }
 
+func (s *Server) getStorageDiff(ps params.Params) (any, *neorpc.Error) {
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'getstoragediff' is not supported: %s", errKeepOnlyLatestState))
+	}
+	if len(ps) < 3 {
+		return nil, neorpc.ErrInvalidParams
+	}
+	var roots [2]util.Uint256
+	for i := range roots {
+		height, respErr := s.stateHeightFromParam(ps.Value(i + 1))
+		if respErr != nil {
+			return nil, respErr
+		}
+		sr, err := s.chain.GetStateModule().GetStateRoot(height)
+		if err != nil {
+			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get stateroot for height %d: %s", height, err))
+		}
+		roots[i] = sr.Root
+	}
+	id, respErr := s.contractIDFromParam(ps.Value(0), roots[1])
+	if respErr != nil {
+		// The contract can already be destroyed.
+		id, respErr = s.contractIDFromParam(ps.Value(0), roots[0])
+		if respErr != nil {
+			return nil, respErr
+		}
+	}
+	var (
+		prefix []byte
+		start  []byte
+		err    error
+		count  = s.config.MaxFindResultItems
+	)
+	if len(ps) > 3 {
+		prefix, err = ps.Value(3).GetBytesBase64()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid prefix: %s", err))
+		}
+	}
+	if len(ps) > 4 {
+		start, err = ps.Value(4).GetBytesBase64()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid start: %s", err))
+		}
+		if len(start) > 0 {
+			if !bytes.HasPrefix(start, prefix) {
+				return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "start key doesn't match prefix")
+			}
+			start = start[len(prefix):]
+		} else {
+			start = nil
+		}
+	}
+	if len(ps) > 5 {
+		count, err = ps.Value(5).GetInt()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid count: %s", err))
+		}
+		if count <= 0 || count > s.config.MaxFindResultItems {
+			count = s.config.MaxFindResultItems
+		}
+	}
+	res := &result.StorageDiff{
+		FromRoot: roots[0],
+		ToRoot:   roots[1],
+		Changes:  make([]result.StorageChange, 0),
+	}
+	err = s.chain.GetStateModule().DiffStates(roots[0], roots[1], makeStorageKey(id, prefix), start, func(k, oldV, newV []byte) bool {
+		if len(res.Changes) == count {
+			res.Truncated = true
+			return false
+		}
+		c := result.StorageChange{
+			State:    "Changed",
+			Key:      k[4:], // Cut contract ID.
+			OldValue: oldV,
+			NewValue: newV,
+		}
+		if oldV == nil {
+			c.State = "Added"
+		} else if newV == nil {
+			c.State = "Deleted"
+		}
+		res.Changes = append(res.Changes, c)
+		return true
+	})
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to compare states: %s", err))
+	}
+	return res, nil
+}
+
 func (s *Server) getrawtransaction(reqParams params.Params) (any, *neorpc.Error) {
 	txHash, err := reqParams.Value(0).GetUint256()
 	if err != nil {
@@ -1985,6 +2266,194 @@ func (s *Server) getTransactionHeight(ps params.Params) (any, *neorpc.Error) {
 	return height, nil
 }
 
+// traceTransaction re-executes the persisted transaction and returns its
+// call-level trace.
+func (s *Server) traceTransaction(ps params.Params) (any, *neorpc.Error) {
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'tracetransaction' is not supported: %s", errKeepOnlyLatestState))
+	}
+	h, err := ps.Value(0).GetUint256()
+	if err != nil {
+		return nil, neorpc.ErrInvalidParams
+	}
+	_, height, err := s.chain.GetTransaction(h)
+	if err != nil || height == math.MaxUint32 {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+	tracer := new(callTracer)
+	aer, err := s.chain.ReplayTransaction(h, tracer)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to replay transaction: %s", err))
+	}
+	return &result.TransactionTrace{
+		TxHash:         h,
+		BlockIndex:     height,
+		VMState:        aer.VMState,
+		GasConsumed:    aer.GasConsumed,
+		FaultException: aer.FaultException,
+		Call:           tracer.root,
+	}, nil
+}
+
+// simulateTransactions implements the `simulatetransactions` RPC call.
+func (s *Server) simulateTransactions(ps params.Params) (any, *neorpc.Error) {
+	if len(ps) < 2 {
+		return nil, neorpc.ErrInvalidParams
+	}
+	height, respErr := s.stateHeightFromParam(ps.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState && height != s.chain.BlockHeight() {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'simulatetransactions' is not supported for old states: %s", errKeepOnlyLatestState))
+	}
+	txParams, err := ps[1].GetArray()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid transactions: %s", err))
+	}
+	if len(txParams) == 0 || len(txParams) > int(s.chain.GetConfig().MaxTransactionsPerBlock) {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid number of transactions: %d", len(txParams)))
+	}
+	sim := &core.Simulation{
+		Height:       height,
+		Transactions: make([]*transaction.Transaction, len(txParams)),
+	}
+	for i := range txParams {
+		sim.Transactions[i], err = s.simulatedTxFromParam(&txParams[i])
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid transaction %d: %s", i, err))
+		}
+	}
+	if len(ps) > 2 {
+		jd := json.NewDecoder(bytes.NewReader(ps[2].RawMessage))
+		jd.DisallowUnknownFields()
+		opts := new(neorpc.SimulationOptions)
+		if err := jd.Decode(opts); err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid options: %s", err))
+		}
+		if err := applySimulationOptions(sim, opts); err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid options: %s", err))
+		}
+	}
+	res, err := s.chain.Simulate(sim)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("simulation failed: %s", err))
+	}
+	out := &result.Simulation{
+		BlockIndex: res.Block.Index,
+		Timestamp:  res.Block.Timestamp,
+		Executions: make([]state.AppExecResult, len(res.Executions)),
+		Changes:    make([]result.ContractStorageChange, len(res.Changes)),
+	}
+	for i := range res.Executions {
+		out.Executions[i] = *res.Executions[i]
+	}
+	for i, c := range res.Changes {
+		out.Changes[i] = result.ContractStorageChange{
+			Contract: c.Hash,
+			StorageChange: result.StorageChange{
+				State:    "Changed",
+				Key:      c.Key,
+				OldValue: c.OldValue,
+				NewValue: c.NewValue,
+			},
+		}
+		if c.OldValue == nil {
+			out.Changes[i].State = "Added"
+		} else if c.NewValue == nil {
+			out.Changes[i].State = "Deleted"
+		}
+	}
+	return out, nil
+}
+
+// simulatedTxFromParam returns a transaction to be simulated. It's either
+// a base64-encoded serialized transaction (its system fee is used as a GAS
+// limit) or an object with the script and signers (the same as for
+// `invokescript` call, MaxGasInvoke is used as a GAS limit).
+func (s *Server) simulatedTxFromParam(p *params.Param) (*transaction.Transaction, error) {
+	if b, err := p.GetBytesBase64(); err == nil {
+		tx, err := transaction.NewTransactionFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		if tx.SystemFee > int64(s.config.MaxGasInvoke) {
+			return nil, fmt.Errorf("system fee %d exceeds MaxGasInvoke %d", tx.SystemFee, int64(s.config.MaxGasInvoke))
+		}
+		return tx, nil
+	}
+	var aux struct {
+		Script  []byte                     `json:"script"`
+		Signers []neorpc.SignerWithWitness `json:"signers"`
+	}
+	jd := json.NewDecoder(bytes.NewReader(p.RawMessage))
+	jd.DisallowUnknownFields()
+	if err := jd.Decode(&aux); err != nil {
+		return nil, err
+	}
+	if len(aux.Script) == 0 {
+		return nil, errors.New("empty script")
+	}
+	if len(aux.Signers) > transaction.MaxAttributes {
+		return nil, errors.New("too many signers")
+	}
+	tx := &transaction.Transaction{
+		Script:          aux.Script,
+		SystemFee:       int64(s.config.MaxGasInvoke),
+		ValidUntilBlock: s.chain.BlockHeight() + s.chain.GetConfig().MaxValidUntilBlockIncrement,
+	}
+	for _, sw := range aux.Signers {
+		tx.Signers = append(tx.Signers, sw.Signer)
+		tx.Scripts = append(tx.Scripts, sw.Witness)
+	}
+	if len(tx.Signers) == 0 {
+		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
+		tx.Scripts = []transaction.Witness{{}}
+	}
+	return tx, nil
+}
+
+// applySimulationOptions converts simulatetransactions options into the
+// simulation parameters.
+func applySimulationOptions(sim *core.Simulation, opts *neorpc.SimulationOptions) error {
+	sim.FullBlock = opts.FullBlock
+	sim.Timestamp = opts.Timestamp
+	for i, c := range opts.Contracts {
+		o := core.SimulatedContract{
+			Hash:     c.Hash,
+			Manifest: c.Manifest,
+		}
+		if c.NEF != nil {
+			nf, err := nef.FileFromBytes(c.NEF)
+			if err != nil {
+				return fmt.Errorf("contract %d: invalid NEF: %w", i, err)
+			}
+			o.NEF = &nf
+		}
+		sim.Contracts = append(sim.Contracts, o)
+	}
+	for _, si := range opts.Storage {
+		if len(si.Key) > limits.MaxStorageKeyLen || len(si.Value) > limits.MaxStorageValueLen {
+			return fmt.Errorf("storage item of contract %s is too big", si.Hash.StringLE())
+		}
+		sim.Storage = append(sim.Storage, core.SimulatedStorageItem{
+			Hash:  si.Hash,
+			Key:   si.Key,
+			Value: si.Value,
+		})
+	}
+	if len(opts.GASBalances) != 0 {
+		sim.GASBalances = make(map[util.Uint160]*big.Int, len(opts.GASBalances))
+		for _, b := range opts.GASBalances {
+			if b.Amount < 0 {
+				return fmt.Errorf("negative GAS balance for %s", b.Account.StringLE())
+			}
+			sim.GASBalances[b.Account] = big.NewInt(b.Amount)
+		}
+	}
+	return nil
+}
+
 // getContractState returns contract state (contract information, according to the contract script hash,
 // contract id or native contract name).
 func (s *Server) getContractState(reqParams params.Params) (any, *neorpc.Error) {
@@ -2134,6 +2603,207 @@ func (s *Server) getCommittee(_ params.Params) (any, *neorpc.Error) {
 	return keys, nil
 }
 
+// getConsensusState returns the state of the dBFT round followed by the node.
+func (s *Server) getConsensusState(_ params.Params) (any, *neorpc.Error) {
+	c := s.consensus.Load()
+	if c == nil {
+		return nil, neorpc.ErrConsensusDisabled
+	}
+	st := (*c).GetState()
+	res := result.ConsensusState{
+		WatchOnly:         st.WatchOnly,
+		Height:            st.Height,
+		View:              st.View,
+		Primary:           uint32(st.Primary),
+		PrimaryKey:        st.PrimaryKey,
+		Validators:        st.Validators,
+		RoundStarted:      uint64(st.RoundStarted.UnixMilli()),
+		ViewStarted:       uint64(st.ViewStarted.UnixMilli()),
+		ViewChanges:       st.ViewChanges,
+		ChangeViewReasons: make(map[string]uint64, len(st.ChangeViewReasons)),
+		PrimaryTimeouts:   st.PrimaryTimeouts,
+		RecoveryRequests:  st.RecoveryRequests,
+		RecoveryMessages:  st.RecoveryMessages,
+	}
+	for r, n := range st.ChangeViewReasons {
+		res.ChangeViewReasons[r.String()] = n
+	}
+	return res, nil
+}
+
+// getConsensusStats returns per-validator consensus statistics for the given
+// range of blocks.
+func (s *Server) getConsensusStats(reqParams params.Params) (any, *neorpc.Error) {
+	c := s.consensus.Load()
+	if c == nil {
+		return nil, neorpc.ErrConsensusDisabled
+	}
+	start, err := reqParams.Value(0).GetInt()
+	if err != nil || start < 0 {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid start height")
+	}
+	end := int(s.chain.BlockHeight())
+	if len(reqParams) > 1 {
+		end, err = reqParams[1].GetInt()
+		if err != nil || end < start {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid end height")
+		}
+	}
+	var verbose bool
+	if len(reqParams) > 2 {
+		verbose, err = reqParams[2].GetBoolean()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid verbose flag")
+		}
+	}
+	if end-start >= s.config.MaxConsensusStatsBlocks {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("too many blocks requested, max %d", s.config.MaxConsensusStatsBlocks))
+	}
+	if verbose && end-start >= maxConsensusRecords {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("too many records requested, max %d", maxConsensusRecords))
+	}
+
+	var (
+		res = result.ConsensusStats{
+			Start:      uint32(start),
+			End:        uint32(end),
+			Validators: []result.ConsensusValidatorStats{},
+		}
+		indexes     = make(map[string]int)
+		commitTimes []uint64
+	)
+	getStats := func(k *keys.PublicKey) *result.ConsensusValidatorStats {
+		key := string(k.Bytes())
+		i, ok := indexes[key]
+		if !ok {
+			i = len(res.Validators)
+			indexes[key] = i
+			res.Validators = append(res.Validators, result.ConsensusValidatorStats{PublicKey: k})
+			commitTimes = append(commitTimes, 0)
+		}
+		return &res.Validators[i]
+	}
+	err = (*c).ForEachRoundRecord(uint32(start), uint32(end), func(r *consensus.RoundRecord) bool {
+		res.Rounds++
+		res.ViewChanges += uint32(r.View)
+		for i := range r.Validators {
+			v := &r.Validators[i]
+			st := getStats(v.Key)
+			st.Rounds++
+			if v.Prepared {
+				st.Prepared++
+			}
+			if v.Committed {
+				st.Committed++
+				commitTimes[indexes[string(v.Key.Bytes())]] += uint64(v.CommittedAt)
+			}
+		}
+		if len(r.Validators) != 0 {
+			for view := 0; view <= int(r.View); view++ {
+				st := getStats(r.Validators[r.PrimaryOf(byte(view))].Key)
+				st.Primary++
+				if view < int(r.View) {
+					st.PrimaryFailures++
+				}
+			}
+		}
+		for _, cv := range r.ChangeViews {
+			if int(cv.Validator) < len(r.Validators) {
+				getStats(r.Validators[cv.Validator].Key).ChangeViews++
+			}
+		}
+		if verbose {
+			res.Records = append(res.Records, roundRecordToResult(r))
+		}
+		return true
+	})
+	if err != nil {
+		if errors.Is(err, consensus.ErrRoundsNotPersisted) {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrConsensusDisabled, err.Error())
+		}
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get round records: %s", err))
+	}
+	for i := range res.Validators {
+		if res.Validators[i].Committed != 0 {
+			res.Validators[i].AverageCommitTime = uint32(commitTimes[i] / uint64(res.Validators[i].Committed))
+		}
+	}
+	return res, nil
+}
+
+// getDBStats returns the number of keys and their sizes per key prefix and per
+// contract ID. It iterates over the whole DB, so it can take some time.
+func (s *Server) getDBStats(_ params.Params) (any, *neorpc.Error) {
+	stats, err := s.chain.GetStorageStats(context.Background())
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to collect DB statistics: %s", err))
+	}
+	res := result.DBStats{
+		Prefixes:  make([]result.DBPrefixStats, 0, len(stats.Prefixes)),
+		Contracts: make([]result.DBContractStats, 0, len(stats.Contracts)),
+	}
+	for p, ps := range stats.Prefixes {
+		res.Prefixes = append(res.Prefixes, result.DBPrefixStats{
+			Prefix:     byte(p),
+			Name:       p.String(),
+			Keys:       ps.Keys,
+			KeyBytes:   ps.KeyBytes,
+			ValueBytes: ps.ValueBytes,
+		})
+	}
+	sort.Slice(res.Prefixes, func(i, j int) bool { return res.Prefixes[i].Prefix < res.Prefixes[j].Prefix })
+	for id, cs := range stats.Contracts {
+		c := result.DBContractStats{
+			ID:         id,
+			Keys:       cs.Keys,
+			KeyBytes:   cs.KeyBytes,
+			ValueBytes: cs.ValueBytes,
+		}
+		if h, err := s.chain.GetContractScriptHash(id); err == nil {
+			c.Hash = &h
+		}
+		res.Contracts = append(res.Contracts, c)
+	}
+	sort.Slice(res.Contracts, func(i, j int) bool { return res.Contracts[i].ID < res.Contracts[j].ID })
+	return res, nil
+}
+
+func roundRecordToResult(r *consensus.RoundRecord) result.ConsensusRound {
+	optTime := func(t uint32, ok bool) *uint32 {
+		if !ok {
+			return nil
+		}
+		return &t
+	}
+	res := result.ConsensusRound{
+		Index:          r.Index,
+		View:           r.View,
+		Primary:        r.Primary,
+		Started:        r.Started,
+		Duration:       r.Duration,
+		PrepareRequest: optTime(r.PrepareRequestAt()),
+		Prepared:       optTime(r.PreparedAt()),
+		Committed:      optTime(r.CommittedAt()),
+		Validators:     make([]result.ConsensusRoundValidator, len(r.Validators)),
+	}
+	for i, v := range r.Validators {
+		res.Validators[i] = result.ConsensusRoundValidator{
+			PublicKey:   v.Key,
+			PreparedAt:  optTime(v.PreparedAt, v.Prepared),
+			CommittedAt: optTime(v.CommittedAt, v.Committed),
+		}
+	}
+	for _, cv := range r.ChangeViews {
+		res.ChangeViews = append(res.ChangeViews, result.ConsensusChangeView{
+			Validator: cv.Validator,
+			NewView:   cv.NewView,
+			Reason:    cv.Reason.String(),
+			At:        cv.At,
+		})
+	}
+	return res
+}
+
 // invokeFunction implements the `invokeFunction` RPC call.
 func (s *Server) invokeFunction(reqParams params.Params) (any, *neorpc.Error) {
 	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams)
@@ -2327,9 +2997,20 @@ func (s *Server) getHistoricParams(reqParams params.Params) (uint32, *neorpc.Err
 	if len(reqParams) < 1 {
 		return 0, neorpc.ErrInvalidParams
 	}
-	height, respErr := s.blockHeightFromParam(reqParams.Value(0))
+	height, respErr := s.stateHeightFromParam(reqParams.Value(0))
+	if respErr != nil {
+		return 0, respErr
+	}
+	return height + 1, nil
+}
+
+// stateHeightFromParam returns the height of the state specified by block
+// index, block hash or stateroot hash and checks that this state is still
+// available.
+func (s *Server) stateHeightFromParam(param *params.Param) (uint32, *neorpc.Error) {
+	height, respErr := s.blockHeightFromParam(param)
 	if respErr != nil {
-		hash, err := reqParams.Value(0).GetUint256()
+		hash, err := param.GetUint256()
 		if err != nil {
 			return 0, neorpc.NewInvalidParamsError(fmt.Sprintf("invalid block hash or index or stateroot hash: %s", err))
 		}
@@ -2344,7 +3025,10 @@ func (s *Server) getHistoricParams(reqParams params.Params) (uint32, *neorpc.Err
 			height = b.Index
 		}
 	}
-	return height + 1, nil
+	if respErr := s.checkStateRetention(height); respErr != nil {
+		return 0, respErr
+	}
+	return height, nil
 }
 
 func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool) (*interop.Context, *neorpc.Error) {
@@ -2733,9 +3417,9 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (any, *neor
 	if event == neorpc.NotaryRequestEventID && !s.chain.P2PSigExtensionsEnabled() {
 		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "P2PSigExtensions are disabled")
 	}
-	// Optional filter.
+	// Optional filter (can be null if start index is specified).
 	var filter neorpc.SubscriptionFilter
-	if p := reqParams.Value(1); p != nil {
+	if p := reqParams.Value(1); p != nil && !p.IsNull() {
 		param := *p
 		jd := json.NewDecoder(bytes.NewReader(param.RawMessage))
 		jd.DisallowUnknownFields()
@@ -2771,6 +3455,25 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (any, *neor
 			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 	}
+	// Optional start index for past events replaying.
+	var (
+		replay bool
+		start  uint32
+	)
+	if p := reqParams.Value(2); p != nil {
+		if event == neorpc.NotaryRequestEventID {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "start index is not supported for notary request events")
+		}
+		num, err := p.GetInt()
+		if err != nil || num < 0 || int64(num) > math.MaxUint32 {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid start index")
+		}
+		if height := int(s.chain.BlockHeight()); height-num >= s.config.MaxSubscriptionReplayBlocks {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("start index is too old, at most %d past blocks can be replayed", s.config.MaxSubscriptionReplayBlocks))
+		}
+		replay = true
+		start = uint32(num)
+	}
 
 	s.subsLock.Lock()
 	var id int
@@ -2785,20 +3488,185 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (any, *neor
 	}
 	sub.feeds[id].event = event
 	sub.feeds[id].filter = filter
+	var ready chan struct{}
+	if replay {
+		sub.feeds[id].stop = make(chan struct{})
+		ready = make(chan struct{})
+		sub.replays = append(sub.replays, ready)
+	}
+	f := sub.feeds[id]
 	s.subsLock.Unlock()
 
 	s.subsCounterLock.Lock()
 	select {
 	case <-s.shutdown:
 		s.subsCounterLock.Unlock()
+		s.releaseFeed(sub, id)
 		return nil, neorpc.NewInternalServerError("server is shutting down")
 	default:
 	}
-	s.subscribeToChannel(event)
+	if replay {
+		err = s.subscribeFrom(f, start, ready, sub.writer)
+	} else {
+		s.subscribeToChannel(event)
+	}
 	s.subsCounterLock.Unlock()
+	if err != nil {
+		s.releaseFeed(sub, id)
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+	}
 	return strconv.FormatInt(int64(id), 10), nil
 }
 
+// releaseFeed frees the feed slot of the subscriber that failed to subscribe.
+func (s *Server) releaseFeed(sub *subscriber, id int) {
+	s.subsLock.Lock()
+	sub.feeds[id] = feed{}
+	s.subsLock.Unlock()
+}
+
+// startReplays allows replaying feeds of the subscriber to send events, it's
+// called after subscription response is passed to the client.
+func (s *Server) startReplays(sub *subscriber) {
+	s.subsLock.Lock()
+	for _, ready := range sub.replays {
+		close(ready)
+	}
+	sub.replays = nil
+	s.subsLock.Unlock()
+}
+
+// subscribeFrom creates a dedicated chain subscription for the feed that
+// starts from the specified block index and sends matching events to the
+// subscriber (after ready is closed) until the feed's stop channel is closed
+// or the server is shut down. Writes to the subscriber are blocking, it only
+// delays replaying, new events are queued by the chain.
+func (s *Server) subscribeFrom(f feed, start uint32, ready <-chan struct{}, writer chan<- intEvent) error {
+	var (
+		recv  func() (any, bool)
+		unsub func()
+		err   error
+	)
+	switch f.event {
+	case neorpc.BlockEventID:
+		ch := make(chan *block.Block)
+		err = s.chain.SubscribeForBlocksFrom(ch, start)
+		recv = func() (any, bool) {
+			select {
+			case b := <-ch:
+				return b, true
+			case <-f.stop:
+			case <-s.shutdown:
+			}
+			return nil, false
+		}
+		unsub = func() { s.chain.UnsubscribeFromBlocks(ch) }
+	case neorpc.HeaderOfAddedBlockEventID:
+		ch := make(chan *block.Header)
+		err = s.chain.SubscribeForHeadersOfAddedBlocksFrom(ch, start)
+		recv = func() (any, bool) {
+			select {
+			case h := <-ch:
+				return h, true
+			case <-f.stop:
+			case <-s.shutdown:
+			}
+			return nil, false
+		}
+		unsub = func() { s.chain.UnsubscribeFromHeadersOfAddedBlocks(ch) }
+	case neorpc.TransactionEventID:
+		ch := make(chan *transaction.Transaction)
+		err = s.chain.SubscribeForTransactionsFrom(ch, start)
+		recv = func() (any, bool) {
+			select {
+			case tx := <-ch:
+				return tx, true
+			case <-f.stop:
+			case <-s.shutdown:
+			}
+			return nil, false
+		}
+		unsub = func() { s.chain.UnsubscribeFromTransactions(ch) }
+	case neorpc.NotificationEventID:
+		ch := make(chan *state.ContainedNotificationEvent)
+		err = s.chain.SubscribeForNotificationsFrom(ch, start)
+		recv = func() (any, bool) {
+			select {
+			case ntf := <-ch:
+				return ntf, true
+			case <-f.stop:
+			case <-s.shutdown:
+			}
+			return nil, false
+		}
+		unsub = func() { s.chain.UnsubscribeFromNotifications(ch) }
+	case neorpc.ExecutionEventID:
+		ch := make(chan *state.AppExecResult)
+		err = s.chain.SubscribeForExecutionsFrom(ch, start)
+		recv = func() (any, bool) {
+			select {
+			case aer := <-ch:
+				return aer, true
+			case <-f.stop:
+			case <-s.shutdown:
+			}
+			return nil, false
+		}
+		unsub = func() { s.chain.UnsubscribeFromExecutions(ch) }
+	default:
+		return fmt.Errorf("unsupported event %s", f.event)
+	}
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer unsub()
+		select {
+		case <-ready:
+		case <-f.stop:
+			return
+		case <-s.shutdown:
+			return
+		}
+		for {
+			ev, ok := recv()
+			if !ok {
+				return
+			}
+			resp := &neorpc.Notification{
+				JSONRPC: neorpc.JSONRPCVersion,
+				Event:   f.event,
+				Payload: []any{ev},
+			}
+			if !rpcevent.Matches(f, resp) {
+				continue
+			}
+			b, err := json.Marshal(resp)
+			if err != nil {
+				s.log.Error("failed to marshal notification",
+					zap.Error(err),
+					zap.Stringer("type", resp.Event))
+				return
+			}
+			msg, err := websocket.NewPreparedMessage(websocket.TextMessage, b)
+			if err != nil {
+				s.log.Error("failed to prepare notification message",
+					zap.Error(err),
+					zap.Stringer("type", resp.Event))
+				return
+			}
+			select {
+			case writer <- intEvent{msg, resp}:
+			case <-f.stop:
+				return
+			case <-s.shutdown:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
 // subscribeToChannel subscribes RPC server to appropriate chain events if
 // it's not yet subscribed for them. It's supposed to be called with s.subsCounterLock
 // taken by the caller.
@@ -2848,13 +3716,16 @@ func (s *Server) unsubscribe(reqParams params.Params, sub *subscriber) (any, *ne
 		s.subsLock.Unlock()
 		return nil, neorpc.ErrInvalidParams
 	}
-	event := sub.feeds[id].event
-	sub.feeds[id].event = neorpc.InvalidEventID
-	sub.feeds[id].filter = nil
+	f := sub.feeds[id]
+	sub.feeds[id] = feed{}
 	s.subsLock.Unlock()
 
+	if f.stop != nil {
+		close(f.stop)
+		return true, nil
+	}
 	s.subsCounterLock.Lock()
-	s.unsubscribeFromChannel(event)
+	s.unsubscribeFromChannel(f.event)
 	s.subsCounterLock.Unlock()
 	return true, nil
 }
@@ -2954,7 +3825,8 @@ chloop:
 				continue
 			}
 			for i := range sub.feeds {
-				if rpcevent.Matches(sub.feeds[i], &resp) {
+				// Replaying feeds get events via their own subscriptions.
+				if sub.feeds[i].stop == nil && rpcevent.Matches(sub.feeds[i], &resp) {
 					if msg == nil {
 						b, err = json.Marshal(resp)
 						if err != nil {
@@ -23,16 +23,20 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/dbft"
 	"github.com/epicchainlabs/epicchain-go/internal/random"
 	"github.com/epicchainlabs/epicchain-go/internal/testchain"
 	"github.com/epicchainlabs/epicchain-go/internal/testserdes"
 	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/consensus"
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/block"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/fee"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/interop/interopnames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/storage"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/storage/dboper"
 	"github.com/epicchainlabs/epicchain-go/pkg/core/transaction"
 	"github.com/epicchainlabs/epicchain-go/pkg/crypto/hash"
@@ -140,6 +144,30 @@ var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnsupportedStateCode,
 		},
 	},
+	"getstoragediff": {
+		{
+			name:    "unsupported state",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"getmultiproof": {
+		{
+			name:    "unsupported state",
+			params:  `["` + block20StateRootLE + `", "0xabcdef"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"getrangeproof": {
+		{
+			name:    "unsupported state",
+			params:  `["` + block20StateRootLE + `", "0xabcdef"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
 	"invokefunctionhistoric": {
 		{
 			name:    "unsupported state",
@@ -148,6 +176,14 @@ var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnsupportedStateCode,
 		},
 	},
+	"tracetransaction": {
+		{
+			name:    "unsupported state",
+			params:  `["` + deploymentTxHash + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
 }
 
 var rpcTestCases = map[string][]rpcTestCase{
@@ -715,6 +751,252 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.InvalidParamsCode,
 		},
 	},
+	"getstoragediff": {
+		{
+			name:   "positive, added",
+			params: fmt.Sprintf(`["%s", 1, 20]`, testContractHash),
+			result: func(_ *executor) any { return new(result.StorageDiff) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.StorageDiff)
+				require.True(t, ok)
+				require.False(t, actual.Truncated)
+				require.NotEmpty(t, actual.Changes)
+
+				sr, err := e.chain.GetStateModule().GetStateRoot(20)
+				require.NoError(t, err)
+				require.Equal(t, sr.Root, actual.ToRoot)
+				cHash, _ := util.Uint160DecodeStringLE(testContractHash)
+				cs := e.chain.GetContractState(cHash)
+				var found bool
+				for _, c := range actual.Changes {
+					require.Equal(t, "Added", c.State)
+					require.Nil(t, c.OldValue)
+					v, err := e.chain.GetStateModule().GetState(sr.Root, makeStorageKey(cs.ID, c.Key))
+					require.NoError(t, err)
+					require.Equal(t, v, c.NewValue)
+					if string(c.Key) == "aa10" {
+						found = true
+						require.Equal(t, []byte("v2"), c.NewValue)
+					}
+				}
+				require.True(t, found)
+			},
+		},
+		{
+			name:   "positive, deleted with prefix",
+			params: fmt.Sprintf(`["%s", 20, 1, "%s"]`, testContractHash, base64.StdEncoding.EncodeToString([]byte("aa1"))),
+			result: func(_ *executor) any { return new(result.StorageDiff) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.StorageDiff)
+				require.True(t, ok)
+				require.NotEmpty(t, actual.Changes)
+				for _, c := range actual.Changes {
+					require.Equal(t, "Deleted", c.State)
+					require.True(t, bytes.HasPrefix(c.Key, []byte("aa1")))
+					require.Nil(t, c.NewValue)
+				}
+			},
+		},
+		{
+			name:   "positive, same state",
+			params: fmt.Sprintf(`["%s", 20, "%s"]`, testContractHash, block20StateRootLE),
+			result: func(_ *executor) any { return new(result.StorageDiff) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.StorageDiff)
+				require.True(t, ok)
+				require.Empty(t, actual.Changes)
+				require.Equal(t, actual.FromRoot, actual.ToRoot)
+			},
+		},
+		{
+			name:   "positive, truncated",
+			params: fmt.Sprintf(`["%s", 1, 20, "", "", 1]`, testContractHash),
+			result: func(_ *executor) any { return new(result.StorageDiff) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.StorageDiff)
+				require.True(t, ok)
+				require.True(t, actual.Truncated)
+				require.Equal(t, 1, len(actual.Changes))
+			},
+		},
+		{
+			name:   "positive, start",
+			params: fmt.Sprintf(`["%s", 1, 20, "%s", "%s"]`, testContractHash, base64.StdEncoding.EncodeToString([]byte("aa")), base64.StdEncoding.EncodeToString([]byte("aa10"))),
+			result: func(_ *executor) any { return new(result.StorageDiff) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.StorageDiff)
+				require.True(t, ok)
+				for _, c := range actual.Changes {
+					require.True(t, bytes.HasPrefix(c.Key, []byte("aa")))
+					require.True(t, bytes.Compare(c.Key, []byte("aa10")) > 0)
+				}
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid contract",
+			params:  `["notahex", 1, 20]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown contract",
+			params:  `["0000000000000000000000000000000000000000", 1, 20]`,
+			fail:    true,
+			errCode: neorpc.ErrUnknownContractCode,
+		},
+		{
+			name:    "invalid height",
+			params:  fmt.Sprintf(`["%s", 1, "notahex"]`, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid prefix",
+			params:  fmt.Sprintf(`["%s", 1, 20, "notabase64$"]`, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "start doesn't match prefix",
+			params:  fmt.Sprintf(`["%s", 1, 20, "%s", "%s"]`, testContractHash, base64.StdEncoding.EncodeToString([]byte("aa")), base64.StdEncoding.EncodeToString([]byte("bb"))),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
+	"getmultiproof": {
+		{
+			name: "positive",
+			params: fmt.Sprintf(`["%s", "%s", ["%s", "%s"]]`, block20StateRootLE, testContractHash,
+				base64.StdEncoding.EncodeToString([]byte("testkey")), base64.StdEncoding.EncodeToString([]byte("missingkey"))),
+			result: func(_ *executor) any { return new(result.MultiProof) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.MultiProof)
+				require.True(t, ok)
+				root, err := util.Uint256DecodeStringLE(block20StateRootLE)
+				require.NoError(t, err)
+				cHash, _ := util.Uint160DecodeStringLE(testContractHash)
+				id := e.chain.GetContractState(cHash).ID
+				require.Equal(t, [][]byte{makeStorageKey(id, []byte("testkey")), makeStorageKey(id, []byte("missingkey"))}, actual.Keys)
+				vals, ok := mpt.VerifyMultiProof(root, actual.Keys, actual.Proof)
+				require.True(t, ok)
+				require.Equal(t, [][]byte{[]byte("newtestvalue"), nil}, vals)
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid contract",
+			params:  fmt.Sprintf(`["%s", "notahex", ["%s"]]`, block20StateRootLE, base64.StdEncoding.EncodeToString([]byte("testkey"))),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown contract",
+			params:  fmt.Sprintf(`["%s", "%s", ["%s"]]`, block20StateRootLE, util.Uint160{}.StringLE(), base64.StdEncoding.EncodeToString([]byte("testkey"))),
+			fail:    true,
+			errCode: neorpc.ErrUnknownContractCode,
+		},
+		{
+			name:    "no keys",
+			params:  fmt.Sprintf(`["%s", "%s", []]`, block20StateRootLE, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid key",
+			params:  fmt.Sprintf(`["%s", "%s", ["notabase64$"]]`, block20StateRootLE, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
+	"getrangeproof": {
+		{
+			name:   "positive",
+			params: fmt.Sprintf(`["%s", "%s", "%s"]`, block20StateRootLE, testContractHash, base64.StdEncoding.EncodeToString([]byte("aa"))),
+			result: func(_ *executor) any { return new(result.RangeProof) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.RangeProof)
+				require.True(t, ok)
+				require.False(t, actual.Truncated)
+				require.NotEmpty(t, actual.Results)
+				root, err := util.Uint256DecodeStringLE(block20StateRootLE)
+				require.NoError(t, err)
+				expected, err := e.chain.GetStateModule().FindStates(root, actual.Prefix, nil, 100)
+				require.NoError(t, err)
+				sort.Slice(expected, func(i, j int) bool { return bytes.Compare(expected[i].Key, expected[j].Key) < 0 })
+				kvs := make([]storage.KeyValue, len(actual.Results))
+				for i := range actual.Results {
+					kvs[i] = storage.KeyValue{Key: actual.Results[i].Key, Value: actual.Results[i].Value}
+				}
+				require.Equal(t, expected, kvs)
+				complete, ok := mpt.VerifyRangeProof(root, actual.Prefix, actual.Start, kvs, actual.Proof)
+				require.True(t, ok)
+				require.True(t, complete)
+			},
+		},
+		{
+			name: "positive, start and count",
+			params: fmt.Sprintf(`["%s", "%s", "%s", "%s", 1]`, block20StateRootLE, testContractHash,
+				base64.StdEncoding.EncodeToString([]byte("aa")), base64.StdEncoding.EncodeToString([]byte("aa10"))),
+			result: func(_ *executor) any { return new(result.RangeProof) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.RangeProof)
+				require.True(t, ok)
+				require.True(t, actual.Truncated)
+				require.Equal(t, 1, len(actual.Results))
+				require.Equal(t, []byte("10"), actual.Start)
+				root, err := util.Uint256DecodeStringLE(block20StateRootLE)
+				require.NoError(t, err)
+				expected, err := e.chain.GetStateModule().FindStates(root, actual.Prefix, []byte("10"), 1)
+				require.NoError(t, err)
+				require.Equal(t, expected[0].Key, actual.Results[0].Key)
+				kvs := []storage.KeyValue{{Key: actual.Results[0].Key, Value: actual.Results[0].Value}}
+				complete, ok := mpt.VerifyRangeProof(root, actual.Prefix, actual.Start, kvs, actual.Proof)
+				require.True(t, ok)
+				require.True(t, complete) // It's the last one.
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid prefix",
+			params:  fmt.Sprintf(`["%s", "%s", "notabase64$"]`, block20StateRootLE, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "start doesn't match prefix",
+			params:  fmt.Sprintf(`["%s", "%s", "%s", "%s"]`, block20StateRootLE, testContractHash, base64.StdEncoding.EncodeToString([]byte("aa")), base64.StdEncoding.EncodeToString([]byte("bb"))),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid count",
+			params:  fmt.Sprintf(`["%s", "%s", "", "", 0]`, block20StateRootLE, testContractHash),
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown contract",
+			params:  fmt.Sprintf(`["%s", "%s", ""]`, block20StateRootLE, util.Uint160{}.StringLE()),
+			fail:    true,
+			errCode: neorpc.ErrUnknownContractCode,
+		},
+	},
 	"findstorage": {
 		{
 			name:   "not truncated",
@@ -1179,6 +1461,58 @@ var rpcTestCases = map[string][]rpcTestCase{
 			},
 		},
 	},
+	"getconsensusstate": {
+		{
+			name:    "consensus disabled",
+			params:  "[]",
+			fail:    true,
+			errCode: neorpc.ErrConsensusDisabledCode,
+		},
+	},
+	"getconsensusstats": {
+		{
+			name:    "consensus disabled",
+			params:  "[1]",
+			fail:    true,
+			errCode: neorpc.ErrConsensusDisabledCode,
+		},
+	},
+	"getdbstats": {
+		{
+			params: "[]",
+			result: func(e *executor) any { return new(result.DBStats) },
+			check: func(t *testing.T, e *executor, acc any) {
+				res, ok := acc.(*result.DBStats)
+				require.True(t, ok)
+				require.True(t, sort.SliceIsSorted(res.Prefixes, func(i, j int) bool {
+					return res.Prefixes[i].Prefix < res.Prefixes[j].Prefix
+				}))
+				var storageKeys uint64
+				for _, p := range res.Prefixes {
+					require.NotZero(t, p.Keys)
+					if p.Prefix == byte(storage.STStorage) {
+						require.Equal(t, "STStorage", p.Name)
+						storageKeys = p.Keys
+					}
+				}
+				require.NotZero(t, storageKeys)
+
+				var (
+					contractKeys uint64
+					found        bool
+				)
+				for _, c := range res.Contracts {
+					contractKeys += c.Keys
+					if c.Hash != nil && c.Hash.StringLE() == testContractHash {
+						require.NotZero(t, c.Keys)
+						found = true
+					}
+				}
+				require.True(t, found)
+				require.Equal(t, storageKeys, contractKeys)
+			},
+		},
+	},
 	"getconnectioncount": {
 		{
 			params: "[]",
@@ -1236,6 +1570,103 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnknownTransactionCode,
 		},
 	},
+	"simulatetransactions": {
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "no transactions",
+			params:  `[1]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "empty transactions",
+			params:  `[1, []]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid height",
+			params:  `[100500, [{"script": "EQ=="}]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid transaction",
+			params:  `[1, ["notabase64"]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "empty script",
+			params:  `[1, [{"signers": []}]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown options",
+			params:  `[1, [{"script": "EQ=="}], {"unknown": true}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "negative balance",
+			params:  `[1, [{"script": "EQ=="}], {"gasbalances": [{"account": "` + util.Uint160{}.StringLE() + `", "amount": "-1"}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid NEF",
+			params:  `[1, [{"script": "EQ=="}], {"contracts": [{"hash": "` + testContractHash + `", "nef": "AQID"}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown contract",
+			params:  `[1, [{"script": "EQ=="}], {"storage": [{"hash": "` + util.Uint160{}.StringLE() + `", "key": "AQ==", "value": "AQ=="}]}]`,
+			fail:    true,
+			errCode: neorpc.InternalServerErrorCode,
+		},
+		{
+			name:   "positive",
+			params: `[1, [{"script": "EQ=="}]]`,
+			result: func(_ *executor) any { return new(result.Simulation) },
+			check: func(t *testing.T, e *executor, res any) {
+				actual, ok := res.(*result.Simulation)
+				require.True(t, ok)
+				require.Equal(t, uint32(2), actual.BlockIndex)
+				require.Equal(t, 1, len(actual.Executions))
+				require.Equal(t, vmstate.Halt, actual.Executions[0].VMState)
+				require.Equal(t, 1, len(actual.Executions[0].Stack))
+				require.Equal(t, int64(1), actual.Executions[0].Stack[0].Value().(*big.Int).Int64())
+				require.Empty(t, actual.Changes)
+			},
+		},
+	},
+	"tracetransaction": {
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid hash",
+			params:  `["notahex"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "missing hash",
+			params:  `["` + util.Uint256{}.String() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnknownTransactionCode,
+		},
+	},
 	"gettransactionheight": {
 		{
 			name:   "positive",
@@ -2278,6 +2709,119 @@ func TestSubmitOracle(t *testing.T) {
 	t.Run("Valid", runCase(t, false, 0, pubStr, `1`, txSigStr, msgSigStr))
 }
 
+type fakeRoundProvider struct {
+	records []*consensus.RoundRecord
+}
+
+func (f *fakeRoundProvider) GetState() consensus.State { return consensus.State{} }
+
+func (f *fakeRoundProvider) ForEachRoundRecord(start, end uint32, fn func(*consensus.RoundRecord) bool) error {
+	if f.records == nil {
+		return consensus.ErrRoundsNotPersisted
+	}
+	for _, r := range f.records {
+		if r.Index >= start && r.Index <= end && !fn(r) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestGetConsensusStats(t *testing.T) {
+	rpc := `{"jsonrpc": "2.0", "id": 1, "method": "getconsensusstats", "params": %s}`
+	_, rpcSrv, httpSrv := initClearServerWithServices(t, false, false, false)
+
+	pubs := make([]*keys.PublicKey, 4)
+	for i := range pubs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i] = priv.PublicKey()
+	}
+	newRecord := func(index uint32, view byte) *consensus.RoundRecord {
+		r := &consensus.RoundRecord{
+			Index:      index,
+			View:       view,
+			Validators: make([]consensus.ValidatorActivity, len(pubs)),
+		}
+		r.Primary = byte(r.PrimaryOf(view))
+		for i := range r.Validators {
+			r.Validators[i] = consensus.ValidatorActivity{
+				Key:         pubs[i],
+				Prepared:    true,
+				PreparedAt:  10,
+				Committed:   i != 3,
+				CommittedAt: 20 * uint32(i+1),
+			}
+		}
+		return r
+	}
+	r2 := newRecord(2, 1)
+	r2.ChangeViews = []consensus.ChangeViewRecord{{Validator: 1, NewView: 1, Reason: dbft.CVTimeout, At: 5}}
+	prov := &fakeRoundProvider{records: []*consensus.RoundRecord{newRecord(1, 0), r2, newRecord(3, 0)}}
+
+	call := func(t *testing.T, params string, fail bool, errCode int64) json.RawMessage {
+		body := doRPCCallOverHTTP(fmt.Sprintf(rpc, params), httpSrv.URL, t)
+		return checkErrGetResult(t, body, fail, errCode)
+	}
+
+	rpcSrv.SetConsensusStateProvider(&fakeRoundProvider{})
+	t.Run("not persisted", func(t *testing.T) {
+		call(t, `[1]`, true, neorpc.ErrConsensusDisabledCode)
+	})
+
+	rpcSrv.SetConsensusStateProvider(prov)
+	t.Run("invalid params", func(t *testing.T) {
+		call(t, `[]`, true, neorpc.InvalidParamsCode)
+		call(t, `["one"]`, true, neorpc.InvalidParamsCode)
+		call(t, `[2, 1]`, true, neorpc.InvalidParamsCode)
+		call(t, `[1, 2, null]`, true, neorpc.InvalidParamsCode)
+		call(t, fmt.Sprintf(`[0, %d, true]`, maxConsensusRecords), true, neorpc.InvalidParamsCode)
+		call(t, fmt.Sprintf(`[1, %d]`, rpcSrv.config.MaxConsensusStatsBlocks+1), true, neorpc.InvalidParamsCode)
+	})
+	t.Run("aggregated", func(t *testing.T) {
+		var res result.ConsensusStats
+		require.NoError(t, json.Unmarshal(call(t, `[2, 3]`, false, 0), &res))
+		require.Equal(t, uint32(2), res.Start)
+		require.Equal(t, uint32(3), res.End)
+		require.Equal(t, uint32(2), res.Rounds)
+		require.Equal(t, uint32(1), res.ViewChanges)
+		require.Nil(t, res.Records)
+		require.Equal(t, len(pubs), len(res.Validators))
+		for i, st := range res.Validators {
+			require.Equal(t, pubs[i], st.PublicKey)
+			require.Equal(t, uint32(2), st.Rounds)
+			require.Equal(t, uint32(2), st.Prepared)
+			if i == 3 {
+				require.Equal(t, uint32(0), st.Committed)
+				require.Equal(t, uint32(0), st.AverageCommitTime)
+			} else {
+				require.Equal(t, uint32(2), st.Committed)
+				require.Equal(t, 20*uint32(i+1), st.AverageCommitTime)
+			}
+		}
+		// Height 2 has primary 2 for view 0 and 1 for view 1, height 3 has primary 3.
+		require.Equal(t, uint32(1), res.Validators[1].Primary)
+		require.Equal(t, uint32(1), res.Validators[2].Primary)
+		require.Equal(t, uint32(1), res.Validators[2].PrimaryFailures)
+		require.Equal(t, uint32(1), res.Validators[3].Primary)
+		require.Equal(t, uint32(1), res.Validators[1].ChangeViews)
+	})
+	t.Run("verbose", func(t *testing.T) {
+		var res result.ConsensusStats
+		require.NoError(t, json.Unmarshal(call(t, `[2, 2, true]`, false, 0), &res))
+		require.Equal(t, 1, len(res.Records))
+		rec := res.Records[0]
+		require.Equal(t, uint32(2), rec.Index)
+		require.Equal(t, byte(1), rec.View)
+		require.Equal(t, byte(1), rec.Primary)
+		require.NotNil(t, rec.PrepareRequest)
+		require.Equal(t, uint32(10), *rec.Prepared)
+		require.Equal(t, uint32(60), *rec.Committed)
+		require.Nil(t, rec.Validators[3].CommittedAt)
+		require.Equal(t, []result.ConsensusChangeView{{Validator: 1, NewView: 1, Reason: "Timeout", At: 5}}, rec.ChangeViews)
+	})
+}
+
 func TestNotaryRequestRPC(t *testing.T) {
 	var notaryRequest1, notaryRequest2 *payload.P2PNotaryRequest
 	rpcSubmit := `{"jsonrpc": "2.0", "id": 1, "method": "submitnotaryrequest", "params": %s}`
@@ -2545,6 +3089,11 @@ func runTestCasesWithExecutor(t *testing.T, e *executor, rpcCall string, method
 // scratch here.
 func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []byte) {
 	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	// getdbstats only sees persisted data, so wait for test blocks to be persisted.
+	require.Eventually(t, func() bool {
+		stats, err := chain.GetStorageStats(context.Background())
+		return err == nil && stats.Prefixes[storage.STStorage].Keys != 0
+	}, 5*time.Second, 10*time.Millisecond)
 
 	e := &executor{chain: chain, httpSrv: httpSrv}
 	t.Run("single request", func(t *testing.T) {
@@ -3378,6 +3927,47 @@ func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []
 		for method, cases := range rpcFunctionsWithUnsupportedStatesTestCases {
 			runTestCasesWithExecutor(t, e, rpc, method, cases, doRPCCall, checkErrGetResult)
 		}
+
+		// Only the latest state can be used for simulation.
+		require.NoError(t, chain.AddBlock(getTestBlocks(t)[0]))
+		body := doRPCCall(fmt.Sprintf(rpc, "simulatetransactions", `[0, [{"script": "EQ=="}]]`), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnsupportedStateCode, "not supported for old states")
+		body = doRPCCall(fmt.Sprintf(rpc, "simulatetransactions", `[1, [{"script": "EQ=="}]]`), httpSrv.URL, t)
+		checkErrGetResult(t, body, false, 0)
+	})
+	t.Run("test functions with StateRetention", func(t *testing.T) {
+		const retention = 5
+		chain, _, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+			c.ApplicationConfiguration.Ledger.StateRetention = retention
+		})
+		for _, b := range getTestBlocks(t) {
+			require.NoError(t, chain.AddBlock(b))
+		}
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "%s", "params": %s}`
+		getRoot := func(h uint32) string {
+			sr, err := chain.GetStateModule().GetStateRoot(h)
+			require.NoError(t, err)
+			return sr.Root.StringLE()
+		}
+		neoHash, err := chain.GetNativeContractScriptHash(nativenames.Neo)
+		require.NoError(t, err)
+		oldH := chain.BlockHeight() - retention
+		newH := oldH + 1
+
+		body := doRPCCall(fmt.Sprintf(rpc, "invokefunctionhistoric", fmt.Sprintf(`[%d, "%s", "symbol", []]`, oldH, neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnsupportedStateCode, "outside of 'StateRetention' window")
+		body = doRPCCall(fmt.Sprintf(rpc, "invokefunctionhistoric", fmt.Sprintf(`[%d, "%s", "symbol", []]`, newH, neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, false, 0)
+
+		body = doRPCCall(fmt.Sprintf(rpc, "findstates", fmt.Sprintf(`["%s", "%s", ""]`, getRoot(oldH), neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnsupportedStateCode, "outside of 'StateRetention' window")
+		body = doRPCCall(fmt.Sprintf(rpc, "findstates", fmt.Sprintf(`["%s", "%s", ""]`, getRoot(newH), neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, false, 0)
+
+		body = doRPCCall(fmt.Sprintf(rpc, "getproof", fmt.Sprintf(`["%s", "%s", "Dg=="]`, getRoot(oldH), neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnsupportedStateCode, "outside of 'StateRetention' window")
+		body = doRPCCall(fmt.Sprintf(rpc, "getproof", fmt.Sprintf(`["%s", "%s", "Dg=="]`, getRoot(newH), neoHash.StringLE())), httpSrv.URL, t)
+		checkErrGetResult(t, body, false, 0)
 	})
 }
 
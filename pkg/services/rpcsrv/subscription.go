@@ -24,11 +24,17 @@ type (
 		// pointing to an EventID is an obvious overkill at the moment, but
 		// that's not for long.
 		feeds [maxFeeds]feed
+		// replays are readiness channels of replaying feeds that are
+		// waiting for the subscription response to be sent.
+		replays []chan struct{}
 	}
 	// feed stores subscriber's desired event ID with filter.
 	feed struct {
 		event  neorpc.EventID
 		filter neorpc.SubscriptionFilter
+		// stop is set for feeds replaying past events, they use dedicated
+		// chain subscriptions and stop them when this channel is closed.
+		stop chan struct{}
 	}
 )
 
@@ -14,7 +14,9 @@ import (
 	"github.com/epicchainlabs/epicchain-go/pkg/core"
 	"github.com/epicchainlabs/epicchain-go/pkg/encoding/address"
 	"github.com/epicchainlabs/epicchain-go/pkg/neorpc"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/trigger"
 	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/vmstate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -519,6 +521,71 @@ func TestHeaderOfAddedBlockSubscriptions(t *testing.T) {
 	callUnsubscribe(t, c, respMsgs, headerSubID)
 }
 
+func TestSubscriptionsFrom(t *testing.T) {
+	chain, _, c, respMsgs := initCleanServerAndWSClient(t)
+
+	blocks := getTestBlocks(t)
+	last := blocks[len(blocks)-1]
+	for _, b := range blocks[:len(blocks)-1] {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	t.Run("blocks", func(t *testing.T) {
+		subID := callSubscribe(t, c, respMsgs, `["block_added", null, 2]`)
+		for i := uint32(2); i <= chain.BlockHeight(); i++ {
+			resp := getNotification(t, respMsgs)
+			require.Equal(t, neorpc.BlockEventID, resp.Event)
+			rmap := resp.Payload[0].(map[string]any)
+			require.Equal(t, float64(i), rmap["index"].(float64))
+		}
+		callUnsubscribe(t, c, respMsgs, subID)
+	})
+	t.Run("filtered executions", func(t *testing.T) {
+		var (
+			expected []util.Uint256
+			actual   []util.Uint256
+		)
+		for i := uint32(5); i <= chain.BlockHeight(); i++ {
+			b, err := chain.GetBlock(chain.GetHeaderHash(i))
+			require.NoError(t, err)
+			expected = append(expected, b.Hash()) // OnPersist.
+			for _, tx := range b.Transactions {
+				aers, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+				require.NoError(t, err)
+				if aers[0].VMState == vmstate.Halt {
+					expected = append(expected, tx.Hash())
+				}
+			}
+			expected = append(expected, b.Hash()) // PostPersist.
+		}
+
+		subID := callSubscribe(t, c, respMsgs, `["transaction_executed", {"state":"HALT"}, 5]`)
+		for range expected {
+			resp := getNotification(t, respMsgs)
+			require.Equal(t, neorpc.ExecutionEventID, resp.Event)
+			rmap := resp.Payload[0].(map[string]any)
+			require.Equal(t, "HALT", rmap["vmstate"].(string))
+			h, err := util.Uint256DecodeStringLE(strings.TrimPrefix(rmap["container"].(string), "0x"))
+			require.NoError(t, err)
+			actual = append(actual, h)
+		}
+		require.Equal(t, expected, actual)
+		callUnsubscribe(t, c, respMsgs, subID)
+	})
+	t.Run("live", func(t *testing.T) {
+		subID := callSubscribe(t, c, respMsgs, fmt.Sprintf(`["header_of_added_block", null, %d]`, chain.BlockHeight()))
+		resp := getNotification(t, respMsgs)
+		require.Equal(t, neorpc.HeaderOfAddedBlockEventID, resp.Event)
+		require.Equal(t, float64(chain.BlockHeight()), resp.Payload[0].(map[string]any)["index"].(float64))
+
+		require.NoError(t, chain.AddBlock(last))
+		resp = getNotification(t, respMsgs)
+		require.Equal(t, neorpc.HeaderOfAddedBlockEventID, resp.Event)
+		require.Equal(t, float64(last.Index), resp.Payload[0].(map[string]any)["index"].(float64))
+		callUnsubscribe(t, c, respMsgs, subID)
+	})
+}
+
 func TestMaxSubscriptions(t *testing.T) {
 	var subIDs = make([]string, 0)
 	_, _, c, respMsgs := initCleanServerAndWSClient(t)
@@ -555,6 +622,10 @@ func TestBadSubUnsub(t *testing.T) {
 		"notification filter 2":  `{"jsonrpc": "2.0", "method": "subscribe", "params": ["notification_from_execution", "name"], "id": 1}`,
 		"execution filter 1":     `{"jsonrpc": "2.0", "method": "subscribe", "params": ["transaction_executed", "FAULT"], "id": 1}`,
 		"execution filter 2":     `{"jsonrpc": "2.0", "method": "subscribe", "params": ["transaction_executed", {"state": "STOP"}], "id": 1}`,
+		"negative start":         `{"jsonrpc": "2.0", "method": "subscribe", "params": ["block_added", null, -1], "id": 1}`,
+		"non-integer start":      `{"jsonrpc": "2.0", "method": "subscribe", "params": ["block_added", null, "one"], "id": 1}`,
+		"start above height":     `{"jsonrpc": "2.0", "method": "subscribe", "params": ["block_added", null, 100500], "id": 1}`,
+		"notary start":           `{"jsonrpc": "2.0", "method": "subscribe", "params": ["notary_request_event", null, 0], "id": 1}`,
 	}
 	var unsubCases = map[string]string{
 		"no params":         `{"jsonrpc": "2.0", "method": "unsubscribe", "params": [], "id": 1}`,
@@ -0,0 +1,133 @@
+package rpcsrv
+
+import (
+	"bytes"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/core/interop"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/stackitem"
+)
+
+// callTracer collects call-level transaction execution trace.
+type callTracer struct {
+	root   *result.CallTrace
+	frames []*traceFrame
+}
+
+// traceFrame is a call that is being executed.
+type traceFrame struct {
+	trace    *result.CallTrace
+	ctx      *vm.Context
+	gasStart int64
+	// needArgs is set until the first event after the call start, arguments
+	// are pushed onto the stack after the context is loaded.
+	needArgs bool
+}
+
+func (t *callTracer) top() *traceFrame {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	f := t.frames[len(t.frames)-1]
+	if f.needArgs {
+		f.needArgs = false
+		es := f.ctx.Estack()
+		for i := 0; i < es.Len(); i++ {
+			// Arguments can be changed by the following execution.
+			f.trace.Arguments = append(f.trace.Arguments, stackitem.DeepCopy(es.Peek(i).Item(), false))
+		}
+	}
+	return f
+}
+
+func (t *callTracer) OnEnter(ic *interop.Context, ctx *vm.Context) {
+	parent := t.top()
+	c := &result.CallTrace{
+		Hash:      ctx.ScriptHash(),
+		CallFlags: ctx.GetCallFlags(),
+	}
+	if ctx.IsDeployed() {
+		if cs, err := ic.GetContract(c.Hash); err == nil {
+			for _, m := range cs.Manifest.ABI.Methods {
+				if m.Offset == ctx.NextIP() {
+					c.Method = m.Name
+					break
+				}
+			}
+		}
+	}
+	if parent != nil {
+		parent.trace.Calls = append(parent.trace.Calls, c)
+	} else if t.root == nil {
+		t.root = c
+	}
+	t.frames = append(t.frames, &traceFrame{
+		trace:    c,
+		ctx:      ctx,
+		gasStart: ic.VM.GasConsumed(),
+		needArgs: true,
+	})
+}
+
+func (t *callTracer) OnExit(ic *interop.Context, _ *vm.Context, commit bool) {
+	f := t.top()
+	if f == nil {
+		return
+	}
+	t.frames = t.frames[:len(t.frames)-1]
+	f.trace.GasConsumed = ic.VM.GasConsumed() - f.gasStart
+	if commit {
+		f.trace.State = result.CallCompleted
+	} else {
+		f.trace.State = result.CallException
+		f.trace.IP = f.ctx.IP()
+	}
+}
+
+func (t *callTracer) OnGas(*interop.Context, int64) {
+	t.top()
+}
+
+func (t *callTracer) OnSyscall(_ *interop.Context, fn *interop.Function) {
+	if f := t.top(); f != nil {
+		f.trace.Syscalls = append(f.trace.Syscalls, fn.Name)
+	}
+}
+
+func (t *callTracer) OnStorageGet(_ *interop.Context, id int32, key []byte, value []byte) {
+	if f := t.top(); f != nil {
+		f.trace.Storage = append(f.trace.Storage, result.StorageAccess{
+			Op:    result.StorageGet,
+			ID:    id,
+			Key:   bytes.Clone(key),
+			Value: bytes.Clone(value),
+		})
+	}
+}
+
+func (t *callTracer) OnStoragePut(_ *interop.Context, id int32, key []byte, value []byte) {
+	if f := t.top(); f != nil {
+		op := result.StoragePut
+		if value == nil {
+			op = result.StorageDelete
+		}
+		f.trace.Storage = append(f.trace.Storage, result.StorageAccess{
+			Op:    op,
+			ID:    id,
+			Key:   bytes.Clone(key),
+			Value: bytes.Clone(value),
+		})
+	}
+}
+
+// OnExecuted completes the calls left after the VM fault.
+func (t *callTracer) OnExecuted(ic *interop.Context, _ *state.AppExecResult) {
+	for f := t.top(); f != nil; f = t.top() {
+		t.frames = t.frames[:len(t.frames)-1]
+		f.trace.GasConsumed = ic.VM.GasConsumed() - f.gasStart
+		f.trace.State = result.CallFault
+		f.trace.IP = f.ctx.IP()
+	}
+}
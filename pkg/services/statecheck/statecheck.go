@@ -0,0 +1,431 @@
+/*
+Package statecheck implements a service detecting local state divergence.
+
+Service periodically compares local state roots with the ones of reference RPC
+nodes. If they differ, it finds the first height with different state roots
+using binary search and then checks storage items changed locally at this
+height against the reference node state (via findstates RPC) to find the
+items that differ. Divergences found are logged and optionally written to
+JSON files. Both local node and reference nodes need to keep historical MPT
+states for storage items to be compared, otherwise only the height and roots
+are reported.
+*/
+package statecheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/rpcclient"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+type (
+	// Ledger is an interface to Blockchain sufficient for Service.
+	Ledger interface {
+		GetContractScriptHash(id int32) (util.Uint160, error)
+		GetStateModule() core.StateRoot
+	}
+
+	// RPC is a set of reference node RPC methods used by Service. It's
+	// implemented by rpcclient.Client.
+	RPC interface {
+		GetBlockCount() (uint32, error)
+		GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+		FindStates(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte,
+			start []byte, maxCount *int) (result.FindStates, error)
+	}
+
+	// Config contains Service parameters.
+	Config struct {
+		Log     *zap.Logger
+		MainCfg config.StateCheck
+		Chain   Ledger
+		// Clients are RPC clients for MainCfg.Nodes (in the same order),
+		// they're created from node addresses if not set.
+		Clients []RPC
+	}
+
+	// Service compares local state roots with the ones of reference nodes.
+	Service struct {
+		Config
+
+		lock  sync.Mutex
+		nodes []*node
+
+		started atomic.Bool
+		quit    chan struct{}
+		done    chan struct{}
+	}
+
+	node struct {
+		addr string
+		rpc  RPC
+		// good is the latest height with matching state roots.
+		good uint32
+		// last is the latest divergence found.
+		last *Divergence
+	}
+
+	// Divergence describes the state difference with the reference node.
+	Divergence struct {
+		// Node is the reference node address.
+		Node string `json:"node"`
+		// Height is the first height with different state roots.
+		Height     uint32       `json:"height"`
+		LocalRoot  util.Uint256 `json:"localroot"`
+		RemoteRoot util.Uint256 `json:"remoteroot"`
+		// Items contains storage items with different values.
+		Items []Item `json:"items"`
+	}
+
+	// Item is a storage item that has different values in local and remote
+	// states. Values are nil for missing items.
+	Item struct {
+		Contract util.Uint160 `json:"contract"`
+		Key      []byte       `json:"key"`
+		Local    []byte       `json:"local"`
+		Remote   []byte       `json:"remote"`
+	}
+)
+
+const (
+	defaultInterval       = time.Minute
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxItems       = 100
+)
+
+// New creates a new Service instance.
+func New(cfg Config) (*Service, error) {
+	if len(cfg.MainCfg.Nodes) == 0 {
+		return nil, errors.New("no reference nodes")
+	}
+	if cfg.Clients != nil && len(cfg.Clients) != len(cfg.MainCfg.Nodes) {
+		return nil, errors.New("clients don't match nodes")
+	}
+	if cfg.Log == nil {
+		cfg.Log = zap.NewNop()
+	}
+	if cfg.MainCfg.Interval <= 0 {
+		cfg.MainCfg.Interval = defaultInterval
+	}
+	if cfg.MainCfg.RequestTimeout <= 0 {
+		cfg.MainCfg.RequestTimeout = defaultRequestTimeout
+	}
+	if cfg.MainCfg.MaxItems <= 0 {
+		cfg.MainCfg.MaxItems = defaultMaxItems
+	}
+	s := &Service{
+		Config: cfg,
+		nodes:  make([]*node, len(cfg.MainCfg.Nodes)),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i, addr := range cfg.MainCfg.Nodes {
+		var c RPC
+		if cfg.Clients != nil {
+			c = cfg.Clients[i]
+		} else {
+			rc, err := rpcclient.New(context.Background(), addr, rpcclient.Options{
+				RequestTimeout: cfg.MainCfg.RequestTimeout,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create RPC client for %s: %w", addr, err)
+			}
+			c = rc
+		}
+		s.nodes[i] = &node{addr: addr, rpc: c}
+	}
+	return s, nil
+}
+
+// Name returns service name.
+func (s *Service) Name() string {
+	return "statecheck"
+}
+
+// Start runs the service in a separate goroutine. The service only starts
+// once, subsequent calls to Start are no-op.
+func (s *Service) Start() {
+	if !s.started.CompareAndSwap(false, true) {
+		return
+	}
+	s.Log.Info("starting state check service")
+	go s.run()
+}
+
+// Shutdown stops the service. It can only be called once, subsequent calls
+// to Shutdown on the same instance are no-op. The instance that was stopped
+// can not be started again by calling Start (use a new instance if needed).
+func (s *Service) Shutdown() {
+	if !s.started.CompareAndSwap(true, false) {
+		return
+	}
+	s.Log.Info("stopping state check service")
+	close(s.quit)
+	<-s.done
+	_ = s.Log.Sync()
+}
+
+func (s *Service) run() {
+	defer close(s.done)
+	t := time.NewTicker(s.MainCfg.Interval)
+	defer t.Stop()
+	for {
+		_, err := s.Check()
+		if err != nil {
+			s.Log.Warn("state check failed", zap.Error(err))
+		}
+		select {
+		case <-s.quit:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Check compares the local state with the state of every reference node at
+// the latest height both have and returns divergences found (one per node at
+// most). New divergences are logged and dumped to files if configured. Nodes
+// that can't be checked are skipped, the error returned joins errors for all
+// of them.
+func (s *Service) Check() ([]Divergence, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		res  []Divergence
+		errs []error
+	)
+	for i, n := range s.nodes {
+		d, isNew, err := s.checkNode(n)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.addr, err))
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		if isNew {
+			s.report(i, d)
+		}
+		res = append(res, *d)
+	}
+	return res, errors.Join(errs...)
+}
+
+// checkNode returns the divergence with the node (if any) and whether it's
+// found for the first time.
+func (s *Service) checkNode(n *node) (*Divergence, bool, error) {
+	count, err := n.rpc.GetBlockCount()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get block count: %w", err)
+	}
+	if count == 0 {
+		return nil, false, errors.New("empty chain")
+	}
+	h := s.Chain.GetStateModule().CurrentLocalHeight()
+	if count-1 < h {
+		h = count - 1
+	}
+	local, remote, err := s.getRoots(n, h)
+	if err != nil {
+		return nil, false, err
+	}
+	if local == remote {
+		n.good = h
+		n.last = nil
+		return nil, false, nil
+	}
+
+	bad, err := s.bisect(n, h)
+	if err != nil {
+		return nil, false, err
+	}
+	if n.last != nil && n.last.Height == bad {
+		return n.last, false, nil
+	}
+	local, remote, err = s.getRoots(n, bad)
+	if err != nil {
+		return nil, false, err
+	}
+	d := &Divergence{
+		Node:       n.addr,
+		Height:     bad,
+		LocalRoot:  local,
+		RemoteRoot: remote,
+	}
+	if bad != 0 {
+		d.Items, err = s.diffItems(n, bad, local, remote)
+		if err != nil {
+			s.Log.Warn("failed to compare storage items",
+				zap.String("node", n.addr),
+				zap.Uint32("height", bad),
+				zap.Error(err))
+		}
+	}
+	n.last = d
+	return d, true, nil
+}
+
+// getRoots returns local and remote state roots for the given height.
+func (s *Service) getRoots(n *node, h uint32) (util.Uint256, util.Uint256, error) {
+	lr, err := s.Chain.GetStateModule().GetStateRoot(h)
+	if err != nil {
+		return util.Uint256{}, util.Uint256{}, fmt.Errorf("failed to get local state root %d: %w", h, err)
+	}
+	rr, err := n.rpc.GetStateRootByHeight(h)
+	if err != nil {
+		return util.Uint256{}, util.Uint256{}, fmt.Errorf("failed to get remote state root %d: %w", h, err)
+	}
+	return lr.Root, rr.Root, nil
+}
+
+// bisect returns the first height with different state roots given that
+// they differ at bad.
+func (s *Service) bisect(n *node, bad uint32) (uint32, error) {
+	good := n.good
+	if good >= bad {
+		// Roots matched before, remote node has probably been resynchronized.
+		good = 0
+	}
+	if good == 0 {
+		local, remote, err := s.getRoots(n, 0)
+		if err != nil {
+			return 0, err
+		}
+		if local != remote {
+			return 0, nil
+		}
+	}
+	for bad-good > 1 {
+		next := good + (bad-good)/2
+		local, remote, err := s.getRoots(n, next)
+		if err != nil {
+			return 0, err
+		}
+		if local == remote {
+			good = next
+		} else {
+			bad = next
+		}
+	}
+	n.good = good
+	return bad, nil
+}
+
+// diffItems returns storage items changed locally at the specified height
+// that have different values in the remote state.
+func (s *Service) diffItems(n *node, h uint32, local, remote util.Uint256) ([]Item, error) {
+	sm := s.Chain.GetStateModule()
+	prev, err := sm.GetStateRoot(h - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local state root %d: %w", h-1, err)
+	}
+	type change struct {
+		key   []byte
+		value []byte
+	}
+	var changes []change
+	err = sm.DiffStates(prev.Root, local, nil, nil, func(k, _, newV []byte) bool {
+		changes = append(changes, change{key: bytes.Clone(k), value: bytes.Clone(newV)})
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local changes: %w", err)
+	}
+
+	var (
+		items  []Item
+		hashes = make(map[int32]util.Uint160)
+		one    = 1
+	)
+	for _, c := range changes {
+		if len(c.key) < 4 {
+			continue
+		}
+		id := int32(binary.LittleEndian.Uint32(c.key))
+		ch, ok := hashes[id]
+		if !ok {
+			ch, err = s.Chain.GetContractScriptHash(id)
+			if err != nil {
+				return items, fmt.Errorf("failed to get contract %d hash: %w", id, err)
+			}
+			hashes[id] = ch
+		}
+		key := c.key[4:]
+		res, err := n.rpc.FindStates(remote, ch, key, nil, &one)
+		if err != nil {
+			return items, fmt.Errorf("failed to get remote state of %s key %s: %w", ch.StringLE(), hex.EncodeToString(key), err)
+		}
+		var rv []byte
+		if len(res.Results) != 0 && bytes.Equal(res.Results[0].Key, key) {
+			rv = res.Results[0].Value
+		}
+		if bytes.Equal(rv, c.value) && (rv == nil) == (c.value == nil) {
+			continue
+		}
+		items = append(items, Item{
+			Contract: ch,
+			Key:      key,
+			Local:    c.value,
+			Remote:   rv,
+		})
+		if len(items) == s.MainCfg.MaxItems {
+			break
+		}
+	}
+	return items, nil
+}
+
+// report logs the divergence found with the i-th node and writes it to the
+// file if configured.
+func (s *Service) report(i int, d *Divergence) {
+	s.Log.Error("state divergence found",
+		zap.String("node", d.Node),
+		zap.Uint32("height", d.Height),
+		zap.String("local", d.LocalRoot.StringLE()),
+		zap.String("remote", d.RemoteRoot.StringLE()),
+		zap.Int("items", len(d.Items)))
+	if s.MainCfg.DumpPath == "" {
+		for _, it := range d.Items {
+			s.Log.Error("storage item differs",
+				zap.String("contract", it.Contract.StringLE()),
+				zap.String("key", hex.EncodeToString(it.Key)),
+				zap.String("local", hex.EncodeToString(it.Local)),
+				zap.String("remote", hex.EncodeToString(it.Remote)))
+		}
+		return
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(s.MainCfg.DumpPath, os.ModePerm)
+	}
+	if err == nil {
+		err = os.WriteFile(DumpFileName(s.MainCfg.DumpPath, i, d.Height), data, 0644)
+	}
+	if err != nil {
+		s.Log.Error("failed to dump state divergence", zap.Error(err))
+	}
+}
+
+// DumpFileName returns the name of the file the divergence found at the
+// specified height with the i-th configured node is written to.
+func DumpFileName(dir string, i int, height uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("divergence-%d-%d.json", i, height))
+}
@@ -0,0 +1,140 @@
+package statecheck_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/internal/random"
+	"github.com/epicchainlabs/epicchain-go/pkg/config"
+	"github.com/epicchainlabs/epicchain-go/pkg/core"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/mpt"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/native/nativenames"
+	"github.com/epicchainlabs/epicchain-go/pkg/core/state"
+	"github.com/epicchainlabs/epicchain-go/pkg/neorpc/result"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/epicchainlabs/epicchain-go/pkg/rpcclient"
+	"github.com/epicchainlabs/epicchain-go/pkg/services/statecheck"
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// chainRPC implements statecheck.RPC over the Blockchain.
+type chainRPC struct {
+	bc *core.Blockchain
+}
+
+func (c chainRPC) GetBlockCount() (uint32, error) {
+	return c.bc.BlockHeight() + 1, nil
+}
+
+func (c chainRPC) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	return c.bc.GetStateModule().GetStateRoot(height)
+}
+
+func (c chainRPC) FindStates(root util.Uint256, h util.Uint160, prefix []byte, start []byte, maxCount *int) (result.FindStates, error) {
+	cs := c.bc.GetContractState(h)
+	if cs == nil {
+		return result.FindStates{}, errors.New("unknown contract")
+	}
+	id := make([]byte, 4)
+	binary.LittleEndian.PutUint32(id, uint32(cs.ID))
+	kvs, err := c.bc.GetStateModule().FindStates(root, append(id, prefix...), start, *maxCount)
+	if err != nil && !errors.Is(err, mpt.ErrNotFound) {
+		return result.FindStates{}, err
+	}
+	var res result.FindStates
+	for _, kv := range kvs {
+		res.Results = append(res.Results, result.KeyValue{Key: kv.Key[4:], Value: kv.Value})
+	}
+	return res, nil
+}
+
+var _ statecheck.RPC = (*rpcclient.Client)(nil)
+
+func TestService(t *testing.T) {
+	bcA, validators, committee := chain.NewMulti(t)
+	bcB, _, _ := chain.NewMulti(t)
+	eA := neotest.NewExecutor(t, bcA, validators, committee)
+	eB := neotest.NewExecutor(t, bcB, validators, committee)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bcB.AddBlock(eA.AddNewBlock(t)))
+	}
+
+	dump := t.TempDir()
+	s, err := statecheck.New(statecheck.Config{
+		Log: zaptest.NewLogger(t),
+		MainCfg: config.StateCheck{
+			Enabled:  true,
+			Nodes:    []string{"http://localhost:1"},
+			DumpPath: dump,
+		},
+		Chain:   bcA,
+		Clients: []statecheck.RPC{chainRPC{bcB}},
+	})
+	require.NoError(t, err)
+
+	ds, err := s.Check()
+	require.NoError(t, err)
+	require.Empty(t, ds)
+
+	// Different transfers at height 4.
+	gasHash := eA.NativeHash(t, nativenames.Gas)
+	from := eA.Validator.ScriptHash()
+	to := random.Uint160()
+	eA.ValidatorInvoker(gasHash).Invoke(t, true, "transfer", from, to, 100, nil)
+	eB.ValidatorInvoker(gasHash).Invoke(t, true, "transfer", from, random.Uint160(), 200, nil)
+	for i := 0; i < 3; i++ {
+		eA.AddNewBlock(t)
+		eB.AddNewBlock(t)
+	}
+
+	ds, err = s.Check()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(ds))
+	d := ds[0]
+	require.Equal(t, uint32(4), d.Height)
+	lr, err := bcA.GetStateModule().GetStateRoot(4)
+	require.NoError(t, err)
+	require.Equal(t, lr.Root, d.LocalRoot)
+	rr, err := bcB.GetStateModule().GetStateRoot(4)
+	require.NoError(t, err)
+	require.Equal(t, rr.Root, d.RemoteRoot)
+
+	var found bool
+	for _, it := range d.Items {
+		require.NotEqual(t, it.Local, it.Remote)
+		if it.Contract == gasHash && len(it.Key) == 21 && it.Key[0] == 20 {
+			if acc, err := util.Uint160DecodeBytesBE(it.Key[1:]); err == nil && acc == to {
+				require.NotNil(t, it.Local)
+				require.Nil(t, it.Remote)
+				found = true
+			}
+		}
+	}
+	require.True(t, found)
+
+	data, err := os.ReadFile(statecheck.DumpFileName(dump, 0, 4))
+	require.NoError(t, err)
+	var dumped statecheck.Divergence
+	require.NoError(t, json.Unmarshal(data, &dumped))
+	require.Equal(t, d, dumped)
+
+	// The same divergence is returned, but not dumped again.
+	require.NoError(t, os.Remove(statecheck.DumpFileName(dump, 0, 4)))
+	eA.AddNewBlock(t)
+	ds, err = s.Check()
+	require.NoError(t, err)
+	require.Equal(t, []statecheck.Divergence{d}, ds)
+	_, err = os.Stat(statecheck.DumpFileName(dump, 0, 4))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	t.Run("bad config", func(t *testing.T) {
+		_, err := statecheck.New(statecheck.Config{Chain: bcA})
+		require.Error(t, err)
+	})
+}
@@ -1,6 +1,6 @@
 // Package zkpbinding contains a set of helper functions aimed to generate and
 // interact with Verifier smart contract written in Go and using Groth-16 proving
-// system over BLS12-381 elliptic curve to verify proofs. Package zkpbinding
+// system over BLS12-381 or BN254 elliptic curve to verify proofs. Package zkpbinding
 // provides the Veifier contract generation functionality itself as far as a
 // helper that converts groth16.Proof to the Verifier-specific set of arguments.
 //
@@ -16,10 +16,13 @@ import (
 	"io"
 	"text/template"
 
-	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	bn254curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend/groth16"
 	curve "github.com/consensys/gnark/backend/groth16/bls12-381"
+	bn254 "github.com/consensys/gnark/backend/groth16/bn254"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/binding"
 	"github.com/epicchainlabs/epicchain-go/pkg/util/slice"
@@ -27,8 +30,9 @@ import (
 
 // Config represents a configuration for Verifier Go smart contract generator.
 type Config struct {
-	// VerifyingKey must be a Groth-16 BLS12-381 specific verifier key,
+	// VerifyingKey must be a Groth-16 BLS12-381 or BN254 specific verifier key,
 	// parameters of which will be used to generate Verifier Neo smart contract.
+	// BN254 verifiers use CryptoLib methods available since Domovoi hardfork.
 	VerifyingKey groth16.VerifyingKey
 	// Output is a writer for the resulting Verifier Go smart contract, it must
 	// not be nil.
@@ -45,13 +49,21 @@ type Config struct {
 	// smart contract needed to compile it. It may be nil if the go.sum file
 	// generation should be omitted.
 	GosumOutput io.Writer
+	// InteropPath is a path to the NeoGo interop package directory (pkg/interop
+	// of NeoGo repository) to be used by the Verifier Go smart contract instead
+	// of the released interop package version. If set, the generated go.mod file
+	// contains `replace` directive pointing to this path (relative paths are
+	// resolved against the go.mod file directory) and the generated go.sum file
+	// is empty. It must be set to generate go.mod and go.sum files for BN254
+	// verifiers, see GenerateVerifier.
+	InteropPath string
 }
 
 // A set of Verifier smart contract template related constants.
 const (
 	// goVerificationTmpl is a verification smart contract template. It contains
 	// a single `verifyProof` method that accepts a proof represented as three
-	// BLS12-381 curve points and public information required for verification
+	// BLS12-381 or BN254 curve points and public information required for verification
 	// represented as a list of serialized 32-bytes field elements in the LE form.
 	// The boolean result of `verifyProof` is either `true` (if the proof is
 	// valid) or `false` (if the proof is invalid). The smart contract generated
@@ -62,10 +74,10 @@ const (
 	// contract to be generated and deployed to the chain.
 	goVerificationTmpl = `//Code generated by neo-go zkpbinding.GenerateVerifier; DO NOT EDIT.
 
-// Package main contains verification smart contract that uses Neo BLS12-381
+// Package main contains verification smart contract that uses Neo {{ .CurveName }}
 // curves interoperability functionality to verify provided proof against provided
 // public input. The contract contains a single 'verifyProof' method that accepts
-// a proof represented as three BLS12-381 curve points and public witnesses
+// a proof represented as three {{ .CurveName }} curve points and public witnesses
 // required for verification represented as a list of serialized 32-bytes field
 // elements in the LE form. This contract is circuit-specific and can not be used
 // to verify other circuits.
@@ -102,7 +114,7 @@ var (
 )
 
 // VerifyProof verifies the given proof represented as three serialized compressed
-// BLS12-381 points against the public information represented as a list of
+// {{ .CurveName }} points against the public information represented as a list of
 // serialized 32-bytes field elements in the LE form. Verification process
 // follows the Groth-16 proving system and is taken from the
 // https://github.com/neo-project/neo/issues/2647#issuecomment-1002893109 without
@@ -110,20 +122,20 @@ var (
 //
 //	A * B = alpha * beta + sum(pub_input[i] * (beta * u_i(x) + alpha * v_i(x) + w_i(x)) / gamma) * gamma + C * delta
 func VerifyProof(a []byte, b []byte, c []byte, publicInput [][]byte) bool {
-	alphaPoint := crypto.Bls12381Deserialize(alpha)
-	betaPoint := crypto.Bls12381Deserialize(beta)
-	gammaPoint := crypto.Bls12381Deserialize(gamma)
-	deltaPoint := crypto.Bls12381Deserialize(delta)
+	alphaPoint := crypto.{{ .Curve }}Deserialize(alpha)
+	betaPoint := crypto.{{ .Curve }}Deserialize(beta)
+	gammaPoint := crypto.{{ .Curve }}Deserialize(gamma)
+	deltaPoint := crypto.{{ .Curve }}Deserialize(delta)
 
-	aPoint := crypto.Bls12381Deserialize(a)
-	bPoint := crypto.Bls12381Deserialize(b)
-	cPoint := crypto.Bls12381Deserialize(c)
+	aPoint := crypto.{{ .Curve }}Deserialize(a)
+	bPoint := crypto.{{ .Curve }}Deserialize(b)
+	cPoint := crypto.{{ .Curve }}Deserialize(c)
 
 	// Equation left1: A*B
-	lt := crypto.Bls12381Pairing(aPoint, bPoint)
+	lt := crypto.{{ .Curve }}Pairing(aPoint, bPoint)
 
 	// Equation right1: alpha*beta
-	rt1 := crypto.Bls12381Pairing(alphaPoint, betaPoint)
+	rt1 := crypto.{{ .Curve }}Pairing(alphaPoint, betaPoint)
 
 	// Equation right2: sum(pub_input[i]*(beta*u_i(x)+alpha*v_i(x)+w_i(x))/gamma)*gamma
 	inputlen := len(publicInput)
@@ -132,24 +144,24 @@ func VerifyProof(a []byte, b []byte, c []byte, publicInput [][]byte) bool {
 	if iclen != inputlen+1 {
 		panic("error: inputlen or iclen")
 	}
-	icPoints := make([]crypto.Bls12381Point, iclen)
+	icPoints := make([]crypto.{{ .Curve }}Point, iclen)
 	for i := 0; i < iclen; i++ {
-		icPoints[i] = crypto.Bls12381Deserialize(ic[i])
+		icPoints[i] = crypto.{{ .Curve }}Deserialize(ic[i])
 	}
 	acc := icPoints[0]
 	for i := 0; i < inputlen; i++ {
 		scalar := publicInput[i] // 32-bytes LE field element.
-		temp := crypto.Bls12381Mul(icPoints[i+1], scalar, false)
-		acc = crypto.Bls12381Add(acc, temp)
+		temp := crypto.{{ .Curve }}Mul(icPoints[i+1], scalar, false)
+		acc = crypto.{{ .Curve }}Add(acc, temp)
 	}
-	rt2 := crypto.Bls12381Pairing(acc, gammaPoint)
+	rt2 := crypto.{{ .Curve }}Pairing(acc, gammaPoint)
 
 	// Equation right3: C*delta
-	rt3 := crypto.Bls12381Pairing(cPoint, deltaPoint)
+	rt3 := crypto.{{ .Curve }}Pairing(cPoint, deltaPoint)
 
 	// Check equality.
-	t1 := crypto.Bls12381Add(rt1, rt2)
-	t2 := crypto.Bls12381Add(t1, rt3)
+	t1 := crypto.{{ .Curve }}Add(rt1, rt2)
+	t2 := crypto.{{ .Curve }}Add(t1, rt3)
 
 	return util.Equals(lt, t2)
 }
@@ -168,6 +180,17 @@ supportedstandards: []`
 go 1.20
 
 require github.com/epicchainlabs/epicchain-go/pkg/interop v0.0.0-20231004150345-8849ccde2524
+`
+
+	// verifyGomodReplace is a go.mod file for smart contract compilation with
+	// the interop package located at the given path.
+	verifyGomodReplace = `module verify
+
+go 1.20
+
+require github.com/epicchainlabs/epicchain-go/pkg/interop v0.0.0
+
+replace github.com/epicchainlabs/epicchain-go/pkg/interop => %q
 `
 
 	// verifyGosum is a standard go.sum file needed for contract compilation.
@@ -187,6 +210,11 @@ type VerifyProofArgs struct {
 
 // tmplParams is a set of parameters used by verification contract template.
 type tmplParams struct {
+	// Curve is a curve-specific prefix of CryptoLib interop wrappers.
+	Curve string
+	// CurveName is a human-readable curve name used in comments.
+	CurveName string
+
 	Alpha []byte
 	Beta  []byte
 	Gamma []byte
@@ -196,8 +224,9 @@ type tmplParams struct {
 
 // GenerateVerifier generates a Verifier smart contract written in Go for Neo
 // blockchain. The contract contains a single `verifyProof` method that accepts
-// a proof represented as three BLS12-381 curve points and public witnesses
-// required for verification represented as a list of serialized 32-bytes field
+// a proof represented as three BLS12-381 or BN254 curve points (depending on the
+// verifying key curve) and public witnesses required for verification
+// represented as a list of serialized 32-bytes field
 // elements in the LE form. The boolean result of `verifyProof` is either `true`
 // (if the proof is valid) or `false` (if the proof is invalid). The smart
 // contract generated from this template can be immediately compiled without
@@ -208,25 +237,33 @@ type tmplParams struct {
 //
 // GenerateVerifier also generates a proper contract YAML configuration file,
 // go.mod and go.sum files if the corresponding writers are provided via cfg.
+// By default, the generated go.mod file requires the released interop package
+// version that has no BN254 CryptoLib wrappers, so cfg.InteropPath must be set
+// to generate go.mod and go.sum files for BN254 verifiers (an error is returned
+// otherwise). It should point to the interop package that has BN254 support
+// (like the one from the same repository revision this package belongs to).
 func GenerateVerifier(cfg Config) error {
 	if cfg.VerifyingKey == nil {
 		return fmt.Errorf("nil verifying key")
 	}
-	if cfg.VerifyingKey.CurveID() != ecc.BLS12_381 {
-		return fmt.Errorf("unexpected elliptic curve: %s", cfg.VerifyingKey.CurveID())
-	}
 
 	// Fetch the contract's public verification parameters. We can directly access
 	// the VerifyingKey elements since gnark v0.9.0.
-	vk := cfg.VerifyingKey.(*curve.VerifyingKey)
-	alphaG1 := vk.G1.Alpha.Bytes()
-	betaG2 := vk.G2.Beta.Bytes()
-	gammaG2 := vk.G2.Gamma.Bytes()
-	deltaG2 := vk.G2.Delta.Bytes()
-	kvks := make([][]byte, len(vk.G1.K))
-	for i := range kvks {
-		arr := vk.G1.K[i].Bytes()
-		kvks[i] = arr[:]
+	var params tmplParams
+	switch vk := cfg.VerifyingKey.(type) {
+	case *curve.VerifyingKey:
+		params = newTmplParams("Bls12381", "BLS12-381", &vk.G1.Alpha, &vk.G2.Beta, &vk.G2.Gamma, &vk.G2.Delta, vk.G1.K,
+			func(p *bls12381.G1Affine) []byte { b := p.Bytes(); return b[:] },
+			func(p *bls12381.G2Affine) []byte { b := p.Bytes(); return b[:] })
+	case *bn254.VerifyingKey:
+		if cfg.InteropPath == "" && (cfg.GomodOutput != nil || cfg.GosumOutput != nil) {
+			return errors.New("go.mod and go.sum generation for BN254 verifiers requires InteropPath: released interop package version has no BN254 support")
+		}
+		params = newTmplParams("Bn254", "BN254", &vk.G1.Alpha, &vk.G2.Beta, &vk.G2.Gamma, &vk.G2.Delta, vk.G1.K,
+			func(p *bn254curve.G1Affine) []byte { b := p.Bytes(); return b[:] },
+			func(p *bn254curve.G2Affine) []byte { b := p.Bytes(); return b[:] })
+	default:
+		return fmt.Errorf("unexpected elliptic curve: %s", cfg.VerifyingKey.CurveID())
 	}
 
 	// Generate verification contract from the template using the retrieved
@@ -235,13 +272,7 @@ func GenerateVerifier(cfg Config) error {
 		"byteSliceToStr": byteSliceToStr,
 	}).Parse(goVerificationTmpl))
 
-	err := binding.FExecute(tmpl, cfg.Output, tmplParams{
-		Alpha: alphaG1[:],
-		Beta:  betaG2[:],
-		Gamma: gammaG2[:],
-		Delta: deltaG2[:],
-		ICs:   kvks,
-	})
+	err := binding.FExecute(tmpl, cfg.Output, params)
 	if err != nil {
 		return err
 	}
@@ -253,13 +284,22 @@ func GenerateVerifier(cfg Config) error {
 		}
 	}
 	if cfg.GomodOutput != nil {
-		_, err = cfg.GomodOutput.Write([]byte(verifyGomod))
+		gomod := verifyGomod
+		if cfg.InteropPath != "" {
+			gomod = fmt.Sprintf(verifyGomodReplace, cfg.InteropPath)
+		}
+		_, err = cfg.GomodOutput.Write([]byte(gomod))
 		if err != nil {
 			return fmt.Errorf("failed to generate go.mod file: %w", err)
 		}
 	}
 	if cfg.GosumOutput != nil {
-		_, err = cfg.GosumOutput.Write([]byte(verifyGosum))
+		gosum := verifyGosum
+		if cfg.InteropPath != "" {
+			// Replaced module has no checksums.
+			gosum = ""
+		}
+		_, err = cfg.GosumOutput.Write([]byte(gosum))
 		if err != nil {
 			return fmt.Errorf("failed to generate go.mod file: %w", err)
 		}
@@ -268,6 +308,25 @@ func GenerateVerifier(cfg Config) error {
 	return nil
 }
 
+// newTmplParams returns verification contract template parameters for the
+// given verifying key points, g1 and g2 serialize G1 and G2 points in the
+// compressed form.
+func newTmplParams[G1, G2 any](curve, name string, alpha *G1, beta, gamma, delta *G2, k []G1, g1 func(*G1) []byte, g2 func(*G2) []byte) tmplParams {
+	kvks := make([][]byte, len(k))
+	for i := range kvks {
+		kvks[i] = g1(&k[i])
+	}
+	return tmplParams{
+		Curve:     curve,
+		CurveName: name,
+		Alpha:     g1(alpha),
+		Beta:      g2(beta),
+		Gamma:     g2(gamma),
+		Delta:     g2(delta),
+		ICs:       kvks,
+	}
+}
+
 // byteSliceToStr is a codegen helper that converts byte slice to a go-like slice.
 func byteSliceToStr(s []byte) string {
 	var res string
@@ -284,7 +343,21 @@ func GetVerifyProofArgs(proof groth16.Proof, publicWitness witness.Witness) (*Ve
 	if proof == nil {
 		return nil, errors.New("nil proof")
 	}
-	if proof.CurveID() != ecc.BLS12_381 {
+	// Get the proof bytes (points are in the compressed form, as Verification contract accepts it).
+	var (
+		aBytes, bBytes, cBytes []byte
+		frSize                 int
+	)
+	switch p := proof.(type) {
+	case *curve.Proof:
+		a, b, c := p.Ar.Bytes(), p.Bs.Bytes(), p.Krs.Bytes()
+		aBytes, bBytes, cBytes = a[:], b[:], c[:]
+		frSize = fr.Bytes
+	case *bn254.Proof:
+		a, b, c := p.Ar.Bytes(), p.Bs.Bytes(), p.Krs.Bytes()
+		aBytes, bBytes, cBytes = a[:], b[:], c[:]
+		frSize = bn254fr.Bytes
+	default:
 		return nil, fmt.Errorf("unexpected elliptic curve: %s", proof.CurveID())
 	}
 	// If a full witness was provided, then retrieve public part, we don't need the secret part of it.
@@ -292,12 +365,6 @@ func GetVerifyProofArgs(proof groth16.Proof, publicWitness witness.Witness) (*Ve
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve public witness: %w", err)
 	}
-	// Get the proof bytes (points are in the compressed form, as Verification contract accepts it).
-	p := proof.(*curve.Proof)
-	aBytes := p.Ar.Bytes()
-	bBytes := p.Bs.Bytes()
-	cBytes := p.Krs.Bytes()
-
 	publicWitnessBytes, err := publicWitness.MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode public witness: %w", err)
@@ -318,15 +385,15 @@ func GetVerifyProofArgs(proof groth16.Proof, publicWitness witness.Witness) (*Ve
 	input := make([]any, numVectorElements)
 	offset := 12
 	for i := range input { // firstly - public witnesses, after that - private ones (but they are missing from publicWitness anyway).
-		start := offset + i*fr.Bytes
-		end := start + fr.Bytes
+		start := offset + i*frSize
+		end := start + frSize
 		slice.Reverse(publicWitnessBytes[start:end]) // gnark stores witnesses in the BE form, but native CryptoLib accepts LE-encoded fields elements (not a canonical form).
 		input[i] = publicWitnessBytes[start:end]
 	}
 	return &VerifyProofArgs{
-		A:               aBytes[:],
-		B:               bBytes[:],
-		C:               cBytes[:],
+		A:               aBytes,
+		B:               bBytes,
+		C:               cBytes,
 		PublicWitnesses: input,
 	}, nil
 }
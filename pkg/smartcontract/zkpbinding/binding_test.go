@@ -0,0 +1,125 @@
+package zkpbinding_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/epicchainlabs/epicchain-go/pkg/compiler"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest"
+	"github.com/epicchainlabs/epicchain-go/pkg/neotest/chain"
+	"github.com/epicchainlabs/epicchain-go/pkg/smartcontract/zkpbinding"
+	"github.com/stretchr/testify/require"
+)
+
+// cubicCircuit defines a simple circuit x**3 + x + 5 == y.
+type cubicCircuit struct {
+	X frontend.Variable `gnark:"x,secret"`
+	Y frontend.Variable `gnark:"y,public"`
+}
+
+// Define implements frontend.Circuit interface.
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestGenerateVerifier(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BLS12_381, ecc.BN254} {
+		t.Run(curve.String(), func(t *testing.T) {
+			var (
+				circuit    cubicCircuit
+				assignment = cubicCircuit{X: 3, Y: 35}
+			)
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &circuit)
+			require.NoError(t, err)
+			pk, vk, err := groth16.Setup(ccs)
+			require.NoError(t, err)
+
+			witness, err := frontend.NewWitness(&assignment, curve.ScalarField())
+			require.NoError(t, err)
+			publicWitness, err := witness.Public()
+			require.NoError(t, err)
+			proof, err := groth16.Prove(ccs, pk, witness)
+			require.NoError(t, err)
+
+			args, err := zkpbinding.GetVerifyProofArgs(proof, publicWitness)
+			require.NoError(t, err)
+
+			src := new(bytes.Buffer)
+			if curve == ecc.BN254 {
+				// Released interop package has no BN254 wrappers.
+				for _, cfg := range []zkpbinding.Config{
+					{VerifyingKey: vk, Output: src, GomodOutput: new(bytes.Buffer)},
+					{VerifyingKey: vk, Output: src, GosumOutput: new(bytes.Buffer)},
+				} {
+					require.ErrorContains(t, zkpbinding.GenerateVerifier(cfg), "requires InteropPath")
+					require.Zero(t, src.Len())
+				}
+			}
+			require.NoError(t, zkpbinding.GenerateVerifier(zkpbinding.Config{
+				VerifyingKey: vk,
+				Output:       src,
+			}))
+
+			bc, committee := chain.NewSingle(t)
+			e := neotest.NewExecutor(t, bc, committee, committee)
+			c := neotest.CompileSource(t, e.CommitteeHash, src, &compiler.Options{Name: "Verifier"})
+			e.DeployContract(t, c, nil)
+
+			inv := e.CommitteeInvoker(c.Hash)
+			inv.Invoke(t, true, "verifyProof", args.A, args.B, args.C, args.PublicWitnesses)
+
+			// Standalone contract module using the interop package from this repository.
+			interopPath, err := filepath.Abs("../../interop")
+			require.NoError(t, err)
+			tmpDir := t.TempDir()
+			srcPath := filepath.Join(tmpDir, "verify.go")
+			cfgPath := filepath.Join(tmpDir, "verify.yml")
+			var srcFile, cfgFile, modFile, sumFile bytes.Buffer
+			require.NoError(t, zkpbinding.GenerateVerifier(zkpbinding.Config{
+				VerifyingKey: vk,
+				Output:       &srcFile,
+				CfgOutput:    &cfgFile,
+				GomodOutput:  &modFile,
+				GosumOutput:  &sumFile,
+				InteropPath:  interopPath,
+			}))
+			require.Contains(t, modFile.String(), "replace github.com/epicchainlabs/epicchain-go/pkg/interop => ")
+			require.Zero(t, sumFile.Len())
+			require.NoError(t, os.WriteFile(srcPath, srcFile.Bytes(), os.ModePerm))
+			require.NoError(t, os.WriteFile(cfgPath, cfgFile.Bytes(), os.ModePerm))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), modFile.Bytes(), os.ModePerm))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.sum"), sumFile.Bytes(), os.ModePerm))
+			cf := neotest.CompileFile(t, e.CommitteeHash, srcPath, cfgPath)
+			e.DeployContract(t, cf, nil)
+			e.CommitteeInvoker(cf.Hash).Invoke(t, true, "verifyProof", args.A, args.B, args.C, args.PublicWitnesses)
+
+			// Wrong public input.
+			wrong := cubicCircuit{Y: 36}
+			wrongWitness, err := frontend.NewWitness(&wrong, curve.ScalarField(), frontend.PublicOnly())
+			require.NoError(t, err)
+			wrongArgs, err := zkpbinding.GetVerifyProofArgs(proof, wrongWitness)
+			require.NoError(t, err)
+			inv.Invoke(t, false, "verifyProof", args.A, args.B, args.C, wrongArgs.PublicWitnesses)
+		})
+	}
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		var circuit cubicCircuit
+		ccs, err := frontend.Compile(ecc.BLS12_377.ScalarField(), r1cs.NewBuilder, &circuit)
+		require.NoError(t, err)
+		_, vk, err := groth16.Setup(ccs)
+		require.NoError(t, err)
+		require.ErrorContains(t, zkpbinding.GenerateVerifier(zkpbinding.Config{
+			VerifyingKey: vk,
+			Output:       new(bytes.Buffer),
+		}), "unexpected elliptic curve")
+	})
+}
@@ -249,6 +249,12 @@ func (c *Context) GetNEF() *nef.File {
 	return c.sc.NEF
 }
 
+// InvocationTree returns the invocation tree node of this context, it's nil if
+// invocation tree collection is not enabled.
+func (c *Context) InvocationTree() *invocations.Tree {
+	return c.sc.invTree
+}
+
 // NumOfReturnVals returns the number of return values expected from this context.
 func (c *Context) NumOfReturnVals() int {
 	return c.retCount
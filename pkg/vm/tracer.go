@@ -0,0 +1,21 @@
+package vm
+
+// Tracer receives VM execution events, it can be used to build debuggers,
+// profilers and other tools that need to follow the execution in detail. Its
+// methods are called synchronously from the VM, so they should return quickly
+// and must not change the VM state. The VM collects the invocation tree when
+// a tracer is set, so the tree node of every script context is available via
+// Context.InvocationTree.
+type Tracer interface {
+	// OnEnter is called after a new script context is loaded into the VM,
+	// it's either an entry script or a contract call.
+	OnEnter(v *VM, ctx *Context)
+	// OnExit is called after the script context is unloaded, commit is false
+	// if it's unloaded because of an exception. It's not called for contexts
+	// left in the invocation stack when the VM faults.
+	OnExit(v *VM, ctx *Context, commit bool)
+	// OnGas is called for every GAS charge (both for instructions and for
+	// interop calls) with the amount charged. It's called before the GAS limit
+	// check, so the last charge can exceed the limit.
+	OnGas(v *VM, gas int64)
+}
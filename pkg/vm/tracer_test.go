@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/epicchainlabs/epicchain-go/pkg/util"
+	"github.com/epicchainlabs/epicchain-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+type testTracer struct {
+	enter []util.Uint160
+	exit  []util.Uint160
+	fails int
+	gas   int64
+}
+
+func (t *testTracer) OnEnter(_ *VM, ctx *Context) {
+	t.enter = append(t.enter, ctx.ScriptHash())
+}
+
+func (t *testTracer) OnExit(_ *VM, ctx *Context, commit bool) {
+	t.exit = append(t.exit, ctx.ScriptHash())
+	if !commit {
+		t.fails++
+	}
+}
+
+func (t *testTracer) OnGas(_ *VM, gas int64) {
+	t.gas += gas
+}
+
+func TestTracer(t *testing.T) {
+	inner := []byte{byte(opcode.PUSH1), byte(opcode.RET)}
+	script := []byte{
+		byte(opcode.SYSCALL), 0, 0, 0, 0, byte(opcode.DROP),
+		byte(opcode.SYSCALL), 0, 0, 0, 0, byte(opcode.DROP),
+		byte(opcode.RET),
+	}
+	v := newTestVM()
+	v.SetPriceGetter(func(opcode.Opcode, []byte) int64 { return 1 })
+	var cnt byte
+	v.SyscallHandler = func(v *VM, _ uint32) error {
+		cnt++
+		v.LoadScriptWithHash(inner, util.Uint160{cnt}, 0)
+		return nil
+	}
+	tr := new(testTracer)
+	v.SetTracer(tr)
+	v.LoadScript(script)
+	topHash := v.Context().ScriptHash()
+	require.NoError(t, v.Run())
+
+	require.Equal(t, []util.Uint160{topHash, {1}, {2}}, tr.enter)
+	require.Equal(t, []util.Uint160{{1}, {2}, topHash}, tr.exit)
+	require.Equal(t, 0, tr.fails)
+	require.Equal(t, v.GasConsumed(), tr.gas)
+	require.True(t, v.AddGas(10))
+	require.Equal(t, v.GasConsumed(), tr.gas)
+
+	// Tracing enables invocation tree.
+	tree := v.GetInvocationTree()
+	require.NotNil(t, tree)
+	require.Equal(t, 1, len(tree.Calls))
+	require.Equal(t, topHash, tree.Calls[0].Current)
+	require.Equal(t, 2, len(tree.Calls[0].Calls))
+
+	t.Run("exception", func(t *testing.T) {
+		thrower := []byte{byte(opcode.PUSH1), byte(opcode.THROW)}
+		script := []byte{
+			byte(opcode.TRY), 8, 0,
+			byte(opcode.SYSCALL), 0, 0, 0, 0,
+			byte(opcode.DROP), byte(opcode.RET),
+		}
+		v := newTestVM()
+		v.SyscallHandler = func(v *VM, _ uint32) error {
+			v.LoadScriptWithHash(thrower, util.Uint160{1}, 0)
+			return nil
+		}
+		tr := new(testTracer)
+		v.SetTracer(tr)
+		v.LoadScript(script)
+		require.NoError(t, v.Run())
+		require.Equal(t, 2, len(tr.enter))
+		require.Equal(t, 2, len(tr.exit))
+		require.Equal(t, 1, tr.fails)
+		require.Equal(t, util.Uint160{1}, tr.exit[0])
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		v.Reset(v.trigger)
+		v.LoadScript([]byte{byte(opcode.RET)})
+		require.NoError(t, v.Run())
+		require.Equal(t, 3, len(tr.enter))
+		require.Nil(t, v.GetInvocationTree())
+	})
+}
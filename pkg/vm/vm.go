@@ -90,6 +90,9 @@ type VM struct {
 
 	// invTree is a top-level invocation tree (if enabled).
 	invTree *invocations.Tree
+
+	// tracer receives execution events (if set).
+	tracer Tracer
 }
 
 var (
@@ -138,6 +141,7 @@ func (v *VM) Reset(t trigger.Type) {
 	v.LoadToken = nil
 	v.trigger = t
 	v.invTree = nil
+	v.tracer = nil
 }
 
 // GasConsumed returns the amount of GAS consumed during execution.
@@ -148,6 +152,9 @@ func (v *VM) GasConsumed() int64 {
 // AddGas consumes the specified amount of gas. It returns true if gas limit wasn't exceeded.
 func (v *VM) AddGas(gas int64) bool {
 	v.gasConsumed += gas
+	if v.tracer != nil {
+		v.tracer.OnGas(v, gas)
+	}
 	return v.GasLimit < 0 || v.gasConsumed <= v.GasLimit
 }
 
@@ -283,6 +290,13 @@ func (v *VM) GetInvocationTree() *invocations.Tree {
 	return v.invTree
 }
 
+// SetTracer sets the tracer receiving execution events, nil disables tracing.
+// Tracing relies on the invocation tree, so it's collected for every script
+// loaded after this call.
+func (v *VM) SetTracer(t Tracer) {
+	v.tracer = t
+}
+
 // Load initializes the VM with the program given.
 func (v *VM) Load(prog []byte) {
 	v.LoadWithFlags(prog, callflag.NoneFlag)
@@ -363,6 +377,9 @@ func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, caller util.Uint
 	ctx.sc.scriptHash = hash
 	ctx.sc.callingScriptHash = caller
 	ctx.sc.NEF = exe
+	if v.invTree == nil && parent == nil && v.tracer != nil {
+		v.invTree = &invocations.Tree{}
+	}
 	if v.invTree != nil {
 		curTree := v.invTree
 		if parent != nil {
@@ -374,6 +391,9 @@ func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, caller util.Uint
 	}
 	ctx.sc.onUnload = onContextUnload
 	v.istack = append(v.istack, ctx)
+	if v.tracer != nil {
+		v.tracer.OnEnter(v, ctx)
+	}
 }
 
 // Context returns the current executed context. Nil if there is no context,
@@ -597,7 +617,11 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 	}()
 
 	if v.getPrice != nil && ctx.ip < len(ctx.sc.prog) {
-		v.gasConsumed += v.getPrice(op, parameter)
+		price := v.getPrice(op, parameter)
+		v.gasConsumed += price
+		if v.tracer != nil {
+			v.tracer.OnGas(v, price)
+		}
 		if v.GasLimit >= 0 && v.gasConsumed > v.GasLimit {
 			panic("gas limit is exceeded")
 		}
@@ -1678,6 +1702,9 @@ func (v *VM) unloadContext(ctx *Context) {
 				panic(errors.New(errMessage))
 			}
 		}
+		if v.tracer != nil {
+			v.tracer.OnExit(v, ctx, v.uncaughtException == nil)
+		}
 	}
 }
 